@@ -0,0 +1,18 @@
+// Command demo runs the DependencyInversion example; see
+// dependencyinversion.Run.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/anil-vinnakoti/go-SOLID/DependencyInversion"
+)
+
+func main() {
+	if err := dependencyinversion.Run(context.Background()); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -38,15 +38,24 @@
 // Implementation depends on abstraction.
 //
 
-package main
+package dependencyinversion
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/timeout"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/tracing"
+)
 
 // Low-level module (PDF implementation)
 type PDFGenerator struct{}
 
-func (p PDFGenerator) Generate(content string) {
+func (p PDFGenerator) Generate(ctx context.Context, content string) error {
 	fmt.Println("Generating PDF with content:", content)
+	return nil
 }
 
 // High-level module (Business logic)
@@ -54,9 +63,9 @@ type ReportService struct {
 	pdf PDFGenerator // ❌ depends on concrete implementation
 }
 
-func (r ReportService) CreateReport() {
+func (r ReportService) CreateReport(ctx context.Context) error {
 	content := "Annual Financial Report"
-	r.pdf.Generate(content)
+	return r.pdf.Generate(ctx, content)
 }
 
 
@@ -66,7 +75,7 @@ func (r ReportService) CreateReport() {
 
 // Abstraction (defined by high-level module)
 type ReportGenerator interface {
-	Generate(content string)
+	Generate(ctx context.Context, content string) error
 }
 
 // High-level module
@@ -74,18 +83,94 @@ type ReportServiceOne struct {
 	generator ReportGenerator // ✅ depends on abstraction
 }
 
-func NewReportServiceOne(generator ReportGenerator) *ReportServiceOne {
+// NewReportServiceOne returns a service generating reports with
+// generator, wrapped in the decorators opts ask for. The service still
+// only sees a ReportGenerator. The trace span covers the timeout, so a
+// report that times out still ends its span.
+func NewReportServiceOne(generator ReportGenerator, opts ...ReportOption) *ReportServiceOne {
+	var o reportOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.timeout > 0 {
+		generator = NewTimeoutReportGenerator(generator, o.timeout, nil)
+	}
+	if o.tracer != nil {
+		generator = NewTracedReportGenerator(generator, o.tracer)
+	}
 	return &ReportServiceOne{generator: generator}
 }
 
-func (r ReportServiceOne) CreateReport() {
+func (r ReportServiceOne) CreateReport(ctx context.Context) error {
 	content := "Annual Financial Report"
-	r.generator.Generate(content)
+	return r.generator.Generate(ctx, content)
+}
+
+// ReportOption configures NewReportServiceOne.
+type ReportOption func(*reportOptions)
+
+type reportOptions struct {
+	tracer  tracing.Tracer
+	timeout time.Duration
+}
+
+// WithTracer traces every report generated to t.
+func WithTracer(t tracing.Tracer) ReportOption {
+	return func(o *reportOptions) { o.tracer = t }
+}
+
+// WithTimeout fails a report that takes longer than d to generate; see
+// TimeoutReportGenerator.
+func WithTimeout(d time.Duration) ReportOption {
+	return func(o *reportOptions) { o.timeout = d }
+}
+
+// TracedReportGenerator wraps any ReportGenerator in a span.
+// The tracer is just another injected abstraction; ReportServiceOne
+// does not know it is being traced. The span is a child of the one in
+// the caller's context, so the report shows up in the caller's trace.
+type TracedReportGenerator struct {
+	next   ReportGenerator
+	tracer tracing.Tracer
+}
+
+func NewTracedReportGenerator(next ReportGenerator, tracer tracing.Tracer) *TracedReportGenerator {
+	return &TracedReportGenerator{next: next, tracer: tracer}
+}
+
+func (t TracedReportGenerator) Generate(ctx context.Context, content string) error {
+	ctx, span := t.tracer.StartSpan(ctx, fmt.Sprintf("%T.Generate", t.next))
+	defer span.End()
+
+	return t.next.Generate(ctx, content)
+}
+
+// TimeoutReportGenerator is a ReportGenerator decorator that gives up
+// on a report after a fixed time with a *timeout.Error. Like the
+// tracer, the deadline is added around the abstraction, so neither
+// ReportServiceOne nor the generator changes. A nil clk means the
+// system clock.
+type TimeoutReportGenerator struct {
+	next    ReportGenerator
+	timeout time.Duration
+	clock   clock.Clock
+}
+
+func NewTimeoutReportGenerator(next ReportGenerator, d time.Duration, clk clock.Clock) *TimeoutReportGenerator {
+	return &TimeoutReportGenerator{next: next, timeout: d, clock: clk}
+}
+
+func (t TimeoutReportGenerator) Generate(ctx context.Context, content string) error {
+	return timeout.Do(ctx, t.clock, "report.generate", t.timeout, func(ctx context.Context) error {
+		return t.next.Generate(ctx, content)
+	})
 }
 
-func main() {
-	pdf := PDFGenerator{}
-	service := NewReportServiceOne(pdf)
+// Run creates a traced report with a timeout; cmd/demo runs it.
+func Run(ctx context.Context) error {
+	service := NewReportServiceOne(PDFGenerator{}, WithTracer(tracing.Console{}), WithTimeout(5*time.Second))
 
-	service.CreateReport()
+	ctx, span := tracing.Console{}.StartSpan(ctx, "Run")
+	defer span.End()
+	return service.CreateReport(ctx)
 }
@@ -0,0 +1,9 @@
+// Command demo runs the InterfaceSegregation example; see
+// interfacesegregation.Run.
+package main
+
+import "github.com/anil-vinnakoti/go-SOLID/InterfaceSegregation"
+
+func main() {
+	interfacesegregation.Run()
+}
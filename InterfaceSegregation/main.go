@@ -35,7 +35,7 @@
 // they do not actually support.
 //
 
-package main
+package interfacesegregation
 
 import "fmt"
 
@@ -117,3 +117,16 @@ func (a AdvancedMachine) Fax() {
 // - No unused methods.
 // - No panic implementations.
 // - Flexible and scalable design.
+
+// Run prints and scans with the segregated interfaces; cmd/demo runs
+// it.
+func Run() {
+	// Each caller asks only for the behaviour it uses.
+	printers := []Printer{SimplePrinter{}, AdvancedMachine{}}
+	for _, p := range printers {
+		p.Print()
+	}
+
+	var scanner Scanner = AdvancedMachine{}
+	scanner.Scan()
+}
@@ -0,0 +1,9 @@
+// Command demo runs the LiskovSubstitution example; see
+// liskovsubstitution.Run.
+package main
+
+import "github.com/anil-vinnakoti/go-SOLID/LiskovSubstitution"
+
+func main() {
+	liskovsubstitution.Run()
+}
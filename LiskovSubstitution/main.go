@@ -51,7 +51,7 @@
 // Implementing an interface is not enough;
 // the implementation must satisfy the behavioral contract.
 
-package main
+package liskovsubstitution
 
 import "fmt"
 
@@ -77,7 +77,8 @@ func MakeBirdFly(b Bird) {
 	b.Fly() // We assume every Bird can fly
 }
 
-func main() {
+// Run makes a bird fly; cmd/demo runs it.
+func Run() {
 	sparrow := Sparrow{}
 
 	// Works fine because Sparrow behaves as expected
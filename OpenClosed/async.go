@@ -0,0 +1,112 @@
+package openclosed
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/errreport"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/workqueue"
+)
+
+var (
+	ErrQueueFull   = workqueue.ErrFull
+	ErrQueueClosed = workqueue.ErrClosed
+)
+
+// AsyncDispatcher queues messages on a bounded queue and delivers them
+// from a pool of worker goroutines. It is a Notification, sending
+// through the channel it was built with, and a DeliveryPolicy, sending
+// through the channel it is given. The queue is a workqueue.Queue, as
+// is the SingleResponsibility EmailQueue.
+//
+// Send waits while the queue is full, so callers slow down to the pace
+// of the channel instead of losing messages. With more than one
+// worker, messages may be delivered out of order.
+type AsyncDispatcher struct {
+	next Notification
+	jobQueue
+}
+
+// QueuedDelivery is a DeliveryPolicy that sends the messages one at a
+// time, in the order they were queued, from a background goroutine.
+// Unlike AsyncDispatcher it never makes the caller wait, and it has no
+// channel of its own, so it is not a Notification.
+type QueuedDelivery struct {
+	jobQueue
+}
+
+// jobQueue is the queue and workers behind AsyncDispatcher and
+// QueuedDelivery.
+type jobQueue struct {
+	queue *workqueue.Queue[queuedJob]
+}
+
+type queuedJob struct {
+	next Notification
+	msg  Message
+}
+
+// NewAsyncDispatcher starts workers goroutines delivering through next
+// with room for size queued messages. onError receives the failed
+// deliveries, panics included, and may be nil; see ReportTo.
+func NewAsyncDispatcher(next Notification, workers, size int, onError func(Message, error)) *AsyncDispatcher {
+	return &AsyncDispatcher{
+		next:     next,
+		jobQueue: newJobQueue(workqueue.Options{Workers: workers, Size: size, Block: true}, onError),
+	}
+}
+
+// NewQueuedDelivery returns a QueuedDelivery with room for size
+// messages. Deliver fails with ErrQueueFull rather than wait for one
+// of them. onError may be nil.
+func NewQueuedDelivery(size int, onError func(Message, error)) *QueuedDelivery {
+	return &QueuedDelivery{newJobQueue(workqueue.Options{Workers: 1, Size: size}, onError)}
+}
+
+func newJobQueue(opts workqueue.Options, onError func(Message, error)) jobQueue {
+	if onError == nil {
+		onError = func(Message, error) {}
+	}
+	send := func(ctx context.Context, job queuedJob) error {
+		return job.next.Send(ctx, job.msg)
+	}
+	failed := func(ctx context.Context, job queuedJob, err error) {
+		onError(job.msg, err)
+	}
+	return jobQueue{queue: workqueue.New(workqueue.Shared(send), opts, failed)}
+}
+
+// ReportTo returns an onError callback for AsyncDispatcher that
+// captures failed deliveries, panics included, with r, tagged with the
+// recipient.
+func ReportTo(r errreport.Reporter) func(Message, error) {
+	return func(msg Message, err error) {
+		r.Capture(context.Background(), err, map[string]string{"to": msg.To})
+	}
+}
+
+// Send queues msg for the channel the dispatcher was built with.
+func (d *AsyncDispatcher) Send(ctx context.Context, msg Message) error {
+	return d.Deliver(ctx, d.next, msg)
+}
+
+// Deliver queues msg for next. The delivery keeps ctx's values but not
+// its cancellation. A full queue fails with ErrQueueFull, at once or,
+// for an AsyncDispatcher, once ctx is done.
+func (q jobQueue) Deliver(ctx context.Context, next Notification, msg Message) error {
+	if err := q.queue.Put(ctx, queuedJob{next: next, msg: msg}); err != nil {
+		return fmt.Errorf("notification queue: %w", err)
+	}
+	return nil
+}
+
+// Shutdown stops accepting messages and waits until the workers have
+// delivered everything queued or in flight, or until ctx is done. A
+// Send waiting for room fails with ErrQueueClosed rather than hold it
+// up.
+func (q jobQueue) Shutdown(ctx context.Context) error {
+	if err := q.queue.Shutdown(ctx); err != nil {
+		return fmt.Errorf("notification queue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package openclosed
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/errreport"
+)
+
+func TestAsyncDispatcher_ReportsPanics(t *testing.T) {
+	var sent int
+	ok := recordingChannel{&sent}
+	panicky := NotificationFunc(func(ctx context.Context, msg Message) error {
+		if msg.To == "panic" {
+			panic("template missing")
+		}
+		return ok.Send(ctx, msg)
+	})
+	reporter := errreport.NewMemory(10, nil)
+	d := NewAsyncDispatcher(panicky, 1, 10, ReportTo(reporter))
+
+	for _, to := range []string{"panic", "a@example.com"} {
+		if err := d.Send(context.Background(), Message{To: to}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := d.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if sent != 1 {
+		t.Errorf("sent %d messages, want 1: the worker should survive the panic", sent)
+	}
+	events := reporter.Events()
+	var perr *errreport.PanicError
+	if len(events) != 1 || !errors.As(events[0].Err, &perr) || events[0].Tags["to"] != "panic" {
+		t.Errorf("reported %+v, want the panic", events)
+	}
+}
+
+// A full dispatcher makes Send wait until a worker frees up room or
+// the caller gives up, and Shutdown releases the Sends still waiting.
+func TestAsyncDispatcher_Backpressure(t *testing.T) {
+	ctx := context.Background()
+	channel := newGatedChannel()
+	d := NewAsyncDispatcher(channel, 1, 1, nil)
+	low := func(ctx context.Context, subject string) error {
+		return d.Send(ctx, Message{Subject: subject, Priority: PriorityLow})
+	}
+
+	if err := low(ctx, "sending"); err != nil {
+		t.Fatal(err)
+	}
+	<-channel.started
+	if err := low(ctx, "waiting"); err != nil {
+		t.Fatal(err)
+	}
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := low(timeout, "gave up"); !errors.Is(err, ErrQueueFull) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("full queue: %v, want ErrQueueFull and the deadline", err)
+	}
+
+	blocked := make(chan error, 1)
+	go func() { blocked <- low(ctx, "blocked") }()
+	stopped, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := d.Shutdown(stopped); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown with a stuck send = %v", err)
+	}
+	if err := <-blocked; !errors.Is(err, ErrQueueClosed) {
+		t.Fatalf("Send waiting at Shutdown = %v, want ErrQueueClosed", err)
+	}
+
+	close(channel.release)
+	if err := d.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := channel.Sent(); !slices.Equal(got, []string{"sending", "waiting"}) {
+		t.Errorf("sent %q", got)
+	}
+}
+
+func TestRecover_Middleware(t *testing.T) {
+	reporter := errreport.NewMemory(10, nil)
+	n := Chain(hangingChannel{}, Recover(reporter))
+	panicky := Chain(NotificationFunc(func(ctx context.Context, msg Message) error {
+		panic("template missing")
+	}), Recover(reporter))
+
+	if err := panicky.Send(context.Background(), Message{To: "a@example.com"}); !errors.As(err, new(*errreport.PanicError)) {
+		t.Fatalf("err = %v, want a *errreport.PanicError", err)
+	}
+	if got := channelName(n); got != "hangingChannel" {
+		t.Errorf("channel name = %q, want the wrapped channel's", got)
+	}
+	if events := reporter.Events(); len(events) != 1 || events[0].Tags["channel"] == "" {
+		t.Errorf("reported %+v, want the panic tagged with its channel", events)
+	}
+}
@@ -0,0 +1,48 @@
+// =========================================
+// BAD EXAMPLE - Violates Open / Closed Principle (OCP)
+// =========================================
+//
+// PaymentProcessor picks the payment method with an if/else chain on
+// the method's name. Every new method means editing ProcessPayment,
+// retesting every branch and redeploying code that already worked.
+//
+// The fixed version is ../payment.go: each branch is a PaymentMethod
+// in its own file, and PaymentProcessor dispatches through a registry.
+//
+// Speed is no reason to keep the switch. BenchmarkDispatch in both
+// directories measures the dispatch alone. The switch takes a few
+// nanoseconds however many methods it has, since Go compiles it to a
+// binary search. The registry takes under 100ns from 1 to 50 methods,
+// for the lock, the flag check and the map lookup. A real payment
+// takes milliseconds.
+//
+// Run it with:
+//
+//	go run badocp/main.go
+
+package main
+
+import "fmt"
+
+// PaymentProcessor handles different payment types
+type PaymentProcessor struct{}
+
+// This function violates OCP
+func (p PaymentProcessor) ProcessPayment(method string, amount float64) {
+	if method == "credit" {
+		fmt.Println("Processing credit card payment of", amount)
+	} else if method == "paypal" {
+		fmt.Println("Processing PayPal payment of", amount)
+	} else if method == "upi" {
+		fmt.Println("Processing UPI payment of", amount)
+	} else {
+		fmt.Println("Unsupported payment method")
+	}
+}
+
+func main() {
+	processor := PaymentProcessor{}
+
+	processor.ProcessPayment("credit", 1000)
+	processor.ProcessPayment("paypal", 2000)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+// captureStdout runs fn with os.Stdout redirected and returns what it
+// printed. ProcessPayment returns nothing, so this is the only way to
+// observe it.
+func captureStdout(tb testing.TB, fn func()) string {
+	tb.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan []byte)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- out
+	}()
+	fn()
+	w.Close()
+	return string(<-done)
+}
+
+// An unknown method is only a printed line; the caller cannot tell it
+// from a payment that went through.
+func TestPaymentProcessor_ProcessPayment(t *testing.T) {
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{"credit", "Processing credit card payment of 1000\n"},
+		{"paypal", "Processing PayPal payment of 1000\n"},
+		{"upi", "Processing UPI payment of 1000\n"},
+		{"crypto", "Unsupported payment method\n"},
+	}
+	for _, tt := range tests {
+		out := captureStdout(t, func() { PaymentProcessor{}.ProcessPayment(tt.method, 1000) })
+		if out != tt.want {
+			t.Errorf("%s: printed %q, want %q", tt.method, out, tt.want)
+		}
+	}
+}
+
+// processWide is PaymentProcessor grown to 50 methods the way it
+// would grow, one more case per method. The cases do no work, so the
+// benchmark measures the dispatch alone.
+func processWide(method string) error {
+	switch method {
+	case "m01":
+		return nil
+	case "m02":
+		return nil
+	case "m03":
+		return nil
+	case "m04":
+		return nil
+	case "m05":
+		return nil
+	case "m06":
+		return nil
+	case "m07":
+		return nil
+	case "m08":
+		return nil
+	case "m09":
+		return nil
+	case "m10":
+		return nil
+	case "m11":
+		return nil
+	case "m12":
+		return nil
+	case "m13":
+		return nil
+	case "m14":
+		return nil
+	case "m15":
+		return nil
+	case "m16":
+		return nil
+	case "m17":
+		return nil
+	case "m18":
+		return nil
+	case "m19":
+		return nil
+	case "m20":
+		return nil
+	case "m21":
+		return nil
+	case "m22":
+		return nil
+	case "m23":
+		return nil
+	case "m24":
+		return nil
+	case "m25":
+		return nil
+	case "m26":
+		return nil
+	case "m27":
+		return nil
+	case "m28":
+		return nil
+	case "m29":
+		return nil
+	case "m30":
+		return nil
+	case "m31":
+		return nil
+	case "m32":
+		return nil
+	case "m33":
+		return nil
+	case "m34":
+		return nil
+	case "m35":
+		return nil
+	case "m36":
+		return nil
+	case "m37":
+		return nil
+	case "m38":
+		return nil
+	case "m39":
+		return nil
+	case "m40":
+		return nil
+	case "m41":
+		return nil
+	case "m42":
+		return nil
+	case "m43":
+		return nil
+	case "m44":
+		return nil
+	case "m45":
+		return nil
+	case "m46":
+		return nil
+	case "m47":
+		return nil
+	case "m48":
+		return nil
+	case "m49":
+		return nil
+	case "m50":
+		return nil
+	}
+	return fmt.Errorf("unsupported payment method %q", method)
+}
+
+// BenchmarkDispatch pays with the n-th method of the switch. Compare
+// with BenchmarkDispatch in the parent directory, which looks the
+// method up in a registry of n:
+//
+//	go test -run - -bench Dispatch ./OpenClosed ./OpenClosed/badocp
+func BenchmarkDispatch(b *testing.B) {
+	for _, n := range []int{1, 5, 10, 25, 50} {
+		method := fmt.Sprintf("m%02d", n)
+		b.Run(fmt.Sprintf("methods=%d", n), func(b *testing.B) {
+			for b.Loop() {
+				if err := processWide(method); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package openclosed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/breaker"
+)
+
+func TestCircuitBreaker_FallsBack(t *testing.T) {
+	down := &FakeTransport{Status: http.StatusServiceUnavailable}
+	slack, err := NewSlackService(SlackConfig{WebhookURL: "https://hooks.slack.example/T000/B000"}, &http.Client{Transport: down})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var emailed int
+	email := recordingChannel{&emailed}
+	b := breaker.New(breaker.Settings{Name: "slack", FailureThreshold: 2})
+	guarded := Chain(slack, CircuitBreaker(b))
+	n := NewFallbackNotifier(guarded, email, ErrDeliveryFailed)
+
+	for range 4 {
+		if err := n.Send(context.Background(), Message{To: "#orders", Body: "hi"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := len(down.Requests()); got != 2 {
+		t.Errorf("Slack got %d requests, want 2 before the breaker opened", got)
+	}
+	if emailed != 4 {
+		t.Errorf("emailed %d times, want 4", emailed)
+	}
+
+	err = guarded.Send(context.Background(), Message{})
+	if !errors.Is(err, breaker.ErrOpen) || !errors.Is(err, ErrDeliveryFailed) {
+		t.Errorf("err = %v, want an open breaker", err)
+	}
+	if got := channelName(guarded); got != channelName(slack) {
+		t.Errorf("channelName = %q, want %q", got, channelName(slack))
+	}
+}
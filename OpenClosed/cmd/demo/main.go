@@ -0,0 +1,13 @@
+// Command demo runs the demos of the OpenClosed chapter; see
+// openclosed.Run.
+package main
+
+import (
+	"context"
+
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed"
+)
+
+func main() {
+	openclosed.Run(context.Background())
+}
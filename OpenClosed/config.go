@@ -0,0 +1,116 @@
+package openclosed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/flags"
+)
+
+var ErrInvalidConfig = errors.New("invalid notifier config")
+
+// Delivery modes of a NotifierConfig.
+const (
+	ModeAll      = "all"      // send through every channel
+	ModeFallback = "fallback" // send through the first channel that works
+)
+
+// NotifierConfig selects and configures the active channels. Swapping
+// one provider for another is a change here, not in code.
+type NotifierConfig struct {
+	Mode     string          `json:"mode"` // ModeAll (the default) or ModeFallback
+	Channels []ChannelConfig `json:"channels"`
+}
+
+// ChannelConfig is one channel of a NotifierConfig. Channels are used
+// in the order they are listed.
+type ChannelConfig struct {
+	Name     string   `json:"name"`
+	Disabled bool     `json:"disabled"`
+	Timeout  string   `json:"timeout"` // per send, such as "3s"; empty means none
+	Flag     string   `json:"flag"`    // a feature flag that turns the channel on and off at runtime
+	Settings Settings `json:"settings"`
+}
+
+// LoadNotifierConfig reads a NotifierConfig from JSON. Unknown fields
+// are rejected, so a misspelt key does not silently fall back to the
+// default.
+func LoadNotifierConfig(r io.Reader) (NotifierConfig, error) {
+	var cfg NotifierConfig
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return NotifierConfig{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	return cfg, nil
+}
+
+// BuildNotifier opens the enabled channels of cfg from reg and combines
+// them according to cfg.Mode. A channel with a Flag is gated by that
+// flag in p, which may be nil; see Gate. Every problem with cfg is
+// reported, not just the first.
+func BuildNotifier(reg *Registry, p flags.Provider, cfg NotifierConfig) (Notification, error) {
+	var errs []error
+	switch cfg.Mode {
+	case "", ModeAll, ModeFallback:
+	default:
+		errs = append(errs, fmt.Errorf("%w: unknown mode %q", ErrInvalidConfig, cfg.Mode))
+	}
+
+	var channels []Notification
+	seen := make(map[string]bool)
+	for i, c := range cfg.Channels {
+		if c.Name == "" {
+			errs = append(errs, fmt.Errorf("%w: channel %d has no name", ErrInvalidConfig, i))
+			continue
+		}
+		if seen[c.Name] {
+			errs = append(errs, fmt.Errorf("%w: channel %q listed twice", ErrInvalidConfig, c.Name))
+			continue
+		}
+		seen[c.Name] = true
+		if c.Disabled {
+			continue
+		}
+		var d time.Duration
+		if c.Timeout != "" {
+			var err error
+			if d, err = time.ParseDuration(c.Timeout); err != nil || d <= 0 {
+				errs = append(errs, fmt.Errorf("%w: channel %q: invalid timeout %q", ErrInvalidConfig, c.Name, c.Timeout))
+				continue
+			}
+		}
+		n, err := reg.OpenWith(c.Name, c.Settings)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if d > 0 {
+			n = Timeout(d, nil)(n)
+		}
+		if c.Flag != "" {
+			n = Gate(p, c.Flag)(n)
+		}
+		channels = append(channels, n)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	switch {
+	case len(channels) == 0:
+		return nil, fmt.Errorf("%w: no enabled channels", ErrInvalidConfig)
+	case len(channels) == 1:
+		return channels[0], nil
+	case cfg.Mode == ModeFallback:
+		n := channels[len(channels)-1]
+		for i := len(channels) - 2; i >= 0; i-- {
+			n = NewFallbackNotifier(channels[i], n)
+		}
+		return n, nil
+	}
+	return NewMultiNotifier(channels...), nil
+}
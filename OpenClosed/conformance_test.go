@@ -0,0 +1,73 @@
+package openclosed
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/notify"
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/notify/notifytest"
+)
+
+// asNotify lets notifytest drive a channel of this package.
+type asNotify struct{ n Notification }
+
+func (a asNotify) Send(ctx context.Context, msg notify.Message) error {
+	return a.n.Send(ctx, Message{To: msg.To, Subject: msg.Subject, Body: msg.Body})
+}
+
+// conformanceChannels builds each registered channel with a recipient
+// it accepts. The HTTP channels answer through a FakeTransport.
+var conformanceChannels = map[string]notifytest.Factory{
+	"email": func(t *testing.T) (notify.Notification, string) {
+		return asNotify{EmailService{}}, "customer@example.com"
+	},
+	"sms": func(t *testing.T) (notify.Notification, string) {
+		return asNotify{SmsService{}}, "+4915112345678"
+	},
+	"push": func(t *testing.T) (notify.Notification, string) {
+		n, err := NewPushNotificationService(PushConfig{Endpoint: "https://push.example.com/send", APIKey: "k3y"}, fakeClient())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return asNotify{n}, "device-1"
+	},
+	"slack": func(t *testing.T) (notify.Notification, string) {
+		n, err := NewSlackService(SlackConfig{WebhookURL: "https://hooks.slack.example.com/T0"}, fakeClient())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return asNotify{n}, ""
+	},
+	"webhook": func(t *testing.T) (notify.Notification, string) {
+		n, err := NewWebhookService(WebhookConfig{URL: "https://hooks.example.com/orders", Secret: "s3cret"}, fakeClient())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return asNotify{n}, "ops"
+	},
+}
+
+func fakeClient() *http.Client {
+	return &http.Client{Transport: &FakeTransport{}}
+}
+
+func TestNotificationConformance_Channels(t *testing.T) {
+	names := DefaultRegistry.Names()
+	for _, name := range names {
+		factory, ok := conformanceChannels[name]
+		if !ok {
+			t.Errorf("channel %q is registered but not checked; add it to conformanceChannels", name)
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			notifytest.TestNotificationConformance(t, factory)
+		})
+	}
+	for name := range conformanceChannels {
+		if !slices.Contains(names, name) {
+			t.Errorf("channel %q is checked but not registered", name)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package openclosed
+
+import (
+	"context"
+	"fmt"
+)
+
+func init() {
+	RegisterPaymentMethod("credit", CreditCard{})
+}
+
+type CreditCard struct{}
+
+func (CreditCard) Pay(ctx context.Context, amount float64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fmt.Println("Processing credit card payment of", amount)
+	return nil
+}
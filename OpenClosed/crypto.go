@@ -0,0 +1,29 @@
+package openclosed
+
+import (
+	"context"
+	"fmt"
+)
+
+func init() {
+	RegisterPaymentMethod("crypto", CryptoPayment{Network: "bitcoin", MinAmount: 50, NetworkFee: 2.5})
+}
+
+// CryptoPayment pays on a blockchain Network. Payments below MinAmount
+// are refused, since the flat NetworkFee would eat most of them.
+type CryptoPayment struct {
+	Network    string
+	MinAmount  float64
+	NetworkFee float64
+}
+
+func (c CryptoPayment) Pay(ctx context.Context, amount float64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if amount < c.MinAmount {
+		return fmt.Errorf("crypto: %w: %v is below the %v minimum", ErrInvalidAmount, amount, c.MinAmount)
+	}
+	fmt.Printf("Processing %s payment of %v (network fee %.2f)\n", c.Network, amount, c.NetworkFee)
+	return nil
+}
@@ -0,0 +1,93 @@
+package openclosed
+
+// DiscountRule is one promotion. Applies reports whether the order
+// qualifies and Apply returns how much the promotion takes off it. A
+// new promotion is a new rule; the engine and the other rules stay as
+// they are.
+type DiscountRule interface {
+	Applies(order Order) bool
+	Apply(order Order) Money
+}
+
+// DiscountEngine evaluates its rules in order and adds up the
+// discounts of those that apply.
+type DiscountEngine struct {
+	rules []DiscountRule
+}
+
+func NewDiscountEngine(rules ...DiscountRule) DiscountEngine {
+	return DiscountEngine{rules: rules}
+}
+
+// Discount is the total discount on order. It never exceeds the
+// order's subtotal: once the order is free, later rules are skipped.
+func (e DiscountEngine) Discount(order Order) Money {
+	subtotal := order.Subtotal()
+	var discount Money
+	for _, rule := range e.rules {
+		if discount >= subtotal {
+			break
+		}
+		if rule.Applies(order) {
+			discount += rule.Apply(order)
+		}
+	}
+	return min(discount, subtotal)
+}
+
+// PercentageOff takes Percent off orders whose subtotal is at least
+// MinSubtotal.
+type PercentageOff struct {
+	Percent     int
+	MinSubtotal Money
+}
+
+func (r PercentageOff) Applies(order Order) bool {
+	return order.Subtotal() >= r.MinSubtotal
+}
+
+func (r PercentageOff) Apply(order Order) Money {
+	return order.Subtotal() * Money(r.Percent) / 100
+}
+
+// BuyOneGetOne makes every second unit of SKU free.
+type BuyOneGetOne struct {
+	SKU string
+}
+
+func (r BuyOneGetOne) Applies(order Order) bool {
+	return r.quantity(order) >= 2
+}
+
+func (r BuyOneGetOne) Apply(order Order) Money {
+	var discount Money
+	for _, item := range order.Items {
+		if item.SKU == r.SKU {
+			discount += item.UnitPrice * Money(item.Quantity/2)
+		}
+	}
+	return discount
+}
+
+func (r BuyOneGetOne) quantity(order Order) int {
+	n := 0
+	for _, item := range order.Items {
+		if item.SKU == r.SKU {
+			n += item.Quantity
+		}
+	}
+	return n
+}
+
+// FirstPurchase takes a fixed Amount off a customer's first order.
+type FirstPurchase struct {
+	Amount Money
+}
+
+func (r FirstPurchase) Applies(order Order) bool {
+	return order.FirstPurchase
+}
+
+func (r FirstPurchase) Apply(Order) Money {
+	return r.Amount
+}
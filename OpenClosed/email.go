@@ -0,0 +1,24 @@
+package openclosed
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+)
+
+func init() {
+	Register("email", func(Settings) (Notification, error) { return EmailService{}, nil })
+}
+
+type EmailService struct{}
+
+func (e EmailService) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	if _, err := mail.ParseAddress(msg.To); err != nil {
+		return fmt.Errorf("email: %w: %q", ErrInvalidRecipient, msg.To)
+	}
+	fmt.Printf("Sending email to %s: %s\n", msg.To, msg.Subject)
+	return nil
+}
@@ -0,0 +1,63 @@
+package openclosed
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrDuplicateExporter = errors.New("exporter already registered")
+	ErrUnknownFormat     = errors.New("unknown export format")
+)
+
+// Record is one row of an export.
+type Record struct {
+	ID       string `json:"id" xml:"id"`
+	Customer string `json:"customer" xml:"customer"`
+	Status   string `json:"status" xml:"status"`
+	Total    Money  `json:"total" xml:"total"`
+}
+
+// Exporter writes records in one file format. A new format is a new
+// Exporter that registers itself for its file extension.
+type Exporter interface {
+	Export(w io.Writer, records []Record) error
+}
+
+var exporters = struct {
+	sync.RWMutex
+	byExt map[string]Exporter
+}{byExt: make(map[string]Exporter)}
+
+// RegisterExporter makes exporter the one for files ending in ext,
+// such as ".csv". Like Register, it is meant to be called from init
+// and panics on a duplicate extension.
+func RegisterExporter(ext string, exporter Exporter) {
+	ext = strings.ToLower(ext)
+
+	exporters.Lock()
+	defer exporters.Unlock()
+
+	if _, ok := exporters.byExt[ext]; ok {
+		panic(fmt.Errorf("%w: %q", ErrDuplicateExporter, ext))
+	}
+	exporters.byExt[ext] = exporter
+}
+
+// ExporterFor returns the exporter for filename's extension.
+func ExporterFor(filename string) (Exporter, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+
+	exporters.RLock()
+	defer exporters.RUnlock()
+
+	exporter, ok := exporters.byExt[ext]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, filename)
+	}
+	return exporter, nil
+}
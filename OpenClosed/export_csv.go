@@ -0,0 +1,23 @@
+package openclosed
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+func init() {
+	RegisterExporter(".csv", CSVExporter{})
+}
+
+// CSVExporter writes a header row followed by one row per record.
+type CSVExporter struct{}
+
+func (CSVExporter) Export(w io.Writer, records []Record) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"id", "customer", "status", "total"})
+	for _, r := range records {
+		cw.Write([]string{r.ID, r.Customer, r.Status, r.Total.String()})
+	}
+	cw.Flush()
+	return cw.Error()
+}
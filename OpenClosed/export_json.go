@@ -0,0 +1,23 @@
+package openclosed
+
+import (
+	"encoding/json"
+	"io"
+)
+
+func init() {
+	RegisterExporter(".json", JSONExporter{})
+}
+
+// JSONExporter writes the records as an indented JSON array.
+type JSONExporter struct{}
+
+func (JSONExporter) Export(w io.Writer, records []Record) error {
+	if records == nil {
+		records = []Record{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
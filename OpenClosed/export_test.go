@@ -0,0 +1,84 @@
+package openclosed
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// golden compares got with testdata/name, or rewrites the file with
+// -update:
+//
+//	go test ./OpenClosed -run TestExporters -update
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs:\n got:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// exportRecords need quoting or escaping in every format.
+var exportRecords = []Record{
+	{ID: "1001", Customer: "Ada Lovelace", Status: "shipped", Total: 5400},
+	{ID: "1002", Customer: `Smith, "Bob" & Co <b>`, Status: "pending", Total: 1999},
+}
+
+func TestExporters(t *testing.T) {
+	for _, name := range []string{"records.csv", "records.json", "records.xml"} {
+		t.Run(name, func(t *testing.T) {
+			exporter, err := ExporterFor(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := exporter.Export(&buf, exportRecords); err != nil {
+				t.Fatal(err)
+			}
+			golden(t, name, buf.Bytes())
+		})
+	}
+}
+
+func TestExporterFor(t *testing.T) {
+	if exporter, err := ExporterFor("Q3/REPORT.CSV"); err != nil || exporter != (CSVExporter{}) {
+		t.Errorf("REPORT.CSV: %T, %v", exporter, err)
+	}
+	for _, name := range []string{"report.pdf", "report"} {
+		if _, err := ExporterFor(name); !errors.Is(err, ErrUnknownFormat) {
+			t.Errorf("%s: %v, want ErrUnknownFormat", name, err)
+		}
+	}
+}
+
+func TestRegisterExporter_PanicsOnDuplicate(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, ErrDuplicateExporter) {
+			t.Errorf("recovered %v, want ErrDuplicateExporter", err)
+		}
+	}()
+	RegisterExporter(".JSON", JSONExporter{})
+}
+
+func TestJSONExporter_NoRecords(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (JSONExporter{}).Export(&buf, nil); err != nil || buf.String() != "[]\n" {
+		t.Errorf("Export(nil) = %q, %v", buf.String(), err)
+	}
+}
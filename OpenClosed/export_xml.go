@@ -0,0 +1,32 @@
+package openclosed
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+func init() {
+	RegisterExporter(".xml", XMLExporter{})
+}
+
+// XMLExporter writes the records as <record> elements of a <records>
+// document.
+type XMLExporter struct{}
+
+func (XMLExporter) Export(w io.Writer, records []Record) error {
+	doc := struct {
+		XMLName xml.Name `xml:"records"`
+		Records []Record `xml:"record"`
+	}{Records: records}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,54 @@
+package openclosed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// FallbackNotifier is a Notification that wraps a primary and a
+// secondary channel and uses the secondary only when the primary
+// fails. Neither channel knows about the other.
+type FallbackNotifier struct {
+	primary   Notification
+	secondary Notification
+	retryOn   []error
+}
+
+// NewFallbackNotifier returns a notifier that falls back to secondary
+// when primary fails with one of retryOn, matched with errors.Is. With
+// no retryOn, every error falls back.
+func NewFallbackNotifier(primary, secondary Notification, retryOn ...error) FallbackNotifier {
+	return FallbackNotifier{primary: primary, secondary: secondary, retryOn: retryOn}
+}
+
+// Send sends msg through the primary channel and, if that fails with a
+// retry-worthy error, through the secondary. When both fail, their
+// errors are joined.
+func (f FallbackNotifier) Send(ctx context.Context, msg Message) error {
+	err := f.primary.Send(ctx, msg)
+	if err == nil || !f.retryable(ctx, err) {
+		return err
+	}
+	if err2 := f.secondary.Send(ctx, msg); err2 != nil {
+		return errors.Join(err, fmt.Errorf("fallback: %w", err2))
+	}
+	return nil
+}
+
+// retryable reports whether err is worth trying the secondary for.
+// Once ctx is done, nothing is.
+func (f FallbackNotifier) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if len(f.retryOn) == 0 {
+		return true
+	}
+	for _, target := range f.retryOn {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package openclosed
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/flags"
+)
+
+// recordingChannel counts the messages it was asked to send.
+type recordingChannel struct{ sent *int }
+
+func (c recordingChannel) Send(ctx context.Context, msg Message) error {
+	*c.sent++
+	return nil
+}
+
+func TestGate(t *testing.T) {
+	var sent int
+	p := flags.Static{"channel.slack": "false"}
+	n := Gate(p, "channel.slack")(recordingChannel{&sent})
+
+	err := n.Send(context.Background(), Message{})
+	if !errors.Is(err, ErrChannelDisabled) || sent != 0 {
+		t.Fatalf("disabled: err = %v, sent = %d", err, sent)
+	}
+
+	p["channel.slack"] = "true"
+	if err := n.Send(context.Background(), Message{}); err != nil || sent != 1 {
+		t.Fatalf("enabled: err = %v, sent = %d", err, sent)
+	}
+}
+
+func TestBuildNotifier_FlaggedChannels(t *testing.T) {
+	var smsSent, emailSent int
+	reg := NewRegistry()
+	reg.Register("sms", func(Settings) (Notification, error) { return recordingChannel{&smsSent}, nil })
+	reg.Register("email", func(Settings) (Notification, error) { return recordingChannel{&emailSent}, nil })
+	p := flags.Static{"channel.sms": "false"}
+
+	for _, mode := range []string{ModeAll, ModeFallback} {
+		t.Run(mode, func(t *testing.T) {
+			smsSent, emailSent = 0, 0
+			cfg, err := LoadNotifierConfig(strings.NewReader(`{"mode": "` + mode + `", "channels": [
+				{"name": "sms", "flag": "channel.sms"},
+				{"name": "email"}
+			]}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			n, err := BuildNotifier(reg, p, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := n.Send(context.Background(), Message{To: "a@example.com"}); err != nil {
+				t.Fatalf("a disabled channel failed the send: %v", err)
+			}
+			if smsSent != 0 || emailSent != 1 {
+				t.Errorf("sms sent %d, email sent %d; want 0 and 1", smsSent, emailSent)
+			}
+		})
+	}
+}
+
+func TestDeliveryReport_SkippedIsNotFailed(t *testing.T) {
+	report := DeliveryReport{Results: []DeliveryResult{
+		{Channel: "sms", Err: ErrChannelDisabled},
+		{Channel: "email"},
+	}}
+	if report.Err() != nil || len(report.Failed()) != 0 {
+		t.Errorf("Err = %v, Failed = %v", report.Err(), report.Failed())
+	}
+	if !strings.Contains(report.String(), "skipped (disabled)") {
+		t.Errorf("report:\n%s", report)
+	}
+}
+
+func TestPaymentProcessor_WithFlags(t *testing.T) {
+	var paid float64
+	methods := NewPaymentMethods()
+	methods.Register("crypto", PaymentMethodFunc(func(ctx context.Context, amount float64) error {
+		paid += amount
+		return nil
+	}))
+	p := flags.Static{"payment.crypto": "false"}
+	processor := NewPaymentProcessor(methods).WithFlags(p)
+
+	if err := processor.ProcessPayment(context.Background(), "crypto", 10); !errors.Is(err, ErrPaymentMethodDisabled) {
+		t.Fatalf("err = %v, want ErrPaymentMethodDisabled", err)
+	}
+	p["payment.crypto"] = "true"
+	if err := processor.ProcessPayment(context.Background(), "crypto", 10); err != nil || paid != 10 {
+		t.Fatalf("err = %v, paid = %v", err, paid)
+	}
+}
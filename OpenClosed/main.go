@@ -12,19 +12,61 @@
 //
 // In this example:
 //
-// - Notification is an interface.
-// - EmailService and SmsService implement Notification.
-// - SendNotification depends on the interface, NOT concrete types.
+// - Notification is an interface: Send(ctx, Message) error.
+// - EmailService, SmsService, SlackService, PushNotificationService
+//   and WebhookService implement Notification, each in its own file,
+//   and register themselves in the channel Registry.
+// - The HTTP channels take an *http.Client, so a FakeTransport can
+//   stand in for Slack, the push provider or the webhook receiver.
+// - SendNotification looks channels up by name, NOT by concrete type.
+// - MultiNotifier is a Notification that fans a message out to other
+//   Notifications, so combining channels needs no new caller code.
+// - FallbackNotifier wraps a primary and a secondary Notification and
+//   uses the secondary only when the primary fails.
+// - RateLimitedNotifier puts a ratelimit.Limiter, a token bucket or a
+//   sliding window, in front of any channel.
+// - Message bodies are rendered from typed Events by text/templates
+//   registered per channel and event; a channel without its own
+//   template uses the shared one.
+// - PaymentProcessor, the fixed bad example below, dispatches to
+//   PaymentMethods looked up by name instead of an if/else chain.
+// - DiscountEngine adds up the DiscountRules that apply to an order;
+//   each promotion is its own rule.
+// - PriceCalculator prices each product with its PricingStrategy:
+//   flat, tiered or a prorated subscription.
+// - ShippingResolver picks a ShippingCalculator per destination:
+//   flat rate, weight-based or zone-based.
+// - Exporters for CSV, JSON and XML register themselves by file
+//   extension, and ExporterFor picks one from a file name.
+// - Middleware (logging, metrics, deduplication, retry, timeout,
+//   circuit breaker, tracing, recover, analytics) wraps any channel,
+//   and Chain composes middlewares in declared order. Generic adapts
+//   the request-agnostic middlewares of pkg/middleware to channels.
+// - PriorityDispatcher hands each message to the DeliveryPolicy of its
+//   Priority, such as Immediate or a QueuedDelivery.
+// - AsyncDispatcher delivers through any channel from a worker pool;
+//   NewAsyncDispatcher makes callers wait when its queue is full.
+// - Deliver and MultiNotifier.Deliver return a DeliveryResult per
+//   channel: its duration, attempts and final error.
+// - BuildNotifier assembles the active channels from a NotifierConfig,
+//   so swapping a provider is a configuration change. Feature flags
+//   switch channels and payment methods on and off at runtime.
+// - plugins takes the registry one step further: each channel is its
+//   own package, and plugins/cmd/basic and plugins/cmd/full choose
+//   theirs with blank imports alone. notifytest checks any channel,
+//   these and the plugins, against the Notification contract.
 //
 // Why this follows OCP:
 //
-// If we want to add a new notification type (e.g., PushNotification),
-// we DO NOT modify:
+// Slack, push and webhook were added later, and to add them
+// we DID NOT modify:
 //
 //   - Notification interface
 //   - SendNotification function
+//   - Registry
 //
-// We only create a new struct that implements Send().
+// We only add a file with a struct that implements Send(ctx, msg)
+// and registers itself from init().
 //
 // This makes the system:
 //
@@ -49,66 +91,496 @@
 // =============
 // BAD EXAMPLE - Violates Open / Closed Principle (OCP)
 // ===============
-// // PaymentProcessor handles different payment types
-// type PaymentProcessor struct{}
-
-// // This function violates OCP
-// func (p PaymentProcessor) ProcessPayment(method string, amount float64) {
-// 	if method == "credit" {
-// 		fmt.Println("Processing credit card payment of", amount)
-// 	} else if method == "paypal" {
-// 		fmt.Println("Processing PayPal payment of", amount)
-// 	} else if method == "upi" {
-// 		fmt.Println("Processing UPI payment of", amount)
-// 	} else {
-// 		fmt.Println("Unsupported payment method")
-// 	}
-// }
-
-// func main() {
-// 	processor := PaymentProcessor{}
-
-// 	processor.ProcessPayment("credit", 1000)
-// 	processor.ProcessPayment("paypal", 2000)
-// }
+//
+// The PaymentProcessor with an if/else on method names lives in
+// badocp/main.go as a program of its own, so it compiles and runs next
+// to this one:
+//
+//	go run badocp/main.go
+//
+// The fixed version is in payment.go: each branch became a
+// PaymentMethod in its own file, and PaymentProcessor dispatches
+// through a registry of them.
 
 // ======== PERFECT EXAMPLE ==========
 
-package main
+package openclosed
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/notify"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/analytics"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/breaker"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/flags"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/ratelimit"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/tracing"
+)
+
+// ErrInvalidRecipient is shared with the plugins, so one conformance
+// suite, notifytest, checks the channels of both.
+var ErrInvalidRecipient = notify.ErrInvalidRecipient
+
+// Message is what a Notification delivers. Metadata carries
+// channel-specific extras, such as a Slack channel or a push badge
+// count, without widening the interface.
+type Message struct {
+	To       string
+	Subject  string
+	Body     string
+	Priority Priority
+	Metadata map[string]string
+}
+
+// Priority says how urgent a message is. The zero value is
+// PriorityNormal.
+type Priority int
 
-import "fmt"
+const (
+	PriorityLow    Priority = -1
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 1
+)
 
+// Notification is a delivery channel. Every implementation, shipped or
+// not, keeps the same contract so callers can treat them alike:
+//
+//   - Send returns ctx's error, possibly wrapped, once ctx is done.
+//   - A channel that addresses recipients rejects a Message.To it cannot
+//     use with an error wrapping ErrInvalidRecipient.
+//   - Errors are prefixed with the channel, e.g. "sms: ...", and wrap
+//     their cause so errors.Is still sees it.
 type Notification interface {
-	Send()
+	Send(ctx context.Context, msg Message) error
 }
 
-type EmailService struct{}
+// SendNotification delivers msg through the channel registered as
+// channel. It never names a concrete channel, so it does not change
+// when one is added.
+func SendNotification(ctx context.Context, channel string, msg Message) error {
+	return Deliver(ctx, channel, msg).Err
+}
 
-func (e EmailService) Send() {
-	fmt.Println("Sending email...")
+// Deliver is SendNotification, reporting how the delivery went.
+func Deliver(ctx context.Context, channel string, msg Message) DeliveryResult {
+	n, err := DefaultRegistry.Open(channel)
+	if err != nil {
+		return DeliveryResult{Channel: channel, Err: err}
+	}
+	return deliver(ctx, channel, n, msg)
 }
 
-type SmsService struct{}
+// SendEvent renders event with the channel's template from
+// DefaultTemplates and sends it to to through channel.
+func SendEvent(ctx context.Context, channel, to string, event Event) error {
+	msg, err := DefaultTemplates.Render(channel, event)
+	if err != nil {
+		return err
+	}
+	msg.To = to
+	return SendNotification(ctx, channel, msg)
+}
 
-func (s SmsService) Send() {
-	fmt.Println("Sending SMS...")
+// TracedNotification is itself a Notification that wraps another one
+// in a span. Tracing is added by extension: neither the channels nor
+// SendNotification had to change.
+type TracedNotification struct {
+	next   Notification
+	tracer tracing.Tracer
 }
 
-func SendNotification(n Notification) {
-	n.Send()
+func NewTracedNotification(next Notification, tracer tracing.Tracer) TracedNotification {
+	return TracedNotification{next: next, tracer: tracer}
 }
 
-func main() {
-	email := EmailService{}
-	sms := SmsService{}
+func (t TracedNotification) Send(ctx context.Context, msg Message) error {
+	ctx, span := t.tracer.StartSpan(ctx, fmt.Sprintf("%T.Send", t.next))
+	defer span.End()
+
+	return t.next.Send(ctx, msg)
+}
 
-	SendNotification(email)
-	SendNotification(sms)
+// Run walks through the demos of the chapter, printing what each one
+// sends; cmd/demo runs it.
+func Run(ctx context.Context) {
+	demoChannels(ctx)
+	demoHTTPChannels(ctx)
+	demoComposition(ctx)
+	demoRateLimit(ctx)
+	demoPayments(ctx)
+	demoPricing()
+	demoExport()
+	demoMiddleware(ctx)
+	demoDelivery(ctx)
+	demoReport(ctx)
+	demoConfig(ctx)
 }
 
-// Future case
+// shippedMessage is the message most demos send.
+var shippedMessage = Message{To: "customer@example.com", Subject: "Order 1042 shipped", Body: "DHL JD0146000033"}
+
+// fakeHTTPClient answers every request with status through a
+// FakeTransport, or with 200 when status is zero.
+func fakeHTTPClient(status int) (*http.Client, *FakeTransport) {
+	transport := &FakeTransport{Status: status}
+	return &http.Client{Transport: transport}, transport
+}
+
+// demoChannels sends through channels looked up by name.
+func demoChannels(ctx context.Context) {
+	fmt.Println("Channels:", DefaultRegistry.Names())
+
+	shipped := OrderShipped{OrderID: "1042", Carrier: "DHL", TrackingNumber: "JD0146000033"}
+	for _, r := range []struct{ channel, to string }{
+		{"email", "customer@example.com"},
+		{"sms", "+4915112345678"},
+	} {
+		if err := SendEvent(ctx, r.channel, r.to, shipped); err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+
+	msg := Message{Subject: "Order shipped", Body: "Your order is on its way."}
+
+	msg.To = "customer@example.com"
+	if err := SendNotification(ctx, "email", msg); err != nil {
+		fmt.Println("error:", err)
+	}
+	msg.To = "+4915112345678"
+	if err := SendNotification(ctx, "sms", msg); err != nil {
+		fmt.Println("error:", err)
+	}
+	msg.To = "not-a-number"
+	if err := SendNotification(ctx, "sms", msg); err != nil {
+		fmt.Println("error:", err)
+	}
+	if err := SendNotification(ctx, "pigeon", msg); err != nil {
+		fmt.Println("error:", err)
+	}
+
+	// A channel taken from the registry can still be decorated.
+	email, err := DefaultRegistry.Open("email")
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	msg.To = "customer@example.com"
+	if err := NewTracedNotification(email, tracing.Console{}).Send(ctx, msg); err != nil {
+		fmt.Println("error:", err)
+	}
+}
+
+// demoHTTPChannels sends through the HTTP channels, talking to a fake
+// transport instead of the network.
+func demoHTTPChannels(ctx context.Context) {
+	client, transport := fakeHTTPClient(0)
+	slack, _ := NewSlackService(SlackConfig{WebhookURL: "https://hooks.slack.example/T000/B000", Channel: "#orders"}, client)
+	push, _ := NewPushNotificationService(PushConfig{Endpoint: "https://push.example/v1/send", APIKey: "key"}, client)
+	webhook, _ := NewWebhookService(WebhookConfig{URL: "https://shop.example/hooks/orders", Secret: "s3cret"}, client)
+
+	msg := Message{Subject: "Order shipped", Body: "Your order is on its way.", Metadata: map[string]string{"badge": "1"}}
+	for _, send := range []struct {
+		n  Notification
+		to string
+	}{{slack, ""}, {push, "device-token-123"}, {webhook, "orders@shop.example"}} {
+		msg.To = send.to
+		if err := send.n.Send(ctx, msg); err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+	for _, req := range transport.Requests() {
+		fmt.Printf("POST %s %s\n", req.URL, req.Body)
+	}
+}
+
+// demoComposition combines channels into one Notification: all at
+// once, one after the other, and behind a circuit breaker.
+func demoComposition(ctx context.Context) {
+	email, _ := DefaultRegistry.Open("email")
+	sms, _ := DefaultRegistry.Open("sms")
+	client, _ := fakeHTTPClient(0)
+	slack, _ := NewSlackService(SlackConfig{WebhookURL: "https://hooks.slack.example/T000/B000", Channel: "#orders"}, client)
+
+	everyone := NewMultiNotifier(email, sms, slack)
+	msg := Message{To: "customer@example.com", Subject: "Order delivered", Body: "Enjoy!"}
+	if err := everyone.Send(ctx, msg); err != nil {
+		fmt.Println("error:", err)
+	}
 
-// type SlackService struct{}
-// func (s SlackService) Send(){
-// 	fmt.Println("sending slack notification...")
-// }
+	// Slack is down, so the message falls back to email. An invalid
+	// recipient is not worth a second channel and is not retried.
+	down, _ := fakeHTTPClient(http.StatusServiceUnavailable)
+	flakySlack, _ := NewSlackService(SlackConfig{WebhookURL: "https://hooks.slack.example/T000/B000"}, down)
+	if err := NewFallbackNotifier(flakySlack, email, ErrDeliveryFailed).Send(ctx, msg); err != nil {
+		fmt.Println("error:", err)
+	}
+	msg.To = "not-a-number"
+	if err := NewFallbackNotifier(sms, email, ErrDeliveryFailed).Send(ctx, msg); err != nil {
+		fmt.Println("error:", err)
+	}
+
+	// After two failures in a row the breaker stops trying Slack, and
+	// the third message goes straight to email.
+	slackBreaker := breaker.New(breaker.Settings{
+		Name:             "slack",
+		FailureThreshold: 2,
+		OnStateChange: func(name string, from, to breaker.State) {
+			fmt.Printf("%s circuit breaker %s -> %s\n", name, from, to)
+		},
+	})
+	guarded := NewFallbackNotifier(Chain(flakySlack, CircuitBreaker(slackBreaker)), email, ErrDeliveryFailed)
+	msg.To = "customer@example.com"
+	for range 3 {
+		if err := guarded.Send(ctx, msg); err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+}
+
+// demoRateLimit allows at most two emails at once, then one every
+// 100ms. A sliding window would instead allow two in any 100ms; the
+// channel is the same.
+func demoRateLimit(ctx context.Context) {
+	email, _ := DefaultRegistry.Open("email")
+	limited := NewRateLimitedNotifier(email, ratelimit.NewTokenBucket(100*time.Millisecond, 2, nil))
+	msg := Message{To: "customer@example.com", Subject: "Order delivered", Body: "Enjoy!"}
+	for i := range 4 {
+		if i == 3 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		if err := limited.Send(ctx, msg); err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+}
+
+// demoPayments pays through the methods that registered themselves,
+// and through a registry of its own.
+func demoPayments(ctx context.Context) {
+	processor := NewPaymentProcessor(nil)
+	fmt.Println("Payment methods:", DefaultPaymentMethods.Names())
+	for _, method := range []string{"credit", "paypal", "upi", "wallet", "crypto", "cash"} {
+		if err := processor.ProcessPayment(ctx, method, 1000); err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+	if err := processor.ProcessPayment(ctx, "wallet", 5000); err != nil {
+		fmt.Println("error:", err)
+	}
+	// A feature flag takes a method out of service without a deploy.
+	if err := processor.WithFlags(flags.Static{"payment.crypto": "false"}).ProcessPayment(ctx, "crypto", 1000); err != nil {
+		fmt.Println("error:", err)
+	}
+
+	// Methods can also be registered at runtime, on a registry of
+	// their own.
+	methods := NewPaymentMethods()
+	methods.Register("eth", CryptoPayment{Network: "ethereum", MinAmount: 10, NetworkFee: 0.8})
+	if err := NewPaymentProcessor(methods).ProcessPayment(ctx, "eth", 5); err != nil {
+		fmt.Println("error:", err)
+	}
+}
+
+// demoPricing applies the discounts, pricing strategies and shipping
+// calculators.
+func demoPricing() {
+	order := Order{
+		CustomerID: 7,
+		Items: []LineItem{
+			{SKU: "TSHIRT", Quantity: 3, UnitPrice: 1500},
+			{SKU: "MUG", Quantity: 1, UnitPrice: 900},
+		},
+		FirstPurchase: true,
+	}
+	promotions := NewDiscountEngine(
+		BuyOneGetOne{SKU: "TSHIRT"},
+		PercentageOff{Percent: 10, MinSubtotal: 5000},
+		FirstPurchase{Amount: 500},
+	)
+	fmt.Printf("Subtotal %s, discount %s\n", order.Subtotal(), promotions.Discount(order))
+
+	calculator := NewPriceCalculator(map[string]PricingStrategy{
+		"MUG":     FlatPrice{UnitPrice: 900},
+		"STICKER": TieredPrice{Tiers: []Tier{{UpTo: 10, UnitPrice: 100}, {UpTo: 100, UnitPrice: 80}, {UnitPrice: 50}}},
+		"PRO":     SubscriptionProration{PeriodPrice: 3000, DaysInPeriod: 30, DaysRemaining: 12},
+	})
+	cart := Order{Items: []LineItem{{SKU: "MUG", Quantity: 2}, {SKU: "STICKER", Quantity: 25}, {SKU: "PRO", Quantity: 3}}}
+	if subtotal, err := calculator.Subtotal(cart); err != nil {
+		fmt.Println("error:", err)
+	} else {
+		fmt.Println("Cart subtotal:", subtotal)
+	}
+
+	shipping := NewShippingResolver(map[string]ShippingCalculator{
+		"DE": FlatRate{Rate: 490},
+		"US": WeightBased{Base: 1500, PerKg: 400},
+	}, ZoneBased{
+		Zones: map[string]string{"FR": "eu", "NL": "eu", "JP": "asia"},
+		Rates: map[string]Money{"eu": 990, "asia": 2490},
+	})
+	for _, country := range []string{"DE", "US", "FR", "JP", "BR"} {
+		cost, err := shipping.Cost(Shipment{Country: country, WeightGrams: 2300})
+		if err != nil {
+			fmt.Println("error:", err)
+			continue
+		}
+		fmt.Printf("Shipping to %s: %s\n", country, cost)
+	}
+}
+
+// demoExport writes the same records in every registered format.
+func demoExport() {
+	records := []Record{
+		{ID: "1042", Customer: "Ada & Co", Status: "shipped", Total: 5400},
+		{ID: "1043", Customer: "Grace, Ltd", Status: "paid", Total: 7600},
+	}
+	for _, file := range []string{"orders.csv", "orders.json", "orders.xml", "orders.pdf"} {
+		exporter, err := ExporterFor(file)
+		if err != nil {
+			fmt.Println("error:", err)
+			continue
+		}
+		fmt.Println("==>", file)
+		if err := exporter.Export(os.Stdout, records); err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+}
+
+// demoMiddleware wraps a channel in middleware.
+func demoMiddleware(ctx context.Context) {
+	email, _ := DefaultRegistry.Open("email")
+
+	// Middleware composes cross-cutting behaviour around a channel.
+	// The channel fails once and Retry sends again; the second, identical
+	// message is dropped before it is logged or counted.
+	attempts := 0
+	flakyEmail := NotificationFunc(func(ctx context.Context, msg Message) error {
+		if attempts++; attempts == 1 {
+			return ErrDeliveryFailed
+		}
+		return email.Send(ctx, msg)
+	})
+	metrics := &Metrics{}
+	funnel := analytics.NewMemory(10, nil)
+	notifier := Chain(flakyEmail,
+		Deduplicate(time.Hour, nil),
+		Logging(os.Stdout),
+		metrics.Middleware(),
+		Track(funnel),
+		Retry(3, 10*time.Millisecond, ErrDeliveryFailed),
+	)
+	for range 2 {
+		if err := notifier.Send(ctx, shippedMessage); err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+	sent, failed := metrics.Counts()
+	fmt.Printf("sent %d, failed %d, attempts %d, tracked %d\n", sent, failed, attempts, funnel.Total())
+}
+
+// demoDelivery sends by priority, and through a worker pool.
+func demoDelivery(ctx context.Context) {
+	email, _ := DefaultRegistry.Open("email")
+
+	// High-priority messages are sent before Send returns; low-priority
+	// ones wait in a queue and are sent in order in the background.
+	queue := NewQueuedDelivery(10, func(msg Message, err error) { fmt.Println("queued send failed:", err) })
+	dispatcher := NewPriorityDispatcher(email, map[Priority]DeliveryPolicy{
+		PriorityHigh: Immediate{},
+		PriorityLow:  queue,
+	})
+	for i, priority := range []Priority{PriorityLow, PriorityLow, PriorityHigh} {
+		msg := Message{To: "customer@example.com", Subject: fmt.Sprintf("Message %d (priority %d)", i+1, priority), Priority: priority}
+		if err := dispatcher.Send(ctx, msg); err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+	if err := queue.Shutdown(ctx); err != nil {
+		fmt.Println("error:", err)
+	}
+
+	// Two workers deliver through a slow channel. With room for two
+	// queued messages, the fifth Send waits and gives up at its
+	// deadline: backpressure instead of an unbounded queue.
+	slow := NotificationFunc(func(ctx context.Context, msg Message) error {
+		time.Sleep(50 * time.Millisecond)
+		return email.Send(ctx, msg)
+	})
+	async := NewAsyncDispatcher(slow, 2, 2, nil)
+	for i := range 5 {
+		sendCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		err := async.Send(sendCtx, Message{To: "customer@example.com", Subject: fmt.Sprintf("Async %d", i+1)})
+		cancel()
+		if err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+	if err := async.Shutdown(ctx); err != nil {
+		fmt.Println("error:", err)
+	}
+}
+
+// demoReport fans out and reports how each channel did.
+func demoReport(ctx context.Context) {
+	email, _ := DefaultRegistry.Open("email")
+	sms, _ := DefaultRegistry.Open("sms")
+
+	// The email fails once and is retried.
+	attempts := 0
+	flakyEmail := NotificationFunc(func(ctx context.Context, msg Message) error {
+		if attempts++; attempts == 1 {
+			return ErrDeliveryFailed
+		}
+		return email.Send(ctx, msg)
+	})
+	report := NewMultiNotifier(email, sms, Chain(flakyEmail, Retry(3, time.Millisecond))).
+		Deliver(ctx, Message{To: "customer@example.com", Subject: "Report"})
+	fmt.Print(report)
+	fmt.Println("failed channels:", len(report.Failed()))
+	if res := Deliver(ctx, "pigeon", shippedMessage); res.Err != nil {
+		fmt.Println("error:", res.Err)
+	}
+}
+
+// demoConfig builds the notifier from configuration.
+func demoConfig(ctx context.Context) {
+	// The active channels come from configuration; SMS is switched off
+	// by a feature flag. A partial config is rejected with every
+	// problem listed.
+	channelFlags := flags.Static{"channel.sms": "false"}
+	for _, config := range []string{
+		`{"mode": "fallback", "channels": [
+			{"name": "sms", "flag": "channel.sms"},
+			{"name": "slack", "disabled": true},
+			{"name": "email"}
+		]}`,
+		`{"mode": "all", "channels": [{"name": "slack"}, {"name": "fax"}]}`,
+	} {
+		cfg, err := LoadNotifierConfig(strings.NewReader(config))
+		if err == nil {
+			var n Notification
+			if n, err = BuildNotifier(DefaultRegistry, channelFlags, cfg); err == nil {
+				err = n.Send(ctx, Message{To: "customer@example.com", Subject: "Configured"})
+			}
+		}
+		if err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+
+	// A program that embeds the channels picks them with options
+	// instead.
+	n, err := NewNotifier(WithChannels("sms", "email"), WithFallback())
+	if err == nil {
+		err = n.Send(ctx, Message{To: "customer@example.com", Subject: "Embedded"})
+	}
+	if err != nil {
+		fmt.Println("error:", err)
+	}
+}
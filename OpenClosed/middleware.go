@@ -0,0 +1,303 @@
+package openclosed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/analytics"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/breaker"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/errreport"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/flags"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/middleware"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/tracing"
+)
+
+// NotificationFunc lets a plain function be used as a Notification.
+type NotificationFunc func(ctx context.Context, msg Message) error
+
+func (f NotificationFunc) Send(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+// Middleware wraps a Notification in another one that adds behaviour
+// around it. Cross-cutting concerns are written once as middleware and
+// composed around any channel, which stays unaware of them.
+type Middleware func(next Notification) Notification
+
+// Chain wraps n in middlewares. The first one is the outermost, so
+// Chain(n, a, b) runs a, then b, then n.
+func Chain(n Notification, middlewares ...Middleware) Notification {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		n = middlewares[i](n)
+	}
+	return n
+}
+
+// Logging writes every message sent and its outcome to w.
+func Logging(w io.Writer) Middleware {
+	return func(next Notification) Notification {
+		return NotificationFunc(func(ctx context.Context, msg Message) error {
+			err := next.Send(ctx, msg)
+			if err != nil {
+				fmt.Fprintf(w, "[log] %q to %s failed: %v\n", msg.Subject, msg.To, err)
+			} else {
+				fmt.Fprintf(w, "[log] %q sent to %s\n", msg.Subject, msg.To)
+			}
+			return err
+		})
+	}
+}
+
+// Metrics counts sent and failed messages. It is safe for concurrent
+// use.
+type Metrics struct {
+	mu     sync.Mutex
+	sent   int
+	failed int
+}
+
+// Counts returns how many messages were sent and how many failed.
+func (m *Metrics) Counts() (sent, failed int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sent, m.failed
+}
+
+// Middleware returns a middleware counting into m.
+func (m *Metrics) Middleware() Middleware {
+	return func(next Notification) Notification {
+		return NotificationFunc(func(ctx context.Context, msg Message) error {
+			err := next.Send(ctx, msg)
+
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if err != nil {
+				m.failed++
+			} else {
+				m.sent++
+			}
+			return err
+		})
+	}
+}
+
+// Deduplicate drops a message identical to one sent successfully
+// within window, returning nil without sending it again. clk may be
+// nil, in which case the system clock is used.
+func Deduplicate(window time.Duration, clk clock.Clock) Middleware {
+	clk = clock.OrSystem(clk)
+	var (
+		mu   sync.Mutex
+		seen = make(map[string]time.Time)
+	)
+	return func(next Notification) Notification {
+		return NotificationFunc(func(ctx context.Context, msg Message) error {
+			key := msg.To + "\x00" + msg.Subject + "\x00" + msg.Body
+			now := clk.Now()
+
+			mu.Lock()
+			for k, at := range seen {
+				if now.Sub(at) >= window {
+					delete(seen, k)
+				}
+			}
+			_, dup := seen[key]
+			mu.Unlock()
+			if dup {
+				return nil
+			}
+
+			if err := next.Send(ctx, msg); err != nil {
+				return err
+			}
+			mu.Lock()
+			seen[key] = now
+			mu.Unlock()
+			return nil
+		})
+	}
+}
+
+// Retry sends again, up to attempts times in all, when sending fails
+// with one of retryOn, matched with errors.Is. With no retryOn, every
+// error is retried. It waits backoff between attempts and gives up
+// when ctx is done.
+func Retry(attempts int, backoff time.Duration, retryOn ...error) Middleware {
+	retryable := func(err error) bool {
+		if len(retryOn) == 0 {
+			return true
+		}
+		for _, target := range retryOn {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+		return false
+	}
+	return func(next Notification) Notification {
+		return NotificationFunc(func(ctx context.Context, msg Message) error {
+			var err error
+			for attempt := 1; ; attempt++ {
+				countAttempt(ctx)
+				if err = next.Send(ctx, msg); err == nil || attempt >= attempts || !retryable(err) {
+					return err
+				}
+				select {
+				case <-ctx.Done():
+					return errors.Join(err, ctx.Err())
+				case <-time.After(backoff):
+				}
+			}
+		})
+	}
+}
+
+// Timeout fails a send that takes longer than d with a
+// *timeout.Error, which matches timeout.ErrDeadlineExceeded. clk may be
+// nil, in which case the system clock is used. The wrapped channel
+// keeps its name in delivery reports.
+func Timeout(d time.Duration, clk clock.Clock) Middleware {
+	return func(next Notification) Notification {
+		return Generic(middleware.Timeout[Message, struct{}](channelName(next)+".Send", d, clk))(next)
+	}
+}
+
+// Recover turns a panic in a channel into an *errreport.PanicError,
+// captured with r, which may be nil, and tagged with the channel's
+// name. The wrapped channel keeps its name in delivery reports.
+func Recover(r errreport.Reporter) Middleware {
+	return func(next Notification) Notification {
+		tags := map[string]string{"channel": channelName(next)}
+		return Generic(middleware.Recover[Message, struct{}](r, tags))(next)
+	}
+}
+
+// Generic adapts a middleware from pkg/middleware, written once for
+// any request type, to notification channels. The wrapped channel
+// keeps its name in delivery reports.
+func Generic(mw middleware.Middleware[Message, struct{}]) Middleware {
+	return func(next Notification) Notification {
+		h := mw(func(ctx context.Context, msg Message) (struct{}, error) {
+			return struct{}{}, next.Send(ctx, msg)
+		})
+		return genericNotification{next: next, handler: h}
+	}
+}
+
+type genericNotification struct {
+	next    Notification
+	handler middleware.Handler[Message, struct{}]
+}
+
+func (g genericNotification) Send(ctx context.Context, msg Message) error {
+	_, err := g.handler(ctx, msg)
+	return err
+}
+
+func (g genericNotification) Name() string {
+	return channelName(g.next)
+}
+
+// CircuitBreaker stops using a channel that keeps failing: while b is
+// open, sends fail without reaching the channel, with an error
+// matching both ErrDeliveryFailed and breaker.ErrOpen, so a
+// FallbackNotifier moves on to its secondary at once. Each channel
+// needs its own breaker. The wrapped channel keeps its name in
+// delivery reports.
+func CircuitBreaker(b *breaker.Breaker) Middleware {
+	return func(next Notification) Notification {
+		return breakerNotification{next: next, breaker: b}
+	}
+}
+
+type breakerNotification struct {
+	next    Notification
+	breaker *breaker.Breaker
+}
+
+func (n breakerNotification) Send(ctx context.Context, msg Message) error {
+	err := n.breaker.Do(ctx, func(ctx context.Context) error {
+		return n.next.Send(ctx, msg)
+	})
+	if errors.Is(err, breaker.ErrOpen) {
+		return fmt.Errorf("%w: %w", ErrDeliveryFailed, err)
+	}
+	return err
+}
+
+func (n breakerNotification) Name() string {
+	return channelName(n.next)
+}
+
+// Gate turns a channel on and off at runtime: while the flag named
+// flag is off in p, sends fail with ErrChannelDisabled without reaching
+// the channel. A flag p does not know is on. Delivery reports count a
+// disabled channel as skipped, not failed, and a FallbackNotifier moves
+// on to its secondary.
+func Gate(p flags.Provider, flag string) Middleware {
+	return func(next Notification) Notification {
+		return gatedNotification{next: next, flags: p, flag: flag}
+	}
+}
+
+type gatedNotification struct {
+	next  Notification
+	flags flags.Provider
+	flag  string
+}
+
+func (g gatedNotification) Send(ctx context.Context, msg Message) error {
+	if !flags.Enabled(g.flags, g.flag, true) {
+		return fmt.Errorf("%w: %s (flag %s)", ErrChannelDisabled, g.Name(), g.flag)
+	}
+	return g.next.Send(ctx, msg)
+}
+
+func (g gatedNotification) Name() string {
+	return channelName(g.next)
+}
+
+// Tracing wraps each channel in a TracedNotification.
+func Tracing(tracer tracing.Tracer) Middleware {
+	return func(next Notification) Notification {
+		return NewTracedNotification(next, tracer)
+	}
+}
+
+// Track records every send with t as "notification_sent" or
+// "notification_failed", with the channel's name and the message's
+// priority. The recipient is left out: analytics has no business with
+// it. The wrapped channel keeps its name in delivery reports.
+func Track(t analytics.Tracker) Middleware {
+	return func(next Notification) Notification {
+		return trackedNotification{next: next, tracker: t}
+	}
+}
+
+type trackedNotification struct {
+	next    Notification
+	tracker analytics.Tracker
+}
+
+func (n trackedNotification) Send(ctx context.Context, msg Message) error {
+	err := n.next.Send(ctx, msg)
+	event := "notification_sent"
+	if err != nil {
+		event = "notification_failed"
+	}
+	n.tracker.Track(ctx, event, map[string]string{
+		"channel":  n.Name(),
+		"priority": strconv.Itoa(int(msg.Priority)),
+	})
+	return err
+}
+
+func (n trackedNotification) Name() string {
+	return channelName(n.next)
+}
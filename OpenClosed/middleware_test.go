@@ -0,0 +1,37 @@
+package openclosed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/analytics"
+)
+
+func TestTrack(t *testing.T) {
+	tracker := analytics.NewMemory(10, nil)
+	fails := true
+	flaky := NotificationFunc(func(ctx context.Context, msg Message) error {
+		if fails {
+			fails = false
+			return ErrDeliveryFailed
+		}
+		return nil
+	})
+	n := Chain(SmsService{}, Track(tracker))
+	if channelName(n) != "SmsService" {
+		t.Errorf("name = %q, want the channel's", channelName(n))
+	}
+
+	n = Track(tracker)(flaky)
+	msg := Message{To: "+15550100", Priority: PriorityHigh}
+	n.Send(context.Background(), msg)
+	n.Send(context.Background(), msg)
+
+	events := tracker.Events()
+	if len(events) != 2 || events[0].Name != "notification_failed" || events[1].Name != "notification_sent" {
+		t.Fatalf("events = %+v", events)
+	}
+	if p := events[1].Props; p["priority"] != "1" || p["to"] != "" {
+		t.Errorf("props = %v", p)
+	}
+}
@@ -0,0 +1,40 @@
+package openclosed
+
+import (
+	"context"
+	"sync"
+)
+
+// MultiNotifier is a Notification made of other Notifications. Send
+// delivers the message through all of them at once, so combining
+// channels is one more implementation rather than a change to any of
+// them or to their callers.
+type MultiNotifier struct {
+	channels []Notification
+}
+
+func NewMultiNotifier(channels ...Notification) MultiNotifier {
+	return MultiNotifier{channels: channels}
+}
+
+// Send sends msg through every channel concurrently and waits for all
+// of them. It returns the channels' errors joined with errors.Join, or
+// nil if every channel succeeded.
+func (m MultiNotifier) Send(ctx context.Context, msg Message) error {
+	return m.Deliver(ctx, msg).Err()
+}
+
+// Deliver is Send, reporting the outcome of every channel.
+func (m MultiNotifier) Deliver(ctx context.Context, msg Message) DeliveryReport {
+	results := make([]DeliveryResult, len(m.channels))
+	var wg sync.WaitGroup
+	for i, n := range m.channels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = deliver(ctx, channelName(n), n, msg)
+		}()
+	}
+	wg.Wait()
+	return DeliveryReport{Results: results}
+}
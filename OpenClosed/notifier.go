@@ -0,0 +1,71 @@
+package openclosed
+
+import "github.com/anil-vinnakoti/go-SOLID/pkg/flags"
+
+// NotifierOption configures NewNotifier.
+type NotifierOption func(*notifierOptions)
+
+type notifierOptions struct {
+	registry    *Registry
+	flags       flags.Provider
+	config      NotifierConfig
+	middlewares []Middleware
+}
+
+// WithRegistry opens channels from reg instead of DefaultRegistry.
+func WithRegistry(reg *Registry) NotifierOption {
+	return func(o *notifierOptions) { o.registry = reg }
+}
+
+// WithChannels sends through the named channels, with their default
+// settings, instead of email alone.
+func WithChannels(names ...string) NotifierOption {
+	return func(o *notifierOptions) {
+		o.config.Channels = nil
+		for _, name := range names {
+			o.config.Channels = append(o.config.Channels, ChannelConfig{Name: name})
+		}
+	}
+}
+
+// WithConfig takes the mode and channels from cfg, as read by
+// LoadNotifierConfig.
+func WithConfig(cfg NotifierConfig) NotifierOption {
+	return func(o *notifierOptions) { o.config = cfg }
+}
+
+// WithFallback sends through the first channel that works instead of
+// through all of them.
+func WithFallback() NotifierOption {
+	return func(o *notifierOptions) { o.config.Mode = ModeFallback }
+}
+
+// WithFlags gates the channels that name a flag by p; see Gate.
+func WithFlags(p flags.Provider) NotifierOption {
+	return func(o *notifierOptions) { o.flags = p }
+}
+
+// WithMiddleware wraps the notifier in middlewares, the first one
+// outermost as in Chain. It adds to the middlewares of earlier options.
+func WithMiddleware(middlewares ...Middleware) NotifierOption {
+	return func(o *notifierOptions) { o.middlewares = append(o.middlewares, middlewares...) }
+}
+
+// NewNotifier returns a Notification for programs that embed the
+// channels rather than configure them from a file. By default it sends
+// through email from DefaultRegistry; opts change that. It fails as
+// BuildNotifier does.
+func NewNotifier(opts ...NotifierOption) (Notification, error) {
+	o := notifierOptions{
+		registry: DefaultRegistry,
+		config:   NotifierConfig{Mode: ModeAll, Channels: []ChannelConfig{{Name: "email"}}},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	n, err := BuildNotifier(o.registry, o.flags, o.config)
+	if err != nil {
+		return nil, err
+	}
+	return Chain(n, o.middlewares...), nil
+}
@@ -0,0 +1,73 @@
+package openclosed
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/flags"
+)
+
+func TestNewNotifier(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		sent map[string]int
+	)
+	reg := NewRegistry()
+	for _, name := range []string{"email", "sms", "down"} {
+		reg.Register(name, func(Settings) (Notification, error) {
+			return NotificationFunc(func(ctx context.Context, msg Message) error {
+				if name == "down" {
+					return ErrDeliveryFailed
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				sent[name]++
+				return nil
+			}), nil
+		})
+	}
+
+	var metrics Metrics
+	tests := []struct {
+		name string
+		opts []NotifierOption
+		want map[string]int
+	}{
+		{"defaults to email", nil, map[string]int{"email": 1}},
+		{"all channels", []NotifierOption{WithChannels("email", "sms")}, map[string]int{"email": 1, "sms": 1}},
+		{"fallback", []NotifierOption{WithChannels("down", "sms", "email"), WithFallback()}, map[string]int{"sms": 1}},
+		{"gated", []NotifierOption{
+			WithConfig(NotifierConfig{Channels: []ChannelConfig{{Name: "email"}, {Name: "sms", Flag: "channel.sms"}}}),
+			WithFlags(flags.Static{"channel.sms": "false"}),
+		}, map[string]int{"email": 1}},
+		{"middleware", []NotifierOption{WithMiddleware(metrics.Middleware())}, map[string]int{"email": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent = make(map[string]int)
+			n, err := NewNotifier(append(tt.opts, WithRegistry(reg))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := n.Send(context.Background(), Message{To: "customer@example.com"}); err != nil {
+				t.Errorf("Send = %v", err)
+			}
+			if len(sent) != len(tt.want) {
+				t.Errorf("sent %v, want %v", sent, tt.want)
+			}
+			for name, n := range tt.want {
+				if sent[name] != n {
+					t.Errorf("sent %v, want %v", sent, tt.want)
+				}
+			}
+		})
+	}
+	if got, _ := metrics.Counts(); got != 1 {
+		t.Errorf("middleware counted %d sends, want 1", got)
+	}
+
+	if _, err := NewNotifier(WithRegistry(reg), WithChannels("fax")); err == nil {
+		t.Error("NewNotifier with an unknown channel succeeded")
+	}
+}
@@ -0,0 +1,43 @@
+package openclosed
+
+import "fmt"
+
+// Money is an amount in cents.
+type Money int64
+
+func (m Money) String() string {
+	sign := ""
+	if m < 0 {
+		sign, m = "-", -m
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, m/100, m%100)
+}
+
+// MarshalText writes m as it prints, so exports show "54.00" rather
+// than cents.
+func (m Money) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// LineItem is one product on an order.
+type LineItem struct {
+	SKU       string
+	Quantity  int
+	UnitPrice Money
+}
+
+// Order is what the pricing examples price.
+type Order struct {
+	CustomerID    int
+	Items         []LineItem
+	FirstPurchase bool // the customer has no earlier orders
+}
+
+// Subtotal is the sum of the order's line amounts.
+func (o Order) Subtotal() Money {
+	var total Money
+	for _, item := range o.Items {
+		total += item.UnitPrice * Money(item.Quantity)
+	}
+	return total
+}
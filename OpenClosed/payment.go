@@ -0,0 +1,149 @@
+package openclosed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/flags"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/timeout"
+)
+
+var (
+	ErrInvalidAmount            = errors.New("invalid payment amount")
+	ErrDuplicatePaymentMethod   = errors.New("payment method already registered")
+	ErrUnsupportedPaymentMethod = errors.New("unsupported payment method")
+	ErrPaymentMethodDisabled    = errors.New("payment method disabled")
+)
+
+// PaymentMethod charges amount in one particular way. It is the
+// working counterpart of one branch of the if/else in the bad example.
+type PaymentMethod interface {
+	Pay(ctx context.Context, amount float64) error
+}
+
+// PaymentMethodFunc lets a plain function be used as a PaymentMethod.
+type PaymentMethodFunc func(ctx context.Context, amount float64) error
+
+func (f PaymentMethodFunc) Pay(ctx context.Context, amount float64) error {
+	return f(ctx, amount)
+}
+
+// WithPaymentTimeout wraps method so a payment taking longer than d
+// fails with a *timeout.Error. clk may be nil, in which case the
+// system clock is used.
+func WithPaymentTimeout(method PaymentMethod, d time.Duration, clk clock.Clock) PaymentMethod {
+	op := fmt.Sprintf("%T.Pay", method)
+	return PaymentMethodFunc(func(ctx context.Context, amount float64) error {
+		return timeout.Do(ctx, clk, op, d, func(ctx context.Context) error {
+			return method.Pay(ctx, amount)
+		})
+	})
+}
+
+// PaymentMethods maps method names to implementations. Like the
+// channel Registry, methods add themselves from init in their own
+// file. It is safe for concurrent use.
+type PaymentMethods struct {
+	mu      sync.RWMutex
+	methods map[string]PaymentMethod
+}
+
+func NewPaymentMethods() *PaymentMethods {
+	return &PaymentMethods{methods: make(map[string]PaymentMethod)}
+}
+
+// DefaultPaymentMethods holds the methods that register themselves.
+var DefaultPaymentMethods = NewPaymentMethods()
+
+// Register adds method under name. A name can be registered once.
+func (r *PaymentMethods) Register(name string, method PaymentMethod) error {
+	if name == "" || method == nil {
+		return fmt.Errorf("registering payment method %q: name and method are required", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.methods[name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicatePaymentMethod, name)
+	}
+	r.methods[name] = method
+	return nil
+}
+
+// Lookup returns the method registered as name.
+func (r *PaymentMethods) Lookup(name string) (PaymentMethod, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	method, ok := r.methods[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedPaymentMethod, name)
+	}
+	return method, nil
+}
+
+// Names returns the registered method names, sorted.
+func (r *PaymentMethods) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.methods))
+	for name := range r.methods {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// RegisterPaymentMethod adds a method to DefaultPaymentMethods. Like
+// Register, it is meant to be called from init and panics on error.
+func RegisterPaymentMethod(name string, method PaymentMethod) {
+	if err := DefaultPaymentMethods.Register(name, method); err != nil {
+		panic(err)
+	}
+}
+
+// PaymentProcessor is the bad example's processor without the if/else:
+// it looks the method up and lets it charge. Adding a method does not
+// touch it.
+type PaymentProcessor struct {
+	methods *PaymentMethods
+	flags   flags.Provider
+}
+
+// NewPaymentProcessor returns a processor dispatching to methods, or
+// to DefaultPaymentMethods if methods is nil.
+func NewPaymentProcessor(methods *PaymentMethods) PaymentProcessor {
+	if methods == nil {
+		methods = DefaultPaymentMethods
+	}
+	return PaymentProcessor{methods: methods}
+}
+
+// WithFlags returns a copy of the processor that refuses a method
+// while its flag "payment.<method>" is off in p, with
+// ErrPaymentMethodDisabled. Flags p does not know are on.
+func (p PaymentProcessor) WithFlags(fp flags.Provider) PaymentProcessor {
+	p.flags = fp
+	return p
+}
+
+func (p PaymentProcessor) ProcessPayment(ctx context.Context, method string, amount float64) error {
+	if amount <= 0 {
+		return fmt.Errorf("%w: %v", ErrInvalidAmount, amount)
+	}
+	if !flags.Enabled(p.flags, "payment."+method, true) {
+		return fmt.Errorf("%w: %q", ErrPaymentMethodDisabled, method)
+	}
+	m, err := p.methods.Lookup(method)
+	if err != nil {
+		return err
+	}
+	return m.Pay(ctx, amount)
+}
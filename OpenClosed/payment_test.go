@@ -0,0 +1,32 @@
+package openclosed
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// BenchmarkDispatch pays through a PaymentProcessor whose registry
+// holds n methods that do no work. Compare with BenchmarkDispatch in
+// badocp, which picks the n-th case of a string switch:
+//
+//	go test -run - -bench Dispatch ./OpenClosed ./OpenClosed/badocp
+func BenchmarkDispatch(b *testing.B) {
+	ctx := context.Background()
+	for _, n := range []int{1, 5, 10, 25, 50} {
+		methods := NewPaymentMethods()
+		for i := 1; i <= n; i++ {
+			methods.Register(fmt.Sprintf("m%02d", i), PaymentMethodFunc(func(context.Context, float64) error { return nil }))
+		}
+		processor := NewPaymentProcessor(methods)
+		method := fmt.Sprintf("m%02d", n)
+
+		b.Run(fmt.Sprintf("methods=%d", n), func(b *testing.B) {
+			for b.Loop() {
+				if err := processor.ProcessPayment(ctx, method, 1000); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
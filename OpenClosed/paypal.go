@@ -0,0 +1,20 @@
+package openclosed
+
+import (
+	"context"
+	"fmt"
+)
+
+func init() {
+	RegisterPaymentMethod("paypal", PayPal{})
+}
+
+type PayPal struct{}
+
+func (PayPal) Pay(ctx context.Context, amount float64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fmt.Println("Processing PayPal payment of", amount)
+	return nil
+}
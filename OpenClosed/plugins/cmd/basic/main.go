@@ -0,0 +1,16 @@
+// Command basic is built with the email and SMS channels and nothing
+// else. Compare cmd/full, which differs only in its imports.
+package main
+
+import (
+	"context"
+
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/cmd/internal/demo"
+
+	_ "github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/email"
+	_ "github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/sms"
+)
+
+func main() {
+	demo.Run(context.Background())
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/notify"
+)
+
+// This test binary links the imports of main, and with them exactly
+// these channels.
+func TestChannels(t *testing.T) {
+	if got := notify.Names(); !slices.Equal(got, []string{"email", "sms"}) {
+		t.Errorf("compiled-in channels %q", got)
+	}
+	if _, err := notify.Open("slack"); err == nil {
+		t.Error("slack is available without being imported")
+	}
+}
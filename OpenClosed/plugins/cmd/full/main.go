@@ -0,0 +1,17 @@
+// Command full is cmd/basic with Slack added: one more blank import,
+// and no other line of the example changes.
+package main
+
+import (
+	"context"
+
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/cmd/internal/demo"
+
+	_ "github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/email"
+	_ "github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/slack"
+	_ "github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/sms"
+)
+
+func main() {
+	demo.Run(context.Background())
+}
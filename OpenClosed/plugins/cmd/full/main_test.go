@@ -0,0 +1,19 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/notify"
+)
+
+// Compared with cmd/basic, the blank import of slack is the only
+// change, and it is enough to add the channel.
+func TestChannels(t *testing.T) {
+	if got := notify.Names(); !slices.Equal(got, []string{"email", "slack", "sms"}) {
+		t.Errorf("compiled-in channels %q", got)
+	}
+	if _, err := notify.Open("slack"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,31 @@
+// Package demo is the program both plugin commands run. It only knows
+// notify, so it sends through whichever channels were compiled in.
+package demo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/notify"
+)
+
+// Recipients maps the channels to an address they accept.
+var Recipients = map[string]string{
+	"email": "customer@example.com",
+	"sms":   "+4915112345678",
+}
+
+// Run lists the compiled-in channels and sends a message through each.
+func Run(ctx context.Context) {
+	fmt.Println("channels:", strings.Join(notify.Names(), ", "))
+	for _, name := range notify.Names() {
+		n, err := notify.Open(name)
+		if err == nil {
+			err = n.Send(ctx, notify.Message{To: Recipients[name], Subject: "Order 1042 shipped", Body: "DHL JD0146000033"})
+		}
+		if err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Package email registers the "email" channel with notify. Import it
+// for its side effect:
+//
+//	import _ "github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/email"
+package email
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/notify"
+)
+
+func init() {
+	notify.Register("email", func() (notify.Notification, error) { return Service{}, nil })
+}
+
+// Service prints the emails it sends.
+type Service struct{}
+
+func (Service) Send(ctx context.Context, msg notify.Message) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	if _, err := mail.ParseAddress(msg.To); err != nil {
+		return fmt.Errorf("email: %w: %q", notify.ErrInvalidRecipient, msg.To)
+	}
+	fmt.Printf("Sending email to %s: %s\n", msg.To, msg.Subject)
+	return nil
+}
@@ -0,0 +1,14 @@
+package email
+
+import (
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/notify"
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/notify/notifytest"
+)
+
+func TestConformance(t *testing.T) {
+	notifytest.TestNotificationConformance(t, func(t *testing.T) (notify.Notification, string) {
+		return Service{}, "customer@example.com"
+	})
+}
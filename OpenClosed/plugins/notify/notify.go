@@ -0,0 +1,97 @@
+// Package notify is the extension point of the plugin example: the
+// Notification contract and the registry channels add themselves to.
+// Each channel is its own package that calls Register from init, so a
+// program chooses its channels with blank imports alone:
+//
+//	import (
+//		_ "github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/email"
+//		_ "github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/sms"
+//	)
+//
+// Adding a channel is a new package and a new import; neither this
+// package nor the other channels change. It is database/sql's driver
+// pattern.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+var (
+	ErrDuplicateChannel = errors.New("notification channel already registered")
+	ErrUnknownChannel   = errors.New("unknown notification channel")
+	ErrInvalidRecipient = errors.New("invalid recipient")
+)
+
+// Message is what a Notification delivers.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notification is a delivery channel. Send returns ctx's error once ctx
+// is done, and prefixes its errors with the channel, e.g. "sms: ...".
+type Notification interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Factory builds a ready-to-use channel.
+type Factory func() (Notification, error)
+
+var registry = struct {
+	sync.RWMutex
+	factories map[string]Factory
+}{factories: make(map[string]Factory)}
+
+// Register makes factory available as name. It is meant to be called
+// from the init function of a channel package and panics on a
+// duplicate name, as two channels claiming one name is a programming
+// error.
+func Register(name string, factory Factory) {
+	if name == "" || factory == nil {
+		panic(fmt.Sprintf("notify: registering channel %q: name and factory are required", name))
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, ok := registry.factories[name]; ok {
+		panic(fmt.Errorf("notify: %w: %q", ErrDuplicateChannel, name))
+	}
+	registry.factories[name] = factory
+}
+
+// Open builds the channel registered as name.
+func Open(name string) (Notification, error) {
+	registry.RLock()
+	factory, ok := registry.factories[name]
+	registry.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("notify: %w: %q", ErrUnknownChannel, name)
+	}
+	n, err := factory()
+	if err != nil {
+		return nil, fmt.Errorf("notify: opening channel %q: %w", name, err)
+	}
+	return n, nil
+}
+
+// Names returns the registered channel names, sorted. They are the
+// channels the program was built with.
+func Names() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	names := make([]string, 0, len(registry.factories))
+	for name := range registry.factories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,97 @@
+// Package notifytest checks that a notify.Notification keeps the
+// contract every channel shares, so a channel written outside this
+// repository can verify itself the same way the shipped ones do:
+//
+//	func TestConformance(t *testing.T) {
+//		notifytest.TestNotificationConformance(t, func(t *testing.T) (notify.Notification, string) {
+//			return fax.Service{}, "+4930123456"
+//		})
+//	}
+package notifytest
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/notify"
+)
+
+// Factory returns a fresh channel and a recipient it accepts.
+type Factory func(t *testing.T) (n notify.Notification, to string)
+
+// prefixed matches an error naming its channel, such as "sms: ...".
+var prefixed = regexp.MustCompile(`^[a-z][a-z0-9-]*: `)
+
+// TestNotificationConformance runs the conformance checks against the
+// channels factory builds, each in a subtest:
+//
+//   - A message to the recipient is sent.
+//   - A zero Message does not panic; it is sent or rejected.
+//   - An empty recipient is rejected with an error wrapping
+//     notify.ErrInvalidRecipient, unless the channel does not address
+//     recipients and sends.
+//   - A cancelled context fails the send with its error, wrapped.
+//   - Every error starts with the channel's name.
+func TestNotificationConformance(t *testing.T, factory Factory) {
+	t.Helper()
+
+	t.Run("Send", func(t *testing.T) {
+		n, to := factory(t)
+		checkPrefix(t, send(t, n, context.Background(), notify.Message{To: to, Subject: "Conformance", Body: "body"}), true)
+	})
+
+	t.Run("ZeroMessage", func(t *testing.T) {
+		n, _ := factory(t)
+		checkPrefix(t, send(t, n, context.Background(), notify.Message{}), false)
+	})
+
+	t.Run("EmptyRecipient", func(t *testing.T) {
+		n, _ := factory(t)
+		err := send(t, n, context.Background(), notify.Message{Subject: "Conformance", Body: "body"})
+		if err != nil && !errors.Is(err, notify.ErrInvalidRecipient) {
+			t.Errorf("Send = %v, want nil or an error wrapping notify.ErrInvalidRecipient", err)
+		}
+		checkPrefix(t, err, false)
+	})
+
+	t.Run("CancelledContext", func(t *testing.T) {
+		n, to := factory(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := send(t, n, ctx, notify.Message{To: to, Subject: "Conformance", Body: "body"})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Send = %v, want an error wrapping context.Canceled", err)
+		}
+		if err == ctx.Err() {
+			t.Error("Send returned ctx.Err() as is; wrap it with the channel's name")
+		}
+		checkPrefix(t, err, false)
+	})
+}
+
+// send calls n.Send, failing the test instead of the test binary if
+// it panics.
+func send(t *testing.T, n notify.Notification, ctx context.Context, msg notify.Message) (err error) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("Send(%+v) panicked: %v", msg, r)
+		}
+	}()
+	return n.Send(ctx, msg)
+}
+
+// checkPrefix reports an error that does not start with a channel
+// name, and with wantNil, any error.
+func checkPrefix(t *testing.T, err error, wantNil bool) {
+	t.Helper()
+	switch {
+	case err == nil:
+	case wantNil:
+		t.Errorf("Send = %v, want nil", err)
+	case !prefixed.MatchString(err.Error()):
+		t.Errorf("error %q does not start with the channel's name", err)
+	}
+}
@@ -0,0 +1,36 @@
+// Package slack registers the "slack" channel with notify. Import it
+// for its side effect:
+//
+//	import _ "github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/slack"
+//
+// The channel posts to SLACK_CHANNEL, or #general.
+package slack
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/notify"
+)
+
+func init() {
+	notify.Register("slack", func() (notify.Notification, error) {
+		return Service{Channel: cmp.Or(os.Getenv("SLACK_CHANNEL"), "#general")}, nil
+	})
+}
+
+// Service prints the Slack posts it sends. Message.To is ignored; the
+// posts go to Channel.
+type Service struct {
+	Channel string
+}
+
+func (s Service) Send(ctx context.Context, msg notify.Message) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
+	fmt.Printf("Posting to Slack %s: *%s* %s\n", s.Channel, msg.Subject, msg.Body)
+	return nil
+}
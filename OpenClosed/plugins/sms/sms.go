@@ -0,0 +1,32 @@
+// Package sms registers the "sms" channel with notify. Import it for
+// its side effect:
+//
+//	import _ "github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/sms"
+package sms
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/notify"
+)
+
+func init() {
+	notify.Register("sms", func() (notify.Notification, error) { return Service{}, nil })
+}
+
+// Service prints the text messages it sends.
+type Service struct{}
+
+func (Service) Send(ctx context.Context, msg notify.Message) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("sms: %w", err)
+	}
+	digits, ok := strings.CutPrefix(msg.To, "+")
+	if !ok || len(digits) < 8 || len(digits) > 15 || strings.Trim(digits, "0123456789") != "" {
+		return fmt.Errorf("sms: %w: %q", notify.ErrInvalidRecipient, msg.To)
+	}
+	fmt.Printf("Sending SMS to %s: %s\n", msg.To, msg.Body)
+	return nil
+}
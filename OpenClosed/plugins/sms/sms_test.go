@@ -0,0 +1,14 @@
+package sms
+
+import (
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/notify"
+	"github.com/anil-vinnakoti/go-SOLID/OpenClosed/plugins/notify/notifytest"
+)
+
+func TestConformance(t *testing.T) {
+	notifytest.TestNotificationConformance(t, func(t *testing.T) (notify.Notification, string) {
+		return Service{}, "+4915112345678"
+	})
+}
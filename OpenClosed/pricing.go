@@ -0,0 +1,51 @@
+package openclosed
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
+
+var ErrNoPricing = errors.New("no pricing strategy for product")
+
+// PricingStrategy is how one product is priced: what quantity units
+// of it cost. A new pricing model is a new implementation in its own
+// file.
+type PricingStrategy interface {
+	Price(quantity int) Money
+}
+
+// PriceCalculator prices orders with the strategy of each product.
+// It only knows the PricingStrategy interface.
+type PriceCalculator struct {
+	strategies map[string]PricingStrategy
+}
+
+// NewPriceCalculator returns a calculator pricing each SKU with its
+// strategy in strategies.
+func NewPriceCalculator(strategies map[string]PricingStrategy) PriceCalculator {
+	return PriceCalculator{strategies: maps.Clone(strategies)}
+}
+
+// Subtotal is what the items of order cost before discounts. The
+// items' own UnitPrice is ignored.
+func (c PriceCalculator) Subtotal(order Order) (Money, error) {
+	var total Money
+	for _, item := range order.Items {
+		strategy, ok := c.strategies[item.SKU]
+		if !ok {
+			return 0, fmt.Errorf("%w: %q", ErrNoPricing, item.SKU)
+		}
+		total += strategy.Price(item.Quantity)
+	}
+	return total, nil
+}
+
+// FlatPrice charges the same UnitPrice for every unit.
+type FlatPrice struct {
+	UnitPrice Money
+}
+
+func (p FlatPrice) Price(quantity int) Money {
+	return p.UnitPrice * Money(quantity)
+}
@@ -0,0 +1,40 @@
+package openclosed
+
+import (
+	"context"
+	"maps"
+)
+
+// DeliveryPolicy decides how a message reaches its channel: now, later,
+// batched, ... A new policy is a new implementation; the dispatcher
+// does not change.
+type DeliveryPolicy interface {
+	Deliver(ctx context.Context, next Notification, msg Message) error
+}
+
+// Immediate sends the message right away and returns its error.
+type Immediate struct{}
+
+func (Immediate) Deliver(ctx context.Context, next Notification, msg Message) error {
+	return next.Send(ctx, msg)
+}
+
+// PriorityDispatcher is a Notification that hands each message to the
+// DeliveryPolicy of its Priority. Priorities without a policy are sent
+// immediately.
+type PriorityDispatcher struct {
+	next     Notification
+	policies map[Priority]DeliveryPolicy
+}
+
+func NewPriorityDispatcher(next Notification, policies map[Priority]DeliveryPolicy) PriorityDispatcher {
+	return PriorityDispatcher{next: next, policies: maps.Clone(policies)}
+}
+
+func (d PriorityDispatcher) Send(ctx context.Context, msg Message) error {
+	policy, ok := d.policies[msg.Priority]
+	if !ok {
+		policy = Immediate{}
+	}
+	return policy.Deliver(ctx, d.next, msg)
+}
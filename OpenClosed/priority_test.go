@@ -0,0 +1,161 @@
+package openclosed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// gatedChannel records the subjects it sends. Low-priority messages
+// wait until release is closed; started receives each of them first.
+type gatedChannel struct {
+	mu      sync.Mutex
+	sent    []string
+	started chan string
+	release chan struct{}
+}
+
+func newGatedChannel() *gatedChannel {
+	return &gatedChannel{started: make(chan string, 100), release: make(chan struct{})}
+}
+
+func (c *gatedChannel) Send(ctx context.Context, msg Message) error {
+	if msg.Priority == PriorityLow {
+		c.started <- msg.Subject
+		<-c.release
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, msg.Subject)
+	return nil
+}
+
+func (c *gatedChannel) Sent() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.sent)
+}
+
+func TestPriorityDispatcher_Ordering(t *testing.T) {
+	ctx := context.Background()
+	channel := newGatedChannel()
+	queue := NewQueuedDelivery(50, nil)
+	d := NewPriorityDispatcher(channel, map[Priority]DeliveryPolicy{PriorityLow: queue})
+
+	var high, low []string
+	for i := range 50 {
+		subject := fmt.Sprintf("low %d", i)
+		if err := d.Send(ctx, Message{Subject: subject, Priority: PriorityLow}); err != nil {
+			t.Fatal(err)
+		}
+		low = append(low, subject)
+		if i%10 == 0 {
+			subject := fmt.Sprintf("high %d", i)
+			if err := d.Send(ctx, Message{Subject: subject, Priority: PriorityHigh}); err != nil {
+				t.Fatal(err)
+			}
+			high = append(high, subject)
+			if got := channel.Sent(); !slices.Equal(got, high) {
+				t.Fatalf("high priority not sent at once: %q", got)
+			}
+		}
+	}
+
+	close(channel.release)
+	if err := queue.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := channel.Sent(), append(high, low...); !slices.Equal(got, want) {
+		t.Errorf("sent %q,\nwant %q", got, want)
+	}
+}
+
+// A QueuedDelivery has no channel to Send through, so it must not pass
+// for a Notification.
+func TestQueuedDelivery_IsNotANotification(t *testing.T) {
+	if _, ok := any(NewQueuedDelivery(1, nil)).(Notification); ok {
+		t.Error("QueuedDelivery is a Notification")
+	}
+}
+
+func TestQueuedDelivery_FullAndClosed(t *testing.T) {
+	ctx := context.Background()
+	channel := newGatedChannel()
+	queue := NewQueuedDelivery(1, nil)
+	low := func(subject string) error {
+		return queue.Deliver(ctx, channel, Message{Subject: subject, Priority: PriorityLow})
+	}
+
+	if err := low("sending"); err != nil {
+		t.Fatal(err)
+	}
+	<-channel.started
+	if err := low("waiting"); err != nil {
+		t.Fatal(err)
+	}
+	if err := low("turned away"); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("full queue: %v, want ErrQueueFull", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := queue.Shutdown(cancelled); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Shutdown with a stuck send = %v, want context.Canceled", err)
+	}
+	if err := low("late"); !errors.Is(err, ErrQueueClosed) {
+		t.Fatalf("after Shutdown: %v, want ErrQueueClosed", err)
+	}
+
+	close(channel.release)
+	if err := queue.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := channel.Sent(); !slices.Equal(got, []string{"sending", "waiting"}) {
+		t.Errorf("sent %q", got)
+	}
+}
+
+// Shutdown must not race the Delivers in progress: each either queues
+// its message, which is then sent, or fails.
+func TestQueuedDelivery_ShutdownUnderLoad(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var queued, sent int
+	channel := NotificationFunc(func(ctx context.Context, msg Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		sent++
+		return nil
+	})
+	queue := NewQueuedDelivery(16, nil)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			for range 200 {
+				err := queue.Deliver(ctx, channel, Message{})
+				switch {
+				case err == nil:
+					mu.Lock()
+					queued++
+					mu.Unlock()
+				case !errors.Is(err, ErrQueueFull) && !errors.Is(err, ErrQueueClosed):
+					t.Errorf("Deliver = %v", err)
+				}
+			}
+		})
+	}
+	if err := queue.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if sent != queued {
+		t.Errorf("sent %d of %d queued messages", sent, queued)
+	}
+}
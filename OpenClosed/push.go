@@ -0,0 +1,72 @@
+package openclosed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/httpx"
+)
+
+func init() {
+	Register("push", func(s Settings) (Notification, error) {
+		return NewPushNotificationService(PushConfig{
+			Endpoint: s.Get("endpoint", "PUSH_ENDPOINT"),
+			APIKey:   s.Get("api_key", "PUSH_API_KEY"),
+		}, nil)
+	})
+}
+
+// PushConfig configures a PushNotificationService.
+type PushConfig struct {
+	Endpoint string // the push provider's send endpoint
+	APIKey   string
+}
+
+// PushNotificationService sends mobile push notifications through a
+// provider's HTTP API. Message.To is the device token, and
+// Message.Metadata["badge"], if set, is the app badge count.
+type PushNotificationService struct {
+	cfg    PushConfig
+	client httpx.Doer
+}
+
+// NewPushNotificationService returns a push channel. client may be
+// nil, for a client that retries transient failures; pkg/httpx builds
+// others. Every request is authorized with the API key.
+func NewPushNotificationService(cfg PushConfig, client httpx.Doer) (*PushNotificationService, error) {
+	if cfg.Endpoint == "" || cfg.APIKey == "" {
+		return nil, errors.New("push: endpoint and API key are required")
+	}
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	return &PushNotificationService{cfg: cfg, client: httpx.Wrap(client, httpx.BearerToken(cfg.APIKey))}, nil
+}
+
+type pushPayload struct {
+	Token string `json:"token"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Badge *int   `json:"badge,omitempty"`
+}
+
+func (p *PushNotificationService) Send(ctx context.Context, msg Message) error {
+	if msg.To == "" {
+		return fmt.Errorf("push: %w: empty device token", ErrInvalidRecipient)
+	}
+	payload := pushPayload{Token: msg.To, Title: msg.Subject, Body: msg.Body}
+	if b, ok := msg.Metadata["badge"]; ok {
+		badge, err := strconv.Atoi(b)
+		if err != nil {
+			return fmt.Errorf("push: badge %q: %w", b, err)
+		}
+		payload.Badge = &badge
+	}
+
+	if err := postJSON(ctx, p.client, p.cfg.Endpoint, nil, payload); err != nil {
+		return fmt.Errorf("push: %w", err)
+	}
+	return nil
+}
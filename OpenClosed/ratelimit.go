@@ -0,0 +1,40 @@
+package openclosed
+
+import (
+	"context"
+	"errors"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/ratelimit"
+)
+
+var ErrRateLimited = errors.New("notification rate limit exceeded")
+
+// RateLimitedNotifier is a Notification that limits how often another
+// one is used. How the sends are counted, with a token bucket or a
+// sliding window, is up to the ratelimit.Limiter; the channel being
+// limited does not change.
+type RateLimitedNotifier struct {
+	next    Notification
+	limiter ratelimit.Limiter
+}
+
+// NewRateLimitedNotifier returns a notifier that sends through next
+// while limiter allows it.
+func NewRateLimitedNotifier(next Notification, limiter ratelimit.Limiter) *RateLimitedNotifier {
+	return &RateLimitedNotifier{next: next, limiter: limiter}
+}
+
+// Send sends msg, or returns ErrRateLimited without sending when the
+// limiter does not allow another send.
+func (r *RateLimitedNotifier) Send(ctx context.Context, msg Message) error {
+	if !r.limiter.Allow() {
+		return ErrRateLimited
+	}
+	return r.next.Send(ctx, msg)
+}
+
+// Name is the name of the limited channel, so delivery reports keep
+// it.
+func (r *RateLimitedNotifier) Name() string {
+	return channelName(r.next)
+}
@@ -0,0 +1,39 @@
+package openclosed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/ratelimit"
+)
+
+func TestRateLimitedNotifier(t *testing.T) {
+	ctx := context.Background()
+	clk := clocktest.NewFake(time.Time{})
+	var sent int
+	n := NewRateLimitedNotifier(recordingChannel{&sent}, ratelimit.NewTokenBucket(time.Minute, 2, clk))
+
+	for i := range 2 {
+		if err := n.Send(ctx, Message{}); err != nil {
+			t.Fatalf("send %d: %v", i+1, err)
+		}
+	}
+	if err := n.Send(ctx, Message{}); !errors.Is(err, ErrRateLimited) || sent != 2 {
+		t.Fatalf("exhausted: err = %v, sent = %d", err, sent)
+	}
+
+	clk.Advance(time.Minute)
+	if err := n.Send(ctx, Message{}); err != nil || sent != 3 {
+		t.Fatalf("after refill: err = %v, sent = %d", err, sent)
+	}
+	if err := n.Send(ctx, Message{}); !errors.Is(err, ErrRateLimited) || sent != 3 {
+		t.Fatalf("one token refilled: err = %v, sent = %d", err, sent)
+	}
+
+	if got := n.Name(); got != "recordingChannel" {
+		t.Errorf("Name = %q, want the limited channel's", got)
+	}
+}
@@ -0,0 +1,108 @@
+package openclosed
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+)
+
+var (
+	ErrDuplicateChannel = errors.New("notification channel already registered")
+	ErrUnknownChannel   = errors.New("unknown notification channel")
+	ErrChannelDisabled  = errors.New("notification channel disabled")
+)
+
+// ChannelFactory builds a ready-to-use channel from its settings. It
+// may fail, for example when the channel's credentials are not
+// configured.
+type ChannelFactory func(settings Settings) (Notification, error)
+
+// Settings are a channel's configuration, such as credentials.
+type Settings map[string]string
+
+// Get returns the setting key, or the environment variable env if the
+// setting is empty.
+func (s Settings) Get(key, env string) string {
+	if v := s[key]; v != "" {
+		return v
+	}
+	return os.Getenv(env)
+}
+
+// Registry maps channel names to factories. Channels add themselves
+// to DefaultRegistry from an init function in their own file, so a new
+// channel is a new file and nothing else changes. It is safe for
+// concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]ChannelFactory
+}
+
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]ChannelFactory)}
+}
+
+// DefaultRegistry holds the channels that register themselves.
+var DefaultRegistry = NewRegistry()
+
+// Register adds factory under name. A name can be registered once.
+func (r *Registry) Register(name string, factory ChannelFactory) error {
+	if name == "" || factory == nil {
+		return fmt.Errorf("registering channel %q: name and factory are required", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.factories[name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateChannel, name)
+	}
+	r.factories[name] = factory
+	return nil
+}
+
+// Open builds the channel registered as name, configured from the
+// environment.
+func (r *Registry) Open(name string) (Notification, error) {
+	return r.OpenWith(name, nil)
+}
+
+// OpenWith builds the channel registered as name with settings.
+func (r *Registry) OpenWith(name string, settings Settings) (Notification, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[name]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownChannel, name)
+	}
+	n, err := factory(settings)
+	if err != nil {
+		return nil, fmt.Errorf("opening channel %q: %w", name, err)
+	}
+	return n, nil
+}
+
+// Names returns the registered channel names, sorted.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Register adds a channel to DefaultRegistry. It is meant to be called
+// from init, so, like database/sql.Register, it panics on a duplicate
+// name: two channels claiming one name is a programming error.
+func Register(name string, factory ChannelFactory) {
+	if err := DefaultRegistry.Register(name, factory); err != nil {
+		panic(err)
+	}
+}
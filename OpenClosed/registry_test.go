@@ -0,0 +1,76 @@
+package openclosed
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	var sent int
+	reg := NewRegistry()
+	if err := reg.Register("pigeon", func(Settings) (Notification, error) { return recordingChannel{&sent}, nil }); err != nil {
+		t.Fatal(err)
+	}
+	errNoKey := errors.New("no key")
+	if err := reg.Register("fax", func(Settings) (Notification, error) { return nil, errNoKey }); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := reg.Register("pigeon", func(Settings) (Notification, error) { return nil, nil }); !errors.Is(err, ErrDuplicateChannel) {
+		t.Errorf("duplicate Register = %v, want ErrDuplicateChannel", err)
+	}
+	if err := reg.Register("", func(Settings) (Notification, error) { return nil, nil }); err == nil {
+		t.Error("Register without a name succeeded")
+	}
+	if err := reg.Register("owl", nil); err == nil {
+		t.Error("Register without a factory succeeded")
+	}
+	if got := reg.Names(); !slices.Equal(got, []string{"fax", "pigeon"}) {
+		t.Errorf("Names = %q", got)
+	}
+
+	n, err := reg.Open("pigeon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Send(context.Background(), Message{}); err != nil || sent != 1 {
+		t.Errorf("Send = %v, sent %d", err, sent)
+	}
+	if _, err := reg.Open("owl"); !errors.Is(err, ErrUnknownChannel) {
+		t.Errorf("Open(owl) = %v, want ErrUnknownChannel", err)
+	}
+	if _, err := reg.Open("fax"); !errors.Is(err, errNoKey) {
+		t.Errorf("Open(fax) = %v, want the factory's error", err)
+	}
+}
+
+func TestRegister_PanicsOnDuplicate(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, ErrDuplicateChannel) {
+			t.Errorf("recovered %v, want ErrDuplicateChannel", err)
+		}
+	}()
+	Register("email", func(Settings) (Notification, error) { return EmailService{}, nil })
+}
+
+// The channels in this package register themselves; SendNotification
+// finds them, and nothing else, by name.
+func TestSendNotification_ResolvesFromDefaultRegistry(t *testing.T) {
+	names := DefaultRegistry.Names()
+	for _, want := range []string{"email", "push", "slack", "sms", "webhook"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("%q not registered: %q", want, names)
+		}
+	}
+
+	ctx := context.Background()
+	if err := SendNotification(ctx, "sms", Message{To: "+4915112345678", Body: "hi"}); err != nil {
+		t.Errorf("sms: %v", err)
+	}
+	if err := SendNotification(ctx, "pigeon", Message{To: "a@example.com"}); !errors.Is(err, ErrUnknownChannel) {
+		t.Errorf("pigeon: %v, want ErrUnknownChannel", err)
+	}
+}
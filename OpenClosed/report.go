@@ -0,0 +1,101 @@
+package openclosed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DeliveryResult is what happened when a message was sent through one
+// channel.
+type DeliveryResult struct {
+	Channel  string
+	Duration time.Duration
+	Attempts int   // sends tried, counting the retries of Retry
+	Err      error // the final error, nil on success
+}
+
+// DeliveryReport collects the results of a fan-out, one per channel in
+// the order the channels were given.
+type DeliveryReport struct {
+	Results []DeliveryResult
+}
+
+// Skipped reports whether the channel was not used because it is
+// disabled; see Gate. A skipped delivery has not failed.
+func (r DeliveryResult) Skipped() bool {
+	return errors.Is(r.Err, ErrChannelDisabled)
+}
+
+// Err joins the errors of the failed deliveries, or is nil if every
+// delivery succeeded or was skipped.
+func (r DeliveryReport) Err() error {
+	var errs []error
+	for _, res := range r.Failed() {
+		errs = append(errs, res.Err)
+	}
+	return errors.Join(errs...)
+}
+
+// Failed returns the results of the failed deliveries.
+func (r DeliveryReport) Failed() []DeliveryResult {
+	var failed []DeliveryResult
+	for _, res := range r.Results {
+		if res.Err != nil && !res.Skipped() {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+func (r DeliveryReport) String() string {
+	var b strings.Builder
+	for _, res := range r.Results {
+		status := "ok"
+		switch {
+		case res.Skipped():
+			status = "skipped (disabled)"
+		case res.Err != nil:
+			status = res.Err.Error()
+		}
+		fmt.Fprintf(&b, "%-16s %d attempt(s) in %s: %s\n", res.Channel, res.Attempts, res.Duration.Round(time.Microsecond), status)
+	}
+	return b.String()
+}
+
+// deliver sends msg through n and reports how it went.
+func deliver(ctx context.Context, name string, n Notification, msg Message) DeliveryResult {
+	attempts := new(atomic.Int32)
+	start := time.Now()
+	err := n.Send(context.WithValue(ctx, attemptsKey{}, attempts), msg)
+	return DeliveryResult{
+		Channel:  name,
+		Duration: time.Since(start),
+		Attempts: max(1, int(attempts.Load())),
+		Err:      err,
+	}
+}
+
+type attemptsKey struct{}
+
+// countAttempt records one send attempt for the DeliveryResult being
+// collected, if any. Middleware that sends more than once calls it for
+// every attempt.
+func countAttempt(ctx context.Context) {
+	if attempts, ok := ctx.Value(attemptsKey{}).(*atomic.Int32); ok {
+		attempts.Add(1)
+	}
+}
+
+// channelName names n in a DeliveryResult: by its Name method if it has
+// one, and by its type otherwise.
+func channelName(n Notification) string {
+	if named, ok := n.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	name := fmt.Sprintf("%T", n)
+	return name[strings.LastIndex(name, ".")+1:]
+}
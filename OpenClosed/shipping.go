@@ -0,0 +1,86 @@
+package openclosed
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
+
+var (
+	ErrNoShippingRate = errors.New("no shipping rate for destination")
+	ErrInvalidWeight  = errors.New("invalid shipment weight")
+)
+
+// Shipment is a parcel to ship.
+type Shipment struct {
+	Country     string // ISO 3166-1 alpha-2 code of the destination
+	WeightGrams int
+}
+
+// ShippingCalculator is one way of costing a shipment. A new carrier
+// or tariff is a new implementation.
+type ShippingCalculator interface {
+	Cost(shipment Shipment) (Money, error)
+}
+
+// ShippingResolver picks the calculator of a shipment's destination
+// country, or the fallback for countries without one. It is itself a
+// ShippingCalculator.
+type ShippingResolver struct {
+	byCountry map[string]ShippingCalculator
+	fallback  ShippingCalculator
+}
+
+// NewShippingResolver returns a resolver over byCountry. fallback may
+// be nil, in which case other countries cannot be shipped to.
+func NewShippingResolver(byCountry map[string]ShippingCalculator, fallback ShippingCalculator) ShippingResolver {
+	return ShippingResolver{byCountry: maps.Clone(byCountry), fallback: fallback}
+}
+
+func (r ShippingResolver) Cost(shipment Shipment) (Money, error) {
+	calc, ok := r.byCountry[shipment.Country]
+	if !ok {
+		calc = r.fallback
+	}
+	if calc == nil {
+		return 0, fmt.Errorf("%w: %q", ErrNoShippingRate, shipment.Country)
+	}
+	return calc.Cost(shipment)
+}
+
+// FlatRate charges Rate for every shipment.
+type FlatRate struct {
+	Rate Money
+}
+
+func (r FlatRate) Cost(Shipment) (Money, error) {
+	return r.Rate, nil
+}
+
+// WeightBased charges Base plus PerKg for every started kilogram.
+type WeightBased struct {
+	Base  Money
+	PerKg Money
+}
+
+func (r WeightBased) Cost(shipment Shipment) (Money, error) {
+	if shipment.WeightGrams <= 0 {
+		return 0, fmt.Errorf("%w: %d g", ErrInvalidWeight, shipment.WeightGrams)
+	}
+	kg := (shipment.WeightGrams + 999) / 1000
+	return r.Base + r.PerKg*Money(kg), nil
+}
+
+// ZoneBased groups countries into zones, each with its own rate.
+type ZoneBased struct {
+	Zones map[string]string // country → zone
+	Rates map[string]Money  // zone → rate
+}
+
+func (r ZoneBased) Cost(shipment Shipment) (Money, error) {
+	rate, ok := r.Rates[r.Zones[shipment.Country]]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q has no zone", ErrNoShippingRate, shipment.Country)
+	}
+	return rate, nil
+}
@@ -0,0 +1,66 @@
+package openclosed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/httpx"
+)
+
+func init() {
+	Register("slack", func(s Settings) (Notification, error) {
+		return NewSlackService(SlackConfig{
+			WebhookURL: s.Get("webhook_url", "SLACK_WEBHOOK_URL"),
+			Channel:    s.Get("channel", "SLACK_CHANNEL"),
+		}, nil)
+	})
+}
+
+// SlackConfig configures a SlackService.
+type SlackConfig struct {
+	WebhookURL string // incoming webhook URL
+	Channel    string // default channel; Message.Metadata["channel"] overrides it
+	Username   string // optional bot name
+}
+
+// SlackService posts messages to a Slack incoming webhook. Message.To
+// is ignored; the webhook decides the workspace.
+type SlackService struct {
+	cfg    SlackConfig
+	client httpx.Doer
+}
+
+// NewSlackService returns a Slack channel. client may be nil, for a
+// client that retries transient failures; pkg/httpx builds others.
+func NewSlackService(cfg SlackConfig, client httpx.Doer) (*SlackService, error) {
+	if cfg.WebhookURL == "" {
+		return nil, errors.New("slack: webhook URL is required")
+	}
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	return &SlackService{cfg: cfg, client: client}, nil
+}
+
+type slackPayload struct {
+	Channel  string `json:"channel,omitempty"`
+	Username string `json:"username,omitempty"`
+	Text     string `json:"text"`
+}
+
+func (s *SlackService) Send(ctx context.Context, msg Message) error {
+	channel := s.cfg.Channel
+	if c := msg.Metadata["channel"]; c != "" {
+		channel = c
+	}
+	text := msg.Body
+	if msg.Subject != "" {
+		text = "*" + msg.Subject + "*\n" + msg.Body
+	}
+	payload := slackPayload{Channel: channel, Username: s.cfg.Username, Text: text}
+	if err := postJSON(ctx, s.client, s.cfg.WebhookURL, nil, payload); err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
+	return nil
+}
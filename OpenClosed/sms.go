@@ -0,0 +1,36 @@
+package openclosed
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+func init() {
+	Register("sms", func(Settings) (Notification, error) { return SmsService{}, nil })
+	RegisterTemplate("sms", OrderShipped{}.EventName(), "",
+		"Order {{.OrderID}} shipped: {{.Carrier}} {{.TrackingNumber}}")
+}
+
+type SmsService struct{}
+
+func (s SmsService) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("sms: %w", err)
+	}
+	if !isPhoneNumber(msg.To) {
+		return fmt.Errorf("sms: %w: %q", ErrInvalidRecipient, msg.To)
+	}
+	fmt.Printf("Sending SMS to %s: %s\n", msg.To, msg.Body)
+	return nil
+}
+
+// isPhoneNumber reports whether s looks like an E.164 number, such as
+// +4915112345678.
+func isPhoneNumber(s string) bool {
+	digits, ok := strings.CutPrefix(s, "+")
+	if !ok || len(digits) < 8 || len(digits) > 15 {
+		return false
+	}
+	return strings.Trim(digits, "0123456789") == ""
+}
@@ -0,0 +1,19 @@
+package openclosed
+
+// SubscriptionProration prices a subscription started partway through
+// a billing period: each seat costs the share of PeriodPrice for the
+// DaysRemaining of the period's DaysInPeriod, rounded to the cent.
+type SubscriptionProration struct {
+	PeriodPrice   Money
+	DaysInPeriod  int
+	DaysRemaining int
+}
+
+func (p SubscriptionProration) Price(quantity int) Money {
+	if p.DaysInPeriod <= 0 {
+		return p.PeriodPrice * Money(quantity)
+	}
+	days := Money(min(p.DaysRemaining, p.DaysInPeriod))
+	perSeat := (p.PeriodPrice*days + Money(p.DaysInPeriod)/2) / Money(p.DaysInPeriod)
+	return perSeat * Money(quantity)
+}
@@ -0,0 +1,139 @@
+package openclosed
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+var (
+	ErrDuplicateTemplate = errors.New("message template already registered")
+	ErrUnknownTemplate   = errors.New("no message template")
+)
+
+// Event is the typed data a message is rendered from. Its name selects
+// the template, and its fields are what the template can use.
+type Event interface {
+	EventName() string
+}
+
+// OrderShipped is sent when an order leaves the warehouse.
+type OrderShipped struct {
+	OrderID        string
+	Carrier        string
+	TrackingNumber string
+}
+
+func (OrderShipped) EventName() string { return "order.shipped" }
+
+// OrderDelivered is sent when an order arrives.
+type OrderDelivered struct {
+	OrderID string
+}
+
+func (OrderDelivered) EventName() string { return "order.delivered" }
+
+// AnyChannel registers a template for channels without one of their
+// own.
+const AnyChannel = ""
+
+type templateKey struct {
+	channel string
+	event   string
+}
+
+// messageTemplate renders the subject and body of a Message.
+type messageTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// TemplateRegistry maps a channel and an event to the text/templates
+// of its message. A new event or a new channel's wording is a new
+// registration; existing templates are not edited. It is safe for
+// concurrent use.
+type TemplateRegistry struct {
+	mu        sync.RWMutex
+	templates map[templateKey]messageTemplate
+}
+
+func NewTemplateRegistry() *TemplateRegistry {
+	return &TemplateRegistry{templates: make(map[templateKey]messageTemplate)}
+}
+
+// DefaultTemplates holds the templates that register themselves.
+var DefaultTemplates = NewTemplateRegistry()
+
+// Register parses subject and body as the templates of event on
+// channel, or on every channel without its own if channel is
+// AnyChannel. A pair can be registered once.
+func (r *TemplateRegistry) Register(channel, event, subject, body string) error {
+	name := channel + "/" + event
+	if event == "" {
+		return fmt.Errorf("registering template %q: event is required", name)
+	}
+	tmpl := messageTemplate{
+		subject: template.New(name + "/subject").Option("missingkey=error"),
+		body:    template.New(name + "/body").Option("missingkey=error"),
+	}
+	if _, err := tmpl.subject.Parse(subject); err != nil {
+		return fmt.Errorf("registering template %q: %w", name, err)
+	}
+	if _, err := tmpl.body.Parse(body); err != nil {
+		return fmt.Errorf("registering template %q: %w", name, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := templateKey{channel: channel, event: event}
+	if _, ok := r.templates[key]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateTemplate, name)
+	}
+	r.templates[key] = tmpl
+	return nil
+}
+
+// Render builds the message of event for channel, using the channel's
+// own template if it has one and the AnyChannel template otherwise.
+// The caller sets Message.To.
+func (r *TemplateRegistry) Render(channel string, event Event) (Message, error) {
+	r.mu.RLock()
+	tmpl, ok := r.templates[templateKey{channel: channel, event: event.EventName()}]
+	if !ok {
+		tmpl, ok = r.templates[templateKey{channel: AnyChannel, event: event.EventName()}]
+	}
+	r.mu.RUnlock()
+
+	if !ok {
+		return Message{}, fmt.Errorf("%w: %q on %q", ErrUnknownTemplate, event.EventName(), channel)
+	}
+
+	var subject, body strings.Builder
+	if err := tmpl.subject.Execute(&subject, event); err != nil {
+		return Message{}, fmt.Errorf("rendering %q subject: %w", event.EventName(), err)
+	}
+	if err := tmpl.body.Execute(&body, event); err != nil {
+		return Message{}, fmt.Errorf("rendering %q body: %w", event.EventName(), err)
+	}
+	return Message{Subject: subject.String(), Body: body.String()}, nil
+}
+
+// RegisterTemplate adds a template to DefaultTemplates. Like Register,
+// it is meant to be called from init and panics on error.
+func RegisterTemplate(channel, event, subject, body string) {
+	if err := DefaultTemplates.Register(channel, event, subject, body); err != nil {
+		panic(err)
+	}
+}
+
+func init() {
+	RegisterTemplate(AnyChannel, OrderShipped{}.EventName(),
+		"Order {{.OrderID}} shipped",
+		"Your order {{.OrderID}} is on its way with {{.Carrier}}. Tracking number: {{.TrackingNumber}}.")
+	RegisterTemplate(AnyChannel, OrderDelivered{}.EventName(),
+		"Order {{.OrderID}} delivered",
+		"Your order {{.OrderID}} has been delivered. Enjoy!")
+}
@@ -0,0 +1,32 @@
+package openclosed
+
+// Tier is a volume band: units up to UpTo cost UnitPrice each. The
+// last tier's UpTo is 0 and covers every remaining unit.
+type Tier struct {
+	UpTo      int
+	UnitPrice Money
+}
+
+// TieredPrice prices graduated volume bands: the first units are
+// charged at the first tier's price, the next ones at the second's,
+// and so on. Tiers are sorted by UpTo.
+type TieredPrice struct {
+	Tiers []Tier
+}
+
+func (p TieredPrice) Price(quantity int) Money {
+	var total Money
+	priced := 0
+	for _, tier := range p.Tiers {
+		if priced >= quantity {
+			break
+		}
+		units := quantity - priced
+		if tier.UpTo > 0 {
+			units = min(units, tier.UpTo-priced)
+		}
+		total += tier.UnitPrice * Money(units)
+		priced += units
+	}
+	return total
+}
@@ -0,0 +1,80 @@
+package openclosed
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/timeout"
+)
+
+// hangingChannel never delivers until the send is cancelled.
+type hangingChannel struct{}
+
+func (hangingChannel) Send(ctx context.Context, msg Message) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestTimeout_Middleware(t *testing.T) {
+	clk := clocktest.NewFake(time.Time{})
+	n := Chain(hangingChannel{}, Timeout(3*time.Second, clk))
+
+	errc := make(chan error)
+	go func() { errc <- n.Send(context.Background(), Message{To: "a@example.com"}) }()
+	clk.BlockUntil(1)
+	clk.Advance(3 * time.Second)
+
+	var te *timeout.Error
+	if err := <-errc; !errors.As(err, &te) || te.Op != "hangingChannel.Send" {
+		t.Fatalf("err = %v, want a timeout of hangingChannel.Send", err)
+	}
+	if got := channelName(n); got != "hangingChannel" {
+		t.Errorf("channel name = %q, want the wrapped channel's", got)
+	}
+}
+
+func TestWithPaymentTimeout(t *testing.T) {
+	clk := clocktest.NewFake(time.Time{})
+	hanging := PaymentMethodFunc(func(ctx context.Context, amount float64) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	method := WithPaymentTimeout(hanging, time.Second, clk)
+
+	errc := make(chan error)
+	go func() { errc <- method.Pay(context.Background(), 10) }()
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+
+	if err := <-errc; !errors.Is(err, timeout.ErrDeadlineExceeded) {
+		t.Errorf("err = %v, want ErrDeadlineExceeded", err)
+	}
+}
+
+func TestBuildNotifier_Timeout(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register("hang", func(Settings) (Notification, error) { return hangingChannel{}, nil })
+
+	cfg, err := LoadNotifierConfig(strings.NewReader(`{"channels": [{"name": "hang", "timeout": "10ms"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := BuildNotifier(reg, nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Send(context.Background(), Message{To: "a@example.com"}); !errors.Is(err, timeout.ErrDeadlineExceeded) {
+		t.Errorf("err = %v, want ErrDeadlineExceeded", err)
+	}
+
+	for _, bad := range []string{"soon", "-1s", "0s"} {
+		cfg.Channels[0].Timeout = bad
+		if _, err := BuildNotifier(reg, nil, cfg); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("timeout %q: err = %v, want ErrInvalidConfig", bad, err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package openclosed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/httpx"
+)
+
+var ErrDeliveryFailed = errors.New("notification delivery failed")
+
+// defaultHTTPClient is used by the HTTP channels when none is given:
+// a client with a timeout that retries answers such as 503 and 429,
+// which providers send when briefly overloaded.
+var defaultHTTPClient = httpx.Wrap(&http.Client{Timeout: 10 * time.Second},
+	httpx.Retry(httpx.RetryPolicy{Attempts: 3, Backoff: 500 * time.Millisecond}))
+
+// postJSON sends payload as a JSON POST and treats any non-2xx answer
+// as ErrDeliveryFailed. The HTTP channels share it, so each of them
+// only builds its own payload.
+func postJSON(ctx context.Context, client httpx.Doer, url string, header http.Header, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return post(ctx, client, url, header, body)
+}
+
+func post(ctx context.Context, client httpx.Doer, url string, header http.Header, body []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDeliveryFailed, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: %s answered %s", ErrDeliveryFailed, req.URL.Host, resp.Status)
+	}
+	return nil
+}
+
+// FakeTransport is an http.RoundTripper that records requests instead
+// of sending them and answers each with Status (200 when zero). It
+// lets the HTTP channels run without a network.
+type FakeTransport struct {
+	Status int
+
+	mu       sync.Mutex
+	requests []RecordedRequest
+}
+
+// RecordedRequest is a request seen by FakeTransport.
+type RecordedRequest struct {
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+func (t *FakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+
+	t.mu.Lock()
+	t.requests = append(t.requests, RecordedRequest{URL: req.URL.String(), Header: req.Header.Clone(), Body: body})
+	t.mu.Unlock()
+
+	status := t.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}, nil
+}
+
+// Requests returns the requests recorded so far.
+func (t *FakeTransport) Requests() []RecordedRequest {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]RecordedRequest(nil), t.requests...)
+}
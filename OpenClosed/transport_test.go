@@ -0,0 +1,22 @@
+package openclosed
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestPushNotificationService_AuthorizesThroughClientStack(t *testing.T) {
+	transport := &FakeTransport{}
+	push, err := NewPushNotificationService(PushConfig{Endpoint: "https://push.example.com/send", APIKey: "k3y"}, &http.Client{Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := push.Send(context.Background(), Message{To: "device-1", Subject: "Shipped"}); err != nil {
+		t.Fatal(err)
+	}
+	reqs := transport.Requests()
+	if len(reqs) != 1 || reqs[0].Header.Get("Authorization") != "Bearer k3y" || reqs[0].Header.Get("Content-Type") != "application/json" {
+		t.Errorf("requests = %+v", reqs)
+	}
+}
@@ -0,0 +1,20 @@
+package openclosed
+
+import (
+	"context"
+	"fmt"
+)
+
+func init() {
+	RegisterPaymentMethod("upi", UPI{})
+}
+
+type UPI struct{}
+
+func (UPI) Pay(ctx context.Context, amount float64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fmt.Println("Processing UPI payment of", amount)
+	return nil
+}
@@ -0,0 +1,29 @@
+package openclosed
+
+import (
+	"context"
+	"fmt"
+)
+
+func init() {
+	RegisterPaymentMethod("wallet", WalletPayment{Limit: 2000, FeeRate: 0.01})
+}
+
+// WalletPayment pays from a stored-value wallet. Wallets cap a single
+// payment at Limit and charge FeeRate of the amount as a fee.
+type WalletPayment struct {
+	Limit   float64
+	FeeRate float64
+}
+
+func (w WalletPayment) Pay(ctx context.Context, amount float64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if w.Limit > 0 && amount > w.Limit {
+		return fmt.Errorf("wallet: %w: %v is over the %v limit", ErrInvalidAmount, amount, w.Limit)
+	}
+	fee := amount * w.FeeRate
+	fmt.Printf("Processing wallet payment of %v (fee %.2f)\n", amount, fee)
+	return nil
+}
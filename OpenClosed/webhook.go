@@ -0,0 +1,79 @@
+package openclosed
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/httpx"
+)
+
+func init() {
+	Register("webhook", func(s Settings) (Notification, error) {
+		return NewWebhookService(WebhookConfig{
+			URL:    s.Get("url", "WEBHOOK_URL"),
+			Secret: s.Get("secret", "WEBHOOK_SECRET"),
+		}, nil)
+	})
+}
+
+// WebhookConfig configures a WebhookService.
+type WebhookConfig struct {
+	URL    string
+	Secret string // optional; signs every body
+}
+
+// WebhookService posts every message as JSON to a URL the receiver
+// controls. With a Secret, the body's HMAC-SHA256 is sent in the
+// X-Signature header so the receiver can verify the sender.
+type WebhookService struct {
+	cfg    WebhookConfig
+	client httpx.Doer
+}
+
+// NewWebhookService returns a webhook channel. client may be nil, for
+// a client that retries transient failures; pkg/httpx builds others.
+func NewWebhookService(cfg WebhookConfig, client httpx.Doer) (*WebhookService, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("webhook: URL is required")
+	}
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	return &WebhookService{cfg: cfg, client: client}, nil
+}
+
+type webhookPayload struct {
+	To       string            `json:"to,omitempty"`
+	Subject  string            `json:"subject,omitempty"`
+	Body     string            `json:"body"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+func (w *WebhookService) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(webhookPayload{
+		To:       msg.To,
+		Subject:  msg.Subject,
+		Body:     msg.Body,
+		Metadata: msg.Metadata,
+	})
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+
+	header := make(http.Header)
+	if w.cfg.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.cfg.Secret))
+		mac.Write(body)
+		header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	if err := post(ctx, w.client, w.cfg.URL, header, body); err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package singleresponsibility
+
+import (
+	"context"
+	"strconv"
+)
+
+// AnalyticsTracker records the steps of the checkout funnel for a
+// product-analytics service; pkg/analytics provides an in-memory one,
+// one posting batches to an HTTP collector and sampling. Like
+// ErrReporter, it keeps the vendor SDK out of the services.
+type AnalyticsTracker interface {
+	Track(ctx context.Context, event string, props map[string]string)
+}
+
+// The checkout funnel events OrderService tracks.
+const (
+	FunnelCheckoutStarted = "checkout_started"
+	FunnelOrderPlaced     = "order_placed"
+	FunnelCheckoutFailed  = "checkout_failed" // with the API error code as "reason"
+)
+
+// WithAnalytics has the service track the checkout funnel with t.
+func WithAnalytics(t AnalyticsTracker) OrderOption {
+	return func(os *OrderService) { os.analytics = t }
+}
+
+func (os OrderService) track(ctx context.Context, event string, order Order, props map[string]string) {
+	if os.analytics == nil {
+		return
+	}
+	if props == nil {
+		props = make(map[string]string, 3)
+	}
+	props["order_id"] = strconv.Itoa(order.ID)
+	props["customer_id"] = strconv.Itoa(order.CustomerID)
+	os.analytics.Track(ctx, event, props)
+}
+
+// trackOutcome tracks how placing order ended: placed, or failed with
+// the reason the API would answer.
+func (os OrderService) trackOutcome(ctx context.Context, order Order, err error) {
+	if err != nil {
+		_, body := apiErrors.Map(err)
+		os.track(ctx, FunnelCheckoutFailed, order, map[string]string{"reason": body.Code})
+		return
+	}
+	os.track(ctx, FunnelOrderPlaced, order, map[string]string{
+		"total":    order.Total.Decimal(),
+		"currency": string(order.Total.Currency),
+		"items":    strconv.Itoa(len(order.Items)),
+	})
+}
@@ -0,0 +1,46 @@
+package singleresponsibility
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/analytics"
+)
+
+func TestOrderService_TracksTheCheckoutFunnel(t *testing.T) {
+	tracker := analytics.NewMemory(10, nil)
+	orders, err := NewOrderService(NewInMemoryOrderRepository(), NewFakeStripeGateway(NewMoney(10000, "USD"), nil), NewLoggingEmailSender(nil), NewInvoiceService(TextInvoiceRenderer{}, NewPricingService(nil), nil),
+		WithAnalytics(tracker))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if _, err := orders.PlaceOrder(ctx, "", testOrder(t, 1)); err != nil {
+		t.Fatal(err)
+	}
+	expensive, err := NewOrder(2, 1, []OrderItem{{SKU: "TV", Quantity: 1, UnitPrice: NewMoney(50000, "USD")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := orders.PlaceOrder(ctx, "", expensive); err == nil {
+		t.Fatal("PlaceOrder over the card limit succeeded")
+	}
+
+	want := []struct{ name, orderID, key, value string }{
+		{FunnelCheckoutStarted, "1", "", ""},
+		{FunnelOrderPlaced, "1", "total", "25.00"},
+		{FunnelCheckoutStarted, "2", "", ""},
+		{FunnelCheckoutFailed, "2", "reason", "payment_declined"},
+	}
+	events := tracker.Events()
+	if len(events) != len(want) {
+		t.Fatalf("tracked %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.Name != w.name || e.Props["order_id"] != w.orderID || e.Props[w.key] != w.value {
+			t.Errorf("event %d = %+v, want %s of order %s with %s=%q", i, e, w.name, w.orderID, w.key, w.value)
+		}
+	}
+}
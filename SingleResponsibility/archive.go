@@ -0,0 +1,50 @@
+package singleresponsibility
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/storage"
+)
+
+// InvoiceArchiver keeps a copy of every generated invoice in a blob
+// store, as invoices/<order ID> with the extension of the document's
+// format. Subscribe Archive to InvoiceGenerated; the archiver only
+// needs to put blobs, so any storage.Putter will do.
+type InvoiceArchiver struct {
+	store storage.Putter
+}
+
+func NewInvoiceArchiver(store storage.Putter) *InvoiceArchiver {
+	return &InvoiceArchiver{store: store}
+}
+
+// Archive is an EventHandler storing the invoice of an
+// InvoiceGenerated event and ignoring other events.
+func (a *InvoiceArchiver) Archive(ctx context.Context, e Event) error {
+	generated, ok := e.(InvoiceGenerated)
+	if !ok {
+		return nil
+	}
+	key := fmt.Sprintf("invoices/%d%s", generated.OrderID, documentExt(generated.Document))
+	if err := a.store.Put(ctx, key, bytes.NewReader(generated.Document)); err != nil {
+		return fmt.Errorf("archiving invoice of order %d: %w", generated.OrderID, err)
+	}
+	return nil
+}
+
+// documentExt names the format of an invoice document, which depends
+// on the customer's preference rather than on the archiver.
+func documentExt(doc []byte) string {
+	switch ct := http.DetectContentType(doc); {
+	case ct == "application/pdf":
+		return ".pdf"
+	case strings.HasPrefix(ct, "text/html"):
+		return ".html"
+	default:
+		return ".txt"
+	}
+}
@@ -0,0 +1,46 @@
+package singleresponsibility
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWire_ArchivesInvoices(t *testing.T) {
+	dir := t.TempDir()
+	env := map[string]string{"ORDERS_ARCHIVE_INVOICES": "true", "ORDERS_STORAGE_DIR": dir}
+	cfg, err := LoadConfig(func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	services, err := Wire(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer services.Close()
+
+	order, err := NewOrder(7, 1, []OrderItem{{SKU: "BOOK", Quantity: 1, UnitPrice: NewMoney(1250, "USD")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := services.Orders.PlaceOrder(context.Background(), "", order); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "invoices", "7.txt")); err != nil || len(data) == 0 {
+		t.Errorf("archived invoice: %q, %v", data, err)
+	}
+}
+
+func TestDocumentExt(t *testing.T) {
+	tests := map[string]string{
+		"%PDF-1.4\n...":                      ".pdf",
+		"<!DOCTYPE html><html></html>":       ".html",
+		"Invoice for order #7\nTotal: 12.50": ".txt",
+	}
+	for doc, want := range tests {
+		if got := documentExt([]byte(doc)); got != want {
+			t.Errorf("documentExt(%.10q) = %s, want %s", doc, got, want)
+		}
+	}
+}
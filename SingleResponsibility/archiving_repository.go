@@ -0,0 +1,113 @@
+package singleresponsibility
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/query"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/storage"
+)
+
+// ArchivingRepository is an OrderStore decorator that never loses an
+// order outright. Delete only marks the order deleted, and reads skip
+// deleted orders as if they were gone. Purge later moves the orders
+// older than the retention period, deleted or not, into the blob store
+// as JSON and only then removes them from the store it wraps.
+type ArchivingRepository struct {
+	next      OrderStore
+	archive   storage.Putter
+	retention time.Duration
+	clock     clock.Clock
+}
+
+// NewArchivingRepository wraps next, archiving into archive the orders
+// created more than retention ago. A nil clk means the system clock.
+func NewArchivingRepository(next OrderStore, archive storage.Putter, retention time.Duration, clk clock.Clock) *ArchivingRepository {
+	return &ArchivingRepository{next: next, archive: archive, retention: retention, clock: clock.OrSystem(clk)}
+}
+
+func (r *ArchivingRepository) Save(ctx context.Context, order Order) error {
+	return r.next.Save(ctx, order)
+}
+
+func (r *ArchivingRepository) FindByID(ctx context.Context, id int) (Order, error) {
+	order, err := r.next.FindByID(ctx, id)
+	if err != nil {
+		return Order{}, err
+	}
+	if !order.DeletedAt.IsZero() {
+		return Order{}, fmt.Errorf("%w: %d", ErrOrderNotFound, id)
+	}
+	return order, nil
+}
+
+// List pages after skipping the deleted orders, so a page is never
+// short because of them. It reads every order matching the rest of the
+// filter to do so.
+func (r *ArchivingRepository) List(ctx context.Context, filter OrderFilter) ([]Order, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	all := filter
+	all.Limit, all.Offset = 0, 0
+	orders, err := r.next.List(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+	live := orders[:0]
+	for _, order := range orders {
+		if order.DeletedAt.IsZero() {
+			live = append(live, order)
+		}
+	}
+	return query.Slice(live, query.Page{Limit: filter.Limit, Offset: filter.Offset}), nil
+}
+
+// Delete marks the order deleted. It stays in the store, hidden, until
+// Purge archives it.
+func (r *ArchivingRepository) Delete(ctx context.Context, id int) error {
+	order, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	order.DeletedAt = r.clock.Now()
+	if err := r.next.Save(ctx, order); err != nil {
+		return fmt.Errorf("deleting order %d: %w", id, err)
+	}
+	return nil
+}
+
+// ArchiveKey is where Purge archives an order: by the month it was
+// created in, such as "orders/2026-03/42.json".
+func ArchiveKey(order Order) string {
+	return fmt.Sprintf("orders/%s/%d.json", order.CreatedAt.UTC().Format("2006-01"), order.ID)
+}
+
+// Purge archives every order created more than the retention period
+// ago and removes it from the store, returning how many it moved. An
+// order is only removed once its archive is stored, so a failure
+// leaves it in place for the next Purge.
+func (r *ArchivingRepository) Purge(ctx context.Context) (int, error) {
+	cutoff := r.clock.Now().Add(-r.retention)
+	old, err := r.next.List(ctx, OrderFilter{CreatedTo: cutoff})
+	if err != nil {
+		return 0, fmt.Errorf("purging orders: %w", err)
+	}
+	for i, order := range old {
+		data, err := json.MarshalIndent(order, "", "  ")
+		if err != nil {
+			return i, fmt.Errorf("archiving order %d: %w", order.ID, err)
+		}
+		if err := r.archive.Put(ctx, ArchiveKey(order), bytes.NewReader(data)); err != nil {
+			return i, fmt.Errorf("archiving order %d: %w", order.ID, err)
+		}
+		if err := r.next.Delete(ctx, order.ID); err != nil {
+			return i, fmt.Errorf("purging order %d: %w", order.ID, err)
+		}
+	}
+	return len(old), nil
+}
@@ -0,0 +1,116 @@
+package singleresponsibility
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/query"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/storage"
+)
+
+func TestArchivingRepository(t *testing.T) {
+	stores := map[string]func(t *testing.T) OrderStore{
+		"memory": func(*testing.T) OrderStore { return NewInMemoryOrderRepository() },
+		"sql":    func(t *testing.T) OrderStore { return NewSQLOrderRepository(openSQLite(t)) },
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+			clock := clocktest.NewFake(start)
+			inner := newStore(t)
+			dir := t.TempDir()
+			repo := NewArchivingRepository(inner, storage.NewLocal(dir), 30*24*time.Hour, clock)
+
+			for id, created := range map[int]time.Time{1: start, 2: start, 3: start.AddDate(0, 0, 40)} {
+				order := testOrder(t, id)
+				order.CreatedAt = created
+				if err := repo.Save(ctx, order); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			clock.Advance(time.Hour)
+			if err := repo.Delete(ctx, 2); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := repo.FindByID(ctx, 2); !errors.Is(err, ErrOrderNotFound) {
+				t.Errorf("FindByID(deleted) = %v, want ErrOrderNotFound", err)
+			}
+			if err := repo.Delete(ctx, 2); !errors.Is(err, ErrOrderNotFound) {
+				t.Errorf("Delete(deleted) = %v, want ErrOrderNotFound", err)
+			}
+			if kept, err := inner.FindByID(ctx, 2); err != nil || !kept.DeletedAt.Equal(start.Add(time.Hour)) {
+				t.Errorf("the store has %+v, %v; want it marked deleted", kept, err)
+			}
+			page, err := repo.List(ctx, OrderFilter{Page: query.Page{Limit: 1, Offset: 1}})
+			if err != nil || len(page) != 1 || page[0].ID != 3 {
+				t.Errorf("second page = %+v, %v; want order 3", page, err)
+			}
+
+			clock.Advance(50 * 24 * time.Hour)
+			n, err := repo.Purge(ctx)
+			if err != nil || n != 2 {
+				t.Fatalf("Purge = %d, %v; want 2", n, err)
+			}
+			for _, id := range []int{1, 2} {
+				if _, err := inner.FindByID(ctx, id); !errors.Is(err, ErrOrderNotFound) {
+					t.Errorf("order %d still stored: %v", id, err)
+				}
+			}
+			if _, err := repo.FindByID(ctx, 3); err != nil {
+				t.Errorf("order 3 was purged too early: %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, "orders", "2026-03", "2.json"))
+			var archived Order
+			if err != nil || json.Unmarshal(data, &archived) != nil || archived.ID != 2 || archived.DeletedAt.IsZero() {
+				t.Errorf("archive of order 2: %s, %v", data, err)
+			}
+		})
+	}
+}
+
+func TestRunPurge(t *testing.T) {
+	dir := t.TempDir()
+	env := map[string]string{
+		"ORDERS_STORE":       "sql",
+		"ORDERS_SQL_DRIVER":  "sqlite",
+		"ORDERS_SQL_DSN":     filepath.Join(dir, "orders.db"),
+		"ORDERS_STORAGE_DIR": filepath.Join(dir, "blobs"),
+	}
+	getenv := func(k string) string { return env[k] }
+	cfg, err := LoadConfig(getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	services, err := Wire(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := testOrder(t, 1)
+	old.CreatedAt = time.Now().AddDate(-1, 0, 0).UTC()
+	if err := services.Store.Save(context.Background(), old); err != nil {
+		t.Fatal(err)
+	}
+	services.Close()
+
+	var out bytes.Buffer
+	if err := runPurge(context.Background(), nil, &out, getenv); err == nil {
+		t.Error("purge without a retention succeeded")
+	}
+	out.Reset()
+	if err := runPurge(context.Background(), []string{"-older-than", "2160h"}, &out, getenv); err != nil || !strings.Contains(out.String(), "Archived 1 orders") {
+		t.Fatalf("purge: %q, %v", out.String(), err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "blobs", ArchiveKey(old))); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,156 @@
+package singleresponsibility
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+// AuditAction names a step of the order lifecycle.
+type AuditAction string
+
+const (
+	AuditOrderSaved       AuditAction = "order.saved"
+	AuditCouponRedeemed   AuditAction = "coupon.redeemed"
+	AuditStockReserved    AuditAction = "stock.reserved"
+	AuditPaymentCharged   AuditAction = "payment.charged"
+	AuditOrderPaid        AuditAction = "order.paid"
+	AuditShipmentBooked   AuditAction = "shipment.booked"
+	AuditEmailSent        AuditAction = "email.sent"
+	AuditInvoiceGenerated AuditAction = "invoice.generated"
+	AuditOrderRolledBack  AuditAction = "order.rolled_back"
+	AuditOrderCancelled   AuditAction = "order.cancelled"
+	AuditOrderRefunded    AuditAction = "order.refunded"
+)
+
+// AuditEntry records who did what to which order, and when.
+type AuditEntry struct {
+	Actor   string
+	Action  AuditAction
+	OrderID int
+	At      time.Time
+	Detail  string
+}
+
+// AuditStore is an append-only log of audit entries.
+type AuditStore interface {
+	Append(ctx context.Context, entry AuditEntry) error
+	// ByOrder returns the entries of orderID, oldest first.
+	ByOrder(ctx context.Context, orderID int) ([]AuditEntry, error)
+}
+
+type actorKey struct{}
+
+// WithActor returns a context that attributes audited actions to
+// actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor set by WithActor, or "system".
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return "system"
+}
+
+// AuditLogService is responsible only for the audit trail: stamping
+// entries with their actor and time and appending them to an
+// AuditStore.
+type AuditLogService struct {
+	store AuditStore
+	clock clock.Clock
+}
+
+// NewAuditLogService stamps entries with clk, or the system clock if
+// clk is nil.
+func NewAuditLogService(store AuditStore, clk clock.Clock) *AuditLogService {
+	return &AuditLogService{store: store, clock: clock.OrSystem(clk)}
+}
+
+// Record appends an entry for action on orderID. The actor comes from
+// ctx; see WithActor.
+func (s *AuditLogService) Record(ctx context.Context, action AuditAction, orderID int, detail string) error {
+	entry := s.Entry(ctx, action, orderID, detail)
+	if err := s.store.Append(ctx, entry); err != nil {
+		return fmt.Errorf("recording %s for order %d: %w", action, orderID, err)
+	}
+	return nil
+}
+
+// Entry stamps an entry for action on orderID without appending it,
+// for callers that append it as part of a UnitOfWork.
+func (s *AuditLogService) Entry(ctx context.Context, action AuditAction, orderID int, detail string) AuditEntry {
+	return AuditEntry{
+		Actor:   ActorFrom(ctx),
+		Action:  action,
+		OrderID: orderID,
+		At:      s.clock.Now(),
+		Detail:  detail,
+	}
+}
+
+// History returns the audit trail of orderID, oldest first.
+func (s *AuditLogService) History(ctx context.Context, orderID int) ([]AuditEntry, error) {
+	return s.store.ByOrder(ctx, orderID)
+}
+
+// InMemoryAuditStore keeps audit entries in a slice. Entries can only
+// be appended. It is safe for concurrent use.
+type InMemoryAuditStore struct {
+	mu      sync.RWMutex
+	entries []AuditEntry
+}
+
+func NewInMemoryAuditStore() *InMemoryAuditStore {
+	return &InMemoryAuditStore{}
+}
+
+func (s *InMemoryAuditStore) Append(ctx context.Context, entry AuditEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *InMemoryAuditStore) ByOrder(ctx context.Context, orderID int) ([]AuditEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var entries []AuditEntry
+	for _, e := range s.entries {
+		if e.OrderID == orderID {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// WithAuditLog has the service record each step of the workflow in
+// audit.
+func WithAuditLog(audit *AuditLogService) OrderOption {
+	return func(os *OrderService) { os.audit = audit }
+}
+
+// record adds an entry to the audit trail. The step it records already
+// happened, so a failure is logged rather than failing the workflow.
+func (os OrderService) record(ctx context.Context, action AuditAction, orderID int, detail string) {
+	if os.audit == nil {
+		return
+	}
+	if err := os.audit.Record(ctx, action, orderID, detail); err != nil {
+		logf(ctx, os.logger(), "Audit: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+// =========================================
+// BAD EXAMPLE - Violates Single Responsibility Principle (SRP)
+// =========================================
+//
+// Definition Reminder:
+// A struct should have only ONE reason to change.
+//
+// Problem in this example:
+// OrderService is handling MULTIPLE responsibilities:
+//
+// 1. Saving order to database
+// 2. Processing payment
+// 3. Sending confirmation email
+// 4. Generating invoice
+//
+// Why this violates SRP:
+//
+// If database logic changes → this struct changes
+// If payment gateway changes → this struct changes
+// If email provider changes → this struct changes
+// If invoice format changes → this struct changes
+//
+// That means this struct has MULTIPLE reasons to change.
+//
+// This makes the code:
+//
+// ❌ Hard to maintain
+// ❌ Hard to test
+// ❌ Tightly coupled
+// ❌ Difficult to scale
+//
+// Why it is hard to test:
+//
+// - There is no seam: the "database", "gateway", "mailer" and
+//   "invoice printer" are hard-coded inside PlaceOrder, so a test
+//   cannot swap any of them for a fake.
+// - Nothing is returned: the only way to check what happened is to
+//   capture stdout and compare text.
+// - A test of the email wording also saves, charges and invoices;
+//   there is no way to exercise one responsibility alone.
+// - Nothing can fail: a declined payment cannot even be expressed,
+//   let alone tested.
+//
+// Proper design would separate these responsibilities
+// into different structs/services, as ../main.go does.
+//
+// Run it with:
+//
+//	go run badsrp/main.go
+//
+// main_test.go shows what testing it takes; compare its benchmark with
+// the one in ../main_test.go:
+//
+//	go test -bench PlaceOrder . ./badsrp
+package main
+
+import "fmt"
+
+type OrderService struct{}
+
+func (o OrderService) PlaceOrder(orderID int, amount float64) {
+
+	// Responsibility 1: Database logic
+	fmt.Printf("Saving order %d to database\n", orderID)
+
+	// Responsibility 2: Payment processing
+	fmt.Printf("Processing payment of %.2f\n", amount)
+
+	// Responsibility 3: Email sending
+	fmt.Println("Sending confirmation email")
+
+	// Responsibility 4: Invoice generation
+	fmt.Printf("Generating invoice for order %d\n", orderID)
+}
+
+func main() {
+	service := OrderService{}
+	service.PlaceOrder(1, 5000)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+// captureStdout runs fn with os.Stdout redirected and returns what it
+// printed. PlaceOrder returns nothing and takes no dependencies, so
+// this is the only way to observe it.
+func captureStdout(tb testing.TB, fn func()) string {
+	tb.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan []byte)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- out
+	}()
+	fn()
+	w.Close()
+	return string(<-done)
+}
+
+// The test can only compare printed text: it cannot check that the
+// order was stored, fake a declined payment or test the email alone.
+func TestOrderService_PlaceOrder(t *testing.T) {
+	out := captureStdout(t, func() { OrderService{}.PlaceOrder(7, 25) })
+	want := "Saving order 7 to database\n" +
+		"Processing payment of 25.00\n" +
+		"Sending confirmation email\n" +
+		"Generating invoice for order 7\n"
+	if out != want {
+		t.Errorf("printed:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+// Compare with BenchmarkOrderService_PlaceOrder in the parent
+// directory, which does real work through injected fakes; this one
+// cannot even leave out the printing.
+func BenchmarkOrderService_PlaceOrder(b *testing.B) {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer devNull.Close()
+	stdout := os.Stdout
+	os.Stdout = devNull
+	defer func() { os.Stdout = stdout }()
+
+	for b.Loop() {
+		OrderService{}.PlaceOrder(7, 25)
+	}
+}
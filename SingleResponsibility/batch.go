@@ -0,0 +1,75 @@
+package singleresponsibility
+
+import (
+	"context"
+	"sync"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/errreport"
+)
+
+// defaultBatchConcurrency is how many orders PlaceOrders places at
+// once unless WithBatchConcurrency says otherwise.
+const defaultBatchConcurrency = 4
+
+// OrderRequest is one entry of a batch: an order and the idempotency
+// key it would have been sent with on its own.
+type OrderRequest struct {
+	IdempotencyKey string
+	Order          Order
+}
+
+// OrderResult is the outcome of one OrderRequest. Exactly one of Order
+// and Err is meaningful.
+type OrderResult struct {
+	Order Order
+	Err   error
+}
+
+// WithBatchConcurrency has PlaceOrders place at most n orders at
+// once.
+func WithBatchConcurrency(n int) OrderOption {
+	return func(os *OrderService) { os.batchConcurrency = n }
+}
+
+// PlaceOrders places every order of a batch and returns one result per
+// request, in request order. Each order goes through PlaceOrder on its
+// own, so a failing order is compensated alone and does not affect
+// the others. Once ctx is done, orders not yet started fail with the
+// context's error.
+func (os OrderService) PlaceOrders(ctx context.Context, reqs []OrderRequest) []OrderResult {
+	limit := os.batchConcurrency
+	if limit < 1 {
+		limit = defaultBatchConcurrency
+	}
+
+	results := make([]OrderResult, len(reqs))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for i, req := range reqs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			// A panic fails this order alone instead of the whole
+			// process.
+			var order Order
+			err := errreport.Guard(ctx, os.reporter, errorTags(ctx, "PlaceOrders"), func() error {
+				var err error
+				order, err = os.PlaceOrder(ctx, req.IdempotencyKey, req.Order)
+				return err
+			})
+			results[i] = OrderResult{Order: order, Err: err}
+		}()
+	}
+	wg.Wait()
+	return results
+}
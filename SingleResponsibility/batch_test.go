@@ -0,0 +1,138 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// chargeFunc is a PaymentGateway whose charges call f.
+type chargeFunc func(ctx context.Context, orderID int) error
+
+func (f chargeFunc) Charge(ctx context.Context, orderID int, amount Money) (string, error) {
+	if err := f(ctx, orderID); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("ch_%d", orderID), nil
+}
+
+func (f chargeFunc) Refund(ctx context.Context, paymentID string) error { return nil }
+
+func newBatchService(t *testing.T, charge chargeFunc, concurrency int) OrderService {
+	t.Helper()
+	orders, err := NewOrderService(NewInMemoryOrderRepository(), charge, NewLoggingEmailSender(nil), fakeInvoicer{&callLog{}, nil},
+		WithBatchConcurrency(concurrency))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *orders
+}
+
+func batchOf(t *testing.T, ids ...int) []OrderRequest {
+	reqs := make([]OrderRequest, len(ids))
+	for i, id := range ids {
+		reqs[i] = OrderRequest{Order: testOrder(t, id)}
+	}
+	return reqs
+}
+
+// Each order succeeds or fails on its own, and the results come back
+// in request order however the orders interleave.
+func TestOrderService_PlaceOrders_MixedResults(t *testing.T) {
+	orders := newBatchService(t, func(ctx context.Context, orderID int) error {
+		// Later orders finish first.
+		time.Sleep(time.Duration(10-orderID) * time.Millisecond)
+		if orderID%2 == 0 {
+			return ErrPaymentDeclined
+		}
+		return nil
+	}, 8)
+
+	results := orders.PlaceOrders(context.Background(), batchOf(t, 1, 2, 3, 4, 5, 6))
+	if len(results) != 6 {
+		t.Fatalf("%d results, want 6", len(results))
+	}
+	for i, res := range results {
+		id := i + 1
+		if id%2 == 0 {
+			if !errors.Is(res.Err, ErrPaymentDeclined) {
+				t.Errorf("result %d: %v, want declined", i, res.Err)
+			}
+			continue
+		}
+		if res.Err != nil || res.Order.ID != id || res.Order.Status != StatusInvoiced {
+			t.Errorf("result %d: %+v, %v; want order %d invoiced", i, res.Order, res.Err, id)
+		}
+	}
+}
+
+func TestOrderService_PlaceOrders_ConcurrencyLimit(t *testing.T) {
+	const limit = 2
+	var (
+		mu       sync.Mutex
+		inFlight int
+		peak     int
+	)
+	release := make(chan struct{})
+	full := make(chan struct{}, 1)
+	orders := newBatchService(t, func(ctx context.Context, orderID int) error {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		if inFlight == limit {
+			select {
+			case full <- struct{}{}:
+			default:
+			}
+		}
+		mu.Unlock()
+		<-release
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return nil
+	}, limit)
+
+	done := make(chan []OrderResult)
+	go func() { done <- orders.PlaceOrders(context.Background(), batchOf(t, 1, 2, 3, 4, 5)) }()
+	<-full
+	// Give a third order the chance to start, if the limit let it.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	results := <-done
+
+	for i, res := range results {
+		if res.Err != nil {
+			t.Errorf("result %d: %v", i, res.Err)
+		}
+	}
+	if peak != limit {
+		t.Errorf("%d orders were charged at once, want %d", peak, limit)
+	}
+}
+
+// Once ctx is cancelled, the orders not yet placed fail with its error
+// and are never charged.
+func TestOrderService_PlaceOrders_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var charged []int
+	orders := newBatchService(t, func(ctx context.Context, orderID int) error {
+		charged = append(charged, orderID)
+		cancel()
+		return nil
+	}, 1)
+
+	results := orders.PlaceOrders(ctx, batchOf(t, 1, 2, 3))
+	if len(charged) != 1 || charged[0] != 1 {
+		t.Fatalf("charged %v, want only order 1", charged)
+	}
+	for i, res := range results[1:] {
+		if !errors.Is(res.Err, context.Canceled) {
+			t.Errorf("result %d: %+v, %v; want cancelled", i+1, res.Order, res.Err)
+		}
+	}
+}
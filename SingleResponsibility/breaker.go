@@ -0,0 +1,62 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/breaker"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/timeout"
+)
+
+// IsPaymentGatewayFailure reports whether err says something about the
+// gateway's health: it was unavailable or did not answer in time. A
+// declined card or an invalid amount does not count.
+func IsPaymentGatewayFailure(err error) bool {
+	return IsTransientPaymentError(err) || errors.Is(err, timeout.ErrDeadlineExceeded)
+}
+
+// BreakerPaymentGateway is a PaymentGateway decorator that stops
+// calling a gateway that keeps failing. While its breaker is open,
+// calls fail at once with an error matching both breaker.ErrOpen and
+// ErrGatewayUnavailable, so callers treat it like any outage.
+type BreakerPaymentGateway struct {
+	next    PaymentGateway
+	breaker *breaker.Breaker
+}
+
+// NewBreakerPaymentGateway wraps next in b. b should count only
+// IsPaymentGatewayFailure errors as failures.
+func NewBreakerPaymentGateway(next PaymentGateway, b *breaker.Breaker) *BreakerPaymentGateway {
+	return &BreakerPaymentGateway{next: next, breaker: b}
+}
+
+func (g *BreakerPaymentGateway) Charge(ctx context.Context, orderID int, amount Money) (string, error) {
+	paymentID, err := breaker.Value(ctx, g.breaker, func(ctx context.Context) (string, error) {
+		return g.next.Charge(ctx, orderID, amount)
+	})
+	return paymentID, unavailableIfOpen(err)
+}
+
+func (g *BreakerPaymentGateway) Refund(ctx context.Context, paymentID string) error {
+	return unavailableIfOpen(g.breaker.Do(ctx, func(ctx context.Context) error {
+		return g.next.Refund(ctx, paymentID)
+	}))
+}
+
+func (g *BreakerPaymentGateway) Void(ctx context.Context, paymentID string) error {
+	voider, ok := g.next.(PaymentVoider)
+	if !ok {
+		return ErrVoidUnsupported
+	}
+	return unavailableIfOpen(g.breaker.Do(ctx, func(ctx context.Context) error {
+		return voider.Void(ctx, paymentID)
+	}))
+}
+
+func unavailableIfOpen(err error) error {
+	if errors.Is(err, breaker.ErrOpen) {
+		return fmt.Errorf("%w: %w", ErrGatewayUnavailable, err)
+	}
+	return err
+}
@@ -0,0 +1,91 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/breaker"
+)
+
+// countingGateway counts the charges that reach it and fails them
+// with err.
+type countingGateway struct {
+	hangingGateway
+	err   error
+	calls int
+}
+
+func (g *countingGateway) Charge(ctx context.Context, orderID int, amount Money) (string, error) {
+	g.calls++
+	return "", g.err
+}
+
+func TestBreakerPaymentGateway(t *testing.T) {
+	ctx := context.Background()
+	amount := NewMoney(1250, "USD")
+	newGateway := func(err error) (*countingGateway, *BreakerPaymentGateway) {
+		next := &countingGateway{err: err}
+		return next, NewBreakerPaymentGateway(next, breaker.New(breaker.Settings{
+			Name:             "payment",
+			FailureThreshold: 2,
+			IsFailure:        IsPaymentGatewayFailure,
+		}))
+	}
+
+	t.Run("outage trips", func(t *testing.T) {
+		next, g := newGateway(ErrGatewayUnavailable)
+		for range 2 {
+			g.Charge(ctx, 1, amount)
+		}
+		_, err := g.Charge(ctx, 1, amount)
+		if !errors.Is(err, breaker.ErrOpen) || !errors.Is(err, ErrGatewayUnavailable) {
+			t.Fatalf("err = %v, want an open breaker", err)
+		}
+		if statusFor(err) != http.StatusServiceUnavailable {
+			t.Errorf("statusFor = %d, want 503", statusFor(err))
+		}
+		if next.calls != 2 {
+			t.Errorf("gateway called %d times, want 2", next.calls)
+		}
+	})
+
+	t.Run("declines do not trip", func(t *testing.T) {
+		next, g := newGateway(ErrPaymentDeclined)
+		for range 3 {
+			if _, err := g.Charge(ctx, 1, amount); !errors.Is(err, ErrPaymentDeclined) {
+				t.Fatalf("err = %v, want ErrPaymentDeclined", err)
+			}
+		}
+		if next.calls != 3 {
+			t.Errorf("gateway called %d times, want 3", next.calls)
+		}
+	})
+}
+
+func TestWire_PaymentBreaker(t *testing.T) {
+	ctx := context.Background()
+	cfg := DefaultConfig()
+	cfg.Gateway = "paypal"
+	cfg.PayPalFailEvery = 1
+	cfg.BreakerThreshold = 2
+	services, err := Wire(ctx, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer services.Close()
+
+	var errs []error
+	for id := 1; id <= 3; id++ {
+		order, err := NewOrder(id, 1, []OrderItem{{SKU: "BOOK", Quantity: 1, UnitPrice: NewMoney(1250, cfg.Currency)}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = services.Orders.PlaceOrder(ctx, "", order)
+		errs = append(errs, err)
+	}
+	if errors.Is(errs[1], breaker.ErrOpen) || !errors.Is(errs[2], breaker.ErrOpen) {
+		t.Errorf("errors = %v; want the third charge refused by the breaker", errs)
+	}
+}
@@ -0,0 +1,140 @@
+package singleresponsibility
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/cache"
+)
+
+// CachedOrderStore is an OrderStore decorator that serves FindByID from
+// a cache. Save and Delete invalidate the order's entry after writing
+// through, so a reader sees a stale order for at most the cache's ttl
+// if the invalidation itself fails. List always goes to the store: its
+// results depend on every order and would need invalidating on every
+// write.
+type CachedOrderStore struct {
+	next   OrderStore
+	orders *cache.Typed[Order]
+}
+
+// NewCachedOrderStore caches next's orders in c for ttl.
+func NewCachedOrderStore(next OrderStore, c cache.Cache, ttl time.Duration) *CachedOrderStore {
+	return &CachedOrderStore{next: next, orders: cache.NewTyped[Order](c, "order:", ttl)}
+}
+
+func (s *CachedOrderStore) Save(ctx context.Context, order Order) error {
+	if err := s.next.Save(ctx, order); err != nil {
+		return err
+	}
+	s.orders.Delete(ctx, strconv.Itoa(order.ID))
+	return nil
+}
+
+// FindByID returns the cached order, or the stored one, which it
+// caches. Missing orders are not cached.
+func (s *CachedOrderStore) FindByID(ctx context.Context, id int) (Order, error) {
+	return s.orders.GetOrLoad(ctx, strconv.Itoa(id), func(ctx context.Context) (Order, error) {
+		return s.next.FindByID(ctx, id)
+	})
+}
+
+func (s *CachedOrderStore) List(ctx context.Context, filter OrderFilter) ([]Order, error) {
+	return s.next.List(ctx, filter)
+}
+
+func (s *CachedOrderStore) Delete(ctx context.Context, id int) error {
+	if err := s.next.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.orders.Delete(ctx, strconv.Itoa(id))
+	return nil
+}
+
+// UnitOfWork returns a UnitOfWork decorator that invalidates the
+// entries of the orders next saved once it commits, as Save does. next
+// must write to the store s caches.
+func (s *CachedOrderStore) UnitOfWork(next UnitOfWork) UnitOfWork {
+	return cachedUnitOfWork{next: next, orders: s.orders}
+}
+
+type cachedUnitOfWork struct {
+	next   UnitOfWork
+	orders *cache.Typed[Order]
+}
+
+func (u cachedUnitOfWork) Do(ctx context.Context, fn func(tx Tx) error) error {
+	var saved []int
+	err := u.next.Do(ctx, func(tx Tx) error {
+		return fn(cachedTx{Tx: tx, saved: &saved})
+	})
+	if err != nil {
+		return err
+	}
+	for _, id := range saved {
+		u.orders.Delete(ctx, strconv.Itoa(id))
+	}
+	return nil
+}
+
+// cachedTx remembers which orders a unit of work saved.
+type cachedTx struct {
+	Tx
+	saved *[]int
+}
+
+func (tx cachedTx) SaveOrder(ctx context.Context, order Order) error {
+	if err := tx.Tx.SaveOrder(ctx, order); err != nil {
+		return err
+	}
+	*tx.saved = append(*tx.saved, order.ID)
+	return nil
+}
+
+// CachedInvoiceGenerator is an InvoiceGenerator decorator that keeps
+// the invoices it generated. An invoice is cached under its order's ID
+// and a hash of everything it is generated from, so an order or
+// customer that changed gets a new invoice without any invalidation.
+type CachedInvoiceGenerator struct {
+	next  InvoiceGenerator
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// NewCachedInvoiceGenerator caches next's invoices in c for ttl.
+func NewCachedInvoiceGenerator(next InvoiceGenerator, c cache.Cache, ttl time.Duration) *CachedInvoiceGenerator {
+	return &CachedInvoiceGenerator{next: next, cache: c, ttl: ttl}
+}
+
+func (g *CachedInvoiceGenerator) Generate(ctx context.Context, customer Customer, order Order) ([]byte, error) {
+	key, err := invoiceCacheKey(customer, order)
+	if err != nil {
+		return nil, err
+	}
+	if doc, err := g.cache.Get(ctx, key); err == nil {
+		return doc, nil
+	}
+	doc, err := g.next.Generate(ctx, customer, order)
+	if err != nil {
+		return nil, err
+	}
+	g.cache.Set(ctx, key, doc, g.ttl)
+	return doc, nil
+}
+
+func invoiceCacheKey(customer Customer, order Order) (string, error) {
+	inputs, err := json.Marshal(struct {
+		Customer Customer
+		Order    Order
+	}{customer, order})
+	if err != nil {
+		return "", fmt.Errorf("hashing invoice inputs: %w", err)
+	}
+	sum := sha256.Sum256(inputs)
+	return fmt.Sprintf("invoice:%d:%s", order.ID, hex.EncodeToString(sum[:16])), nil
+}
@@ -0,0 +1,134 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/cache"
+)
+
+// countingStore counts the FindByID calls reaching the repository.
+type countingStore struct {
+	*InMemoryOrderRepository
+	finds int
+}
+
+func (s *countingStore) FindByID(ctx context.Context, id int) (Order, error) {
+	s.finds++
+	return s.InMemoryOrderRepository.FindByID(ctx, id)
+}
+
+func TestCachedOrderStore(t *testing.T) {
+	ctx := context.Background()
+	next := &countingStore{InMemoryOrderRepository: NewInMemoryOrderRepository()}
+	store := NewCachedOrderStore(next, cache.NewLRU(10, nil), time.Minute)
+
+	order := testOrder(t, 1)
+	if err := store.Save(ctx, order); err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		if _, err := store.FindByID(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if next.finds != 1 {
+		t.Errorf("store read %d times, want 1", next.finds)
+	}
+
+	order.Status = StatusPaid
+	order.Version = 1
+	if err := store.Save(ctx, order); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.FindByID(ctx, 1); err != nil || got.Status != StatusPaid {
+		t.Errorf("FindByID after Save = %v, %v; want the saved order", got.Status, err)
+	}
+
+	if err := store.Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if _, err := store.FindByID(ctx, 1); !errors.Is(err, ErrOrderNotFound) {
+			t.Errorf("FindByID after Delete = %v, want ErrOrderNotFound", err)
+		}
+	}
+	if next.finds != 4 {
+		t.Errorf("store read %d times, want 4: a missing order must not be cached", next.finds)
+	}
+}
+
+func TestCachedInvoiceGenerator(t *testing.T) {
+	ctx := context.Background()
+	generated := 0
+	gen := NewCachedInvoiceGenerator(invoiceFunc(func(ctx context.Context, customer Customer, order Order) ([]byte, error) {
+		generated++
+		return []byte("invoice for " + customer.Name), nil
+	}), cache.NewLRU(10, nil), time.Minute)
+
+	customer := Customer{ID: 1, Name: "Ada"}
+	order := testOrder(t, 1)
+	for range 3 {
+		if doc, err := gen.Generate(ctx, customer, order); err != nil || string(doc) != "invoice for Ada" {
+			t.Fatalf("Generate = %q, %v", doc, err)
+		}
+	}
+	if generated != 1 {
+		t.Errorf("generated %d invoices, want 1", generated)
+	}
+
+	customer.Name = "Grace"
+	if doc, err := gen.Generate(ctx, customer, order); err != nil || string(doc) != "invoice for Grace" {
+		t.Errorf("Generate for a changed customer = %q, %v", doc, err)
+	}
+	order.Status = StatusPaid
+	gen.Generate(ctx, customer, order)
+	if generated != 3 {
+		t.Errorf("generated %d invoices, want 3: changed inputs need a new invoice", generated)
+	}
+}
+
+func TestWire_Cache(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Cache = "memory"
+	services, err := Wire(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer services.Close()
+	if _, ok := services.Store.(*CachedOrderStore); !ok {
+		t.Errorf("Store is %T, want a *CachedOrderStore", services.Store)
+	}
+
+	cfg.Cache = "redis"
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Validate without redis_addr = %v, want ErrInvalidConfig", err)
+	}
+}
+
+// An order saved through a unit of work is not served stale from the
+// cache afterwards.
+func TestCachedOrderStore_UnitOfWork(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryOrderRepository()
+	store := NewCachedOrderStore(repo, cache.NewLRU(10, nil), time.Minute)
+	order := testOrder(t, 1)
+	if err := store.Save(ctx, order); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.FindByID(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	uow := store.UnitOfWork(NewInMemoryUnitOfWork(repo, nil, nil))
+	order.Status = StatusPaid
+	order.Version = 1
+	if err := uow.Do(ctx, func(tx Tx) error { return tx.SaveOrder(ctx, order) }); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.FindByID(ctx, 1); err != nil || got.Status != StatusPaid {
+		t.Errorf("FindByID = %s, %v; want the order saved by the unit of work", got.Status, err)
+	}
+}
@@ -0,0 +1,74 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrVoidUnsupported is returned by a decorator's Void when the
+// gateway it wraps cannot void charges.
+var ErrVoidUnsupported = errors.New("payment gateway cannot void charges")
+
+// PaymentVoider is implemented by gateways that can cancel a charge
+// before it settles, which is cheaper than refunding it and never shows
+// on the customer's statement. Decorators implement it by forwarding to
+// the gateway they wrap and return ErrVoidUnsupported if it cannot.
+type PaymentVoider interface {
+	Void(ctx context.Context, paymentID string) error
+}
+
+// CancelOrder cancels an order that has not shipped yet. Money is
+// given back first: a Paid order's charge is voided when the gateway
+// supports it, and an Invoiced order's charge, which has settled, is
+// refunded. If that fails, the order is left as it was.
+//
+// Once the order is saved as Cancelled, its stock reservation and
+// shipment are released. Those failures are logged rather than
+// returned, because the cancellation itself already happened.
+func (os OrderService) CancelOrder(ctx context.Context, orderID int) (Order, error) {
+	order, err := os.repo.FindByID(ctx, orderID)
+	if err != nil {
+		return Order{}, err
+	}
+	if !CanTransition(order.Status, StatusCancelled) {
+		return Order{}, fmt.Errorf("%w: order %d from %s to %s", ErrInvalidTransition, order.ID, order.Status, StatusCancelled)
+	}
+
+	detail := "no payment"
+	if order.PaymentID != "" {
+		if detail, err = os.returnPayment(ctx, order); err != nil {
+			return Order{}, fmt.Errorf("cancelling order %d: %w", order.ID, err)
+		}
+	}
+
+	// The money is back; finish even if the caller gives up now.
+	ctx = context.WithoutCancel(ctx)
+	if err := os.advance(ctx, &order, StatusCancelled); err != nil {
+		return Order{}, fmt.Errorf("payment %s returned but cancelling order %d failed: %w", order.PaymentID, order.ID, err)
+	}
+	os.record(ctx, AuditOrderCancelled, order.ID, detail)
+
+	os.cancelSteps(ctx, order)
+
+	logf(ctx, os.logger(), "Order %d cancelled (%s)", order.ID, detail)
+	return order, nil
+}
+
+// returnPayment voids or refunds the charge of order and describes
+// what it did.
+func (os OrderService) returnPayment(ctx context.Context, order Order) (string, error) {
+	if voider, ok := os.payment.(PaymentVoider); ok && order.Status == StatusPaid {
+		err := voider.Void(ctx, order.PaymentID)
+		if err == nil {
+			return "voided " + order.PaymentID, nil
+		}
+		if !errors.Is(err, ErrVoidUnsupported) {
+			return "", err
+		}
+	}
+	if err := os.payment.Refund(ctx, order.PaymentID); err != nil {
+		return "", err
+	}
+	return "refunded " + order.PaymentID, nil
+}
@@ -0,0 +1,155 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+func TestOrderService_CancelOrder(t *testing.T) {
+	tests := []struct {
+		from    OrderStatus
+		want    OrderStatus
+		detail  string // prefix of the audit detail
+		wantErr error
+	}{
+		{from: StatusPending, want: StatusCancelled, detail: "no payment"},
+		{from: StatusPaid, want: StatusCancelled, detail: "voided "},
+		{from: StatusInvoiced, want: StatusCancelled, detail: "refunded "},
+		{from: StatusShipped, want: StatusShipped, wantErr: ErrInvalidTransition},
+		{from: StatusCancelled, want: StatusCancelled, wantErr: ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from), func(t *testing.T) {
+			ctx := context.Background()
+			repo := NewInMemoryOrderRepository()
+			gateway := NewFakeStripeGateway(NewMoney(10000, "USD"), nil)
+			stock := NewInMemoryStockRepository()
+			stock.SetStock("BOOK", 5)
+			audit := NewInMemoryAuditStore()
+			orders, err := NewOrderService(repo, gateway, NewLoggingEmailSender(nil),
+				NewInvoiceService(TextInvoiceRenderer{}, NewPricingService(nil), nil),
+				WithInventory(NewInventoryService(stock, nil)),
+				WithAuditLog(NewAuditLogService(audit, clock.System{})))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			order := testOrder(t, 1)
+			order.Status = tt.from
+			if tt.from != StatusPending {
+				if order.PaymentID, err = gateway.Charge(ctx, order.ID, order.Total); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := stock.Reserve(ctx, order.ID, order.Items); err != nil {
+				t.Fatal(err)
+			}
+			if err := repo.Save(ctx, order); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = orders.CancelOrder(ctx, order.ID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CancelOrder = %v, want %v", err, tt.wantErr)
+			}
+			stored, _ := repo.FindByID(ctx, order.ID)
+			if stored.Status != tt.want {
+				t.Errorf("status %s, want %s", stored.Status, tt.want)
+			}
+
+			entries, _ := audit.ByOrder(ctx, order.ID)
+			if tt.wantErr != nil {
+				if stock.Available("BOOK") != 3 || len(entries) != 0 {
+					t.Errorf("rejected cancel released stock (%d) or recorded %+v", stock.Available("BOOK"), entries)
+				}
+				return
+			}
+			if stock.Available("BOOK") != 5 {
+				t.Errorf("stock not released: %d available", stock.Available("BOOK"))
+			}
+			if len(entries) != 1 || entries[0].Action != AuditOrderCancelled || !strings.HasPrefix(entries[0].Detail, tt.detail) {
+				t.Errorf("audit %+v, want one %s entry %q...", entries, AuditOrderCancelled, tt.detail)
+			}
+			if order.PaymentID != "" {
+				if err := gateway.Refund(ctx, order.PaymentID); !errors.Is(err, ErrAlreadyRefunded) {
+					t.Errorf("payment not returned: Refund = %v", err)
+				}
+			}
+		})
+	}
+}
+
+// A decorator around a gateway that cannot void reports
+// ErrVoidUnsupported, and CancelOrder refunds instead.
+func TestOrderService_CancelOrder_RefundsWhenVoidUnsupported(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryOrderRepository()
+	log := &callLog{}
+	payment := NewMeteredPaymentGateway(fakeGateway{log: log}, NewMetricsRegistry(clock.System{}, nil))
+	orders, err := NewOrderService(repo, payment, NewLoggingEmailSender(nil),
+		NewInvoiceService(TextInvoiceRenderer{}, NewPricingService(nil), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := testOrder(t, 1)
+	order.Status, order.PaymentID = StatusPaid, "pay_1"
+	if err := repo.Save(ctx, order); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := orders.CancelOrder(ctx, order.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(log.all(), ","); got != "payment.Refund pay_1" {
+		t.Errorf("payment calls %q", got)
+	}
+}
+
+// Wire wraps the gateway in every payment decorator; a Paid order's
+// charge must still be voided through them.
+func TestWire_CancelOrderVoidsCharge(t *testing.T) {
+	ctx := context.Background()
+	cfg := DefaultConfig()
+	cfg.PaymentTimeout = Duration(time.Second)
+	cfg.PaymentAttempts = 3
+	cfg.BreakerThreshold = 5
+	cfg.BreakerOpenTimeout = Duration(time.Minute)
+	log := &CapturingLogger{}
+	services, err := Wire(ctx, cfg, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer services.Close()
+
+	order := testOrder(t, 1)
+	if order.PaymentID, err = services.Orders.payment.Charge(ctx, order.ID, order.Total); err != nil {
+		t.Fatal(err)
+	}
+	order.Status = StatusPaid
+	if err := services.Store.Save(ctx, order); err != nil {
+		t.Fatal(err)
+	}
+
+	cancelled, err := services.Commands.CancelOrder(ctx, order.ID)
+	if err != nil || cancelled.Status != StatusCancelled {
+		t.Fatalf("CancelOrder = %+v, %v", cancelled, err)
+	}
+	var voided bool
+	for _, line := range log.Lines() {
+		voided = voided || strings.Contains(line, "Stripe voided "+order.PaymentID)
+	}
+	if !voided {
+		t.Errorf("charge not voided; log:\n%s", strings.Join(log.Lines(), "\n"))
+	}
+	for _, op := range services.Metrics.Snapshot() {
+		if op.Name == "payment.void" && op.Calls == 1 && op.Errors == 0 {
+			return
+		}
+	}
+	t.Errorf("no payment.void metric in %+v", services.Metrics.Snapshot())
+}
@@ -0,0 +1,197 @@
+package singleresponsibility
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Run runs the example as a small CLI. args is the command line without
+// the program name:
+//
+//	go run ./cmd/orders -item BOOK:2:12.50 -item PEN:1:1.99 -pay paypal
+//
+// Without -item it prompts for items on stdin. The wiring comes from
+// LoadConfig; the flags override the payment method and invoice format.
+//
+// "serve" as the first argument runs the HTTP API instead; see
+// runServer. "purge" archives old orders; see runPurge. A "store":
+// "sql" config needs its database/sql driver registered by the
+// program; cmd/orders registers "sqlite".
+func Run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer, getenv func(string) string) error {
+	switch {
+	case len(args) > 0 && args[0] == "serve":
+		return runServer(ctx, args[1:], stdout, getenv)
+	case len(args) > 0 && args[0] == "purge":
+		return runPurge(ctx, args[1:], stdout, getenv)
+	}
+	return runCLI(ctx, args, stdin, stdout, getenv)
+}
+
+// itemFlags collects repeated -item SKU:QTY:PRICE flags.
+type itemFlags []string
+
+func (f *itemFlags) String() string     { return strings.Join(*f, ",") }
+func (f *itemFlags) Set(v string) error { *f = append(*f, v); return nil }
+
+// runCLI places one order from the command line; see Run.
+func runCLI(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer, getenv func(string) string) error {
+	fs := flag.NewFlagSet("orders", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	customerID := fs.Int("customer", 1, "customer `id`")
+	pay := fs.String("pay", "", "payment method: stripe or paypal (default from config)")
+	format := fs.String("invoice", "", "invoice format: text, html or pdf (default from config)")
+	out := fs.String("out", "", "write the invoice to `file` instead of stdout")
+	verbose := fs.Bool("v", false, "log every step of the workflow")
+	var items itemFlags
+	fs.Var(&items, "item", "order line as `SKU:QTY:PRICE`; repeatable")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := LoadConfig(getenv)
+	if err != nil {
+		return err
+	}
+	if *pay != "" {
+		cfg.Gateway = *pay
+	}
+	if *format != "" {
+		cfg.InvoiceFormat = *format
+	}
+	// Refuse before the order is placed, not after it is charged.
+	if cfg.InvoiceFormat == "pdf" && *out == "" {
+		return errors.New("refusing to print a PDF invoice to the terminal; use -out")
+	}
+
+	var log Logger = NopLogger{}
+	if *verbose {
+		log = WriterLogger{W: stdout}
+	}
+	services, err := Wire(ctx, cfg, log)
+	if err != nil {
+		return err
+	}
+	defer services.Close()
+
+	if len(items) == 0 {
+		if items, err = promptItems(stdin, stdout); err != nil {
+			return err
+		}
+	}
+	lines, err := parseItems(items, cfg.Currency)
+	if err != nil {
+		return err
+	}
+
+	// The invoice document travels on the InvoiceGenerated event, so
+	// the CLI listens for it like any other subscriber.
+	var invoice []byte
+	services.Events.Subscribe(EventInvoiceGenerated, func(_ context.Context, e Event) error {
+		invoice = e.(InvoiceGenerated).Document
+		return nil
+	})
+
+	order, err := NewOrder(services.NextOrderID(), *customerID, lines)
+	if err != nil {
+		return err
+	}
+	placed, err := services.Commands.PlaceOrder(ctx, "", order)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Order %d is %s, paid %s via %s (%s)\n", placed.ID, placed.Status, placed.Total, cfg.Gateway, placed.PaymentID)
+	if *verbose {
+		services.Metrics.Export(stdout)
+	}
+	if *out != "" {
+		if err := os.WriteFile(*out, invoice, 0o644); err != nil {
+			return fmt.Errorf("writing invoice: %w", err)
+		}
+		fmt.Fprintf(stdout, "Invoice written to %s\n", *out)
+		return nil
+	}
+	_, err = fmt.Fprintf(stdout, "\n%s", invoice)
+	return err
+}
+
+// runPurge moves the orders older than the retention period, deleted
+// or not, from the order store into the blob store:
+//
+//	go run ./cmd/orders purge -older-than 2160h
+//
+// Without -older-than the retention is archive_after from the config.
+func runPurge(ctx context.Context, args []string, stdout io.Writer, getenv func(string) string) error {
+	fs := flag.NewFlagSet("orders purge", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	olderThan := fs.Duration("older-than", 0, "archive the orders created more than `age` ago (default archive_after)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := LoadConfig(getenv)
+	if err != nil {
+		return err
+	}
+	if *olderThan > 0 {
+		cfg.ArchiveAfter = Duration(*olderThan)
+	}
+	if cfg.ArchiveAfter <= 0 {
+		return errors.New("purge needs -older-than or archive_after")
+	}
+	services, err := Wire(ctx, cfg, NopLogger{})
+	if err != nil {
+		return err
+	}
+	defer services.Close()
+
+	n, err := services.Archive.Purge(ctx)
+	fmt.Fprintf(stdout, "Archived %d orders\n", n)
+	return err
+}
+
+// promptItems reads order lines from in until an empty line or EOF.
+func promptItems(in io.Reader, out io.Writer) ([]string, error) {
+	fmt.Fprintln(out, "Enter items as SKU:QTY:PRICE, one per line; an empty line finishes.")
+	var items []string
+	sc := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "> ")
+		if !sc.Scan() {
+			break
+		}
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			break
+		}
+		items = append(items, line)
+	}
+	return items, sc.Err()
+}
+
+func parseItems(specs []string, currency Currency) ([]OrderItem, error) {
+	items := make([]OrderItem, 0, len(specs))
+	for _, spec := range specs {
+		parts := strings.Split(spec, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("%w: %q is not SKU:QTY:PRICE", ErrInvalidItem, spec)
+		}
+		qty, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: quantity: %w", ErrInvalidItem, spec, err)
+		}
+		price, err := ParseMoney(parts[2], currency)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidItem, spec, err)
+		}
+		items = append(items, OrderItem{SKU: parts[0], Quantity: qty, UnitPrice: price})
+	}
+	return items, nil
+}
@@ -0,0 +1,77 @@
+package singleresponsibility
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCLI(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		args    []string
+		stdin   string
+		wantErr string
+		want    []string // substrings of stdout, in order
+	}{
+		{"text invoice", []string{"-item", "BOOK:2:12.50", "-item", "PEN:1:1.99"}, "",
+			"", []string{"Order 1 is invoiced, paid", "via stripe (ch_", "BOOK"}},
+		{"paypal", []string{"-pay", "paypal", "-item", "BOOK:1:12.50"}, "",
+			"", []string{"via paypal"}},
+		{"items from stdin", nil, "BOOK:1:12.50\nPEN:2:1.99\n\n",
+			"", []string{"Enter items", "Order 1 is invoiced", "PEN"}},
+		{"pdf to a file", []string{"-invoice", "pdf", "-out", filepath.Join(dir, "invoice.pdf"), "-item", "BOOK:1:12.50"}, "",
+			"", []string{"Order 1 is invoiced", "Invoice written to"}},
+		{"bad item", []string{"-item", "BOOK:two:12.50"}, "",
+			"invalid order item", nil},
+		{"unknown flag", []string{"-nope"}, "",
+			"flag provided but not defined", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			env := map[string]string{"ORDERS_STORAGE_DIR": t.TempDir()}
+			err := runCLI(context.Background(), tt.args, strings.NewReader(tt.stdin), &stdout, func(k string) string { return env[k] })
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("runCLI = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("runCLI = %v\n%s", err, &stdout)
+			}
+			out := stdout.String()
+			for _, want := range tt.want {
+				i := strings.Index(out, want)
+				if i < 0 {
+					t.Fatalf("output lacks %q:\n%s", want, stdout.String())
+				}
+				out = out[i+len(want):]
+			}
+		})
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "invoice.pdf")); err != nil || !bytes.HasPrefix(data, []byte("%PDF-")) {
+		t.Errorf("invoice.pdf: %.20q, %v", data, err)
+	}
+}
+
+// A PDF invoice with nowhere to go is refused before the order is
+// placed, so the customer is not charged for it.
+func TestRunCLI_PDFWithoutOut(t *testing.T) {
+	var stdout bytes.Buffer
+	env := map[string]string{"ORDERS_STORAGE_DIR": t.TempDir()}
+	err := runCLI(context.Background(), []string{"-v", "-invoice", "pdf", "-item", "BOOK:1:12.50"}, strings.NewReader(""), &stdout, func(k string) string { return env[k] })
+	if err == nil || !strings.Contains(err.Error(), "use -out") {
+		t.Fatalf("runCLI = %v, want the PDF refused", err)
+	}
+	// -v logs every step of the workflow; none ran.
+	if stdout.Len() != 0 {
+		t.Errorf("output:\n%s\nwant none", &stdout)
+	}
+}
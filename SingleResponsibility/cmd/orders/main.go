@@ -0,0 +1,29 @@
+// Command orders runs the SingleResponsibility example: it places an
+// order from the command line, serves the order API or purges old
+// orders. See singleresponsibility.Run.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/anil-vinnakoti/go-SOLID/SingleResponsibility"
+
+	// The sqlite driver behind "store": "sql", so the binary can use
+	// it without a cgo toolchain. Other drivers are imported the same
+	// way.
+	_ "modernc.org/sqlite"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := singleresponsibility.Run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Getenv); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,110 @@
+package singleresponsibility
+
+import (
+	"context"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/commandbus"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/middleware"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/validate"
+)
+
+// PlaceOrderCommand, RefundOrderCommand and CancelOrderCommand are the
+// order commands. The transports build them and dispatch them through
+// Commands instead of calling the services.
+type PlaceOrderCommand struct {
+	IdempotencyKey string
+	Order          Order
+}
+
+func (PlaceOrderCommand) CommandName() string { return "PlaceOrder" }
+
+func (c PlaceOrderCommand) Validate() error {
+	var errs validate.Errors
+	if c.Order.ID <= 0 {
+		errs.Add("order.id", "must be positive")
+	}
+	if len(c.Order.Items) == 0 {
+		errs.Add("order.items", "must not be empty")
+	}
+	return errs.Err()
+}
+
+type RefundOrderCommand struct{ OrderID int }
+
+func (RefundOrderCommand) CommandName() string { return "RefundOrder" }
+
+func (c RefundOrderCommand) Validate() error { return validOrderID(c.OrderID) }
+
+type CancelOrderCommand struct{ OrderID int }
+
+func (CancelOrderCommand) CommandName() string { return "CancelOrder" }
+
+func (c CancelOrderCommand) Validate() error { return validOrderID(c.OrderID) }
+
+func validOrderID(id int) error {
+	var errs validate.Errors
+	if id <= 0 {
+		errs.Add("order_id", "must be positive")
+	}
+	return errs.Err()
+}
+
+// Commands is the command bus of the order commands, with a typed
+// route per command. Each command is validated, logged and guarded
+// against panics on its way to the service. There is no transaction
+// middleware: OrderService already commits its writes through
+// WithUnitOfWork.
+//
+// Commands also satisfies OrderPlacer and OrderRefunder, so
+// OrderHandler dispatches commands without knowing about the bus.
+type Commands struct {
+	Bus         *commandbus.Bus
+	PlaceRoute  commandbus.Route[PlaceOrderCommand, Order]
+	RefundRoute commandbus.Route[RefundOrderCommand, Order]
+	CancelRoute commandbus.Route[CancelOrderCommand, Order]
+}
+
+// NewCommands registers the order commands with orders and refunds.
+// log may be nil, in which case commands are not logged, and so may
+// reporter.
+func NewCommands(orders *OrderService, refunds OrderRefunder, log Logger, reporter ErrReporter) (Commands, error) {
+	var mws []commandbus.Middleware
+	if log != nil {
+		mws = append(mws, commandbus.Logging(log, nil))
+	}
+	mws = append(mws, middleware.Recover[commandbus.Command, any](reporter, map[string]string{"op": "command"}), commandbus.Validate())
+	c := Commands{Bus: commandbus.New(mws...)}
+
+	var err error
+	if c.PlaceRoute, err = commandbus.Register(c.Bus, func(ctx context.Context, cmd PlaceOrderCommand) (Order, error) {
+		return orders.PlaceOrder(ctx, cmd.IdempotencyKey, cmd.Order)
+	}); err != nil {
+		return Commands{}, err
+	}
+	if c.RefundRoute, err = commandbus.Register(c.Bus, func(ctx context.Context, cmd RefundOrderCommand) (Order, error) {
+		return refunds.Refund(ctx, cmd.OrderID)
+	}); err != nil {
+		return Commands{}, err
+	}
+	if c.CancelRoute, err = commandbus.Register(c.Bus, func(ctx context.Context, cmd CancelOrderCommand) (Order, error) {
+		return orders.CancelOrder(ctx, cmd.OrderID)
+	}); err != nil {
+		return Commands{}, err
+	}
+	return c, nil
+}
+
+// PlaceOrder dispatches a PlaceOrderCommand.
+func (c Commands) PlaceOrder(ctx context.Context, idempotencyKey string, order Order) (Order, error) {
+	return c.PlaceRoute.Dispatch(ctx, PlaceOrderCommand{IdempotencyKey: idempotencyKey, Order: order})
+}
+
+// Refund dispatches a RefundOrderCommand.
+func (c Commands) Refund(ctx context.Context, orderID int) (Order, error) {
+	return c.RefundRoute.Dispatch(ctx, RefundOrderCommand{OrderID: orderID})
+}
+
+// CancelOrder dispatches a CancelOrderCommand.
+func (c Commands) CancelOrder(ctx context.Context, orderID int) (Order, error) {
+	return c.CancelRoute.Dispatch(ctx, CancelOrderCommand{OrderID: orderID})
+}
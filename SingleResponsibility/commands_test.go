@@ -0,0 +1,45 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/validate"
+)
+
+func TestCommands(t *testing.T) {
+	log := &CapturingLogger{}
+	services, err := Wire(context.Background(), DefaultConfig(), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer services.Close()
+	commands := services.Commands
+	ctx := context.Background()
+
+	placed, err := commands.PlaceOrder(ctx, "", testOrder(t, 1))
+	if err != nil || placed.Status != StatusInvoiced {
+		t.Fatalf("PlaceOrder = %+v, %v", placed, err)
+	}
+	cancelled, err := commands.CancelOrder(ctx, placed.ID)
+	if err != nil || cancelled.Status != StatusCancelled {
+		t.Fatalf("CancelOrder = %+v, %v", cancelled, err)
+	}
+
+	_, err = commands.Refund(ctx, 0)
+	if status, body := apiErrors.Map(err); !errors.Is(err, validate.ErrInvalid) || status != 400 || body.Fields[0].Field != "order_id" {
+		t.Errorf("Refund(0): %d %+v %v", status, body, err)
+	}
+
+	var logged []string
+	for _, line := range log.Lines() {
+		if strings.HasPrefix(line, "command ") {
+			logged = append(logged, strings.Fields(line)[1])
+		}
+	}
+	if strings.Join(logged, ",") != "PlaceOrder,CancelOrder,RefundOrder" {
+		t.Errorf("logged commands %q", logged)
+	}
+}
@@ -0,0 +1,1155 @@
+package singleresponsibility
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/analytics"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/auth"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/breaker"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/cache"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/errreport"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/flags"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/health"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/metrics"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/sched"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/storage"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/tracing"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/webhook"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/workqueue"
+	"github.com/redis/go-redis/v9"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Config selects the implementations wired into OrderService. It is
+// the only place that knows which concrete store, gateway and sender
+// are in use.
+type Config struct {
+	Store     string `json:"store"`      // "memory" or "sql"
+	SQLDriver string `json:"sql_driver"` // a registered database/sql driver; the binary registers "sqlite"
+	SQLDSN    string `json:"sql_dsn"`
+
+	Gateway         string   `json:"gateway"`           // "stripe" or "paypal"
+	StripeLimit     string   `json:"stripe_limit"`      // decimal amount in Currency; empty means no limit
+	PayPalFailEvery int      `json:"paypal_fail_every"` // see FakePayPalGateway
+	PaymentAttempts int      `json:"payment_attempts"`  // more than 1 retries transient failures
+	PaymentTimeout  Duration `json:"payment_timeout"`   // per call, such as "5s"; zero means none
+
+	// BreakerThreshold consecutive gateway failures stop payments for
+	// BreakerOpenTimeout, then a probe charge decides whether they
+	// resume. Zero disables the breaker.
+	BreakerThreshold   int      `json:"breaker_threshold"`
+	BreakerOpenTimeout Duration `json:"breaker_open_timeout"`
+
+	Email          string   `json:"email"`            // "log"
+	EmailWorkers   int      `json:"email_workers"`    // more than 0 sends emails in the background
+	EmailQueueSize int      `json:"email_queue_size"` // emails waiting for a worker
+	EmailTimeout   Duration `json:"email_timeout"`    // per send; zero means none
+
+	InvoiceFormat string   `json:"invoice_format"` // "text", "html" or "pdf"; customers may prefer another
+	Currency      Currency `json:"currency"`
+
+	Metrics   string `json:"metrics"`    // "text" or "prometheus": the format of GET /metrics
+	RateLimit int    `json:"rate_limit"` // order API requests per second; zero means no limit
+
+	// Tracing "otel" traces the orders and the reports with the
+	// OpenTelemetry SDK, writing each finished span to the log; empty
+	// means no tracing.
+	Tracing string `json:"tracing"`
+
+	ErrorReportURL string `json:"error_report_url"` // where errors needing attention are posted; empty means nowhere
+
+	// AnalyticsURL is the collector the checkout funnel events are
+	// posted to, in batches; empty means nowhere. AnalyticsSample is
+	// the percentage of events sent.
+	AnalyticsURL    string `json:"analytics_url"`
+	AnalyticsSample int    `json:"analytics_sample"`
+
+	ReportSchedule  string   `json:"report_schedule"`  // cron expression for the order report, such as "@daily"; empty means none
+	OutboxInterval  Duration `json:"outbox_interval"`  // how often the confirmations recorded in the outbox are sent
+	ArchiveInvoices bool     `json:"archive_invoices"` // keep a copy of every invoice in the blob store
+
+	// ArchiveAfter makes deleting an order only mark it deleted, until
+	// "purge" moves the orders older than ArchiveAfter into the blob
+	// store; see ArchivingRepository. Zero deletes orders outright.
+	ArchiveAfter Duration `json:"archive_after"`
+
+	// The blob store holding reports and archived invoices: "local",
+	// under StorageDir, or "s3", a bucket of an S3-compatible store.
+	Storage     string `json:"storage"`
+	StorageDir  string `json:"storage_dir"`
+	S3Endpoint  string `json:"s3_endpoint"`
+	S3Region    string `json:"s3_region"`
+	S3Bucket    string `json:"s3_bucket"`
+	S3AccessKey string `json:"s3_access_key"`
+	S3SecretKey string `json:"s3_secret_key"`
+
+	// Cache keeps orders and invoices in "memory", up to CacheSize
+	// entries, or in the Redis server at RedisAddr, for CacheTTL.
+	// Empty means no cache.
+	Cache     string   `json:"cache"`
+	CacheSize int      `json:"cache_size"`
+	CacheTTL  Duration `json:"cache_ttl"`
+	RedisAddr string   `json:"redis_addr"`
+
+	// JWTSecret, at least auth.MinSecretSize bytes, verifies the bearer
+	// tokens of the order API; APIKeys are the keys it accepts too.
+	// With neither, the API is open.
+	JWTSecret string   `json:"jwt_secret"`
+	APIKeys   []APIKey `json:"api_keys"`
+
+	// WebhookSecret signs the payment gateway's callbacks to
+	// POST /webhooks/payments; empty means the route is not served.
+	WebhookSecret string `json:"webhook_secret"`
+
+	// The optional steps of placing an order, each skipped unless set.
+	// They are only read from the JSON config.
+	//
+	// Customers are the customers orders are placed for; orders of any
+	// other customer are refused. Without them, every order goes to a
+	// placeholder customer with no address. Carrier, "dhl" or "ups",
+	// books the delivery of each paid order, so it needs Customers to
+	// know where to. Stock is how many of each SKU there are; an order
+	// that would oversell is refused before it is charged. Coupons are
+	// the codes orders may redeem, and Fraud holds or declines risky
+	// orders.
+	Customers []CustomerConfig `json:"customers"`
+	Carrier   string           `json:"carrier"`
+	Stock     map[string]int   `json:"stock"`
+	Coupons   []CouponConfig   `json:"coupons"`
+	Fraud     FraudConfig      `json:"fraud"`
+
+	// Flags are feature flags and experiments, such as
+	// "invoice.format": "html:50,pdf:50" to split the customers without
+	// a preferred invoice format between HTML and PDF; see
+	// InvoiceExperiment. They are only read from the JSON config.
+	Flags map[string]string `json:"flags"`
+}
+
+// APIKey is an API key accepted for Subject, with Scopes such as
+// "orders:write". API keys are only read from the JSON config.
+type APIKey struct {
+	Key     string   `json:"key"`
+	Subject string   `json:"subject"`
+	Scopes  []string `json:"scopes"`
+}
+
+// CustomerConfig is a Customer. InvoiceFormat and Locale are
+// optional.
+type CustomerConfig struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	Street        string `json:"street"`
+	City          string `json:"city"`
+	PostalCode    string `json:"postal_code"`
+	Country       string `json:"country"`
+	InvoiceFormat string `json:"invoice_format"`
+	Locale        string `json:"locale"`
+}
+
+func (c CustomerConfig) customer() (Customer, error) {
+	customer, err := NewCustomer(c.ID, c.Name, EmailAddress(c.Email), Address{
+		Street:     c.Street,
+		City:       c.City,
+		PostalCode: c.PostalCode,
+		Country:    c.Country,
+	})
+	if err != nil {
+		return Customer{}, err
+	}
+	if c.InvoiceFormat != "" && !InvoiceFormat(c.InvoiceFormat).Valid() {
+		return Customer{}, fmt.Errorf("unknown invoice format %q", c.InvoiceFormat)
+	}
+	customer.InvoiceFormat = InvoiceFormat(c.InvoiceFormat)
+	customer.Locale = c.Locale
+	return customer, nil
+}
+
+// CouponConfig is a Coupon taking Percent percent off, a fixed Amount
+// in Currency off, or granting FreeShipping: exactly one of them.
+// Expires, a date such as "2027-01-01", is the first day (UTC) the code
+// is refused; empty never expires. A zero MaxUses allows unlimited
+// redemptions.
+type CouponConfig struct {
+	Code         string  `json:"code"`
+	Percent      float64 `json:"percent"`
+	Amount       string  `json:"amount"`
+	FreeShipping bool    `json:"free_shipping"`
+	Expires      string  `json:"expires"`
+	MaxUses      int     `json:"max_uses"`
+}
+
+func (c CouponConfig) coupon(currency Currency) (Coupon, error) {
+	if c.Code == "" {
+		return Coupon{}, errors.New("needs a code")
+	}
+	var kinds []DiscountKind
+	if c.Percent != 0 {
+		if c.Percent < 0 || c.Percent > 100 {
+			return Coupon{}, fmt.Errorf("percent %g is not a percentage", c.Percent)
+		}
+		kinds = append(kinds, PercentageDiscount{Percent: c.Percent})
+	}
+	if c.Amount != "" {
+		amount, err := ParseMoney(c.Amount, currency)
+		if err != nil {
+			return Coupon{}, fmt.Errorf("amount: %w", err)
+		}
+		kinds = append(kinds, FixedDiscount{Amount: amount})
+	}
+	if c.FreeShipping {
+		kinds = append(kinds, FreeShippingDiscount{})
+	}
+	if len(kinds) != 1 {
+		return Coupon{}, errors.New("needs exactly one of percent, amount and free_shipping")
+	}
+	if c.MaxUses < 0 {
+		return Coupon{}, errors.New("max_uses must not be negative")
+	}
+	coupon := Coupon{Code: c.Code, Kind: kinds[0], MaxUses: c.MaxUses}
+	if c.Expires != "" {
+		expires, err := time.Parse(time.DateOnly, c.Expires)
+		if err != nil {
+			return Coupon{}, fmt.Errorf("expires: %w", err)
+		}
+		coupon.ExpiresAt = expires
+	}
+	return coupon, nil
+}
+
+// FraudConfig configures the fraud check; the zero value checks
+// nothing. Orders totalling Review or more, in Currency, are held for
+// review and those totalling Decline or more are declined. A customer
+// who already placed MaxOrdersPerHour orders in the last hour is held
+// for review, and the customers in Blocklist are always declined.
+type FraudConfig struct {
+	Review           string `json:"review"`
+	Decline          string `json:"decline"`
+	MaxOrdersPerHour int    `json:"max_orders_per_hour"`
+	Blocklist        []int  `json:"blocklist"`
+}
+
+// rules returns the FraudRules f turns on. The velocity rule counts
+// the orders in orders.
+func (f FraudConfig) rules(currency Currency, orders OrderStore) ([]FraudRule, error) {
+	var rules []FraudRule
+	if f.Review != "" || f.Decline != "" {
+		threshold := AmountThreshold{Review: NewMoney(0, currency), Decline: NewMoney(0, currency)}
+		for _, t := range []struct {
+			name   string
+			amount string
+			limit  *Money
+		}{{"review", f.Review, &threshold.Review}, {"decline", f.Decline, &threshold.Decline}} {
+			if t.amount == "" {
+				continue
+			}
+			limit, err := ParseMoney(t.amount, currency)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", t.name, err)
+			}
+			*t.limit = limit
+		}
+		rules = append(rules, threshold)
+	}
+	if f.MaxOrdersPerHour < 0 {
+		return nil, errors.New("max_orders_per_hour must not be negative")
+	}
+	if f.MaxOrdersPerHour > 0 {
+		rules = append(rules, NewVelocityRule(orders, clock.System{}, f.MaxOrdersPerHour, time.Hour))
+	}
+	if len(f.Blocklist) > 0 {
+		rules = append(rules, NewBlocklist(f.Blocklist...))
+	}
+	return rules, nil
+}
+
+// DefaultConfig keeps everything in memory and charges through the
+// fake Stripe gateway.
+func DefaultConfig() Config {
+	return Config{
+		Store:           "memory",
+		Gateway:         "stripe",
+		PaymentAttempts: 1,
+		Email:           "log",
+		EmailQueueSize:  100,
+		InvoiceFormat:   "text",
+		Currency:        "USD",
+		Metrics:         "text",
+		Storage:         "local",
+		StorageDir:      "data",
+		CacheSize:       1000,
+		CacheTTL:        Duration(5 * time.Minute),
+		AnalyticsSample: 100,
+		OutboxInterval:  Duration(5 * time.Second),
+	}
+}
+
+// LoadConfig starts from DefaultConfig, overlays the JSON file named by
+// ORDERS_CONFIG if set, then the individual ORDERS_* variables, and
+// validates the result. getenv is usually os.Getenv.
+func LoadConfig(getenv func(string) string) (Config, error) {
+	cfg := DefaultConfig()
+
+	if path := getenv("ORDERS_CONFIG"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("reading config: %w", err)
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&cfg); err != nil {
+			return Config{}, fmt.Errorf("%w: %s: %w", ErrInvalidConfig, path, err)
+		}
+	}
+
+	texts := map[string]*string{
+		"ORDERS_STORE":            &cfg.Store,
+		"ORDERS_SQL_DRIVER":       &cfg.SQLDriver,
+		"ORDERS_SQL_DSN":          &cfg.SQLDSN,
+		"ORDERS_GATEWAY":          &cfg.Gateway,
+		"ORDERS_STRIPE_LIMIT":     &cfg.StripeLimit,
+		"ORDERS_EMAIL":            &cfg.Email,
+		"ORDERS_INVOICE_FORMAT":   &cfg.InvoiceFormat,
+		"ORDERS_METRICS":          &cfg.Metrics,
+		"ORDERS_TRACING":          &cfg.Tracing,
+		"ORDERS_ERROR_REPORT_URL": &cfg.ErrorReportURL,
+		"ORDERS_ANALYTICS_URL":    &cfg.AnalyticsURL,
+		"ORDERS_REPORT_SCHEDULE":  &cfg.ReportSchedule,
+		"ORDERS_STORAGE":          &cfg.Storage,
+		"ORDERS_STORAGE_DIR":      &cfg.StorageDir,
+		"ORDERS_S3_ENDPOINT":      &cfg.S3Endpoint,
+		"ORDERS_S3_REGION":        &cfg.S3Region,
+		"ORDERS_S3_BUCKET":        &cfg.S3Bucket,
+		"ORDERS_S3_ACCESS_KEY":    &cfg.S3AccessKey,
+		"ORDERS_S3_SECRET_KEY":    &cfg.S3SecretKey,
+		"ORDERS_CACHE":            &cfg.Cache,
+		"ORDERS_REDIS_ADDR":       &cfg.RedisAddr,
+		"ORDERS_JWT_SECRET":       &cfg.JWTSecret,
+		"ORDERS_WEBHOOK_SECRET":   &cfg.WebhookSecret,
+	}
+	for name, field := range texts {
+		if v := getenv(name); v != "" {
+			*field = v
+		}
+	}
+	if v := getenv("ORDERS_CURRENCY"); v != "" {
+		cfg.Currency = Currency(v)
+	}
+
+	durations := map[string]*Duration{
+		"ORDERS_PAYMENT_TIMEOUT":      &cfg.PaymentTimeout,
+		"ORDERS_EMAIL_TIMEOUT":        &cfg.EmailTimeout,
+		"ORDERS_BREAKER_OPEN_TIMEOUT": &cfg.BreakerOpenTimeout,
+		"ORDERS_CACHE_TTL":            &cfg.CacheTTL,
+		"ORDERS_ARCHIVE_AFTER":        &cfg.ArchiveAfter,
+		"ORDERS_OUTBOX_INTERVAL":      &cfg.OutboxInterval,
+	}
+	for name, field := range durations {
+		if v := getenv(name); v != "" {
+			if err := field.UnmarshalText([]byte(v)); err != nil {
+				return Config{}, fmt.Errorf("%w: %s: %w", ErrInvalidConfig, name, err)
+			}
+		}
+	}
+
+	ints := map[string]*int{
+		"ORDERS_PAYPAL_FAIL_EVERY": &cfg.PayPalFailEvery,
+		"ORDERS_PAYMENT_ATTEMPTS":  &cfg.PaymentAttempts,
+		"ORDERS_EMAIL_WORKERS":     &cfg.EmailWorkers,
+		"ORDERS_EMAIL_QUEUE_SIZE":  &cfg.EmailQueueSize,
+		"ORDERS_RATE_LIMIT":        &cfg.RateLimit,
+		"ORDERS_BREAKER_THRESHOLD": &cfg.BreakerThreshold,
+		"ORDERS_CACHE_SIZE":        &cfg.CacheSize,
+		"ORDERS_ANALYTICS_SAMPLE":  &cfg.AnalyticsSample,
+	}
+	for name, field := range ints {
+		v := getenv(name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: %s: %w", ErrInvalidConfig, name, err)
+		}
+		*field = n
+	}
+
+	if v := getenv("ORDERS_ARCHIVE_INVOICES"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: ORDERS_ARCHIVE_INVOICES: %w", ErrInvalidConfig, err)
+		}
+		cfg.ArchiveInvoices = b
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Duration is a time.Duration written as text such as "1.5s" in the
+// JSON config.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Validate reports every setting that is missing or unknown.
+func (c Config) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]any{ErrInvalidConfig}, args...)...))
+	}
+
+	switch c.Store {
+	case "memory":
+	case "sql":
+		if c.SQLDriver == "" || c.SQLDSN == "" {
+			invalid("store %q needs sql_driver and sql_dsn", c.Store)
+		}
+	default:
+		invalid("unknown store %q", c.Store)
+	}
+
+	if _, err := c.Currency.Decimals(); err != nil {
+		invalid("currency: %v", err)
+	}
+	switch c.Gateway {
+	case "stripe":
+		if c.StripeLimit != "" {
+			if _, err := ParseMoney(c.StripeLimit, c.Currency); err != nil {
+				invalid("stripe_limit: %v", err)
+			}
+		}
+	case "paypal":
+		if c.PayPalFailEvery < 0 {
+			invalid("paypal_fail_every must not be negative")
+		}
+	default:
+		invalid("unknown gateway %q", c.Gateway)
+	}
+	if c.PaymentAttempts < 1 {
+		invalid("payment_attempts must be at least 1")
+	}
+	if c.PaymentTimeout < 0 || c.EmailTimeout < 0 {
+		invalid("payment_timeout and email_timeout must not be negative")
+	}
+	if c.BreakerThreshold < 0 || c.BreakerOpenTimeout < 0 {
+		invalid("breaker_threshold and breaker_open_timeout must not be negative")
+	}
+
+	if c.Email != "log" {
+		invalid("unknown email sender %q", c.Email)
+	}
+	if c.EmailWorkers < 0 || c.EmailQueueSize < 0 {
+		invalid("email_workers and email_queue_size must not be negative")
+	}
+	if !InvoiceFormat(c.InvoiceFormat).Valid() {
+		invalid("unknown invoice format %q", c.InvoiceFormat)
+	}
+	if c.Metrics != "text" && c.Metrics != "prometheus" {
+		invalid("unknown metrics format %q", c.Metrics)
+	}
+	if c.Tracing != "" && c.Tracing != "otel" {
+		invalid("unknown tracing %q", c.Tracing)
+	}
+	if c.RateLimit < 0 {
+		invalid("rate_limit must not be negative")
+	}
+	if c.ErrorReportURL != "" {
+		if u, err := url.Parse(c.ErrorReportURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("error_report_url %q is not an http(s) URL", c.ErrorReportURL)
+		}
+	}
+	if c.AnalyticsURL != "" {
+		if u, err := url.Parse(c.AnalyticsURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("analytics_url %q is not an http(s) URL", c.AnalyticsURL)
+		}
+	}
+	if c.AnalyticsSample < 0 || c.AnalyticsSample > 100 {
+		invalid("analytics_sample %d is not a percentage", c.AnalyticsSample)
+	}
+	if c.ReportSchedule != "" {
+		if _, err := sched.Parse(c.ReportSchedule); err != nil {
+			invalid("report_schedule: %v", err)
+		}
+	}
+	if c.OutboxInterval <= 0 {
+		invalid("outbox_interval must be positive")
+	}
+	switch c.Storage {
+	case "local":
+		if c.StorageDir == "" {
+			invalid("storage %q needs storage_dir", c.Storage)
+		}
+	case "s3":
+		if _, err := c.s3(); err != nil {
+			invalid("storage %q: %v", c.Storage, err)
+		}
+	default:
+		invalid("unknown storage %q", c.Storage)
+	}
+	switch c.Cache {
+	case "":
+	case "memory":
+		if c.CacheSize < 1 {
+			invalid("cache %q needs a cache_size of at least 1", c.Cache)
+		}
+	case "redis":
+		if c.RedisAddr == "" {
+			invalid("cache %q needs redis_addr", c.Cache)
+		}
+	default:
+		invalid("unknown cache %q", c.Cache)
+	}
+	if c.CacheTTL < 0 {
+		invalid("cache_ttl must not be negative")
+	}
+	if c.ArchiveAfter < 0 {
+		invalid("archive_after must not be negative")
+	}
+	for name, value := range c.Flags {
+		if name != invoiceFormatFlag && !strings.HasPrefix(name, invoiceFormatFlag+".") {
+			continue
+		}
+		weights, err := flags.ParseWeights(value)
+		if err != nil {
+			invalid("flag %s: %v", name, err)
+			continue
+		}
+		for _, w := range weights {
+			if !InvoiceFormat(w.Variant).Valid() {
+				invalid("flag %s: unknown invoice format %q", name, w.Variant)
+			}
+		}
+	}
+	if c.JWTSecret != "" && len(c.JWTSecret) < auth.MinSecretSize {
+		invalid("jwt_secret must be at least %d bytes", auth.MinSecretSize)
+	}
+	for i, k := range c.APIKeys {
+		if k.Key == "" || k.Subject == "" {
+			invalid("api_keys[%d] needs a key and a subject", i)
+		}
+	}
+	known := make(map[int]bool, len(c.Customers))
+	for i, cc := range c.Customers {
+		if _, err := cc.customer(); err != nil {
+			invalid("customers[%d]: %v", i, err)
+		}
+		if known[cc.ID] {
+			invalid("customers[%d]: customer %d is listed twice", i, cc.ID)
+		}
+		known[cc.ID] = true
+	}
+	switch c.Carrier {
+	case "":
+	case "dhl", "ups":
+		if len(c.Customers) == 0 {
+			invalid("carrier %q needs customers to ship to", c.Carrier)
+		}
+	default:
+		invalid("unknown carrier %q", c.Carrier)
+	}
+	for sku, quantity := range c.Stock {
+		if quantity < 0 {
+			invalid("stock of %s must not be negative", sku)
+		}
+	}
+	for i, cc := range c.Coupons {
+		if _, err := cc.coupon(c.Currency); err != nil {
+			invalid("coupons[%d]: %v", i, err)
+		}
+	}
+	if _, err := c.Fraud.rules(c.Currency, nil); err != nil {
+		invalid("fraud: %v", err)
+	}
+	return errors.Join(errs...)
+}
+
+// Services is the service graph Wire builds.
+type Services struct {
+	Orders  *OrderService
+	Refunds *RefundService
+	Store   OrderStore
+	// Audit is the audit trail Orders records each step in.
+	Audit   *AuditLogService
+	Metrics *MetricsRegistry
+	// Commands dispatches the order commands to Orders and Refunds;
+	// the transports go through it.
+	Commands Commands
+	// Summaries is the read model of the placed orders, kept up to
+	// date from Events.
+	Summaries SummaryStore
+	// Archive soft-deletes and purges the orders; nil unless
+	// archive_after is set.
+	Archive *ArchivingRepository
+	// NextOrderID numbers new orders, after the ones already in
+	// Store.
+	NextOrderID func() int
+
+	// MetricsHandler serves the metrics in the configured format.
+	MetricsHandler http.Handler
+	// Health checks the dependencies that can fail at runtime.
+	Health *health.Aggregator
+	// Reporter receives the errors needing attention; nil unless
+	// error_report_url is set.
+	Reporter ErrReporter
+	// Jobs are the scheduled jobs to run: the outbox dispatcher and,
+	// if report_schedule is set, the order report.
+	Jobs []sched.Entry
+	// Events carries the domain events Orders publishes.
+	Events *EventBus
+	// Verifier checks the credentials of API requests; nil unless
+	// jwt_secret or api_keys is set.
+	Verifier auth.TokenVerifier
+	// Webhook receives the payment gateway's signed callbacks; nil
+	// unless webhook_secret is set.
+	Webhook http.Handler
+
+	// Outbox sends the confirmation emails Orders records in the
+	// outbox when it marks an order paid.
+	Outbox *OutboxDispatcher
+
+	// Close releases what Wire opened, such as the database, after
+	// sending the confirmations still in the outbox and delivering the
+	// queued emails.
+	Close func() error
+}
+
+// healthCheckTimeout bounds each readiness check.
+const healthCheckTimeout = 2 * time.Second
+
+// emailDrainTimeout bounds how long Services.Close waits for queued
+// emails.
+const emailDrainTimeout = 10 * time.Second
+
+// outboxDrainTimeout bounds how long Services.Close spends sending the
+// confirmations still in the outbox.
+const outboxDrainTimeout = 10 * time.Second
+
+// traceFlushTimeout bounds how long Services.Close waits for the last
+// spans to be exported.
+const traceFlushTimeout = 5 * time.Second
+
+// Errors needing attention wait for the collector in a queue of
+// errorReportQueueSize; Services.Close waits errorReportDrainTimeout
+// for them to be posted.
+const (
+	errorReportQueueSize    = 100
+	errorReportDrainTimeout = 5 * time.Second
+)
+
+// analyticsDrainTimeout bounds how long Services.Close waits for the
+// last funnel events to be posted.
+const analyticsDrainTimeout = 5 * time.Second
+
+// Wire builds the services described by cfg.
+func Wire(ctx context.Context, cfg Config, log Logger) (Services, error) {
+	if err := cfg.Validate(); err != nil {
+		return Services{}, err
+	}
+	w := &wiring{cfg: cfg, log: log, checks: health.NewAggregator(healthCheckTimeout)}
+	services, err := w.services(ctx)
+	if err != nil {
+		w.close()
+		return Services{}, err
+	}
+	return services, nil
+}
+
+// wiring holds what the steps of Wire share. Each step builds one
+// collaborator from cfg and registers what it opened with onClose and
+// checks.
+type wiring struct {
+	cfg    Config
+	log    Logger
+	checks *health.Aggregator
+
+	// provider is nil unless metrics is "prometheus".
+	provider metrics.Provider
+	registry *MetricsRegistry
+	// tracer is nil unless tracing is set.
+	tracer tracing.Tracer
+
+	closers []func() error
+}
+
+// onClose makes Services.Close run f, before everything registered
+// earlier: the emails are delivered before the database closes.
+func (w *wiring) onClose(f func() error) {
+	w.closers = append(w.closers, f)
+}
+
+func (w *wiring) close() error {
+	var errs []error
+	for i := len(w.closers) - 1; i >= 0; i-- {
+		errs = append(errs, w.closers[i]())
+	}
+	return errors.Join(errs...)
+}
+
+func (w *wiring) services(ctx context.Context) (Services, error) {
+	cfg, log := w.cfg, w.log
+	reporter := w.errReporter()
+	tracker := w.analytics()
+	metricsHandler := w.metrics()
+	w.tracer = w.tracing()
+	stores, err := w.stores(ctx)
+	if err != nil {
+		return Services{}, err
+	}
+	cached := w.cache()
+
+	// Every dependency is metered; the services never notice.
+	var repo OrderStore = NewMeteredOrderStore(stores.orders, w.registry)
+	var uow UnitOfWork = NewMeteredUnitOfWork(stores.uow, w.registry)
+	if cached != nil {
+		cachedRepo := NewCachedOrderStore(repo, cached, time.Duration(cfg.CacheTTL))
+		repo, uow = cachedRepo, cachedRepo.UnitOfWork(uow)
+	}
+	payment := w.paymentGateway()
+	mail := w.emailSender(reporter)
+	pricing := NewPricingService(nil)
+	invoice := w.invoiceGenerator(pricing, cached)
+
+	audit := NewAuditLogService(stores.audit, clock.System{})
+	customers, err := w.customers(ctx)
+	if err != nil {
+		return Services{}, err
+	}
+	events := NewEventBus()
+	opts := []OrderOption{
+		WithPricing(pricing), WithValidation(DefaultOrderRules()), WithLogger(log), WithErrReporter(reporter), WithAuditLog(audit),
+		WithOutbox(stores.outbox), WithUnitOfWork(uow), WithEventPublisher(events),
+	}
+	if tracker != nil {
+		opts = append(opts, WithAnalytics(tracker))
+	}
+	if w.tracer != nil {
+		opts = append(opts, WithTracer(w.tracer))
+	}
+	if w.provider != nil {
+		opts = append(opts, OnStatusChange(CountOrders(w.provider.Counter("orders_paid_total", "Orders placed and paid."))))
+	}
+	opts = append(opts, w.orderSteps(repo, customers)...)
+	orders, err := NewOrderService(repo, payment, mail, invoice, opts...)
+	if err != nil {
+		return Services{}, err
+	}
+	dispatcher := w.outboxDispatcher(stores.outbox, repo, customers, mail)
+
+	summaries := NewInMemorySummaryStore()
+	projector := NewOrderProjector(summaries)
+	events.Subscribe(EventOrderPlaced, projector.Handle)
+	if _, err := projector.Rebuild(ctx, repo); err != nil {
+		return Services{}, err
+	}
+	if cfg.ArchiveInvoices {
+		events.Subscribe(EventInvoiceGenerated, NewInvoiceArchiver(stores.blobs).Archive)
+	}
+
+	var verifier auth.TokenVerifier
+	if cfg.JWTSecret != "" || len(cfg.APIKeys) > 0 {
+		verifier = cfg.verifier()
+	}
+	var hook http.Handler
+	if cfg.WebhookSecret != "" {
+		payments := NewPaymentWebhook(*orders, NewInMemoryIdempotencyStore(), log)
+		hook = webhook.Handler(webhook.NewHMAC([]byte(cfg.WebhookSecret), 0, nil), maxRequestBody, payments.Handle, writeError)
+	}
+	refunds := NewRefundService(*orders, payment, mail, customers, log)
+	commands, err := NewCommands(orders, refunds, log, reporter)
+	if err != nil {
+		return Services{}, err
+	}
+	return Services{
+		Orders:    orders,
+		Refunds:   refunds,
+		Store:     repo,
+		Audit:     audit,
+		Outbox:    dispatcher,
+		Metrics:   w.registry,
+		Commands:  commands,
+		Summaries: summaries,
+		Archive:   stores.archive,
+		Close:     w.close,
+
+		NextOrderID: stores.nextID,
+
+		MetricsHandler: metricsHandler,
+		Health:         w.checks,
+		Reporter:       reporter,
+		Jobs:           w.jobs(repo, stores.blobs, dispatcher),
+		Events:         events,
+		Verifier:       verifier,
+		Webhook:        hook,
+	}, nil
+}
+
+// errReporter returns where the errors needing attention go; nil
+// unless error_report_url is set.
+func (w *wiring) errReporter() ErrReporter {
+	if w.cfg.ErrorReportURL == "" {
+		return nil
+	}
+	posting := errreport.NewHTTP(w.cfg.ErrorReportURL, errreport.HTTPOptions{QueueSize: errorReportQueueSize})
+	w.onClose(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), errorReportDrainTimeout)
+		defer cancel()
+		return posting.Close(ctx)
+	})
+	return posting
+}
+
+// analytics returns the tracker of the checkout funnel; nil unless
+// analytics_url is set.
+func (w *wiring) analytics() AnalyticsTracker {
+	if w.cfg.AnalyticsURL == "" {
+		return nil
+	}
+	posting := analytics.NewHTTP(w.cfg.AnalyticsURL, analytics.HTTPOptions{})
+	w.onClose(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), analyticsDrainTimeout)
+		defer cancel()
+		return posting.Close(ctx)
+	})
+	return analytics.Sample(posting, float64(w.cfg.AnalyticsSample)/100, nil)
+}
+
+// metrics sets up the registry the dependencies are metered in and
+// returns the handler serving it.
+func (w *wiring) metrics() http.Handler {
+	if w.cfg.Metrics == "prometheus" {
+		prom := metrics.NewPrometheus()
+		w.provider = prom
+		w.registry = NewMetricsRegistry(clock.System{}, prom)
+		return prom.Handler()
+	}
+	registry := NewMetricsRegistry(clock.System{}, nil)
+	w.registry = registry
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		registry.Export(rw)
+	})
+}
+
+// tracing returns the configured tracer; nil unless tracing is set.
+// Services.Close exports the spans not exported yet.
+func (w *wiring) tracing() tracing.Tracer {
+	if w.cfg.Tracing != "otel" {
+		return nil
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(logSpanExporter{log: orNop(w.log)}))
+	w.onClose(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), traceFlushTimeout)
+		defer cancel()
+		return provider.Shutdown(ctx)
+	})
+	return tracing.NewOTel(provider.Tracer("orders"))
+}
+
+// wiredStores are the stores Wire opened, before any decorator.
+type wiredStores struct {
+	orders OrderStore
+	audit  AuditStore
+	outbox Outbox
+	// uow writes orders, audit entries and outbox messages together.
+	uow   UnitOfWork
+	blobs storage.Store
+	// archive is nil unless archive_after is set; orders is then
+	// archive.
+	archive *ArchivingRepository
+	// nextID numbers new orders after those already stored.
+	nextID func() int
+}
+
+func (w *wiring) stores(ctx context.Context) (wiredStores, error) {
+	var s wiredStores
+	switch w.cfg.Store {
+	case "memory":
+		orders, audit := NewInMemoryOrderRepository(), NewInMemoryAuditStore()
+		outbox := NewInMemoryOutbox(orders)
+		s.orders, s.audit, s.outbox = orders, audit, outbox
+		s.uow = NewInMemoryUnitOfWork(orders, audit, outbox)
+	case "sql":
+		db, err := sql.Open(w.cfg.SQLDriver, w.cfg.SQLDSN)
+		if err != nil {
+			return wiredStores{}, fmt.Errorf("opening %s database: %w", w.cfg.SQLDriver, err)
+		}
+		w.onClose(db.Close)
+		if err := Migrate(ctx, db); err != nil {
+			return wiredStores{}, err
+		}
+		s.orders = NewSQLOrderRepository(db)
+		s.audit = NewSQLAuditStore(db)
+		s.outbox = NewSQLOutbox(db)
+		s.uow = NewSQLUnitOfWork(db)
+		w.checks.Register("database", health.DB(db))
+	}
+	// Asked before archiving hides the deleted orders, whose IDs are
+	// still taken.
+	last, err := LastOrderID(ctx, s.orders)
+	if err != nil {
+		return wiredStores{}, err
+	}
+	s.nextID = NewSequence(last)
+	s.blobs = w.cfg.blobStore()
+	if w.cfg.ArchiveAfter > 0 {
+		s.archive = NewArchivingRepository(s.orders, s.blobs, time.Duration(w.cfg.ArchiveAfter), nil)
+		s.orders = s.archive
+	}
+	return s, nil
+}
+
+// cache returns the cache of orders and invoices; nil unless cache is
+// set.
+func (w *wiring) cache() cache.Cache {
+	switch w.cfg.Cache {
+	case "memory":
+		return cache.NewLRU(w.cfg.CacheSize, nil)
+	case "redis":
+		client := redis.NewClient(&redis.Options{Addr: w.cfg.RedisAddr})
+		w.onClose(client.Close)
+		w.checks.Register("cache", health.CheckerFunc(func(ctx context.Context) error {
+			return client.Ping(ctx).Err()
+		}))
+		return cache.NewRedis(client, "orders:")
+	}
+	return nil
+}
+
+func (w *wiring) paymentGateway() PaymentGateway {
+	cfg := w.cfg
+	var payment PaymentGateway
+	switch cfg.Gateway {
+	case "stripe":
+		limit := NewMoney(0, cfg.Currency)
+		if cfg.StripeLimit != "" {
+			limit, _ = ParseMoney(cfg.StripeLimit, cfg.Currency) // checked by Validate
+		}
+		payment = NewFakeStripeGateway(limit, w.log)
+	case "paypal":
+		payment = NewFakePayPalGateway(cfg.PayPalFailEvery, w.log)
+	}
+	// The timeout bounds each attempt. A timed-out charge is not
+	// retried, because it may still have gone through.
+	if cfg.PaymentTimeout > 0 {
+		payment = NewTimeoutPaymentGateway(payment, time.Duration(cfg.PaymentTimeout), clock.System{})
+	}
+	if cfg.PaymentAttempts > 1 {
+		policy := DefaultRetryPolicy
+		policy.MaxAttempts = cfg.PaymentAttempts
+		payment = NewRetryingGateway(payment, policy, clock.System{})
+	}
+	// The breaker counts a charge once, however often it was retried.
+	if cfg.BreakerThreshold > 0 {
+		payment = NewBreakerPaymentGateway(payment, breaker.New(breaker.Settings{
+			Name:             "payment",
+			FailureThreshold: cfg.BreakerThreshold,
+			OpenTimeout:      time.Duration(cfg.BreakerOpenTimeout),
+			IsFailure:        IsPaymentGatewayFailure,
+			OnStateChange: func(name string, from, to breaker.State) {
+				orNop(w.log).Printf("%s circuit breaker %s -> %s", name, from, to)
+			},
+		}))
+	}
+	return NewMeteredPaymentGateway(payment, w.registry)
+}
+
+// emailSender returns the sender of the customer emails, delivering
+// them in the background when email_workers is set.
+func (w *wiring) emailSender(reporter ErrReporter) EmailSender {
+	cfg := w.cfg
+	var mail EmailSender = NewLoggingEmailSender(w.log)
+	if cfg.EmailTimeout > 0 {
+		mail = NewTimeoutEmailSender(mail, time.Duration(cfg.EmailTimeout), clock.System{})
+	}
+	mail = NewMeteredEmailSender(mail, w.registry)
+	if cfg.EmailWorkers == 0 {
+		return mail
+	}
+	queueCfg := EmailQueueConfig{
+		Workers: cfg.EmailWorkers,
+		Size:    cfg.EmailQueueSize,
+		Sender:  func(int) EmailSender { return mail },
+	}
+	if w.provider != nil {
+		queueCfg.Hooks = workqueue.Instrument(w.provider, "email_queue")
+	}
+	queue := NewEmailQueueFrom(queueCfg, w.log, reporter)
+	w.checks.Register("email_queue", health.CheckerFunc(queue.Check))
+	w.onClose(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), emailDrainTimeout)
+		defer cancel()
+		return queue.Shutdown(ctx)
+	})
+	return queue
+}
+
+func (w *wiring) invoiceGenerator(pricing *PricingService, cached cache.Cache) InvoiceGenerator {
+	cfg := w.cfg
+	renderers := map[InvoiceFormat]InvoiceRenderer{
+		InvoiceText: TextInvoiceRenderer{},
+		InvoiceHTML: HTMLInvoiceRenderer{},
+		InvoicePDF:  PDFInvoiceRenderer{},
+	}
+	for format, r := range renderers {
+		renderers[format] = NewMeteredInvoiceRenderer(r, w.registry)
+	}
+	invoices := NewInvoiceService(renderers[InvoiceFormat(cfg.InvoiceFormat)], pricing, w.log)
+	for format, r := range renderers {
+		invoices = invoices.WithFormat(format, r)
+	}
+	if len(cfg.Flags) > 0 {
+		experiment := InvoiceExperiment{Flags: flags.Static(cfg.Flags)}
+		if w.provider != nil {
+			experiment.Served = CountInvoiceVariants(w.provider)
+		}
+		invoices = invoices.WithExperiment(experiment)
+	}
+	if cached != nil {
+		return NewCachedInvoiceGenerator(invoices, cached, time.Duration(cfg.CacheTTL))
+	}
+	return invoices
+}
+
+// customers returns the configured customers; nil unless customers is
+// set.
+func (w *wiring) customers(ctx context.Context) (CustomerRepository, error) {
+	if len(w.cfg.Customers) == 0 {
+		return nil, nil
+	}
+	repo := NewInMemoryCustomerRepository()
+	for _, c := range w.cfg.Customers {
+		customer, _ := c.customer() // checked by Validate
+		if err := repo.Save(ctx, customer); err != nil {
+			return nil, err
+		}
+	}
+	return repo, nil
+}
+
+// orderSteps returns the options adding the optional steps of placing
+// an order that cfg turns on. customers may be nil.
+func (w *wiring) orderSteps(repo OrderStore, customers CustomerRepository) []OrderOption {
+	cfg, log := w.cfg, w.log
+	var opts []OrderOption
+	if customers != nil {
+		opts = append(opts, WithCustomers(customers))
+	}
+	if len(cfg.Stock) > 0 {
+		stock := NewInMemoryStockRepository()
+		for sku, quantity := range cfg.Stock {
+			stock.SetStock(sku, quantity)
+		}
+		opts = append(opts, WithInventory(NewInventoryService(stock, log)))
+	}
+	if len(cfg.Coupons) > 0 {
+		coupons := NewInMemoryCouponRepository()
+		for _, c := range cfg.Coupons {
+			coupon, _ := c.coupon(cfg.Currency) // checked by Validate
+			coupons.Add(coupon)
+		}
+		opts = append(opts, WithCoupons(NewCouponService(coupons, clock.System{}, log)))
+	}
+	if rules, _ := cfg.Fraud.rules(cfg.Currency, repo); len(rules) > 0 { // checked by Validate
+		opts = append(opts, WithFraudCheck(NewFraudCheckService(log, rules...)))
+	}
+	switch cfg.Carrier {
+	case "dhl":
+		opts = append(opts, WithShipping(NewShippingService(NewFakeDHLCarrier(), log)))
+	case "ups":
+		opts = append(opts, WithShipping(NewShippingService(NewFakeUPSCarrier(), log)))
+	}
+	return opts
+}
+
+// outboxDispatcher returns the dispatcher sending the confirmations
+// recorded in outbox. Services.Close sends those still pending.
+func (w *wiring) outboxDispatcher(outbox Outbox, repo OrderStore, customers CustomerRepository, mail EmailSender) *OutboxDispatcher {
+	dispatcher := NewOutboxDispatcher(outbox, repo, customers, mail, w.log)
+	w.onClose(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), outboxDrainTimeout)
+		defer cancel()
+		for {
+			sent, err := dispatcher.DispatchPending(ctx)
+			if err != nil || sent < outboxBatch {
+				return err
+			}
+		}
+	})
+	return dispatcher
+}
+
+func (w *wiring) jobs(repo OrderStore, blobs storage.Store, outbox *OutboxDispatcher) []sched.Entry {
+	jobs := []sched.Entry{{Name: "outbox", Schedule: sched.Every(time.Duration(w.cfg.OutboxInterval)), Job: outbox.Job()}}
+	if w.cfg.ReportSchedule != "" {
+		schedule, _ := sched.Parse(w.cfg.ReportSchedule) // checked by Validate
+		report := NewOrderReport(repo, blobs, nil).WithTracer(w.tracer)
+		jobs = append(jobs, sched.Entry{Name: "order report", Schedule: schedule, Job: report, Jitter: reportJitter})
+	}
+	return jobs
+}
+
+// verifier returns the configured token verifiers. It must only be
+// called on a valid Config.
+func (c Config) verifier() auth.Any {
+	var v auth.Any
+	if c.JWTSecret != "" {
+		jwt, _ := auth.NewJWT([]byte(c.JWTSecret), "orders", nil) // checked by Validate
+		v = append(v, jwt)
+	}
+	if len(c.APIKeys) > 0 {
+		keys := auth.NewAPIKeys(nil)
+		for _, k := range c.APIKeys {
+			keys.Add(k.Key, auth.Principal{Subject: k.Subject, Scopes: k.Scopes}) // checked by Validate
+		}
+		v = append(v, keys)
+	}
+	return v
+}
+
+// blobStore returns the configured blob store. It must only be called
+// on a valid Config.
+func (c Config) blobStore() storage.Store {
+	if c.Storage == "s3" {
+		s3, _ := c.s3() // checked by Validate
+		return s3
+	}
+	return storage.NewLocal(c.StorageDir)
+}
+
+func (c Config) s3() (*storage.S3, error) {
+	return storage.NewS3(storage.S3Config{
+		Endpoint:  c.S3Endpoint,
+		Region:    c.S3Region,
+		Bucket:    c.S3Bucket,
+		AccessKey: c.S3AccessKey,
+		SecretKey: c.S3SecretKey,
+	}, nil, nil)
+}
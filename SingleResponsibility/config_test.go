@@ -0,0 +1,135 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConfig_ValidatesOrderSteps(t *testing.T) {
+	ada := CustomerConfig{ID: 1, Name: "Ada", Email: "ada@example.com", Country: "US"}
+	tests := []struct {
+		name    string
+		edit    func(*Config)
+		wantErr string
+	}{
+		{"customer without email", func(c *Config) { c.Customers = []CustomerConfig{{ID: 1, Name: "Ada"}} }, "customers[0]"},
+		{"customer listed twice", func(c *Config) { c.Customers = []CustomerConfig{ada, ada} }, "listed twice"},
+		{"customer invoice format", func(c *Config) {
+			bad := ada
+			bad.InvoiceFormat = "doc"
+			c.Customers = []CustomerConfig{bad}
+		}, `unknown invoice format "doc"`},
+		{"carrier without customers", func(c *Config) { c.Carrier = "ups" }, "needs customers"},
+		{"unknown carrier", func(c *Config) { c.Customers, c.Carrier = []CustomerConfig{ada}, "fedex" }, `unknown carrier "fedex"`},
+		{"negative stock", func(c *Config) { c.Stock = map[string]int{"BOOK": -1} }, "stock of BOOK"},
+		{"coupon without discount", func(c *Config) { c.Coupons = []CouponConfig{{Code: "NONE"}} }, "exactly one of"},
+		{"coupon with two discounts", func(c *Config) { c.Coupons = []CouponConfig{{Code: "BOTH", Percent: 10, FreeShipping: true}} }, "exactly one of"},
+		{"coupon percent", func(c *Config) { c.Coupons = []CouponConfig{{Code: "ALL", Percent: 120}} }, "not a percentage"},
+		{"coupon expiry", func(c *Config) { c.Coupons = []CouponConfig{{Code: "OLD", Percent: 10, Expires: "soon"}} }, "expires"},
+		{"fraud amount", func(c *Config) { c.Fraud.Decline = "lots" }, "fraud: decline"},
+		{"fraud velocity", func(c *Config) { c.Fraud.MaxOrdersPerHour = -1 }, "max_orders_per_hour"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.edit(&cfg)
+			err := cfg.Validate()
+			if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Each optional step of placing an order is wired from the config, and
+// every step is audited.
+func TestWire_OrderSteps(t *testing.T) {
+	ctx := context.Background()
+	cfg := DefaultConfig()
+	cfg.Customers = []CustomerConfig{
+		{ID: 1, Name: "Ada", Email: "ada@example.com", Country: "US"},
+		{ID: 2, Name: "Mallory", Email: "mallory@example.com", Country: "US"},
+	}
+	cfg.Carrier = "ups"
+	cfg.Stock = map[string]int{"BOOK": 3}
+	cfg.Coupons = []CouponConfig{{Code: "TENOFF", Percent: 10}}
+	cfg.Fraud.Blocklist = []int{2}
+	services, err := Wire(ctx, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer services.Close()
+
+	order := testOrder(t, 1)
+	order.CouponCode = "TENOFF"
+	placed, err := services.Orders.PlaceOrder(ctx, "", order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if placed.Total.String() != "22.50 USD" || placed.Carrier != "UPS" || placed.TrackingNumber == "" {
+		t.Errorf("placed for %s with %s %q; want 22.50 USD shipped with UPS", placed.Total, placed.Carrier, placed.TrackingNumber)
+	}
+	var actions []AuditAction
+	entries, err := services.Audit.History(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		actions = append(actions, e.Action)
+	}
+	for _, want := range []AuditAction{AuditCouponRedeemed, AuditStockReserved, AuditOrderPaid, AuditShipmentBooked} {
+		if !slices.Contains(actions, want) {
+			t.Errorf("audit trail %v lacks %s", actions, want)
+		}
+	}
+
+	// One book is left.
+	if _, err := services.Orders.PlaceOrder(ctx, "", testOrder(t, 2)); !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("overselling PlaceOrder = %v, want ErrInsufficientStock", err)
+	}
+	blocked, err := NewOrder(3, 2, []OrderItem{{SKU: "BOOK", Quantity: 1, UnitPrice: NewMoney(1250, "USD")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := services.Orders.PlaceOrder(ctx, "", blocked); !errors.Is(err, ErrFraudDeclined) {
+		t.Errorf("blocked customer's PlaceOrder = %v, want ErrFraudDeclined", err)
+	}
+	unknown, err := NewOrder(4, 9, []OrderItem{{SKU: "BOOK", Quantity: 1, UnitPrice: NewMoney(1250, "USD")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := services.Orders.PlaceOrder(ctx, "", unknown); !errors.Is(err, ErrCustomerNotFound) {
+		t.Errorf("unknown customer's PlaceOrder = %v, want ErrCustomerNotFound", err)
+	}
+}
+
+// Order IDs continue after the orders stored by an earlier run, and
+// an order is never saved over another.
+func TestWire_OrderIDsSurviveRestart(t *testing.T) {
+	ctx := context.Background()
+	cfg := DefaultConfig()
+	cfg.Store, cfg.SQLDriver, cfg.SQLDSN = "sql", "sqlite", filepath.Join(t.TempDir(), "orders.db")
+
+	for run, want := range []int{1, 2} {
+		services, err := Wire(ctx, cfg, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		order, err := NewOrder(services.NextOrderID(), 1, []OrderItem{{SKU: "BOOK", Quantity: 1, UnitPrice: NewMoney(1250, "USD")}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		placed, err := services.Orders.PlaceOrder(ctx, "", order)
+		if err != nil || placed.ID != want {
+			t.Errorf("run %d placed order %d, %v; want order %d", run, placed.ID, err, want)
+		}
+		if _, err := services.Orders.PlaceOrder(ctx, "", testOrder(t, 1)); !errors.Is(err, ErrOrderExists) {
+			t.Errorf("run %d: placing order 1 again = %v, want ErrOrderExists", run, err)
+		}
+		services.Close()
+	}
+}
@@ -0,0 +1,53 @@
+package singleresponsibility
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/consumer"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/eventbus"
+)
+
+// ConfirmationConsumer is a consumer.MessageHandler that turns the
+// OrderPlaced records BusPublisher writes to a log into confirmation
+// emails, the streaming counterpart of the confirmation step
+// SubscribeSteps runs in process. Give it an EmailService over an
+// EmailQueue and the consumer only enqueues.
+//
+// The consumer delivers at least once, so every email carries a dedup
+// key naming the order: a redelivered record does not email the
+// customer twice.
+type ConfirmationConsumer struct {
+	customers CustomerRepository
+	email     *EmailService
+	log       Logger
+}
+
+// NewConfirmationConsumer returns a consumer of OrderPlaced records.
+// customers may be nil, as for OrderService.
+func NewConfirmationConsumer(customers CustomerRepository, email *EmailService, log Logger) ConfirmationConsumer {
+	return ConfirmationConsumer{customers: customers, email: email, log: orNop(log)}
+}
+
+func (c ConfirmationConsumer) Handle(ctx context.Context, r consumer.Record) error {
+	e, err := decodeEvent(eventbus.Message{Topic: r.Topic, Data: r.Value})
+	if err != nil {
+		// Retrying cannot fix a record we cannot read, and would hold up
+		// the partition; skip it.
+		logf(ctx, c.log, "Skipping record %s/%d@%d: %v", r.Topic, r.Partition, r.Offset, err)
+		return nil
+	}
+	placed, ok := e.(OrderPlaced)
+	if !ok {
+		return nil
+	}
+	order := placed.Order
+	customer, err := resolveCustomer(ctx, c.customers, order)
+	if err != nil {
+		return err
+	}
+	if err := c.email.SendOrderConfirmationOnce(ctx, fmt.Sprintf("order-%d-confirmation", order.ID), customer, order); err != nil {
+		return fmt.Errorf("sending confirmation for order %d: %w", order.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package singleresponsibility
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/consumer"
+)
+
+func TestConfirmationConsumer_EnqueuesOnce(t *testing.T) {
+	ctx := context.Background()
+	broker := consumer.NewMemoryBroker(2)
+	orders, err := NewOrderService(NewInMemoryOrderRepository(), NewFakeStripeGateway(NewMoney(10000, "USD"), nil), NewLoggingEmailSender(nil),
+		NewInvoiceService(TextInvoiceRenderer{}, NewPricingService(nil), nil), WithEventPublisher(NewBusPublisher(broker)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, err := NewOrder(7, 1, []OrderItem{{SKU: "BOOK", Quantity: 1, UnitPrice: NewMoney(1250, "USD")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := orders.PlaceOrder(ctx, "", order); err != nil {
+		t.Fatal(err)
+	}
+	// A publisher retrying after a lost acknowledgement writes the
+	// event twice, and a record the consumer cannot read is skipped.
+	placed, _ := broker.Fetch(ctx, EventOrderPlaced, 0, 0, 1)
+	broker.Produce(EventOrderPlaced, nil, placed[0].Value)
+	broker.Produce(EventOrderPlaced, nil, []byte("not json"))
+
+	sender := NewLoggingEmailSender(nil)
+	queue := NewEmailQueue(sender, 2, 10, nil, nil)
+	handled := make(chan struct{}, 10)
+	confirmations := NewConfirmationConsumer(nil, NewEmailService(queue), nil)
+	c, err := consumer.New(broker, consumer.HandlerFunc(func(ctx context.Context, r consumer.Record) error {
+		defer func() { handled <- struct{}{} }()
+		return confirmations.Handle(ctx, r)
+	}), consumer.Config{Group: "confirmations", Topic: EventOrderPlaced})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() { done <- c.Run(runCtx) }()
+	for range 3 {
+		select {
+		case <-handled:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the consumer")
+		}
+	}
+	stop()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if sent := sender.Sent(); len(sent) != 1 || sent[0].DedupKey != "order-7-confirmation" {
+		t.Errorf("sent %+v, want one confirmation for order 7", sent)
+	}
+}
@@ -0,0 +1,261 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+var (
+	ErrCouponNotFound  = errors.New("coupon not found")
+	ErrCouponExpired   = errors.New("coupon expired")
+	ErrCouponExhausted = errors.New("coupon usage limit reached")
+)
+
+// Discount is what a coupon takes off an order.
+type Discount struct {
+	Amount       Money
+	FreeShipping bool
+}
+
+// DiscountKind computes the discount of one kind of coupon. Adding a
+// kind of discount means adding a type, not editing CouponService.
+type DiscountKind interface {
+	Discount(order Order) (Discount, error)
+}
+
+// PercentageDiscount takes Percent percent off the price of the goods.
+type PercentageDiscount struct {
+	Percent float64
+}
+
+func (d PercentageDiscount) Discount(order Order) (Discount, error) {
+	return Discount{Amount: itemsSubtotal(order.Items).MulRate(d.Percent / 100)}, nil
+}
+
+// FixedDiscount takes a fixed amount off, never more than the price of
+// the goods.
+type FixedDiscount struct {
+	Amount Money
+}
+
+func (d FixedDiscount) Discount(order Order) (Discount, error) {
+	subtotal := itemsSubtotal(order.Items)
+	cmp, err := d.Amount.Cmp(subtotal)
+	if err != nil {
+		return Discount{}, err
+	}
+	if cmp > 0 {
+		return Discount{Amount: subtotal}, nil
+	}
+	return Discount{Amount: d.Amount}, nil
+}
+
+// FreeShippingDiscount waives shipping and leaves the goods at full
+// price.
+type FreeShippingDiscount struct{}
+
+func (FreeShippingDiscount) Discount(order Order) (Discount, error) {
+	return Discount{Amount: NewMoney(0, itemsCurrency(order.Items)), FreeShipping: true}, nil
+}
+
+// Coupon is a redeemable discount code. A zero ExpiresAt never expires
+// and a zero MaxUses allows unlimited redemptions.
+type Coupon struct {
+	Code      string
+	Kind      DiscountKind
+	ExpiresAt time.Time
+	MaxUses   int
+	Uses      int
+}
+
+// CouponRepository stores coupons and counts their redemptions.
+type CouponRepository interface {
+	FindByCode(ctx context.Context, code string) (Coupon, error)
+	// Redeem counts one use of code, failing with ErrCouponExhausted
+	// once MaxUses is reached.
+	Redeem(ctx context.Context, code string) error
+	// Unredeem takes back a use counted by Redeem.
+	Unredeem(ctx context.Context, code string) error
+}
+
+// CouponService is responsible only for coupons: checking that a code
+// may be used and working out its discount.
+type CouponService struct {
+	coupons CouponRepository
+	clock   clock.Clock
+	log     Logger
+}
+
+func NewCouponService(coupons CouponRepository, clk clock.Clock, log Logger) *CouponService {
+	return &CouponService{coupons: coupons, clock: clock.OrSystem(clk), log: orNop(log)}
+}
+
+// Validate returns the discount code gives on order without using the
+// coupon up.
+func (s *CouponService) Validate(ctx context.Context, code string, order Order) (Discount, error) {
+	coupon, err := s.coupons.FindByCode(ctx, code)
+	if err != nil {
+		return Discount{}, err
+	}
+	if !coupon.ExpiresAt.IsZero() && !s.clock.Now().Before(coupon.ExpiresAt) {
+		return Discount{}, fmt.Errorf("%w: %s expired on %s", ErrCouponExpired, coupon.Code, coupon.ExpiresAt.Format("2006-01-02"))
+	}
+	if coupon.MaxUses > 0 && coupon.Uses >= coupon.MaxUses {
+		return Discount{}, fmt.Errorf("%w: %s", ErrCouponExhausted, coupon.Code)
+	}
+
+	discount, err := coupon.Kind.Discount(order)
+	if err != nil {
+		return Discount{}, fmt.Errorf("coupon %s: %w", coupon.Code, err)
+	}
+	return discount, nil
+}
+
+// Redeem validates order's coupon, counts the use and returns the
+// order carrying the discount. PricingService takes it off the total.
+func (s *CouponService) Redeem(ctx context.Context, order Order) (Order, error) {
+	discount, err := s.Validate(ctx, order.CouponCode, order)
+	if err != nil {
+		return Order{}, fmt.Errorf("applying coupon to order %d: %w", order.ID, err)
+	}
+	if err := s.coupons.Redeem(ctx, order.CouponCode); err != nil {
+		return Order{}, fmt.Errorf("applying coupon to order %d: %w", order.ID, err)
+	}
+
+	order.Discount = discount.Amount
+	order.FreeShipping = discount.FreeShipping
+	logf(ctx, s.log, "Coupon %s took %s off order %d", order.CouponCode, discount.Amount, order.ID)
+	return order, nil
+}
+
+// Release gives back a use of code, for when the order it was
+// redeemed for is not placed after all.
+func (s *CouponService) Release(ctx context.Context, code string) error {
+	if err := s.coupons.Unredeem(ctx, code); err != nil {
+		return fmt.Errorf("releasing coupon %s: %w", code, err)
+	}
+	return nil
+}
+
+// InMemoryCouponRepository keeps coupons in a map keyed by their
+// upper-cased code, so codes are case-insensitive. It is safe for
+// concurrent use.
+type InMemoryCouponRepository struct {
+	mu      sync.Mutex
+	coupons map[string]Coupon
+}
+
+func NewInMemoryCouponRepository() *InMemoryCouponRepository {
+	return &InMemoryCouponRepository{coupons: make(map[string]Coupon)}
+}
+
+// Add stores coupon, replacing any coupon with the same code.
+func (r *InMemoryCouponRepository) Add(coupon Coupon) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.coupons[strings.ToUpper(coupon.Code)] = coupon
+}
+
+func (r *InMemoryCouponRepository) FindByCode(ctx context.Context, code string) (Coupon, error) {
+	if err := ctx.Err(); err != nil {
+		return Coupon{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	coupon, ok := r.coupons[strings.ToUpper(code)]
+	if !ok {
+		return Coupon{}, fmt.Errorf("%w: %q", ErrCouponNotFound, code)
+	}
+	return coupon, nil
+}
+
+func (r *InMemoryCouponRepository) Redeem(ctx context.Context, code string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := strings.ToUpper(code)
+	coupon, ok := r.coupons[key]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrCouponNotFound, code)
+	}
+	// Checked again under the lock: another order may have used the
+	// last redemption since Validate.
+	if coupon.MaxUses > 0 && coupon.Uses >= coupon.MaxUses {
+		return fmt.Errorf("%w: %s", ErrCouponExhausted, coupon.Code)
+	}
+	coupon.Uses++
+	r.coupons[key] = coupon
+	return nil
+}
+
+func (r *InMemoryCouponRepository) Unredeem(ctx context.Context, code string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := strings.ToUpper(code)
+	coupon, ok := r.coupons[key]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrCouponNotFound, code)
+	}
+	if coupon.Uses > 0 {
+		coupon.Uses--
+		r.coupons[key] = coupon
+	}
+	return nil
+}
+
+// WithCoupons has the service honour coupon codes. Without it an
+// order carrying a coupon code is rejected.
+func WithCoupons(coupons *CouponService) OrderOption {
+	return WithStep(couponStep{coupons: coupons})
+}
+
+// acceptsCoupons reports whether the service redeems coupon codes.
+func (os OrderService) acceptsCoupons() bool {
+	return slices.ContainsFunc(os.steps, func(s PlacementStep) bool {
+		_, ok := s.(couponStep)
+		return ok
+	})
+}
+
+// couponStep redeems the coupon of an order before it is priced.
+type couponStep struct {
+	coupons *CouponService
+}
+
+func (couponStep) Name() string          { return "coupon" }
+func (couponStep) Stage() PlacementStage { return BeforePricing }
+
+func (s couponStep) Run(ctx context.Context, _ Customer, order Order) (StepResult, error) {
+	if order.CouponCode == "" {
+		return StepResult{Order: order}, nil
+	}
+	discounted, err := s.coupons.Redeem(ctx, order)
+	if err != nil {
+		return StepResult{}, err
+	}
+	code := discounted.CouponCode
+	return StepResult{
+		Order:  discounted,
+		Audit:  AuditCouponRedeemed,
+		Detail: fmt.Sprintf("%s: -%s", code, discounted.Discount),
+		Undo:   func(ctx context.Context) error { return s.coupons.Release(ctx, code) },
+	}, nil
+}
@@ -0,0 +1,118 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+)
+
+func TestDiscountKinds(t *testing.T) {
+	order := testOrder(t, 1) // 25.00 USD of goods
+	tests := []struct {
+		name    string
+		kind    DiscountKind
+		want    Discount
+		wantErr error
+	}{
+		{"percentage", PercentageDiscount{Percent: 10}, Discount{Amount: NewMoney(250, "USD")}, nil},
+		{"fixed", FixedDiscount{Amount: NewMoney(500, "USD")}, Discount{Amount: NewMoney(500, "USD")}, nil},
+		{"fixed above the price", FixedDiscount{Amount: NewMoney(3000, "USD")}, Discount{Amount: NewMoney(2500, "USD")}, nil},
+		{"fixed in another currency", FixedDiscount{Amount: NewMoney(500, "EUR")}, Discount{}, ErrCurrencyMismatch},
+		{"free shipping", FreeShippingDiscount{}, Discount{Amount: NewMoney(0, "USD"), FreeShipping: true}, nil},
+	}
+	for _, tt := range tests {
+		got, err := tt.kind.Discount(order)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("%s: Discount = %+v, %v; want %+v, %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCouponService_Validate(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	coupons := NewInMemoryCouponRepository()
+	coupons.Add(Coupon{Code: "SAVE10", Kind: PercentageDiscount{Percent: 10}})
+	coupons.Add(Coupon{Code: "MARCH", Kind: PercentageDiscount{Percent: 10}, ExpiresAt: now.Add(time.Hour)})
+	coupons.Add(Coupon{Code: "FEB", Kind: PercentageDiscount{Percent: 10}, ExpiresAt: now})
+	coupons.Add(Coupon{Code: "ONCE", Kind: PercentageDiscount{Percent: 10}, MaxUses: 1, Uses: 1})
+	coupons.Add(Coupon{Code: "TWICE", Kind: PercentageDiscount{Percent: 10}, MaxUses: 2, Uses: 1})
+	s := NewCouponService(coupons, clocktest.NewFake(now), nil)
+
+	tests := []struct {
+		code    string
+		wantErr error
+	}{
+		{"SAVE10", nil},
+		{"save10", nil}, // codes are case-insensitive
+		{"MARCH", nil},
+		{"FEB", ErrCouponExpired}, // expires at the instant given
+		{"ONCE", ErrCouponExhausted},
+		{"TWICE", nil},
+		{"NOPE", ErrCouponNotFound},
+	}
+	for _, tt := range tests {
+		discount, err := s.Validate(ctx, tt.code, testOrder(t, 1))
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Validate(%q) = %v, want %v", tt.code, err, tt.wantErr)
+		}
+		if err == nil && discount.Amount != NewMoney(250, "USD") {
+			t.Errorf("Validate(%q) = %+v, want 2.50 USD off", tt.code, discount)
+		}
+	}
+}
+
+func TestCouponService_RedeemAndRelease(t *testing.T) {
+	ctx := context.Background()
+	coupons := NewInMemoryCouponRepository()
+	coupons.Add(Coupon{Code: "ONCE", Kind: FixedDiscount{Amount: NewMoney(500, "USD")}, MaxUses: 1})
+	s := NewCouponService(coupons, clock.System{}, nil)
+	order := testOrder(t, 1)
+	order.CouponCode = "ONCE"
+
+	redeemed, err := s.Redeem(ctx, order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redeemed.Discount != NewMoney(500, "USD") || redeemed.FreeShipping {
+		t.Errorf("Redeem = %+v, want 5.00 USD off", redeemed)
+	}
+	if _, err := s.Redeem(ctx, order); !errors.Is(err, ErrCouponExhausted) {
+		t.Errorf("second Redeem = %v, want ErrCouponExhausted", err)
+	}
+
+	if err := s.Release(ctx, "ONCE"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Redeem(ctx, order); err != nil {
+		t.Errorf("Redeem after Release = %v", err)
+	}
+	if err := s.Release(ctx, "NOPE"); !errors.Is(err, ErrCouponNotFound) {
+		t.Errorf("Release(NOPE) = %v, want ErrCouponNotFound", err)
+	}
+}
+
+// A coupon used by an order that is not placed can be used again.
+func TestOrderService_PlaceOrder_ReleasesCoupon(t *testing.T) {
+	ctx := context.Background()
+	coupons := NewInMemoryCouponRepository()
+	coupons.Add(Coupon{Code: "ONCE", Kind: PercentageDiscount{Percent: 10}, MaxUses: 1})
+	orders, err := NewOrderService(NewInMemoryOrderRepository(), NewFakeStripeGateway(NewMoney(100, "USD"), nil), NewLoggingEmailSender(nil),
+		NewInvoiceService(TextInvoiceRenderer{}, NewPricingService(nil), nil), WithCoupons(NewCouponService(coupons, clock.System{}, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := testOrder(t, 1)
+	order.CouponCode = "ONCE"
+
+	if _, err := orders.PlaceOrder(ctx, "", order); !errors.Is(err, ErrPaymentDeclined) {
+		t.Fatalf("PlaceOrder = %v, want ErrPaymentDeclined", err)
+	}
+	if coupon, _ := coupons.FindByCode(ctx, "ONCE"); coupon.Uses != 0 {
+		t.Errorf("coupon used %d times after a declined order, want 0", coupon.Uses)
+	}
+}
@@ -0,0 +1,138 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrCustomerNotFound = errors.New("customer not found")
+	ErrInvalidCustomer  = errors.New("invalid customer")
+)
+
+// Address is a postal address used for billing and shipping.
+type Address struct {
+	Street     string
+	City       string
+	PostalCode string
+	Country    string
+}
+
+// Lines formats the address for documents, skipping empty parts.
+func (a Address) Lines() []string {
+	var lines []string
+	if a.Street != "" {
+		lines = append(lines, a.Street)
+	}
+	if city := strings.TrimSpace(a.PostalCode + " " + a.City); city != "" {
+		lines = append(lines, city)
+	}
+	if a.Country != "" {
+		lines = append(lines, a.Country)
+	}
+	return lines
+}
+
+// Customer is the person an order is placed for.
+type Customer struct {
+	ID      int
+	Name    string
+	Email   EmailAddress
+	Address Address
+
+	// InvoiceFormat is the preferred invoice format; empty means the
+	// InvoiceService default.
+	InvoiceFormat InvoiceFormat
+	// Locale, such as "de" or "de-AT", is the language of the emails
+	// and invoices; empty means English.
+	Locale string
+}
+
+// NewCustomer validates its inputs and returns a Customer.
+func NewCustomer(id int, name string, email EmailAddress, address Address) (Customer, error) {
+	if id <= 0 {
+		return Customer{}, fmt.Errorf("%w: %d", ErrInvalidCustomerID, id)
+	}
+	if strings.TrimSpace(name) == "" {
+		return Customer{}, fmt.Errorf("%w: name is required", ErrInvalidCustomer)
+	}
+	if err := email.Validate(); err != nil {
+		return Customer{}, err
+	}
+	return Customer{ID: id, Name: name, Email: email, Address: address}, nil
+}
+
+// CustomerRepository stores customers.
+type CustomerRepository interface {
+	Save(ctx context.Context, customer Customer) error
+	FindByID(ctx context.Context, id int) (Customer, error)
+}
+
+// InMemoryCustomerRepository keeps customers in a map. It is safe for
+// concurrent use.
+type InMemoryCustomerRepository struct {
+	mu        sync.RWMutex
+	customers map[int]Customer
+}
+
+func NewInMemoryCustomerRepository() *InMemoryCustomerRepository {
+	return &InMemoryCustomerRepository{customers: make(map[int]Customer)}
+}
+
+func (r *InMemoryCustomerRepository) Save(ctx context.Context, customer Customer) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if customer.ID <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidCustomerID, customer.ID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.customers[customer.ID] = customer
+	return nil
+}
+
+func (r *InMemoryCustomerRepository) FindByID(ctx context.Context, id int) (Customer, error) {
+	if err := ctx.Err(); err != nil {
+		return Customer{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	customer, ok := r.customers[id]
+	if !ok {
+		return Customer{}, fmt.Errorf("%w: %d", ErrCustomerNotFound, id)
+	}
+	return customer, nil
+}
+
+// WithCustomers has the service resolve each order's customer from
+// customers. Unknown customers fail the order before any side effect
+// runs.
+func WithCustomers(customers CustomerRepository) OrderOption {
+	return func(os *OrderService) { os.customers = customers }
+}
+
+// resolveCustomer loads the customer an order belongs to. Without a
+// CustomerRepository only the ID is known, so the customer gets a
+// placeholder address built from it.
+func resolveCustomer(ctx context.Context, customers CustomerRepository, order Order) (Customer, error) {
+	if customers == nil {
+		return Customer{
+			ID:    order.CustomerID,
+			Name:  fmt.Sprintf("Customer #%d", order.CustomerID),
+			Email: EmailAddress(fmt.Sprintf("customer-%d@example.com", order.CustomerID)),
+		}, nil
+	}
+
+	customer, err := customers.FindByID(ctx, order.CustomerID)
+	if err != nil {
+		return Customer{}, fmt.Errorf("resolving customer of order %d: %w", order.ID, err)
+	}
+	return customer, nil
+}
@@ -0,0 +1,179 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"sync"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/i18n"
+)
+
+var ErrInvalidEmail = errors.New("invalid email address")
+
+// EmailAddress is a recipient address. Using a dedicated type instead
+// of a plain string keeps subjects and bodies from being passed as
+// recipients by mistake.
+type EmailAddress string
+
+func (a EmailAddress) Validate() error {
+	if _, err := mail.ParseAddress(string(a)); err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidEmail, string(a))
+	}
+	return nil
+}
+
+// EmailMessage is a fully rendered email, ready for delivery.
+type EmailMessage struct {
+	To      EmailAddress
+	Subject string
+	Body    string
+
+	// DedupKey, if set, identifies the message across retries: a
+	// sender drops a second message with the same key.
+	DedupKey string
+}
+
+// EmailSender delivers rendered messages. SMTP, an HTTP provider or a
+// fake can sit behind it; EmailService does not care.
+type EmailSender interface {
+	Send(ctx context.Context, msg EmailMessage) error
+}
+
+// EmailService is responsible only for composing customer emails, in
+// the customer's language. Delivery is delegated to an EmailSender and
+// the wording to an i18n.Translator.
+type EmailService struct {
+	sender   EmailSender
+	messages i18n.Translator
+}
+
+// NewEmailService returns an email service writing the built-in
+// messages; see WithMessages.
+func NewEmailService(sender EmailSender) *EmailService {
+	return &EmailService{sender: sender, messages: defaultMessages}
+}
+
+// WithMessages returns a copy of the service that takes its subjects
+// and bodies, the email.<kind>.subject and email.<kind>.body messages,
+// from messages.
+func (e *EmailService) WithMessages(messages i18n.Translator) *EmailService {
+	c := *e
+	c.messages = messagesOr(messages)
+	return &c
+}
+
+// SendOrderConfirmation renders the order-confirmation email for order
+// and hands it to the sender, addressed to the customer.
+func (e *EmailService) SendOrderConfirmation(ctx context.Context, customer Customer, order Order) error {
+	return e.send(ctx, "confirmation", "", customer, order)
+}
+
+// SendOrderConfirmationOnce is SendOrderConfirmation for callers that
+// may retry: every attempt carries dedupKey, so the customer gets the
+// email once.
+func (e *EmailService) SendOrderConfirmationOnce(ctx context.Context, dedupKey string, customer Customer, order Order) error {
+	return e.send(ctx, "confirmation", dedupKey, customer, order)
+}
+
+// SendRefundNotice tells the customer that order was refunded.
+func (e *EmailService) SendRefundNotice(ctx context.Context, customer Customer, order Order) error {
+	return e.send(ctx, "refund", "", customer, order)
+}
+
+// SendShippedNotice tells the customer that order has shipped.
+func (e *EmailService) SendShippedNotice(ctx context.Context, customer Customer, order Order) error {
+	return e.send(ctx, "shipped", "", customer, order)
+}
+
+// SendDeliveredNotice tells the customer that order was delivered.
+func (e *EmailService) SendDeliveredNotice(ctx context.Context, customer Customer, order Order) error {
+	return e.send(ctx, "delivered", "", customer, order)
+}
+
+// Accepting fails with ErrEmailQueueFull while the sender, such as an
+// EmailQueue, has no room for another email, so callers can hold off
+// before doing anything an email must follow.
+func (e *EmailService) Accepting(ctx context.Context) error {
+	checker, ok := e.sender.(interface{ Check(context.Context) error })
+	if !ok {
+		return nil
+	}
+	if err := checker.Check(ctx); errors.Is(err, ErrEmailQueueFull) {
+		return err
+	}
+	return nil
+}
+
+func (e *EmailService) send(ctx context.Context, kind, dedupKey string, customer Customer, order Order) error {
+	if err := customer.Email.Validate(); err != nil {
+		return err
+	}
+
+	data := struct {
+		Customer Customer
+		Order    Order
+	}{customer, order}
+
+	subject, err := e.messages.Translate(customer.Locale, "email."+kind+".subject", data)
+	if err != nil {
+		return fmt.Errorf("rendering %s email: %w", kind, err)
+	}
+	body, err := e.messages.Translate(customer.Locale, "email."+kind+".body", data)
+	if err != nil {
+		return fmt.Errorf("rendering %s email: %w", kind, err)
+	}
+
+	return e.sender.Send(ctx, EmailMessage{
+		To:       customer.Email,
+		Subject:  subject,
+		Body:     body,
+		DedupKey: dedupKey,
+	})
+}
+
+// LoggingEmailSender logs every message instead of delivering it and
+// remembers what it sent. Like a real provider, it drops messages
+// whose DedupKey it has already seen.
+type LoggingEmailSender struct {
+	log Logger
+
+	mu   sync.Mutex
+	sent []EmailMessage
+	keys map[string]bool
+}
+
+func NewLoggingEmailSender(log Logger) *LoggingEmailSender {
+	return &LoggingEmailSender{log: orNop(log)}
+}
+
+func (s *LoggingEmailSender) Send(ctx context.Context, msg EmailMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if msg.DedupKey != "" {
+		if s.keys[msg.DedupKey] {
+			logf(ctx, s.log, "Dropped duplicate email %s to %s", msg.DedupKey, msg.To)
+			return nil
+		}
+		if s.keys == nil {
+			s.keys = make(map[string]bool)
+		}
+		s.keys[msg.DedupKey] = true
+	}
+	logf(ctx, s.log, "To: %s\nSubject: %s\n\n%s", msg.To, msg.Subject, msg.Body)
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (s *LoggingEmailSender) Sent() []EmailMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]EmailMessage(nil), s.sent...)
+}
@@ -0,0 +1,120 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/errreport"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/workqueue"
+)
+
+var (
+	ErrEmailQueueClosed = workqueue.ErrClosed
+	// ErrEmailQueueFull is the queue's backpressure: no email can be
+	// queued until a worker frees up room.
+	ErrEmailQueueFull = workqueue.ErrFull
+)
+
+// EmailQueue is an EmailSender that only enqueues. A pool of worker
+// goroutines delivers the queued messages through the wrapped sender,
+// so a slow mail provider no longer holds up placing an order.
+// EmailService and OrderService are unchanged; they just get a sender
+// that returns immediately.
+//
+// When the queue is full, Send fails with ErrEmailQueueFull instead of
+// waiting, and OrderService turns orders away before charging them
+// (see EmailService.Accepting); the API answers those with 503.
+//
+// Delivery failures can no longer reach the caller, so the workers log
+// them and report them to an ErrReporter. A sender that panics is
+// reported too, and the worker carries on with the next email.
+type EmailQueue struct {
+	queue    *workqueue.Queue[EmailMessage]
+	log      Logger
+	reporter ErrReporter
+}
+
+// EmailQueueConfig sizes an EmailQueue.
+type EmailQueueConfig struct {
+	Workers int // at least one runs
+	Size    int // emails waiting for a worker
+	// Sender returns the sender of each worker, numbered from 0, so
+	// each can hold its own connection to the provider.
+	Sender func(worker int) EmailSender
+	// Hooks observe the queue; see workqueue.Instrument.
+	Hooks workqueue.Hooks
+}
+
+// NewEmailQueue starts workers goroutines delivering through next. Up
+// to size messages wait in the queue. reporter may be nil.
+func NewEmailQueue(next EmailSender, workers, size int, log Logger, reporter ErrReporter) *EmailQueue {
+	return NewEmailQueueFrom(EmailQueueConfig{
+		Workers: workers,
+		Size:    size,
+		Sender:  func(int) EmailSender { return next },
+	}, log, reporter)
+}
+
+// NewEmailQueueFrom starts the EmailQueue described by cfg. reporter
+// may be nil.
+func NewEmailQueueFrom(cfg EmailQueueConfig, log Logger, reporter ErrReporter) *EmailQueue {
+	q := &EmailQueue{log: orNop(log), reporter: errreport.OrNop(reporter)}
+	q.queue = workqueue.New(func(worker int) workqueue.Handler[EmailMessage] {
+		return q.deliver(cfg.Sender(worker))
+	}, workqueue.Options{Workers: cfg.Workers, Size: cfg.Size, Hooks: cfg.Hooks}, q.failed)
+	return q
+}
+
+// Send enqueues msg. The delivery keeps ctx's values but not its
+// cancellation, so an email outlives the request that queued it.
+func (q *EmailQueue) Send(ctx context.Context, msg EmailMessage) error {
+	if err := q.queue.Put(ctx, msg); err != nil {
+		return fmt.Errorf("email queue: %w", err)
+	}
+	return nil
+}
+
+// deliver sends through next, reporting a panic with the email's tags.
+func (q *EmailQueue) deliver(next EmailSender) workqueue.Handler[EmailMessage] {
+	return func(ctx context.Context, msg EmailMessage) error {
+		return errreport.Guard(ctx, q.reporter, errorTags(ctx, "EmailQueue.Send"), func() error {
+			return next.Send(ctx, msg)
+		})
+	}
+}
+
+func (q *EmailQueue) failed(ctx context.Context, msg EmailMessage, err error) {
+	if !errors.As(err, new(*errreport.PanicError)) { // Guard reported the panic
+		q.reporter.Capture(ctx, err, errorTags(ctx, "EmailQueue.Send"))
+	}
+	logf(ctx, q.log, "Email queue: sending %q to %s: %v", msg.Subject, msg.To, err)
+}
+
+// Shutdown stops accepting messages and waits until the workers have
+// delivered everything already queued, or until ctx is done. Calling
+// it again waits again.
+func (q *EmailQueue) Shutdown(ctx context.Context) error {
+	if err := q.queue.Shutdown(ctx); err != nil {
+		return fmt.Errorf("email queue: %w", err)
+	}
+	return nil
+}
+
+// Check reports whether the queue accepts messages: it fails once the
+// queue is shut down or while it is full. It is a health check.
+func (q *EmailQueue) Check(ctx context.Context) error {
+	if q.queue.Closed() {
+		return fmt.Errorf("email queue: %w", ErrEmailQueueClosed)
+	}
+	if q.queue.Cap() > 0 && q.queue.Len() == q.queue.Cap() {
+		return fmt.Errorf("email queue: %w: %d messages waiting", ErrEmailQueueFull, q.queue.Len())
+	}
+	return nil
+}
+
+// Drained reports whether Shutdown has finished delivering every
+// queued message.
+func (q *EmailQueue) Drained() bool {
+	return q.queue.Drained()
+}
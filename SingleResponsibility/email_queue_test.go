@@ -0,0 +1,62 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEmailQueue_FullTurnsOrdersAwayBeforeCharging(t *testing.T) {
+	release := make(chan struct{})
+	var workers [2]atomic.Int32
+	queue := NewEmailQueueFrom(EmailQueueConfig{
+		Workers: 2,
+		Size:    1,
+		Sender: func(worker int) EmailSender {
+			return emailSenderFunc(func(ctx context.Context, msg EmailMessage) error {
+				workers[worker].Add(1)
+				<-release
+				return nil
+			})
+		},
+	}, nil, nil)
+
+	// Both workers busy and one email waiting: no room.
+	for n := range int32(2) {
+		if err := queue.Send(context.Background(), EmailMessage{To: "busy@example.com"}); err != nil {
+			t.Fatal(err)
+		}
+		for workers[0].Load()+workers[1].Load() <= n {
+			time.Sleep(time.Millisecond) // until a worker holds it
+		}
+	}
+	if err := queue.Send(context.Background(), EmailMessage{To: "waiting@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.Send(context.Background(), EmailMessage{To: "one-too-many@example.com"}); !errors.Is(err, ErrEmailQueueFull) {
+		t.Fatalf("Send on a full queue: err = %v, want ErrEmailQueueFull", err)
+	}
+
+	payment := &countingGateway{}
+	orders, err := NewOrderService(NewInMemoryOrderRepository(), payment, queue, NewInvoiceService(TextInvoiceRenderer{}, nil, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = orders.PlaceOrder(context.Background(), "", testOrder(t, 1))
+	if !errors.Is(err, ErrEmailQueueFull) || statusFor(err) != 503 {
+		t.Errorf("PlaceOrder: err = %v (status %d), want ErrEmailQueueFull and 503", err, statusFor(err))
+	}
+	if payment.calls != 0 {
+		t.Errorf("%d charges; a full queue must turn the order away first", payment.calls)
+	}
+
+	close(release)
+	if err := queue.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if workers[0].Load() == 0 || workers[1].Load() == 0 {
+		t.Errorf("emails per worker = %d, %d; each worker has its own sender", workers[0].Load(), workers[1].Load())
+	}
+}
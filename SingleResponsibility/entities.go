@@ -0,0 +1,38 @@
+package singleresponsibility
+
+import "github.com/anil-vinnakoti/go-SOLID/pkg/repository"
+
+// Orders, invoices and customers are entities, so the generic
+// repository.Repository can store all three. Compare it with the
+// per-entity interfaces: OrderStore can filter and page because it was
+// written for orders, while Repository[Order] only saves, finds, lists
+// and deletes, the same for every entity. The repository tests and
+// benchmarks measure both side by side.
+
+func (o Order) EntityID() int    { return o.ID }
+func (c Customer) EntityID() int { return c.ID }
+
+// EntityID is the order's ID: an order has one invoice.
+func (inv Invoice) EntityID() int { return inv.OrderID }
+
+// NewGenericOrderRepository returns Repository[Order] in memory.
+func NewGenericOrderRepository() *repository.Memory[Order] {
+	return repository.NewMemory(ErrOrderNotFound, cloneOrder)
+}
+
+// NewGenericInvoiceRepository returns Repository[Invoice] in memory.
+func NewGenericInvoiceRepository() *repository.Memory[Invoice] {
+	return repository.NewMemory(ErrInvoiceNotFound, cloneInvoice)
+}
+
+func cloneInvoice(inv Invoice) Invoice {
+	inv.Lines = append([]PriceLine(nil), inv.Lines...)
+	inv.Taxes = append([]TaxLine(nil), inv.Taxes...)
+	return inv
+}
+
+// NewGenericCustomerRepository returns Repository[Customer] in memory.
+// It is also a CustomerRepository.
+func NewGenericCustomerRepository() *repository.Memory[Customer] {
+	return repository.NewMemory[Customer](ErrCustomerNotFound, nil)
+}
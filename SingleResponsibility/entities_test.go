@@ -0,0 +1,168 @@
+package singleresponsibility
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/repository"
+	_ "modernc.org/sqlite"
+)
+
+var _ CustomerRepository = NewGenericCustomerRepository()
+
+// openSQLite returns a migrated private in-memory database, closed
+// when the test ends.
+func openSQLite(tb testing.TB) *sql.DB {
+	tb.Helper()
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=memory&cache=shared", tb.Name()))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	tb.Cleanup(func() { db.Close() })
+	if err := Migrate(context.Background(), db); err != nil {
+		tb.Fatal(err)
+	}
+	return db
+}
+
+func newSQLGeneric[T repository.Entity](tb testing.TB, db *sql.DB, table string, notFound error) *repository.SQL[T] {
+	tb.Helper()
+	if _, err := db.Exec(repository.Schema(table)); err != nil {
+		tb.Fatal(err)
+	}
+	r, err := repository.NewSQL[T](db, table, notFound)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return r
+}
+
+func testOrder(tb testing.TB, id int) Order {
+	tb.Helper()
+	order, err := NewOrder(id, 1, []OrderItem{{SKU: "BOOK", Quantity: 2, UnitPrice: NewMoney(1250, "USD")}})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	order.CreatedAt = order.CreatedAt.UTC().Truncate(0)
+	return order
+}
+
+// orderCRUD is what both kinds of repository have in common.
+type orderCRUD interface {
+	Save(ctx context.Context, order Order) error
+	FindByID(ctx context.Context, id int) (Order, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// The per-entity and the generic repositories store orders alike.
+func TestOrderRepositories_PerEntityAndGeneric(t *testing.T) {
+	ctx := context.Background()
+	repos := map[string]func(t *testing.T) orderCRUD{
+		"per-entity memory": func(*testing.T) orderCRUD { return NewInMemoryOrderRepository() },
+		"per-entity sql":    func(t *testing.T) orderCRUD { return NewSQLOrderRepository(openSQLite(t)) },
+		"generic memory":    func(*testing.T) orderCRUD { return NewGenericOrderRepository() },
+		"generic sql": func(t *testing.T) orderCRUD {
+			return newSQLGeneric[Order](t, openSQLite(t), "order_documents", ErrOrderNotFound)
+		},
+	}
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			r := newRepo(t)
+			order := testOrder(t, 7)
+			if err := r.Save(ctx, order); err != nil {
+				t.Fatal(err)
+			}
+			got, err := r.FindByID(ctx, 7)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ID != 7 || got.Total != order.Total || len(got.Items) != 1 || got.Items[0] != order.Items[0] || !got.CreatedAt.Equal(order.CreatedAt) {
+				t.Errorf("FindByID = %+v, want %+v", got, order)
+			}
+			if err := r.Delete(ctx, 7); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := r.FindByID(ctx, 7); !errors.Is(err, ErrOrderNotFound) {
+				t.Errorf("after Delete: err = %v, want ErrOrderNotFound", err)
+			}
+		})
+	}
+}
+
+// The generic repository serves every entity with no code of its own.
+func TestGenericRepositories_InvoicesAndCustomers(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	invoice := Invoice{OrderID: 7, CustomerID: 1, BillTo: "Ada", Price: Price{
+		Lines: []PriceLine{{SKU: "BOOK", Quantity: 1, Amount: NewMoney(1250, "USD")}},
+		Total: NewMoney(1250, "USD"),
+	}}
+	customer := Customer{ID: 1, Name: "Ada", Email: "ada@example.com"}
+
+	invoiceRepos := []repository.Repository[Invoice]{
+		NewGenericInvoiceRepository(),
+		newSQLGeneric[Invoice](t, db, "invoices", ErrInvoiceNotFound),
+	}
+	for _, r := range invoiceRepos {
+		if err := r.Save(ctx, invoice); err != nil {
+			t.Fatal(err)
+		}
+		got, err := r.FindByID(ctx, 7)
+		if err != nil || got.BillTo != "Ada" || len(got.Lines) != 1 || got.Total != invoice.Total {
+			t.Errorf("%T: FindByID = %+v, %v", r, got, err)
+		}
+		if _, err := r.FindByID(ctx, 8); !errors.Is(err, ErrInvoiceNotFound) {
+			t.Errorf("%T: err = %v, want ErrInvoiceNotFound", r, err)
+		}
+	}
+
+	customerRepos := []repository.Repository[Customer]{
+		NewGenericCustomerRepository(),
+		newSQLGeneric[Customer](t, db, "customers", ErrCustomerNotFound),
+	}
+	for _, r := range customerRepos {
+		if err := r.Save(ctx, customer); err != nil {
+			t.Fatal(err)
+		}
+		list, err := r.List(ctx)
+		if err != nil || len(list) != 1 || list[0] != customer {
+			t.Errorf("%T: List = %+v, %v", r, list, err)
+		}
+	}
+}
+
+// benchmarkOrders saves an order and reads it back.
+func benchmarkOrders(b *testing.B, r orderCRUD) {
+	ctx := context.Background()
+	order := testOrder(b, 1)
+	for b.Loop() {
+		if err := r.Save(ctx, order); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := r.FindByID(ctx, 1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkOrderRepository_PerEntityMemory(b *testing.B) {
+	benchmarkOrders(b, NewInMemoryOrderRepository())
+}
+
+func BenchmarkOrderRepository_GenericMemory(b *testing.B) {
+	benchmarkOrders(b, NewGenericOrderRepository())
+}
+
+// The per-entity SQL repository writes a row per item in a
+// transaction; the generic one writes a single JSON document.
+func BenchmarkOrderRepository_PerEntitySQL(b *testing.B) {
+	benchmarkOrders(b, NewSQLOrderRepository(openSQLite(b)))
+}
+
+func BenchmarkOrderRepository_GenericSQL(b *testing.B) {
+	benchmarkOrders(b, newSQLGeneric[Order](b, openSQLite(b), "order_documents", ErrOrderNotFound))
+}
@@ -0,0 +1,76 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/codec"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/eventbus"
+)
+
+var ErrUnknownEvent = errors.New("unknown event")
+
+// eventCodecs are the formats events may travel in. Every message is a
+// codec envelope naming its format, so subscribers decode whatever
+// the publisher chose.
+var eventCodecs = codec.NewRegistry(codec.JSON, codec.XML)
+
+// BusPublisher publishes domain events on an eventbus.Publisher, on a
+// topic named after the event, so their subscribers may run in other
+// processes. Events are JSON unless WithCodec picks another of
+// eventCodecs.
+type BusPublisher struct {
+	pub   eventbus.Publisher
+	codec codec.Codec
+}
+
+func NewBusPublisher(pub eventbus.Publisher) BusPublisher {
+	return BusPublisher{pub: pub, codec: codec.JSON}
+}
+
+// WithCodec returns a copy of the publisher encoding events with c.
+func (p BusPublisher) WithCodec(c codec.Codec) BusPublisher {
+	p.codec = c
+	return p
+}
+
+func (p BusPublisher) Publish(ctx context.Context, e Event) error {
+	data, err := codec.Seal(p.codec, e)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", e.EventName(), err)
+	}
+	return p.pub.Publish(ctx, e.EventName(), data)
+}
+
+// SubscribeEvents calls h with the domain events BusPublisher
+// publishes on the topics matching pattern, such as "order.*".
+func SubscribeEvents(sub eventbus.Subscriber, pattern string, h EventHandler) (eventbus.Subscription, error) {
+	return sub.Subscribe(pattern, func(ctx context.Context, msg eventbus.Message) error {
+		e, err := decodeEvent(msg)
+		if err != nil {
+			return err
+		}
+		return h(ctx, e)
+	})
+}
+
+func decodeEvent(msg eventbus.Message) (Event, error) {
+	switch msg.Topic {
+	case EventOrderPlaced:
+		return decodeAs[OrderPlaced](msg)
+	case EventPaymentCaptured:
+		return decodeAs[PaymentCaptured](msg)
+	case EventInvoiceGenerated:
+		return decodeAs[InvoiceGenerated](msg)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, msg.Topic)
+}
+
+func decodeAs[E Event](msg eventbus.Message) (Event, error) {
+	var e E
+	if err := eventCodecs.Open(msg.Data, &e); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", msg.Topic, err)
+	}
+	return e, nil
+}
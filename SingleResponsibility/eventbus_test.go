@@ -0,0 +1,101 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/codec"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/eventbus"
+)
+
+func TestBusPublisher_RunsEventDrivenSteps(t *testing.T) {
+	ctx := context.Background()
+	bus := eventbus.NewMemory()
+	var confirmed []EmailAddress
+	email := emailSenderFunc(func(ctx context.Context, msg EmailMessage) error {
+		confirmed = append(confirmed, msg.To)
+		return nil
+	})
+	orders, err := NewOrderService(NewInMemoryOrderRepository(), NewFakeStripeGateway(NewMoney(10000, "USD"), nil), email,
+		NewInvoiceService(TextInvoiceRenderer{}, NewPricingService(nil), nil), WithEventPublisher(NewBusPublisher(bus)), WithEventDrivenSteps())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var seen []Event
+	if _, err := SubscribeEvents(bus, ">", func(ctx context.Context, e Event) error {
+		seen = append(seen, e)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SubscribeEvents(bus, EventOrderPlaced, orders.StepsHandler()); err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := NewOrder(7, 1, []OrderItem{{SKU: "BOOK", Quantity: 1, UnitPrice: NewMoney(1250, "USD")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := orders.PlaceOrder(ctx, "", order); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(confirmed) != 1 {
+		t.Errorf("sent %d confirmations, want 1", len(confirmed))
+	}
+	var names []string
+	for _, e := range seen {
+		names = append(names, e.EventName())
+	}
+	want := []string{EventPaymentCaptured, EventOrderPlaced, EventInvoiceGenerated}
+	if !slices.Equal(names, want) {
+		t.Fatalf("events = %v, want %v", names, want)
+	}
+	if placed := seen[1].(OrderPlaced); placed.Order.ID != 7 || placed.Order.Total.IsZero() {
+		t.Errorf("OrderPlaced = %+v, want the priced order", placed.Order)
+	}
+}
+
+func TestSubscribeEvents_UnknownTopic(t *testing.T) {
+	bus := eventbus.NewMemory()
+	if _, err := SubscribeEvents(bus, ">", func(context.Context, Event) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Publish(context.Background(), "order.archived", []byte("{}")); !errors.Is(err, ErrUnknownEvent) {
+		t.Errorf("err = %v, want ErrUnknownEvent", err)
+	}
+}
+
+func TestBusPublisher_Codecs(t *testing.T) {
+	order := testOrder(t, 7)
+	order.CreatedAt = time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	events := []Event{
+		OrderPlaced{Order: order},
+		PaymentCaptured{OrderID: 7, PaymentID: "ch_1", Amount: order.Total},
+	}
+	for _, c := range []codec.Codec{codec.JSON, codec.XML} {
+		t.Run(c.ContentType(), func(t *testing.T) {
+			bus := eventbus.NewMemory()
+			var got []Event
+			if _, err := SubscribeEvents(bus, ">", func(ctx context.Context, e Event) error {
+				got = append(got, e)
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+			pub := NewBusPublisher(bus).WithCodec(c)
+			for _, e := range events {
+				if err := pub.Publish(context.Background(), e); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !reflect.DeepEqual(got, events) {
+				t.Errorf("received %+v, want %+v", got, events)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+const (
+	EventOrderPlaced      = "order.placed"
+	EventPaymentCaptured  = "payment.captured"
+	EventInvoiceGenerated = "invoice.generated"
+)
+
+// Event is something that already happened in the order workflow.
+type Event interface {
+	EventName() string
+}
+
+type OrderPlaced struct {
+	Order Order
+}
+
+func (OrderPlaced) EventName() string { return EventOrderPlaced }
+
+type PaymentCaptured struct {
+	OrderID   int
+	PaymentID string
+	Amount    Money
+}
+
+func (PaymentCaptured) EventName() string { return EventPaymentCaptured }
+
+type InvoiceGenerated struct {
+	OrderID  int
+	Document []byte
+}
+
+func (InvoiceGenerated) EventName() string { return EventInvoiceGenerated }
+
+// EventPublisher is how OrderService announces what happened. It does
+// not know who is listening.
+type EventPublisher interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// EventHandler reacts to a published event.
+type EventHandler func(ctx context.Context, e Event) error
+
+// EventBus is an in-memory, synchronous EventPublisher. Handlers run
+// in subscription order on the publisher's goroutine.
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]EventHandler
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: make(map[string][]EventHandler)}
+}
+
+// Subscribe registers h for events with the given name.
+func (b *EventBus) Subscribe(name string, h EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[name] = append(b.handlers[name], h)
+}
+
+// Publish calls every handler subscribed to e. A failing handler does
+// not stop the others; all errors are joined.
+func (b *EventBus) Publish(ctx context.Context, e Event) error {
+	b.mu.RLock()
+	handlers := append([]EventHandler(nil), b.handlers[e.EventName()]...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, h := range handlers {
+		if err := h(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithEventPublisher has the service publish domain events to p.
+func WithEventPublisher(p EventPublisher) OrderOption {
+	return func(os *OrderService) { os.events = p }
+}
+
+// WithEventDrivenSteps has PlaceOrder stop once the order is paid.
+// Sending the confirmation and generating the invoice are left to
+// OrderPlaced subscribers; see SubscribeSteps.
+func WithEventDrivenSteps() OrderOption {
+	return func(os *OrderService) { os.eventDriven = true }
+}
+
+// SubscribeSteps registers the confirmation email and invoice steps as
+// an OrderPlaced handler on bus. Orders that are not in Paid status
+// were already completed by PlaceOrder and are ignored.
+func (os OrderService) SubscribeSteps(bus *EventBus) {
+	bus.Subscribe(EventOrderPlaced, os.StepsHandler())
+}
+
+// StepsHandler returns the OrderPlaced handler SubscribeSteps
+// registers, for subscribing it on another bus; see SubscribeEvents.
+func (os OrderService) StepsHandler() EventHandler {
+	return func(ctx context.Context, e Event) error {
+		placed, ok := e.(OrderPlaced)
+		if !ok || placed.Order.Status != StatusPaid {
+			return nil
+		}
+		order := placed.Order
+		customer, err := resolveCustomer(ctx, os.customers, order)
+		if err != nil {
+			return err
+		}
+		return os.fulfil(ctx, customer, &order)
+	}
+}
+
+// publish announces e. Events describe what already happened, so a
+// failing subscriber is logged rather than failing the workflow.
+func (os OrderService) publish(ctx context.Context, e Event) {
+	if os.events == nil {
+		return
+	}
+	if err := os.events.Publish(ctx, e); err != nil {
+		logf(ctx, os.logger(), "Publishing %s: %v", e.EventName(), err)
+	}
+}
@@ -0,0 +1,72 @@
+package singleresponsibility
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/flags"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/metrics"
+)
+
+// invoiceFormatFlag is the experiment flag choosing the invoice format
+// of customers without a preference: "invoice.format.<segment>", or
+// "invoice.format" for every segment without its own flag. Its value
+// weighs the formats, such as "html:50,pdf:50".
+const invoiceFormatFlag = "invoice.format"
+
+// InvoiceExperiment runs an A/B test of invoice formats. Each customer
+// lands in one variant for good (see flags.Variant), so they always get
+// the same format while the experiment runs. Customers who chose a
+// format keep it.
+type InvoiceExperiment struct {
+	Flags flags.Provider
+	// Segment names the customer's segment; nil means their country,
+	// such as "de".
+	Segment func(Customer) string
+	// Served, if set, is told which variant each customer was served.
+	Served func(segment string, format InvoiceFormat)
+}
+
+// WithExperiment returns a copy of the service that picks the format
+// of customers without a preference with e. A variant without a
+// renderer falls back to the default one.
+func (s *InvoiceService) WithExperiment(e InvoiceExperiment) *InvoiceService {
+	c := *s
+	if e.Segment == nil {
+		e.Segment = countrySegment
+	}
+	c.experiment = &e
+	return &c
+}
+
+func countrySegment(customer Customer) string {
+	return strings.ToLower(customer.Address.Country)
+}
+
+// format returns the variant customer is in, if the experiment covers
+// their segment.
+func (e *InvoiceExperiment) format(customer Customer) (segment string, format InvoiceFormat, ok bool) {
+	segment = e.Segment(customer)
+	unit := strconv.Itoa(customer.ID)
+	v, ok := flags.Variant(e.Flags, invoiceFormatFlag+"."+segment, unit)
+	if !ok {
+		v, ok = flags.Variant(e.Flags, invoiceFormatFlag, unit)
+	}
+	return segment, InvoiceFormat(v), ok
+}
+
+// CountInvoiceVariants returns an InvoiceExperiment.Served counting the
+// invoices served in each format with p, as invoice_variant_total with
+// a format label.
+func CountInvoiceVariants(p metrics.Provider) func(segment string, format InvoiceFormat) {
+	counters := make(map[InvoiceFormat]metrics.Counter)
+	served := p.CounterVec("invoice_variant_total", "Invoices served in each variant of the format experiment.", "format")
+	for _, f := range []InvoiceFormat{InvoiceText, InvoiceHTML, InvoicePDF} {
+		counters[f] = served.With(string(f))
+	}
+	return func(_ string, format InvoiceFormat) {
+		if c, ok := counters[format]; ok {
+			c.Add(1)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package singleresponsibility
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/flags"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/metrics"
+)
+
+// formatRenderer renders the name of its format, so tests can see
+// which renderer was picked.
+type formatRenderer InvoiceFormat
+
+func (r formatRenderer) Render(w io.Writer, inv Invoice) error {
+	_, err := io.WriteString(w, string(r))
+	return err
+}
+
+func TestInvoiceService_Experiment(t *testing.T) {
+	m := metrics.NewInMemory()
+	invoices := NewInvoiceService(formatRenderer(InvoiceText), nil, nil).
+		WithFormat(InvoiceHTML, formatRenderer(InvoiceHTML)).
+		WithFormat(InvoicePDF, formatRenderer(InvoicePDF)).
+		WithExperiment(InvoiceExperiment{
+			Flags:  flags.Static{"invoice.format": "html:50,pdf:50", "invoice.format.de": "pdf"},
+			Served: CountInvoiceVariants(m),
+		})
+	generate := func(customer Customer) string {
+		t.Helper()
+		doc, err := invoices.Generate(context.Background(), customer, testOrder(t, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(doc)
+	}
+
+	served := map[string]int{}
+	for id := 1; id <= 200; id++ {
+		customer := Customer{ID: id, Name: fmt.Sprint("Customer ", id), Address: Address{Country: "US"}}
+		format := generate(customer)
+		if again := generate(customer); again != format {
+			t.Fatalf("customer %d got %s, then %s", id, format, again)
+		}
+		served[format]++
+	}
+	if served["html"] < 70 || served["pdf"] < 70 || served["text"] != 0 {
+		t.Errorf("served %v, want about half html and half pdf", served)
+	}
+	if got := m.Value(`invoice_variant_total{format="html"}`) + m.Value(`invoice_variant_total{format="pdf"}`); got != 400 {
+		t.Errorf("counted %v variants served, want 400", got)
+	}
+
+	if got := generate(Customer{ID: 7, Address: Address{Country: "DE"}}); got != "pdf" {
+		t.Errorf("segment de got %s, want its own flag's pdf", got)
+	}
+	if got := generate(Customer{ID: 7, Address: Address{Country: "US"}, InvoiceFormat: InvoiceText}); got != "text" {
+		t.Errorf("a customer preferring text got %s", got)
+	}
+}
+
+func TestConfig_ValidatesInvoiceExperiment(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Flags = map[string]string{"invoice.format.de": "html:50,docx:50", "payment.crypto": "false"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "docx") {
+		t.Errorf("Validate = %v, want the unknown format docx", err)
+	}
+}
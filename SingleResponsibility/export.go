@@ -0,0 +1,129 @@
+package singleresponsibility
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// OrderEncoder writes orders in one export format.
+type OrderEncoder interface {
+	Encode(w io.Writer, orders []Order) error
+}
+
+// OrderExporter is responsible only for exporting stored orders. The
+// repository only stores them and the encoders only format them.
+type OrderExporter struct {
+	orders OrderStore
+}
+
+func NewOrderExporter(orders OrderStore) *OrderExporter {
+	return &OrderExporter{orders: orders}
+}
+
+// Export writes every stored order to w using enc.
+func (e *OrderExporter) Export(ctx context.Context, w io.Writer, enc OrderEncoder) error {
+	orders, err := e.orders.List(ctx, OrderFilter{})
+	if err != nil {
+		return fmt.Errorf("exporting orders: %w", err)
+	}
+	if err := enc.Encode(w, orders); err != nil {
+		return fmt.Errorf("exporting orders: %w", err)
+	}
+	return nil
+}
+
+// exportedOrder is the export schema. It is kept apart from Order so
+// the domain model can change without breaking exported files.
+type exportedOrder struct {
+	ID             int                 `json:"id"`
+	CustomerID     int                 `json:"customer_id"`
+	Status         string              `json:"status"`
+	CreatedAt      time.Time           `json:"created_at"`
+	Currency       Currency            `json:"currency"`
+	Total          string              `json:"total"`
+	Discount       string              `json:"discount,omitempty"`
+	CouponCode     string              `json:"coupon_code,omitempty"`
+	PaymentID      string              `json:"payment_id,omitempty"`
+	Carrier        string              `json:"carrier,omitempty"`
+	TrackingNumber string              `json:"tracking_number,omitempty"`
+	Items          []exportedOrderItem `json:"items"`
+}
+
+type exportedOrderItem struct {
+	SKU       string `json:"sku"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice string `json:"unit_price"`
+}
+
+// JSONOrderEncoder writes orders as an indented JSON array.
+type JSONOrderEncoder struct{}
+
+func (JSONOrderEncoder) Encode(w io.Writer, orders []Order) error {
+	out := make([]exportedOrder, len(orders))
+	for i, o := range orders {
+		out[i] = exportedOrder{
+			ID:             o.ID,
+			CustomerID:     o.CustomerID,
+			Status:         string(o.Status),
+			CreatedAt:      o.CreatedAt.UTC(),
+			Currency:       o.Total.Currency,
+			Total:          o.Total.Decimal(),
+			CouponCode:     o.CouponCode,
+			PaymentID:      o.PaymentID,
+			Carrier:        o.Carrier,
+			TrackingNumber: o.TrackingNumber,
+			Items:          make([]exportedOrderItem, len(o.Items)),
+		}
+		if !o.Discount.IsZero() {
+			out[i].Discount = o.Discount.Decimal()
+		}
+		for j, item := range o.Items {
+			out[i].Items[j] = exportedOrderItem{SKU: item.SKU, Quantity: item.Quantity, UnitPrice: item.UnitPrice.Decimal()}
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// CSVOrderEncoder writes one row per order item, repeating the order
+// columns, so the file loads straight into a spreadsheet.
+type CSVOrderEncoder struct{}
+
+var csvOrderHeader = []string{
+	"order_id", "customer_id", "status", "created_at", "currency", "total",
+	"sku", "quantity", "unit_price",
+}
+
+func (CSVOrderEncoder) Encode(w io.Writer, orders []Order) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvOrderHeader); err != nil {
+		return err
+	}
+	for _, o := range orders {
+		for _, item := range o.Items {
+			err := cw.Write([]string{
+				strconv.Itoa(o.ID),
+				strconv.Itoa(o.CustomerID),
+				string(o.Status),
+				o.CreatedAt.UTC().Format(time.RFC3339),
+				string(o.Total.Currency),
+				o.Total.Decimal(),
+				item.SKU,
+				strconv.Itoa(item.Quantity),
+				item.UnitPrice.Decimal(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
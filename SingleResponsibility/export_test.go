@@ -0,0 +1,92 @@
+package singleresponsibility
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// golden compares got with testdata/name, or rewrites the file with
+// -update:
+//
+//	go test ./SingleResponsibility -run TestOrderExporter -update
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs:\n got:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// exportFixture stores a placed order with a coupon and a shipment,
+// and a pending order of two items.
+func exportFixture(t *testing.T) OrderStore {
+	t.Helper()
+	repo := NewInMemoryOrderRepository()
+	shipped := testOrder(t, 1)
+	shipped.CreatedAt = time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	shipped.Status = StatusShipped
+	shipped.PaymentID = "ch_1"
+	shipped.CouponCode = "SAVE10"
+	shipped.Discount = NewMoney(250, "USD")
+	shipped.Total = NewMoney(2250, "USD")
+	shipped.Carrier = "ups"
+	shipped.TrackingNumber = "1Z999"
+
+	pending, err := NewOrder(2, 3, []OrderItem{
+		{SKU: "PEN", Quantity: 3, UnitPrice: NewMoney(199, "EUR")},
+		{SKU: "INK, BLUE", Quantity: 1, UnitPrice: NewMoney(450, "EUR")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending.CreatedAt = time.Date(2026, 3, 2, 18, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	for _, o := range []Order{shipped, pending} {
+		if err := repo.Save(context.Background(), o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+func TestOrderExporter(t *testing.T) {
+	for name, enc := range map[string]OrderEncoder{
+		"orders.json": JSONOrderEncoder{},
+		"orders.csv":  CSVOrderEncoder{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := NewOrderExporter(exportFixture(t)).Export(context.Background(), &buf, enc); err != nil {
+				t.Fatal(err)
+			}
+			golden(t, name, buf.Bytes())
+		})
+	}
+}
+
+func TestOrderExporter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewOrderExporter(NewInMemoryOrderRepository()).Export(context.Background(), &buf, JSONOrderEncoder{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "[]\n" {
+		t.Errorf("empty export = %q, want an empty array", got)
+	}
+}
@@ -0,0 +1,125 @@
+package singleresponsibility
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/query"
+)
+
+var ErrInvalidFilter = errors.New("invalid order filter")
+
+// Orders sort by one of orderSortFields; the zero Sort is by ID.
+var orderSortFields = []string{"id", "created_at"}
+
+// OrderFilter selects orders for OrderStore.List. Zero fields do not
+// filter, so the zero OrderFilter lists every order. Results are
+// ordered by Sort, and the Page pages through them; a zero Limit means
+// no limit.
+type OrderFilter struct {
+	Status      OrderStatus
+	CustomerID  int
+	CreatedFrom time.Time // inclusive
+	CreatedTo   time.Time // exclusive
+
+	Sort query.Sort
+	query.Page
+}
+
+func (f OrderFilter) Validate() error {
+	if err := f.Page.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+	if f.Sort != (query.Sort{}) && !slices.Contains(orderSortFields, f.Sort.Field) {
+		return fmt.Errorf("%w: cannot sort by %q", ErrInvalidFilter, f.Sort.Field)
+	}
+	if !f.After.IsZero() {
+		if f.After.Sort != f.sort().String() {
+			return fmt.Errorf("%w: the cursor belongs to a listing sorted by %q", ErrInvalidFilter, f.After.Sort)
+		}
+		if _, err := cursorOrder(f.After); err != nil {
+			return err
+		}
+	}
+	if !f.CreatedFrom.IsZero() && !f.CreatedTo.IsZero() && !f.CreatedFrom.Before(f.CreatedTo) {
+		return fmt.Errorf("%w: empty date range", ErrInvalidFilter)
+	}
+	return nil
+}
+
+// Matches reports whether order passes every filter condition.
+// Pagination is not considered.
+func (f OrderFilter) Matches(order Order) bool {
+	switch {
+	case f.Status != "" && order.Status != f.Status:
+		return false
+	case f.CustomerID != 0 && order.CustomerID != f.CustomerID:
+		return false
+	case !f.CreatedFrom.IsZero() && order.CreatedAt.Before(f.CreatedFrom):
+		return false
+	case !f.CreatedTo.IsZero() && !order.CreatedAt.Before(f.CreatedTo):
+		return false
+	}
+	return true
+}
+
+func (f OrderFilter) sort() query.Sort {
+	if f.Sort == (query.Sort{}) {
+		return query.Sort{Field: "id"}
+	}
+	return f.Sort
+}
+
+// compare orders a before b, or after for a descending Sort, by the
+// sort field and then by ID.
+func (f OrderFilter) compare(a, b Order) int {
+	s := f.sort()
+	c := 0
+	if s.Field == "created_at" {
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	}
+	if c == 0 {
+		c = cmp.Compare(a.ID, b.ID)
+	}
+	if s.Desc {
+		return -c
+	}
+	return c
+}
+
+// page applies the Page to orders that already match, sorted.
+func (f OrderFilter) page(orders []Order) []Order {
+	if !f.After.IsZero() {
+		last, _ := cursorOrder(f.After) // checked by Validate
+		for len(orders) > 0 && f.compare(orders[0], last) <= 0 {
+			orders = orders[1:]
+		}
+	}
+	return query.Slice(orders, f.Page)
+}
+
+// Cursor returns the cursor continuing the listing after last.
+func (f OrderFilter) Cursor(last Order) query.Cursor {
+	c := query.Cursor{Sort: f.sort().String(), ID: last.ID}
+	if f.sort().Field == "created_at" {
+		c.Key = last.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return c
+}
+
+// cursorOrder is the order a cursor points after, as far as the
+// cursor knows it: the fields compare looks at.
+func cursorOrder(c query.Cursor) (Order, error) {
+	order := Order{ID: c.ID}
+	if c.Key != "" {
+		t, err := time.Parse(time.RFC3339Nano, c.Key)
+		if err != nil {
+			return Order{}, fmt.Errorf("%w: malformed cursor", ErrInvalidFilter)
+		}
+		order.CreatedAt = t
+	}
+	return order, nil
+}
@@ -0,0 +1,189 @@
+package singleresponsibility
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/query"
+)
+
+// saveTimedOrders stores orders 1 to 6, created an hour apart except
+// for 2, 3 and 4, created at the same time.
+func saveTimedOrders(t *testing.T, store OrderStore) {
+	t.Helper()
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	offsets := map[int]time.Duration{1: 5, 2: 1, 3: 1, 4: 1, 5: 0, 6: 3}
+	for id := 1; id <= 6; id++ {
+		order := testOrder(t, id)
+		order.CreatedAt = start.Add(offsets[id] * time.Hour)
+		if err := store.Save(context.Background(), order); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Every store filters and pages alike.
+func TestOrderStores_List(t *testing.T) {
+	stores := map[string]func(t *testing.T) OrderStore{
+		"memory": func(*testing.T) OrderStore { return NewInMemoryOrderRepository() },
+		"sql":    func(t *testing.T) OrderStore { return NewSQLOrderRepository(openSQLite(t)) },
+	}
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 12, 0, 0, 0, time.UTC) }
+	orders := []struct {
+		id, customer int
+		status       OrderStatus
+		created      time.Time
+	}{
+		{1, 1, StatusPending, day(1)},
+		{2, 1, StatusPaid, day(2)},
+		{3, 2, StatusPaid, day(3)},
+		{4, 2, StatusShipped, day(4)},
+		{5, 3, StatusPaid, day(5)},
+	}
+	tests := []struct {
+		name    string
+		filter  OrderFilter
+		want    []int
+		wantErr error
+	}{
+		{"everything", OrderFilter{}, []int{1, 2, 3, 4, 5}, nil},
+		{"status", OrderFilter{Status: StatusPaid}, []int{2, 3, 5}, nil},
+		{"customer", OrderFilter{CustomerID: 2}, []int{3, 4}, nil},
+		{"from", OrderFilter{CreatedFrom: day(4)}, []int{4, 5}, nil},
+		{"to is exclusive", OrderFilter{CreatedTo: day(3)}, []int{1, 2}, nil},
+		{"range", OrderFilter{CreatedFrom: day(2), CreatedTo: day(5)}, []int{2, 3, 4}, nil},
+		{"combined", OrderFilter{Status: StatusPaid, CustomerID: 1, CreatedFrom: day(2)}, []int{2}, nil},
+		{"no match", OrderFilter{CustomerID: 9}, nil, nil},
+		{"limit", OrderFilter{Page: query.Page{Limit: 2}}, []int{1, 2}, nil},
+		{"limit and offset", OrderFilter{Page: query.Page{Limit: 2, Offset: 2}}, []int{3, 4}, nil},
+		{"offset only", OrderFilter{Page: query.Page{Offset: 3}}, []int{4, 5}, nil},
+		{"offset past the end", OrderFilter{Page: query.Page{Offset: 9}}, nil, nil},
+		{"filtered page", OrderFilter{Status: StatusPaid, Page: query.Page{Limit: 1, Offset: 1}}, []int{3}, nil},
+		{"empty range", OrderFilter{CreatedFrom: day(3), CreatedTo: day(3)}, nil, ErrInvalidFilter},
+		{"negative limit", OrderFilter{Page: query.Page{Limit: -1}}, nil, ErrInvalidFilter},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+			for _, o := range orders {
+				order := testOrder(t, o.id)
+				order.CustomerID, order.Status, order.CreatedAt = o.customer, o.status, o.created
+				if err := store.Save(ctx, order); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, tt := range tests {
+				list, err := store.List(ctx, tt.filter)
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+					continue
+				}
+				var got []int
+				for _, order := range list {
+					got = append(got, order.ID)
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("%s: listed %v, want %v", tt.name, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// Walking a listing page by page with cursors returns every order
+// once, in the store's sort order, whichever store it is.
+func TestOrderStores_CursorPaging(t *testing.T) {
+	stores := map[string]func(t *testing.T) OrderStore{
+		"memory": func(*testing.T) OrderStore { return NewInMemoryOrderRepository() },
+		"sql":    func(t *testing.T) OrderStore { return NewSQLOrderRepository(openSQLite(t)) },
+	}
+	sorts := []struct {
+		sort query.Sort
+		want []int
+	}{
+		{query.Sort{}, []int{1, 2, 3, 4, 5, 6}},
+		{query.Sort{Field: "id", Desc: true}, []int{6, 5, 4, 3, 2, 1}},
+		{query.Sort{Field: "created_at"}, []int{5, 2, 3, 4, 6, 1}},
+		{query.Sort{Field: "created_at", Desc: true}, []int{1, 6, 4, 3, 2, 5}},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+			saveTimedOrders(t, store)
+
+			for _, tt := range sorts {
+				filter := OrderFilter{Sort: tt.sort, Page: query.Page{Limit: 2}}
+				var got []int
+				for range 10 {
+					page, err := store.List(ctx, filter)
+					if err != nil {
+						t.Fatalf("%v: %v", tt.sort, err)
+					}
+					for _, order := range page {
+						got = append(got, order.ID)
+					}
+					if len(page) < filter.Limit {
+						break
+					}
+					filter.After = filter.Cursor(page[len(page)-1])
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("sorted by %q: %v, want %v", tt.sort, got, tt.want)
+				}
+			}
+
+			wrongSort := OrderFilter{Sort: query.Sort{Field: "created_at"}, Page: query.Page{After: query.Cursor{Sort: "id", ID: 2}}}
+			if _, err := store.List(ctx, wrongSort); !errors.Is(err, ErrInvalidFilter) {
+				t.Errorf("cursor of another sort: err = %v, want ErrInvalidFilter", err)
+			}
+		})
+	}
+}
+
+func TestOrderHandler_ListPaging(t *testing.T) {
+	repo := NewInMemoryOrderRepository()
+	saveTimedOrders(t, repo)
+	mux := http.NewServeMux()
+	NewOrderHandler(nil, repo, nil, NewSequence(0)).Register(mux)
+
+	var got []int
+	url := "/orders?sort=-created_at&limit=4"
+	for url != "" {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: %d %s", url, rec.Code, rec.Body)
+		}
+		var resp orderListResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		for _, order := range resp.Orders {
+			got = append(got, order.ID)
+		}
+		url = ""
+		if resp.NextCursor != "" {
+			url = "/orders?sort=-created_at&limit=4&cursor=" + resp.NextCursor
+		}
+	}
+	if want := []int{1, 6, 4, 3, 2, 5}; !slices.Equal(got, want) {
+		t.Errorf("listed %v, want %v", got, want)
+	}
+
+	for _, bad := range []string{"/orders?sort=total", "/orders?cursor=garbage", "/orders?limit=-1"} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, bad, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s: %d, want 400", bad, rec.Code)
+		}
+	}
+}
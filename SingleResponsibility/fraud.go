@@ -0,0 +1,190 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+var (
+	ErrFraudDeclined = errors.New("order declined by fraud check")
+	ErrFraudReview   = errors.New("order held for fraud review")
+)
+
+// FraudVerdict is the outcome of a fraud check. Verdicts are ordered
+// by severity, so the strictest of several is their maximum.
+type FraudVerdict int
+
+const (
+	Approve FraudVerdict = iota
+	Review
+	Decline
+)
+
+func (v FraudVerdict) String() string {
+	switch v {
+	case Approve:
+		return "approve"
+	case Review:
+		return "review"
+	case Decline:
+		return "decline"
+	}
+	return fmt.Sprintf("FraudVerdict(%d)", int(v))
+}
+
+// FraudDecision is the verdict on an order with the reasons of every
+// rule that did not approve it.
+type FraudDecision struct {
+	Verdict FraudVerdict
+	Reasons []string
+}
+
+// FraudRule assesses one risk signal of an order. It returns Approve
+// with an empty reason when the signal is absent.
+type FraudRule interface {
+	Assess(ctx context.Context, order Order) (FraudVerdict, string, error)
+}
+
+// FraudCheckService is responsible only for deciding whether an order
+// is too risky to charge. Each signal is a FraudRule, so a new signal
+// is a new rule rather than a change here.
+type FraudCheckService struct {
+	rules []FraudRule
+	log   Logger
+}
+
+func NewFraudCheckService(log Logger, rules ...FraudRule) *FraudCheckService {
+	return &FraudCheckService{rules: rules, log: orNop(log)}
+}
+
+// Check runs every rule against order. The decision is the strictest
+// verdict any rule gave.
+func (s *FraudCheckService) Check(ctx context.Context, order Order) (FraudDecision, error) {
+	var decision FraudDecision
+	for _, rule := range s.rules {
+		verdict, reason, err := rule.Assess(ctx, order)
+		if err != nil {
+			return FraudDecision{}, fmt.Errorf("fraud check of order %d: %w", order.ID, err)
+		}
+		if verdict != Approve {
+			decision.Reasons = append(decision.Reasons, reason)
+		}
+		decision.Verdict = max(decision.Verdict, verdict)
+	}
+	if decision.Verdict != Approve {
+		logf(ctx, s.log, "Fraud check: %s order %d: %s", decision.Verdict, order.ID, strings.Join(decision.Reasons, "; "))
+	}
+	return decision, nil
+}
+
+// AmountThreshold reviews orders totalling at least Review and declines
+// those totalling at least Decline. A zero threshold is not checked.
+type AmountThreshold struct {
+	Review  Money
+	Decline Money
+}
+
+func (r AmountThreshold) Assess(_ context.Context, order Order) (FraudVerdict, string, error) {
+	for _, t := range []struct {
+		limit   Money
+		verdict FraudVerdict
+	}{{r.Decline, Decline}, {r.Review, Review}} {
+		if t.limit.IsZero() {
+			continue
+		}
+		cmp, err := order.Total.Cmp(t.limit)
+		if err != nil {
+			return Approve, "", err
+		}
+		if cmp >= 0 {
+			return t.verdict, fmt.Sprintf("total %s reaches %s", order.Total, t.limit), nil
+		}
+	}
+	return Approve, "", nil
+}
+
+// VelocityRule reviews a customer's order once they already placed Max
+// orders within Window. Orders are counted in the OrderStore, so it
+// must run before the order being checked is saved.
+type VelocityRule struct {
+	orders OrderStore
+	clock  clock.Clock
+	max    int
+	window time.Duration
+}
+
+func NewVelocityRule(orders OrderStore, clk clock.Clock, max int, window time.Duration) *VelocityRule {
+	return &VelocityRule{orders: orders, clock: clock.OrSystem(clk), max: max, window: window}
+}
+
+func (r *VelocityRule) Assess(ctx context.Context, order Order) (FraudVerdict, string, error) {
+	recent, err := r.orders.List(ctx, OrderFilter{
+		CustomerID:  order.CustomerID,
+		CreatedFrom: r.clock.Now().Add(-r.window),
+	})
+	if err != nil {
+		return Approve, "", err
+	}
+	if len(recent) >= r.max {
+		return Review, fmt.Sprintf("customer %d placed %d orders in %s", order.CustomerID, len(recent), r.window), nil
+	}
+	return Approve, "", nil
+}
+
+// Blocklist declines every order of the listed customers.
+type Blocklist map[int]bool
+
+func NewBlocklist(customerIDs ...int) Blocklist {
+	b := make(Blocklist, len(customerIDs))
+	for _, id := range customerIDs {
+		b[id] = true
+	}
+	return b
+}
+
+func (b Blocklist) Assess(_ context.Context, order Order) (FraudVerdict, string, error) {
+	if b[order.CustomerID] {
+		return Decline, fmt.Sprintf("customer %d is blocked", order.CustomerID), nil
+	}
+	return Approve, "", nil
+}
+
+// WithFraudCheck has the service check each priced order before it is
+// saved or charged. Orders the check declines or holds for review are
+// not placed.
+func WithFraudCheck(fraud *FraudCheckService) OrderOption {
+	return WithStep(fraudStep{fraud: fraud})
+}
+
+// fraudStep checks a priced order before it is saved.
+type fraudStep struct {
+	fraud *FraudCheckService
+}
+
+func (fraudStep) Name() string          { return "fraud check" }
+func (fraudStep) Stage() PlacementStage { return BeforeSave }
+
+func (s fraudStep) Run(ctx context.Context, _ Customer, order Order) (StepResult, error) {
+	return StepResult{Order: order}, s.check(ctx, order)
+}
+
+// check turns a fraud decision into an error, or nil on Approve.
+func (s fraudStep) check(ctx context.Context, order Order) error {
+	decision, err := s.fraud.Check(ctx, order)
+	if err != nil {
+		return err
+	}
+	reasons := strings.Join(decision.Reasons, "; ")
+	switch decision.Verdict {
+	case Decline:
+		return fmt.Errorf("%w: order %d: %s", ErrFraudDeclined, order.ID, reasons)
+	case Review:
+		return fmt.Errorf("%w: order %d: %s", ErrFraudReview, order.ID, reasons)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+)
+
+// orderTotalling is order 1 of customer 1 totalling amount minor units
+// of USD.
+func orderTotalling(tb testing.TB, amount int64) Order {
+	tb.Helper()
+	order, err := NewOrder(1, 1, []OrderItem{{SKU: "BOOK", Quantity: 1, UnitPrice: NewMoney(amount, "USD")}})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return order
+}
+
+func TestAmountThreshold(t *testing.T) {
+	rule := AmountThreshold{Review: NewMoney(50000, "USD"), Decline: NewMoney(100000, "USD")}
+	tests := []struct {
+		name    string
+		rule    AmountThreshold
+		total   int64
+		want    FraudVerdict
+		wantErr error
+	}{
+		{"below review", rule, 49999, Approve, nil},
+		{"at review", rule, 50000, Review, nil},
+		{"between", rule, 99999, Review, nil},
+		{"at decline", rule, 100000, Decline, nil},
+		{"no thresholds", AmountThreshold{}, 1 << 40, Approve, nil},
+		{"decline only", AmountThreshold{Decline: NewMoney(100000, "USD")}, 99999, Approve, nil},
+		{"other currency", AmountThreshold{Review: NewMoney(50000, "EUR")}, 1, Approve, ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, reason, err := tt.rule.Assess(context.Background(), orderTotalling(t, tt.total))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if verdict != tt.want || (verdict == Approve) != (reason == "") {
+				t.Errorf("Assess = %s %q, want %s", verdict, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestVelocityRule(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := NewInMemoryOrderRepository()
+	for i, placed := range []struct {
+		customer int
+		ago      time.Duration
+	}{
+		{1, 90 * time.Minute}, // outside the window
+		{1, 50 * time.Minute},
+		{1, 10 * time.Minute},
+		{2, 5 * time.Minute},
+	} {
+		order := testOrder(t, i+1)
+		order.CustomerID = placed.customer
+		order.CreatedAt = now.Add(-placed.ago)
+		if err := store.Save(ctx, order); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clk := clocktest.NewFake(now)
+
+	tests := []struct {
+		name     string
+		max      int
+		customer int
+		advance  time.Duration
+		want     FraudVerdict
+	}{
+		{"under the limit", 3, 1, 0, Approve},
+		{"at the limit", 2, 1, 0, Review},
+		{"other customers do not count", 2, 2, 0, Approve},
+		{"new customer", 1, 3, 0, Approve},
+		// Twenty minutes on, the order of fifty minutes ago has left the
+		// hour.
+		{"window moves with the clock", 2, 1, 20 * time.Minute, Approve},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk.Advance(tt.advance)
+			defer clk.Advance(-tt.advance)
+			order := testOrder(t, 10)
+			order.CustomerID = tt.customer
+			verdict, reason, err := NewVelocityRule(store, clk, tt.max, time.Hour).Assess(ctx, order)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if verdict != tt.want || (verdict == Approve) != (reason == "") {
+				t.Errorf("Assess = %s %q, want %s", verdict, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlocklist(t *testing.T) {
+	blocked := NewBlocklist(7, 9)
+	for customer, want := range map[int]FraudVerdict{7: Decline, 9: Decline, 1: Approve} {
+		order := testOrder(t, 1)
+		order.CustomerID = customer
+		if verdict, _, err := blocked.Assess(context.Background(), order); err != nil || verdict != want {
+			t.Errorf("customer %d: %s, %v; want %s", customer, verdict, err, want)
+		}
+	}
+}
+
+// fraudRuleFunc is a FraudRule giving a fixed answer.
+type fraudRuleFunc func() (FraudVerdict, string, error)
+
+func (f fraudRuleFunc) Assess(context.Context, Order) (FraudVerdict, string, error) { return f() }
+
+func verdictRule(v FraudVerdict, reason string) FraudRule {
+	return fraudRuleFunc(func() (FraudVerdict, string, error) { return v, reason, nil })
+}
+
+// The decision is the strictest verdict, with the reason of every rule
+// that did not approve, and decides whether OrderService places the
+// order.
+func TestFraudCheckService(t *testing.T) {
+	errLookup := errors.New("store down")
+	tests := []struct {
+		name        string
+		rules       []FraudRule
+		want        FraudDecision
+		wantErr     error
+		wantPlaced  error
+		wantCharged bool
+	}{
+		{"allow", []FraudRule{verdictRule(Approve, ""), verdictRule(Approve, "")},
+			FraudDecision{}, nil, nil, true},
+		{"no rules", nil,
+			FraudDecision{}, nil, nil, true},
+		{"review", []FraudRule{verdictRule(Approve, ""), verdictRule(Review, "velocity")},
+			FraudDecision{Review, []string{"velocity"}}, nil, ErrFraudReview, false},
+		{"deny wins", []FraudRule{verdictRule(Review, "velocity"), verdictRule(Decline, "blocked"), verdictRule(Review, "amount")},
+			FraudDecision{Decline, []string{"velocity", "blocked", "amount"}}, nil, ErrFraudDeclined, false},
+		{"rule fails", []FraudRule{verdictRule(Review, "velocity"), fraudRuleFunc(func() (FraudVerdict, string, error) { return Approve, "", errLookup })},
+			FraudDecision{}, errLookup, errLookup, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fraud := NewFraudCheckService(nil, tt.rules...)
+			got, err := fraud.Check(context.Background(), testOrder(t, 1))
+			if !errors.Is(err, tt.wantErr) || !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Check = %+v, %v; want %+v, %v", got, err, tt.want, tt.wantErr)
+			}
+
+			log := &callLog{}
+			orders, err := NewOrderService(fakeStore{NewInMemoryOrderRepository(), log, nil}, fakeGateway{log, nil}, fakeSender{log, nil}, fakeInvoicer{log, nil},
+				WithFraudCheck(fraud))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = orders.PlaceOrder(context.Background(), "", testOrder(t, 1))
+			if !errors.Is(err, tt.wantPlaced) {
+				t.Fatalf("PlaceOrder = %v, want %v", err, tt.wantPlaced)
+			}
+			if charged := len(log.all()) > 0; charged != tt.wantCharged {
+				t.Errorf("calls = %q, want charged %v", log.all(), tt.wantCharged)
+			}
+		})
+	}
+}
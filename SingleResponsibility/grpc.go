@@ -0,0 +1,334 @@
+package singleresponsibility
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// OrderGRPCServer is the gRPC transport of the order workflow: the
+// orders.v1.OrderService of proto/orders.proto. Like OrderHandler it
+// only converts messages and maps errors; it calls the same services,
+// through the same middleware, with the same scopes.
+//
+// The messages are read and written with protowire, like orderpb.go,
+// so the service needs no generated code: grpcCodec carries them.
+type OrderGRPCServer struct {
+	h *OrderHandler
+}
+
+// NewOrderGRPCServer serves the services of h, with its logger, error
+// reporter and verifier, over gRPC.
+func NewOrderGRPCServer(h *OrderHandler) *OrderGRPCServer {
+	return &OrderGRPCServer{h: h}
+}
+
+// NewServer returns a gRPC server with s registered, checking
+// credentials if the handler has a verifier.
+func (s *OrderGRPCServer) NewServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{grpc.ForceServerCodec(grpcCodec{})}, opts...)
+	if s.h.verifier != nil {
+		opts = append(opts, grpc.UnaryInterceptor(grpcAuth(s.h.verifier)))
+	}
+	srv := grpc.NewServer(opts...)
+	s.Register(srv)
+	return srv
+}
+
+// Register adds the service to srv, which must use grpcCodec.
+func (s *OrderGRPCServer) Register(srv grpc.ServiceRegistrar) {
+	srv.RegisterService(&orderServiceDesc, s)
+}
+
+func (s *OrderGRPCServer) placeOrder(ctx context.Context, req *placeOrderMessage) (*orderResponse, error) {
+	order, err := NewOrder(s.h.nextID(), req.customerID, req.items)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	order.CouponCode = req.couponCode
+	placed, err := call(s.h, "PlaceOrder", func(ctx context.Context, order Order) (Order, error) {
+		return s.h.orders.PlaceOrder(ctx, req.idempotencyKey, order)
+	})(ctx, order)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &orderResponse{order: placed}, nil
+}
+
+func (s *OrderGRPCServer) getOrder(ctx context.Context, req *orderIDMessage) (*orderResponse, error) {
+	order, err := call(s.h, "FindByID", s.h.finder.FindByID)(ctx, req.id)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &orderResponse{order: order}, nil
+}
+
+func (s *OrderGRPCServer) refundOrder(ctx context.Context, req *orderIDMessage) (*orderResponse, error) {
+	order, err := call(s.h, "Refund", s.h.refunds.Refund)(ctx, req.id)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &orderResponse{order: order}, nil
+}
+
+func (s *OrderGRPCServer) listOrders(ctx context.Context, req *listOrdersMessage) (*orderListResponse, error) {
+	parsed, err := parseListOrders(req.query())
+	if err == nil {
+		err = parsed.Validate()
+	}
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	filter := parsed.filter
+	orders, err := call(s.h, "List", s.h.finder.List)(ctx, filter)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resp := &orderListResponse{Sort: filter.sort().String(), Limit: filter.Limit, Offset: filter.Offset, orders: orders}
+	if len(orders) > 0 && len(orders) == filter.Limit {
+		resp.NextCursor = filter.Cursor(orders[len(orders)-1]).Encode()
+	}
+	return resp, nil
+}
+
+// orderServiceDesc is what protoc-gen-go-grpc would generate for the
+// service in proto/orders.proto.
+var orderServiceDesc = grpc.ServiceDesc{
+	ServiceName: "orders.v1.OrderService",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{
+		unaryMethod("PlaceOrder", (*OrderGRPCServer).placeOrder),
+		unaryMethod("GetOrder", (*OrderGRPCServer).getOrder),
+		unaryMethod("RefundOrder", (*OrderGRPCServer).refundOrder),
+		unaryMethod("ListOrders", (*OrderGRPCServer).listOrders),
+	},
+	Metadata: "proto/orders.proto",
+}
+
+// unaryMethod describes the method name of the service, served by a
+// method of OrderGRPCServer taking a Req.
+func unaryMethod[Req, Res any](name string, method func(*OrderGRPCServer, context.Context, *Req) (Res, error)) grpc.MethodDesc {
+	info := &grpc.UnaryServerInfo{FullMethod: "/orders.v1.OrderService/" + name}
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			req := new(Req)
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			s := srv.(*OrderGRPCServer)
+			if interceptor == nil {
+				return method(s, ctx, req)
+			}
+			info := *info
+			info.Server = srv
+			return interceptor(ctx, req, &info, func(ctx context.Context, req any) (any, error) {
+				return method(s, ctx, req.(*Req))
+			})
+		},
+	}
+}
+
+// grpcScopes are the scopes the methods require, as OrderHandler
+// requires them of the matching routes.
+var grpcScopes = map[string]string{
+	"/orders.v1.OrderService/PlaceOrder":  scopeWrite,
+	"/orders.v1.OrderService/GetOrder":    scopeRead,
+	"/orders.v1.OrderService/ListOrders":  scopeRead,
+	"/orders.v1.OrderService/RefundOrder": scopeRefund,
+}
+
+// grpcAuth admits the calls v verifies, given as "authorization:
+// Bearer <token>" or "x-api-key: <token>" metadata, to the methods
+// their scopes allow.
+func grpcAuth(v auth.TokenVerifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		var token string
+		if keys := md.Get("x-api-key"); len(keys) > 0 {
+			token = keys[0]
+		}
+		if values := md.Get("authorization"); len(values) > 0 {
+			if bearer, ok := strings.CutPrefix(values[0], "Bearer "); ok {
+				token = strings.TrimSpace(bearer)
+			}
+		}
+		if token == "" {
+			return nil, grpcError(fmt.Errorf("%w: no credentials", auth.ErrUnauthenticated))
+		}
+		p, err := v.Verify(ctx, token)
+		if err != nil {
+			return nil, grpcError(err)
+		}
+		if scope := grpcScopes[info.FullMethod]; !p.HasScope(scope) {
+			return nil, grpcError(fmt.Errorf("%w: %s lacks scope %s", auth.ErrForbidden, p.Subject, scope))
+		}
+		return next(auth.WithPrincipal(ctx, p), req)
+	}
+}
+
+// grpcCodes maps the HTTP statuses apiErrors gives the domain errors to
+// gRPC codes, so both transports classify an error the same way.
+var grpcCodes = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnprocessableEntity: codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.FailedPrecondition,
+	http.StatusPaymentRequired:     codes.FailedPrecondition,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusGatewayTimeout:      codes.DeadlineExceeded,
+}
+
+// grpcError converts err to a gRPC status. Internal errors keep their
+// details to the server, as writeError does.
+func grpcError(err error) error {
+	code, ok := grpcCodes[statusFor(err)]
+	if !ok {
+		return status.Error(codes.Internal, "internal error")
+	}
+	return status.Error(code, err.Error())
+}
+
+// grpcCodec carries the messages of this file in the protobuf wire
+// format. It is forced on the server, so it does not replace the
+// registered "proto" codec for anything else in the process.
+type grpcCodec struct{}
+
+func (grpcCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(interface{ MarshalProto() ([]byte, error) })
+	if !ok {
+		return nil, fmt.Errorf("grpc codec: cannot marshal %T", v)
+	}
+	return m.MarshalProto()
+}
+
+func (grpcCodec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(interface{ UnmarshalProto([]byte) error })
+	if !ok {
+		return fmt.Errorf("grpc codec: cannot unmarshal %T", v)
+	}
+	return m.UnmarshalProto(data)
+}
+
+func (grpcCodec) Name() string { return "proto" }
+
+// placeOrderMessage is the orders.v1.PlaceOrderRequest message.
+type placeOrderMessage struct {
+	idempotencyKey string
+	customerID     int
+	items          []OrderItem
+	couponCode     string
+}
+
+func (m *placeOrderMessage) UnmarshalProto(b []byte) error {
+	return parseFields(b, func(num protowire.Number, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			m.idempotencyKey = string(data)
+		case 2:
+			m.customerID = int(v)
+		case 3:
+			item, err := parseOrderItemProto(data)
+			m.items = append(m.items, item)
+			return err
+		case 4:
+			m.couponCode = string(data)
+		}
+		return nil
+	})
+}
+
+func (m *placeOrderMessage) MarshalProto() ([]byte, error) {
+	b := appendString(nil, 1, m.idempotencyKey)
+	b = appendInt(b, 2, int64(m.customerID))
+	for _, item := range m.items {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendBytes(b, appendOrderItemProto(nil, item))
+	}
+	return appendString(b, 4, m.couponCode), nil
+}
+
+// orderIDMessage is the orders.v1.GetOrderRequest and
+// RefundOrderRequest message.
+type orderIDMessage struct {
+	id int
+}
+
+func (m *orderIDMessage) UnmarshalProto(b []byte) error {
+	return parseFields(b, func(num protowire.Number, v uint64, data []byte) error {
+		if num == 1 {
+			m.id = int(v)
+		}
+		return nil
+	})
+}
+
+func (m *orderIDMessage) MarshalProto() ([]byte, error) {
+	return appendInt(nil, 1, int64(m.id)), nil
+}
+
+// listOrdersMessage is the orders.v1.ListOrdersRequest message.
+type listOrdersMessage struct {
+	status     string
+	customerID int
+	sort       string
+	limit      int
+	cursor     string
+}
+
+func (m *listOrdersMessage) UnmarshalProto(b []byte) error {
+	return parseFields(b, func(num protowire.Number, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			m.status = string(data)
+		case 2:
+			m.customerID = int(v)
+		case 3:
+			m.sort = string(data)
+		case 4:
+			m.limit = int(v)
+		case 5:
+			m.cursor = string(data)
+		}
+		return nil
+	})
+}
+
+func (m *listOrdersMessage) MarshalProto() ([]byte, error) {
+	b := appendString(nil, 1, m.status)
+	b = appendInt(b, 2, int64(m.customerID))
+	b = appendString(b, 3, m.sort)
+	b = appendInt(b, 4, int64(m.limit))
+	return appendString(b, 5, m.cursor), nil
+}
+
+// query is the request as GET /orders query parameters; unset fields
+// are left out, as proto3 leaves them out.
+func (m *listOrdersMessage) query() url.Values {
+	q := url.Values{}
+	for name, v := range map[string]string{"status": m.status, "sort": m.sort, "cursor": m.cursor} {
+		if v != "" {
+			q.Set(name, v)
+		}
+	}
+	if m.customerID != 0 {
+		q.Set("customer_id", strconv.Itoa(m.customerID))
+	}
+	if m.limit != 0 {
+		q.Set("limit", strconv.Itoa(m.limit))
+	}
+	return q
+}
@@ -0,0 +1,160 @@
+package singleresponsibility
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// orderReply and orderListReply decode the service's answers, as a
+// client would.
+type orderReply struct{ Order }
+
+func (r *orderReply) UnmarshalProto(b []byte) (err error) {
+	r.Order, err = parseOrderProto(b)
+	return err
+}
+
+type orderListReply struct {
+	orders     []Order
+	nextCursor string
+}
+
+func (r *orderListReply) UnmarshalProto(b []byte) error {
+	return parseFields(b, func(num protowire.Number, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			order, err := parseOrderProto(data)
+			r.orders = append(r.orders, order)
+			return err
+		case 5:
+			r.nextCursor = string(data)
+		}
+		return nil
+	})
+}
+
+// dialGRPC serves s on an in-memory listener and returns a client
+// connection to it.
+func dialGRPC(t *testing.T, s *OrderGRPCServer) *grpc.ClientConn {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	srv := s.NewServer()
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(grpcCodec{})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// The gRPC transport drives the same wired services as the HTTP API:
+// an order placed over gRPC can be found, listed and refunded.
+func TestOrderGRPCServer(t *testing.T) {
+	ctx := context.Background()
+	cfg := DefaultConfig()
+	cfg.StorageDir = t.TempDir()
+	services, err := Wire(ctx, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer services.Close()
+	conn := dialGRPC(t, NewOrderGRPCServer(NewOrderHandler(services.Commands, services.Store, services.Commands, NewSequence(0))))
+	invoke := func(method string, req, reply any) error {
+		return conn.Invoke(ctx, "/orders.v1.OrderService/"+method, req, reply)
+	}
+
+	place := &placeOrderMessage{
+		idempotencyKey: "key-1",
+		customerID:     1,
+		items:          []OrderItem{{SKU: "BOOK", Quantity: 2, UnitPrice: NewMoney(1250, "USD")}},
+	}
+	var placed orderReply
+	if err := invoke("PlaceOrder", place, &placed); err != nil {
+		t.Fatal(err)
+	}
+	if placed.ID != 1 || placed.Status != StatusInvoiced || placed.Total != NewMoney(2500, "USD") {
+		t.Fatalf("placed %+v, want order 1 invoiced for 25.00 USD", placed.Order)
+	}
+	// A retry with the same key returns the same order.
+	var retried orderReply
+	if err := invoke("PlaceOrder", place, &retried); err != nil || retried.ID != placed.ID {
+		t.Errorf("retry = %+v, %v; want order %d", retried.Order, err, placed.ID)
+	}
+
+	var found orderReply
+	if err := invoke("GetOrder", &orderIDMessage{id: placed.ID}, &found); err != nil || found.PaymentID != placed.PaymentID {
+		t.Errorf("GetOrder = %+v, %v; want %+v", found.Order, err, placed.Order)
+	}
+	var list orderListReply
+	if err := invoke("ListOrders", &listOrdersMessage{customerID: 1, limit: 1}, &list); err != nil || len(list.orders) != 1 || list.nextCursor == "" {
+		t.Errorf("ListOrders = %+v, %v; want one order and a cursor", list, err)
+	}
+	var refunded orderReply
+	if err := invoke("RefundOrder", &orderIDMessage{id: placed.ID}, &refunded); err != nil || refunded.Status != StatusRefunded {
+		t.Errorf("RefundOrder = %+v, %v; want refunded", refunded.Order, err)
+	}
+
+	for name, tt := range map[string]struct {
+		method string
+		req    any
+		code   codes.Code
+	}{
+		"unknown order":  {"GetOrder", &orderIDMessage{id: 99}, codes.NotFound},
+		"refunded twice": {"RefundOrder", &orderIDMessage{id: placed.ID}, codes.FailedPrecondition},
+		"no items":       {"PlaceOrder", &placeOrderMessage{customerID: 1}, codes.InvalidArgument},
+		"bad status":     {"ListOrders", &listOrdersMessage{status: "lost"}, codes.InvalidArgument},
+	} {
+		if err := invoke(tt.method, tt.req, &orderReply{}); status.Code(err) != tt.code {
+			t.Errorf("%s: %v, want %s", name, err, tt.code)
+		}
+	}
+}
+
+func TestOrderGRPCServer_Auth(t *testing.T) {
+	repo := NewInMemoryOrderRepository()
+	if err := repo.Save(context.Background(), testOrder(t, 1)); err != nil {
+		t.Fatal(err)
+	}
+	cfg := DefaultConfig()
+	cfg.APIKeys = []APIKey{{Key: "reader", Subject: "dashboard", Scopes: []string{"orders:read"}}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	conn := dialGRPC(t, NewOrderGRPCServer(NewOrderHandler(nil, repo, panickingRefunder{}, NewSequence(0)).WithVerifier(cfg.verifier())))
+
+	tests := []struct {
+		method, key string
+		code        codes.Code
+	}{
+		{"GetOrder", "", codes.Unauthenticated},
+		{"GetOrder", "stolen", codes.Unauthenticated},
+		{"GetOrder", "reader", codes.OK},
+		{"RefundOrder", "reader", codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.key != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", tt.key)
+		}
+		err := conn.Invoke(ctx, "/orders.v1.OrderService/"+tt.method, &orderIDMessage{id: 1}, &orderReply{})
+		if status.Code(err) != tt.code {
+			t.Errorf("%s with %q: %v, want %s", tt.method, tt.key, err, tt.code)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package singleresponsibility
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/apierror"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/auth"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/codec"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/middleware"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/timeout"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/validate"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/webhook"
+)
+
+// OrderPlacer, OrderFinder and OrderRefunder are what the HTTP layer
+// needs from the service layer. OrderService, any OrderStore and
+// RefundService satisfy them.
+type OrderPlacer interface {
+	PlaceOrder(ctx context.Context, idempotencyKey string, order Order) (Order, error)
+}
+
+type OrderFinder interface {
+	FindByID(ctx context.Context, id int) (Order, error)
+	List(ctx context.Context, filter OrderFilter) ([]Order, error)
+}
+
+type OrderRefunder interface {
+	Refund(ctx context.Context, orderID int) (Order, error)
+}
+
+// OrderHandler is the transport layer: it decodes requests, calls the
+// service and maps the outcome to HTTP status codes. It holds no
+// business rules of its own.
+//
+// Register has validate.Handler decode and check every request first,
+// so the handlers start from valid input.
+//
+// Every service call goes through the same middleware.Chain: a panic
+// becomes a 500 answer instead of a dropped connection, and with
+// WithLogger each call is logged with its duration.
+//
+// With WithVerifier every route needs credentials: a token whose
+// principal has the route's scope, orders:read, orders:write or
+// orders:refund.
+type OrderHandler struct {
+	orders  OrderPlacer
+	finder  OrderFinder
+	refunds OrderRefunder
+	nextID  func() int
+
+	log      Logger
+	reporter ErrReporter
+	verifier auth.TokenVerifier
+}
+
+// NewOrderHandler returns a handler that numbers new orders with
+// nextID.
+func NewOrderHandler(orders OrderPlacer, finder OrderFinder, refunds OrderRefunder, nextID func() int) *OrderHandler {
+	return &OrderHandler{orders: orders, finder: finder, refunds: refunds, nextID: nextID}
+}
+
+// WithLogger returns a copy of the handler that logs every service
+// call.
+func (h OrderHandler) WithLogger(log Logger) *OrderHandler {
+	h.log = log
+	return &h
+}
+
+// WithErrReporter returns a copy of the handler that reports the
+// service calls that panic to r.
+func (h OrderHandler) WithErrReporter(r ErrReporter) *OrderHandler {
+	h.reporter = r
+	return &h
+}
+
+// WithVerifier returns a copy of the handler that admits only the
+// requests v verifies, each to the routes its scopes allow.
+func (h OrderHandler) WithVerifier(v auth.TokenVerifier) *OrderHandler {
+	h.verifier = v
+	return &h
+}
+
+// The scopes the order routes require under WithVerifier.
+const (
+	scopeRead   = "orders:read"
+	scopeWrite  = "orders:write"
+	scopeRefund = "orders:refund"
+)
+
+// call wraps a service call named op in the handler's middleware.
+func call[Req, Res any](h *OrderHandler, op string, fn middleware.Handler[Req, Res]) middleware.Handler[Req, Res] {
+	var mws []middleware.Middleware[Req, Res]
+	if h.log != nil {
+		// Outside Recover, so a recovered panic is logged as a failure.
+		mws = append(mws, middleware.Logging[Req, Res](op, h.log, nil))
+	}
+	mws = append(mws, middleware.Recover[Req, Res](h.reporter, map[string]string{"op": op}))
+	return middleware.Chain(fn, mws...)
+}
+
+// NewSequence returns an ID generator counting up from last+1. It is
+// safe for concurrent use.
+func NewSequence(last int) func() int {
+	var n atomic.Int64
+	n.Store(int64(last))
+	return func() int { return int(n.Add(1)) }
+}
+
+// Register mounts the order routes on mux.
+func (h *OrderHandler) Register(mux *http.ServeMux) {
+	mux.Handle("POST /orders", h.secured(scopeWrite, validate.Handler(validate.JSON[createOrderRequest](maxRequestBody), h.createOrder, writeError)))
+	mux.Handle("GET /orders", h.secured(scopeRead, validate.Handler(decodeListOrders, h.listOrders, writeError)))
+	mux.Handle("GET /orders/{id}", h.secured(scopeRead, validate.Handler(decodeOrderID, h.getOrder, writeError)))
+	mux.Handle("POST /orders/{id}/refund", h.secured(scopeRefund, validate.Handler(decodeOrderID, h.refundOrder, writeError)))
+}
+
+// secured lets only the requests with scope through to next, if the
+// handler has a verifier.
+func (h *OrderHandler) secured(scope string, next http.HandlerFunc) http.Handler {
+	return requireScope(h.verifier, scope, next)
+}
+
+// requireScope lets only the requests v verifies with scope through to
+// next. A nil v lets every request through.
+func requireScope(v auth.TokenVerifier, scope string, next http.Handler) http.Handler {
+	if v == nil {
+		return next
+	}
+	return auth.Middleware(v, writeError)(auth.Require(scope, writeError)(next))
+}
+
+type orderResponse struct {
+	XMLName    xml.Name        `json:"-" xml:"order"`
+	ID         int             `json:"id" xml:"id"`
+	CustomerID int             `json:"customer_id" xml:"customer_id"`
+	Items      []orderItemJSON `json:"items" xml:"items>item"`
+	CouponCode string          `json:"coupon_code,omitempty" xml:"coupon_code,omitempty"`
+	Discount   string          `json:"discount,omitempty" xml:"discount,omitempty"`
+	Total      string          `json:"total" xml:"total"`
+	Currency   Currency        `json:"currency" xml:"currency"`
+	Status     string          `json:"status" xml:"status"`
+	CreatedAt  time.Time       `json:"created_at" xml:"created_at"`
+
+	Carrier        string `json:"carrier,omitempty" xml:"carrier,omitempty"`
+	TrackingNumber string `json:"tracking_number,omitempty" xml:"tracking_number,omitempty"`
+
+	order Order // for MarshalProto
+}
+
+func (h *OrderHandler) createOrder(w http.ResponseWriter, r *http.Request, req createOrderRequest) {
+	order, err := NewOrder(h.nextID(), req.CustomerID, req.items())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	order.CouponCode = req.CouponCode
+	key := r.Header.Get("Idempotency-Key")
+	placed, err := call(h, "PlaceOrder", func(ctx context.Context, order Order) (Order, error) {
+		return h.orders.PlaceOrder(ctx, key, order)
+	})(r.Context(), order)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeBody(w, r, http.StatusCreated, newOrderResponse(placed))
+}
+
+func (h *OrderHandler) getOrder(w http.ResponseWriter, r *http.Request, req orderIDRequest) {
+	order, err := call(h, "FindByID", h.finder.FindByID)(r.Context(), req.id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeBody(w, r, http.StatusOK, newOrderResponse(order))
+}
+
+type orderListResponse struct {
+	XMLName xml.Name        `json:"-" xml:"orders"`
+	Orders  []orderResponse `json:"orders" xml:"order"`
+	Sort    string          `json:"sort" xml:"sort,attr"`
+	Limit   int             `json:"limit" xml:"limit,attr"`
+	Offset  int             `json:"offset" xml:"offset,attr"`
+	// NextCursor continues the listing after a full page: pass it as
+	// ?cursor= with the same filters and sort.
+	NextCursor string `json:"next_cursor,omitempty" xml:"next_cursor,attr,omitempty"`
+
+	orders []Order // for MarshalProto
+}
+
+func (h *OrderHandler) listOrders(w http.ResponseWriter, r *http.Request, req listOrdersRequest) {
+	filter := req.filter
+	orders, err := call(h, "List", h.finder.List)(r.Context(), filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := orderListResponse{Orders: make([]orderResponse, len(orders)), Sort: filter.sort().String(), Limit: filter.Limit, Offset: filter.Offset, orders: orders}
+	for i, order := range orders {
+		resp.Orders[i] = newOrderResponse(order)
+	}
+	if len(orders) > 0 && len(orders) == filter.Limit {
+		resp.NextCursor = filter.Cursor(orders[len(orders)-1]).Encode()
+	}
+	writeBody(w, r, http.StatusOK, resp)
+}
+
+func (h *OrderHandler) refundOrder(w http.ResponseWriter, r *http.Request, req orderIDRequest) {
+	order, err := call(h, "Refund", h.refunds.Refund)(r.Context(), req.id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeBody(w, r, http.StatusOK, newOrderResponse(order))
+}
+
+func newOrderResponse(order Order) orderResponse {
+	items := make([]orderItemJSON, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = orderItemJSON{SKU: item.SKU, Quantity: item.Quantity, UnitPrice: item.UnitPrice.Decimal()}
+	}
+	resp := orderResponse{
+		ID:         order.ID,
+		CustomerID: order.CustomerID,
+		Items:      items,
+		CouponCode: order.CouponCode,
+		Total:      order.Total.Decimal(),
+		Currency:   order.Total.Currency,
+		Status:     string(order.Status),
+		CreatedAt:  order.CreatedAt,
+
+		Carrier:        order.Carrier,
+		TrackingNumber: order.TrackingNumber,
+
+		order: order,
+	}
+	if !order.Discount.IsZero() {
+		resp.Discount = order.Discount.Decimal()
+	}
+	return resp
+}
+
+// apiErrors maps domain errors to HTTP answers: the status, and the
+// stable code clients can branch on. The domain never sees HTTP, and
+// anything not listed is an internal error.
+var apiErrors = apierror.NewMapper(
+	apierror.Rule{Err: validate.ErrInvalid, Status: http.StatusBadRequest, Code: "invalid_request"},
+	apierror.Rule{Err: validate.ErrMalformed, Status: http.StatusBadRequest, Code: "malformed_request"},
+	apierror.Rule{Err: ErrInvalidFilter, Status: http.StatusBadRequest, Code: "invalid_filter"},
+	apierror.Rule{Err: codec.ErrUnsupported, Status: http.StatusNotAcceptable, Code: "not_acceptable"},
+
+	apierror.Rule{Err: auth.ErrUnauthenticated, Status: http.StatusUnauthorized, Code: "unauthenticated"},
+	apierror.Rule{Err: auth.ErrForbidden, Status: http.StatusForbidden, Code: "forbidden"},
+	apierror.Rule{Err: webhook.ErrInvalidSignature, Status: http.StatusUnauthorized, Code: "invalid_signature"},
+
+	apierror.Rule{Err: ErrInvalidOrderID, Status: http.StatusUnprocessableEntity, Code: "invalid_order_id"},
+	apierror.Rule{Err: ErrInvalidCustomerID, Status: http.StatusUnprocessableEntity, Code: "invalid_customer_id"},
+	apierror.Rule{Err: ErrNoItems, Status: http.StatusUnprocessableEntity, Code: "no_items"},
+	apierror.Rule{Err: ErrInvalidItem, Status: http.StatusUnprocessableEntity, Code: "invalid_item"},
+	apierror.Rule{Err: ErrInvalidAmount, Status: http.StatusUnprocessableEntity, Code: "invalid_amount"},
+	apierror.Rule{Err: ErrInvalidEmail, Status: http.StatusUnprocessableEntity, Code: "invalid_email"},
+	apierror.Rule{Err: ErrCustomerNotFound, Status: http.StatusUnprocessableEntity, Code: "customer_not_found"},
+	apierror.Rule{Err: ErrCouponNotFound, Status: http.StatusUnprocessableEntity, Code: "coupon_not_found"},
+	apierror.Rule{Err: ErrCouponExpired, Status: http.StatusUnprocessableEntity, Code: "coupon_expired"},
+	apierror.Rule{Err: ErrCouponExhausted, Status: http.StatusUnprocessableEntity, Code: "coupon_exhausted"},
+	apierror.Rule{Err: ErrUndeliverable, Status: http.StatusUnprocessableEntity, Code: "undeliverable"},
+
+	apierror.Rule{Err: ErrOrderNotFound, Status: http.StatusNotFound, Code: "order_not_found"},
+	apierror.Rule{Err: ErrSummaryNotFound, Status: http.StatusNotFound, Code: "summary_not_found"},
+
+	apierror.Rule{Err: ErrOrderExists, Status: http.StatusConflict, Code: "order_exists"},
+	apierror.Rule{Err: ErrOrderChanged, Status: http.StatusConflict, Code: "order_changed"},
+	apierror.Rule{Err: ErrInvalidTransition, Status: http.StatusConflict, Code: "invalid_transition"},
+	apierror.Rule{Err: ErrInsufficientStock, Status: http.StatusConflict, Code: "insufficient_stock"},
+	apierror.Rule{Err: ErrAlreadyRefunded, Status: http.StatusConflict, Code: "already_refunded"},
+	apierror.Rule{Err: ErrUnknownPayment, Status: http.StatusConflict, Code: "unknown_payment"},
+	apierror.Rule{Err: ErrRequestInProgress, Status: http.StatusConflict, Code: "request_in_progress"},
+
+	apierror.Rule{Err: ErrPaymentDeclined, Status: http.StatusPaymentRequired, Code: "payment_declined"},
+	apierror.Rule{Err: ErrFraudDeclined, Status: http.StatusPaymentRequired, Code: "fraud_declined"},
+	apierror.Rule{Err: ErrFraudReview, Status: http.StatusPaymentRequired, Code: "fraud_review"},
+
+	apierror.Rule{Err: ErrGatewayUnavailable, Status: http.StatusServiceUnavailable, Code: "payment_unavailable"},
+	apierror.Rule{Err: ErrEmailQueueFull, Status: http.StatusServiceUnavailable, Code: "busy"},
+	apierror.Rule{Err: timeout.ErrDeadlineExceeded, Status: http.StatusGatewayTimeout, Code: "timeout"},
+)
+
+// statusFor is the status apiErrors answers err with.
+func statusFor(err error) int {
+	return apiErrors.Status(err)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	apiErrors.Write(w, err)
+}
+
+// apiCodecs are the formats the order API answers in, chosen by the
+// Accept header; JSON is the default.
+var apiCodecs = codec.NewRegistry(codec.JSON, codec.XML, codec.Protobuf)
+
+// writeBody writes v in the format r accepts.
+func writeBody(w http.ResponseWriter, r *http.Request, status int, v any) {
+	writeBodyWith(apiCodecs, w, r, status, v)
+}
+
+// writeBodyWith writes v in the format r accepts among codecs.
+func writeBodyWith(codecs *codec.Registry, w http.ResponseWriter, r *http.Request, status int, v any) {
+	c, err := codecs.Negotiate(r.Header.Get("Accept"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var buf bytes.Buffer
+	if err := c.Encode(&buf, v); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", c.ContentType())
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,319 @@
+package singleresponsibility
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/apierror"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/auth"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/errreport"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/timeout"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/validate"
+)
+
+// panickingRefunder stands in for a refund service with a bug.
+type panickingRefunder struct{}
+
+func (panickingRefunder) Refund(ctx context.Context, orderID int) (Order, error) {
+	panic("nil gateway")
+}
+
+func TestOrderHandler_Middleware(t *testing.T) {
+	repo := NewInMemoryOrderRepository()
+	if err := repo.Save(context.Background(), testOrder(t, 1)); err != nil {
+		t.Fatal(err)
+	}
+	reporter := errreport.NewMemory(10, nil)
+	log := &CapturingLogger{}
+	mux := http.NewServeMux()
+	NewOrderHandler(nil, repo, panickingRefunder{}, NewSequence(0)).
+		WithLogger(log).
+		WithErrReporter(reporter).
+		Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders/1/refund", nil))
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), `"error"`) {
+		t.Errorf("panicking refund: %d %s", rec.Code, rec.Body)
+	}
+	if events := reporter.Events(); len(events) != 1 || events[0].Tags["op"] != "Refund" {
+		t.Errorf("reported %+v, want the Refund panic", events)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET /orders/1: %d %s", rec.Code, rec.Body)
+	}
+
+	lines := log.Lines()
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "Refund failed after") || !strings.HasPrefix(lines[1], "FindByID took") {
+		t.Errorf("log = %q", lines)
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{ErrInvalidFilter, http.StatusBadRequest, "invalid_filter"},
+		{ErrNoItems, http.StatusUnprocessableEntity, "no_items"},
+		{ErrCustomerNotFound, http.StatusUnprocessableEntity, "customer_not_found"},
+		{ErrCouponExpired, http.StatusUnprocessableEntity, "coupon_expired"},
+		{ErrOrderNotFound, http.StatusNotFound, "order_not_found"},
+		{ErrInvalidTransition, http.StatusConflict, "invalid_transition"},
+		{ErrAlreadyRefunded, http.StatusConflict, "already_refunded"},
+		{ErrRequestInProgress, http.StatusConflict, "request_in_progress"},
+		{ErrPaymentDeclined, http.StatusPaymentRequired, "payment_declined"},
+		{ErrFraudReview, http.StatusPaymentRequired, "fraud_review"},
+		{ErrGatewayUnavailable, http.StatusServiceUnavailable, "payment_unavailable"},
+		{timeout.ErrDeadlineExceeded, http.StatusGatewayTimeout, "timeout"},
+		{errors.New("disk full"), http.StatusInternalServerError, "internal"},
+	}
+	for _, tt := range tests {
+		err := fmt.Errorf("order 7: %w", tt.err)
+		status, body := apiErrors.Map(err)
+		if status != tt.status || body.Code != tt.code {
+			t.Errorf("Map(%v) = %d %q, want %d %q", err, status, body.Code, tt.status, tt.code)
+		}
+		if status == http.StatusInternalServerError && strings.Contains(body.Error, "disk") {
+			t.Errorf("internal error leaked: %q", body.Error)
+		}
+	}
+}
+
+func TestOrderHandler_ErrorBody(t *testing.T) {
+	mux := http.NewServeMux()
+	NewOrderHandler(nil, NewInMemoryOrderRepository(), nil, NewSequence(0)).Register(mux)
+
+	for path, want := range map[string]apierror.Body{
+		"/orders/7":          {Error: "order not found: 7", Code: "order_not_found"},
+		"/orders/x":          {Error: `order not found: "x"`, Code: "order_not_found"},
+		"/orders?sort=total": {Code: "invalid_request"},
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var body apierror.Body
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		if body.Code != want.Code || (want.Error != "" && body.Error != want.Error) {
+			t.Errorf("GET %s: body = %+v, want %+v", path, body, want)
+		}
+	}
+}
+
+func TestOrderHandler_Auth(t *testing.T) {
+	repo := NewInMemoryOrderRepository()
+	if err := repo.Save(context.Background(), testOrder(t, 1)); err != nil {
+		t.Fatal(err)
+	}
+	cfg := DefaultConfig()
+	cfg.APIKeys = []APIKey{
+		{Key: "reader", Subject: "dashboard", Scopes: []string{"orders:read"}},
+		{Key: "support", Subject: "support", Scopes: []string{"orders:read", "orders:refund"}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	NewOrderHandler(nil, repo, panickingRefunder{}, NewSequence(0)).WithVerifier(cfg.verifier()).Register(mux)
+
+	tests := []struct {
+		method, path, key string
+		status            int
+		code              string
+	}{
+		{http.MethodGet, "/orders/1", "", http.StatusUnauthorized, "unauthenticated"},
+		{http.MethodGet, "/orders/1", "stolen", http.StatusUnauthorized, "unauthenticated"},
+		{http.MethodGet, "/orders/1", "reader", http.StatusOK, ""},
+		{http.MethodPost, "/orders/1/refund", "reader", http.StatusForbidden, "forbidden"},
+		// The refund itself panics: the request got past the scope check.
+		{http.MethodPost, "/orders/1/refund", "support", http.StatusInternalServerError, "internal"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.key != "" {
+			req.Header.Set("X-API-Key", tt.key)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		var body apierror.Body
+		json.NewDecoder(rec.Body).Decode(&body)
+		if rec.Code != tt.status || body.Code != tt.code {
+			t.Errorf("%s %s with %q: %d %q, want %d %q", tt.method, tt.path, tt.key, rec.Code, body.Code, tt.status, tt.code)
+		}
+	}
+}
+
+func TestOrderHandler_ContentNegotiation(t *testing.T) {
+	order := testOrder(t, 1)
+	order.CreatedAt = time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	order.Status = StatusPaid
+	order.PaymentID = "ch_1"
+	repo := NewInMemoryOrderRepository()
+	if err := repo.Save(context.Background(), order); err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	NewOrderHandler(nil, repo, nil, NewSequence(0)).Register(mux)
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, accept := range []string{"", "application/json"} {
+		rec := get("/orders/1", accept)
+		var body orderResponse
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || rec.Header().Get("Content-Type") != "application/json" || body.Total != "25.00" {
+			t.Errorf("Accept %q: %s %+v %v", accept, rec.Header().Get("Content-Type"), body, err)
+		}
+	}
+
+	rec := get("/orders/1", "application/xml")
+	var doc orderResponse
+	if err := xml.NewDecoder(rec.Body).Decode(&doc); err != nil || doc.ID != 1 || doc.Total != "25.00" || len(doc.Items) != 1 || doc.Items[0].SKU != "BOOK" {
+		t.Errorf("XML: %+v %v", doc, err)
+	}
+
+	rec = get("/orders/1", "application/x-protobuf")
+	got, err := parseOrderProto(rec.Body.Bytes())
+	if err != nil || !reflect.DeepEqual(got, order) {
+		t.Errorf("protobuf: %+v %v, want %+v", got, err, order)
+	}
+
+	rec = get("/orders?limit=10", "application/xml")
+	var list orderListResponse
+	if err := xml.NewDecoder(rec.Body).Decode(&list); err != nil || len(list.Orders) != 1 || list.Limit != 10 {
+		t.Errorf("XML list: %+v %v", list, err)
+	}
+
+	rec = get("/orders/1", "text/html")
+	if rec.Code != http.StatusNotAcceptable || !strings.Contains(rec.Body.String(), "not_acceptable") {
+		t.Errorf("Accept text/html: %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestOrderHandler_CreateOrder(t *testing.T) {
+	orders, err := NewOrderService(NewInMemoryOrderRepository(), NewFakeStripeGateway(NewMoney(10000, "USD"), nil), NewLoggingEmailSender(nil),
+		NewInvoiceService(TextInvoiceRenderer{}, NewPricingService(nil), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	NewOrderHandler(orders, NewInMemoryOrderRepository(), nil, NewSequence(0)).Register(mux)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		code   string
+	}{
+		{"valid", `{"customer_id":1,"currency":"USD","items":[{"sku":"BOOK","quantity":2,"unit_price":"12.50"}]}`, http.StatusCreated, ""},
+		{"malformed", `{"customer_id":`, http.StatusBadRequest, "malformed_request"},
+		{"no items", `{"customer_id":1,"currency":"USD","items":[]}`, http.StatusBadRequest, "invalid_request"},
+		{"bad customer", `{"customer_id":0,"currency":"USD","items":[{"sku":"BOOK","quantity":1,"unit_price":"1.00"}]}`, http.StatusBadRequest, "invalid_request"},
+		{"bad currency", `{"customer_id":1,"currency":"XXX","items":[{"sku":"BOOK","quantity":1,"unit_price":"1.00"}]}`, http.StatusBadRequest, "invalid_request"},
+		{"declined", `{"customer_id":1,"currency":"USD","items":[{"sku":"TV","quantity":1,"unit_price":"999.00"}]}`, http.StatusPaymentRequired, "payment_declined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(tt.body)))
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.code == "" {
+				var order orderResponse
+				if err := json.NewDecoder(rec.Body).Decode(&order); err != nil || order.Status != string(StatusInvoiced) || order.Total != "25.00" {
+					t.Errorf("body = %+v, %v", order, err)
+				}
+				return
+			}
+			var body apierror.Body
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body.Code != tt.code {
+				t.Errorf("body = %+v, %v, want code %q", body, err, tt.code)
+			}
+		})
+	}
+}
+
+func TestStatusFor(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{validate.ErrInvalid, http.StatusBadRequest},
+		{validate.ErrMalformed, http.StatusBadRequest},
+		{auth.ErrUnauthenticated, http.StatusUnauthorized},
+		{auth.ErrForbidden, http.StatusForbidden},
+		{ErrInvalidOrderID, http.StatusUnprocessableEntity},
+		{ErrInvalidItem, http.StatusUnprocessableEntity},
+		{ErrOrderNotFound, http.StatusNotFound},
+		{ErrSummaryNotFound, http.StatusNotFound},
+		{ErrInsufficientStock, http.StatusConflict},
+		{ErrUnknownPayment, http.StatusConflict},
+		{ErrFraudDeclined, http.StatusPaymentRequired},
+		{ErrEmailQueueFull, http.StatusServiceUnavailable},
+		{context.Canceled, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		// Services wrap their errors, sometimes twice.
+		err := fmt.Errorf("placing order 7: %w", fmt.Errorf("step: %w", tt.err))
+		if got := statusFor(err); got != tt.status {
+			t.Errorf("statusFor(%v) = %d, want %d", err, got, tt.status)
+		}
+	}
+}
+
+func TestOrderHandler_ListPageSize(t *testing.T) {
+	repo := NewInMemoryOrderRepository()
+	for id := 1; id <= 5; id++ {
+		if err := repo.Save(context.Background(), testOrder(t, id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mux := http.NewServeMux()
+	NewOrderHandler(nil, repo, nil, NewSequence(0)).Register(mux)
+
+	tests := []struct {
+		query     string
+		limit     int
+		orders    int
+		hasCursor bool
+	}{
+		{"", defaultPageSize, 5, false},
+		// A zero limit asks for the largest page, not an empty one.
+		{"?limit=0", maxPageSize, 5, false},
+		{"?limit=1000", maxPageSize, 5, false},
+		{"?limit=2", 2, 2, true},
+		{"?limit=2&offset=4", 2, 1, false},
+		{"?offset=5", defaultPageSize, 0, false},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders"+tt.query, nil))
+		var resp orderListResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("GET /orders%s: %d %v", tt.query, rec.Code, err)
+		}
+		if resp.Limit != tt.limit || len(resp.Orders) != tt.orders || (resp.NextCursor != "") != tt.hasCursor {
+			t.Errorf("GET /orders%s: limit %d, %d orders, cursor %q; want limit %d, %d orders, cursor %v",
+				tt.query, resp.Limit, len(resp.Orders), resp.NextCursor, tt.limit, tt.orders, tt.hasCursor)
+		}
+	}
+}
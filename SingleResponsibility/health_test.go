@@ -0,0 +1,32 @@
+package singleresponsibility
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWire_ReadinessFollowsEmailQueue(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.EmailWorkers = 1
+	services, err := Wire(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ready := func() int {
+		rec := httptest.NewRecorder()
+		services.Health.ReadyHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+		return rec.Code
+	}
+	if code := ready(); code != http.StatusOK {
+		t.Errorf("running queue: readyz = %d, want 200", code)
+	}
+	if err := services.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if code := ready(); code != http.StatusServiceUnavailable {
+		t.Errorf("closed queue: readyz = %d, want 503", code)
+	}
+}
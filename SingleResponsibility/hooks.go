@@ -0,0 +1,52 @@
+package singleresponsibility
+
+import "context"
+
+// StatusHook reacts to an order status change. It receives the order
+// in its new status and the status it left. Hooks run after the change
+// is saved and cannot undo it, so they report their own failures. A
+// change made while placing an order is still rolled back if a later
+// step fails.
+type StatusHook func(order Order, from OrderStatus)
+
+// OnStatusChange has the service call hook after every status change
+// it makes, after the hooks of earlier options. New lifecycle
+// reactions are attached here instead of being written into
+// PlaceOrder or Transition.
+func OnStatusChange(hook StatusHook) OrderOption {
+	return func(os *OrderService) { os.statusHooks = append(os.statusHooks, hook) }
+}
+
+func (os OrderService) fireStatusChange(order Order, from OrderStatus) {
+	for _, hook := range os.statusHooks {
+		hook(order, from)
+	}
+}
+
+// NotifyShipment returns a hook that emails the customer when their
+// order ships and when it is delivered. Failures are logged.
+func NotifyShipment(mail EmailSender, customers CustomerRepository, log Logger) StatusHook {
+	email := NewEmailService(mail)
+	log = orNop(log)
+
+	return func(order Order, _ OrderStatus) {
+		var send func(context.Context, Customer, Order) error
+		switch order.Status {
+		case StatusShipped:
+			send = email.SendShippedNotice
+		case StatusDelivered:
+			send = email.SendDeliveredNotice
+		default:
+			return
+		}
+
+		ctx := context.Background()
+		customer, err := resolveCustomer(ctx, customers, order)
+		if err == nil {
+			err = send(ctx, customer, order)
+		}
+		if err != nil {
+			log.Printf("Notifying customer of order %d %s: %v", order.ID, order.Status, err)
+		}
+	}
+}
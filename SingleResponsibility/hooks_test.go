@@ -0,0 +1,72 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Hooks run in the order they were registered, once per status change,
+// and a hook that fails neither stops the others nor undoes the change.
+func TestOrderService_OnStatusChange(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryOrderRepository()
+	var fired []string
+	hook := func(name string) StatusHook {
+		return func(order Order, from OrderStatus) {
+			fired = append(fired, fmt.Sprintf("%s %s->%s", name, from, order.Status))
+		}
+	}
+	log := &CapturingLogger{}
+	failingMail := emailSenderFunc(func(context.Context, EmailMessage) error { return errors.New("smtp down") })
+	orders, err := NewOrderService(repo, NewFakeStripeGateway(NewMoney(0, "USD"), nil), NewLoggingEmailSender(nil), fakeInvoicer{&callLog{}, nil},
+		OnStatusChange(hook("first")),
+		OnStatusChange(NotifyShipment(failingMail, nil, log)),
+		OnStatusChange(hook("last")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := orders.PlaceOrder(ctx, "", testOrder(t, 1)); err != nil {
+		t.Fatal(err)
+	}
+	shipped, err := orders.Transition(ctx, 1, StatusShipped)
+	if err != nil || shipped.Status != StatusShipped {
+		t.Fatalf("Transition = %+v, %v; want shipped despite the failing hook", shipped, err)
+	}
+
+	want := []string{
+		"first pending->paid", "last pending->paid",
+		"first paid->invoiced", "last paid->invoiced",
+		"first invoiced->shipped", "last invoiced->shipped",
+	}
+	if !reflect.DeepEqual(fired, want) {
+		t.Errorf("fired:\n got %q\nwant %q", fired, want)
+	}
+	if stored, _ := repo.FindByID(ctx, 1); stored.Status != StatusShipped {
+		t.Errorf("stored order is %s, want shipped", stored.Status)
+	}
+	if lines := log.Lines(); len(lines) != 1 || !strings.Contains(lines[0], "smtp down") {
+		t.Errorf("logged %q, want the hook's failure", lines)
+	}
+}
+
+// A status change that is not saved fires no hook.
+func TestOrderService_OnStatusChange_NotSaved(t *testing.T) {
+	log := &callLog{}
+	fired := 0
+	orders, err := NewOrderService(fakeStore{NewInMemoryOrderRepository(), log, errors.New("disk full")}, fakeGateway{log, nil}, fakeSender{log, nil}, fakeInvoicer{log, nil},
+		OnStatusChange(func(Order, OrderStatus) { fired++ }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := orders.PlaceOrder(context.Background(), "", testOrder(t, 1)); err == nil {
+		t.Fatal("PlaceOrder succeeded with a failing store")
+	}
+	if fired != 0 {
+		t.Errorf("%d hooks fired, want none", fired)
+	}
+}
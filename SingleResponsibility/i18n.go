@@ -0,0 +1,35 @@
+package singleresponsibility
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/i18n"
+)
+
+// The customer-facing text of emails and invoices lives in
+// locales/<locale>.json. A customer's Locale picks the file; English
+// is the fallback for locales and messages without a translation.
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var defaultMessages = func() *i18n.Catalog {
+	dir, err := fs.Sub(localeFiles, "locales")
+	if err != nil {
+		panic(err)
+	}
+	c, err := i18n.Load(dir, "en")
+	if err != nil {
+		panic(err)
+	}
+	return c
+}()
+
+// messagesOr returns t, or the built-in catalog if t is nil.
+func messagesOr(t i18n.Translator) i18n.Translator {
+	if t == nil {
+		return defaultMessages
+	}
+	return t
+}
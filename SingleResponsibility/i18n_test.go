@@ -0,0 +1,67 @@
+package singleresponsibility
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// Every locale translates every message; a missing one would quietly
+// show customers English.
+func TestMessages_Complete(t *testing.T) {
+	for _, locale := range defaultMessages.Locales() {
+		if missing := defaultMessages.Missing(locale); len(missing) > 0 {
+			t.Errorf("locale %s misses %q", locale, missing)
+		}
+	}
+}
+
+func TestEmailService_CustomerLocale(t *testing.T) {
+	sender := NewLoggingEmailSender(nil)
+	emails := NewEmailService(sender)
+	customer := Customer{ID: 1, Name: "Ada", Email: "ada@example.com", Locale: "de-AT"}
+	if err := emails.SendRefundNotice(context.Background(), customer, testOrder(t, 7)); err != nil {
+		t.Fatal(err)
+	}
+	customer.Locale = ""
+	if err := emails.SendRefundNotice(context.Background(), customer, testOrder(t, 8)); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := sender.Sent()
+	if len(sent) != 2 {
+		t.Fatalf("sent %d emails, want 2", len(sent))
+	}
+	if sent[0].Subject != "Bestellung #7 erstattet" || !strings.HasPrefix(sent[0].Body, "Hallo Ada,") {
+		t.Errorf("German email = %q\n%s", sent[0].Subject, sent[0].Body)
+	}
+	if sent[1].Subject != "Order #8 refunded" || !strings.HasPrefix(sent[1].Body, "Hi Ada,") {
+		t.Errorf("English email = %q\n%s", sent[1].Subject, sent[1].Body)
+	}
+}
+
+func TestInvoiceRenderers_CustomerLocale(t *testing.T) {
+	invoices := NewInvoiceService(TextInvoiceRenderer{}, NewPricingService(nil), nil).
+		WithFormat(InvoiceHTML, HTMLInvoiceRenderer{})
+	customer := Customer{ID: 1, Name: "Ada", Locale: "de"}
+	order := testOrder(t, 7)
+
+	text, err := invoices.Generate(context.Background(), customer, order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"RECHNUNG - Bestellung #7", "Rechnung an:  Ada (Kunde 1)", "ARTIKEL", "Gesamt"} {
+		if !strings.Contains(string(text), want) {
+			t.Errorf("text invoice lacks %q:\n%s", want, text)
+		}
+	}
+
+	customer.InvoiceFormat = InvoiceHTML
+	html, err := invoices.Generate(context.Background(), customer, order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(html), "<title>Rechnung - Bestellung #7</title>") {
+		t.Errorf("HTML invoice is not German:\n%s", html)
+	}
+}
@@ -0,0 +1,82 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrRequestInProgress = errors.New("request with this idempotency key is in progress")
+
+// IdempotencyStore remembers which order an idempotency key produced,
+// so a retried request returns the first result instead of charging
+// the customer again.
+type IdempotencyStore interface {
+	// Begin claims key. If a request with key already completed, it
+	// returns that request's order ID and done == true. If one is
+	// still running, it returns ErrRequestInProgress.
+	Begin(ctx context.Context, key string) (orderID int, done bool, err error)
+	// Complete records the order placed for a claimed key.
+	Complete(ctx context.Context, key string, orderID int) error
+	// Release forgets a claimed key so the request can be retried
+	// after a failure.
+	Release(ctx context.Context, key string) error
+}
+
+// InMemoryIdempotencyStore keeps idempotency keys in a map. It is safe
+// for concurrent use.
+type InMemoryIdempotencyStore struct {
+	mu   sync.Mutex
+	keys map[string]idempotencyEntry
+}
+
+type idempotencyEntry struct {
+	orderID int
+	done    bool
+}
+
+func NewInMemoryIdempotencyStore() *InMemoryIdempotencyStore {
+	return &InMemoryIdempotencyStore{keys: make(map[string]idempotencyEntry)}
+}
+
+func (s *InMemoryIdempotencyStore) Begin(ctx context.Context, key string) (int, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.keys[key]
+	switch {
+	case !ok:
+		s.keys[key] = idempotencyEntry{}
+		return 0, false, nil
+	case entry.done:
+		return entry.orderID, true, nil
+	default:
+		return 0, false, ErrRequestInProgress
+	}
+}
+
+func (s *InMemoryIdempotencyStore) Complete(ctx context.Context, key string, orderID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key] = idempotencyEntry{orderID: orderID, done: true}
+	return nil
+}
+
+func (s *InMemoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, key)
+	return nil
+}
@@ -0,0 +1,96 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+// A retried request returns the order of the first one without
+// charging again; a failed one leaves its key free to retry.
+func TestOrderService_PlaceOrder_Idempotent(t *testing.T) {
+	ctx := context.Background()
+	var charges atomic.Int32
+	decline := true
+	orders := newBatchService(t, func(context.Context, int) error {
+		charges.Add(1)
+		if decline {
+			return ErrPaymentDeclined
+		}
+		return nil
+	}, 1)
+
+	if _, err := orders.PlaceOrder(ctx, "key-1", testOrder(t, 1)); !errors.Is(err, ErrPaymentDeclined) {
+		t.Fatalf("first attempt = %v, want declined", err)
+	}
+	decline = false
+	first, err := orders.PlaceOrder(ctx, "key-1", testOrder(t, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The retry carries a different order; the key decides.
+	again, err := orders.PlaceOrder(ctx, "key-1", testOrder(t, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != first.ID || again.PaymentID != first.PaymentID || again.Status != first.Status {
+		t.Errorf("retry returned %+v, want %+v", again, first)
+	}
+	if n := charges.Load(); n != 2 {
+		t.Errorf("%d charges, want 2: the declined one and the placed one", n)
+	}
+
+	if _, err := orders.PlaceOrder(ctx, "key-2", testOrder(t, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if n := charges.Load(); n != 3 {
+		t.Errorf("%d charges, want another key to charge again", n)
+	}
+}
+
+// Of concurrent requests with one key, one places the order and the
+// rest fail with ErrRequestInProgress until it is done.
+func TestOrderService_PlaceOrder_IdempotentConcurrent(t *testing.T) {
+	const requests = 8
+	ctx := context.Background()
+	var charges atomic.Int32
+	release := make(chan struct{})
+	orders := newBatchService(t, func(context.Context, int) error {
+		charges.Add(1)
+		<-release
+		return nil
+	}, 1)
+
+	type result struct {
+		order Order
+		err   error
+	}
+	results := make(chan result)
+	for range requests {
+		go func() {
+			order, err := orders.PlaceOrder(ctx, "key-1", testOrder(t, 1))
+			results <- result{order, err}
+		}()
+	}
+	// The request that claimed the key is held in Charge, so the others
+	// answer first.
+	for range requests - 1 {
+		if res := <-results; !errors.Is(res.err, ErrRequestInProgress) {
+			t.Errorf("concurrent request = %+v, %v; want in progress", res.order, res.err)
+		}
+	}
+	close(release)
+	placed := <-results
+	if placed.err != nil || placed.order.Status != StatusInvoiced {
+		t.Fatalf("claiming request = %+v, %v; want invoiced", placed.order, placed.err)
+	}
+
+	again, err := orders.PlaceOrder(ctx, "key-1", testOrder(t, 1))
+	if err != nil || again.ID != placed.order.ID || again.PaymentID != placed.order.PaymentID {
+		t.Errorf("retry after completion = %+v, %v; want %+v", again, err, placed.order)
+	}
+	if n := charges.Load(); n != 1 {
+		t.Errorf("%d charges, want 1", n)
+	}
+}
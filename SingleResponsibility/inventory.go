@@ -0,0 +1,167 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrInsufficientStock   = errors.New("insufficient stock")
+	ErrReservationNotFound = errors.New("stock reservation not found")
+)
+
+// StockRepository stores stock levels and the reservations held
+// against them.
+type StockRepository interface {
+	// Reserve takes the quantities of items out of stock on behalf of
+	// orderID. Either every item is reserved or none is.
+	Reserve(ctx context.Context, orderID int, items []OrderItem) error
+	// Release puts everything orderID reserved back into stock.
+	Release(ctx context.Context, orderID int) error
+}
+
+// InventoryService is responsible only for stock: holding it for an
+// order while the order is placed and handing it back if placement
+// fails. Stock levels live behind a StockRepository.
+type InventoryService struct {
+	stock StockRepository
+	log   Logger
+}
+
+func NewInventoryService(stock StockRepository, log Logger) *InventoryService {
+	return &InventoryService{stock: stock, log: orNop(log)}
+}
+
+// Reserve holds the stock order needs. It fails with
+// ErrInsufficientStock if any item is short.
+func (s *InventoryService) Reserve(ctx context.Context, order Order) error {
+	if err := s.stock.Reserve(ctx, order.ID, order.Items); err != nil {
+		return fmt.Errorf("reserving stock for order %d: %w", order.ID, err)
+	}
+	logf(ctx, s.log, "Reserved stock for order %d", order.ID)
+	return nil
+}
+
+// Release returns the stock reserved for orderID.
+func (s *InventoryService) Release(ctx context.Context, orderID int) error {
+	if err := s.stock.Release(ctx, orderID); err != nil {
+		return fmt.Errorf("releasing stock of order %d: %w", orderID, err)
+	}
+	logf(ctx, s.log, "Released stock of order %d", orderID)
+	return nil
+}
+
+// InMemoryStockRepository keeps stock levels in a map. It is safe for
+// concurrent use.
+type InMemoryStockRepository struct {
+	mu           sync.Mutex
+	stock        map[string]int
+	reservations map[int]map[string]int
+}
+
+func NewInMemoryStockRepository() *InMemoryStockRepository {
+	return &InMemoryStockRepository{
+		stock:        make(map[string]int),
+		reservations: make(map[int]map[string]int),
+	}
+}
+
+// SetStock sets the quantity of sku available for reservation.
+func (r *InMemoryStockRepository) SetStock(sku string, quantity int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stock[sku] = quantity
+}
+
+// Available returns the unreserved quantity of sku.
+func (r *InMemoryStockRepository) Available(sku string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stock[sku]
+}
+
+func (r *InMemoryStockRepository) Reserve(ctx context.Context, orderID int, items []OrderItem) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// The same SKU may appear on several lines.
+	wanted := make(map[string]int)
+	for _, item := range items {
+		wanted[item.SKU] += item.Quantity
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for sku, qty := range wanted {
+		if r.stock[sku] < qty {
+			return fmt.Errorf("%w: %s has %d, order %d needs %d", ErrInsufficientStock, sku, r.stock[sku], orderID, qty)
+		}
+	}
+
+	held := r.reservations[orderID]
+	if held == nil {
+		held = make(map[string]int)
+		r.reservations[orderID] = held
+	}
+	for sku, qty := range wanted {
+		r.stock[sku] -= qty
+		held[sku] += qty
+	}
+	return nil
+}
+
+func (r *InMemoryStockRepository) Release(ctx context.Context, orderID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	held, ok := r.reservations[orderID]
+	if !ok {
+		return fmt.Errorf("%w: order %d", ErrReservationNotFound, orderID)
+	}
+	for sku, qty := range held {
+		r.stock[sku] += qty
+	}
+	delete(r.reservations, orderID)
+	return nil
+}
+
+// WithInventory has the service reserve stock before charging.
+// Without it orders are placed regardless of stock.
+func WithInventory(inventory *InventoryService) OrderOption {
+	return WithStep(inventoryStep{inventory: inventory})
+}
+
+// inventoryStep reserves the stock of a stored order before it is
+// charged, and releases it when the order is cancelled.
+type inventoryStep struct {
+	inventory *InventoryService
+}
+
+func (inventoryStep) Name() string          { return "stock reservation" }
+func (inventoryStep) Stage() PlacementStage { return BeforeCharge }
+
+func (s inventoryStep) Run(ctx context.Context, _ Customer, order Order) (StepResult, error) {
+	if err := s.inventory.Reserve(ctx, order); err != nil {
+		return StepResult{}, err
+	}
+	return StepResult{
+		Order: order,
+		Audit: AuditStockReserved,
+		Undo:  func(ctx context.Context) error { return s.inventory.Release(ctx, order.ID) },
+	}, nil
+}
+
+func (s inventoryStep) Cancel(ctx context.Context, order Order) error {
+	if err := s.inventory.Release(ctx, order.ID); err != nil && !errors.Is(err, ErrReservationNotFound) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestInventoryService_ReserveAndRelease(t *testing.T) {
+	ctx := context.Background()
+	stock := NewInMemoryStockRepository()
+	stock.SetStock("BOOK", 5)
+	stock.SetStock("PEN", 2)
+	inventory := NewInventoryService(stock, nil)
+	order := func(id int, items ...OrderItem) Order {
+		return Order{ID: id, Items: items}
+	}
+	item := func(sku string, qty int) OrderItem { return OrderItem{SKU: sku, Quantity: qty} }
+	available := func() string { return fmt.Sprintf("BOOK %d, PEN %d", stock.Available("BOOK"), stock.Available("PEN")) }
+
+	// Lines of the same SKU count together.
+	if err := inventory.Reserve(ctx, order(1, item("BOOK", 2), item("PEN", 1), item("BOOK", 1))); err != nil {
+		t.Fatal(err)
+	}
+	if got := available(); got != "BOOK 2, PEN 1" {
+		t.Errorf("after reserving order 1: %s", got)
+	}
+
+	// An order that would oversell any item reserves nothing.
+	if err := inventory.Reserve(ctx, order(2, item("BOOK", 1), item("PEN", 2))); !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("oversell = %v, want ErrInsufficientStock", err)
+	}
+	if err := inventory.Reserve(ctx, order(2, item("BOOK", 1), item("BOOK", 2))); !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("oversell across lines = %v, want ErrInsufficientStock", err)
+	}
+	if got := available(); got != "BOOK 2, PEN 1" {
+		t.Errorf("after the failed reservations: %s", got)
+	}
+	if err := inventory.Release(ctx, 2); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("Release of a failed reservation = %v, want ErrReservationNotFound", err)
+	}
+
+	// Exactly what is left can be reserved.
+	if err := inventory.Reserve(ctx, order(3, item("BOOK", 2), item("PEN", 1))); err != nil {
+		t.Fatal(err)
+	}
+	if got := available(); got != "BOOK 0, PEN 0" {
+		t.Errorf("after reserving order 3: %s", got)
+	}
+
+	if err := inventory.Release(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := available(); got != "BOOK 3, PEN 1" {
+		t.Errorf("after releasing order 1: %s", got)
+	}
+	if err := inventory.Release(ctx, 1); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("second Release = %v, want ErrReservationNotFound", err)
+	}
+}
+
+// Concurrent orders never take more than there is.
+func TestInMemoryStockRepository_ConcurrentOversell(t *testing.T) {
+	const inStock, orders = 10, 50
+	stock := NewInMemoryStockRepository()
+	stock.SetStock("BOOK", inStock)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		reserved int
+	)
+	for id := range orders {
+		wg.Go(func() {
+			err := stock.Reserve(context.Background(), id, []OrderItem{{SKU: "BOOK", Quantity: 1}})
+			if err != nil && !errors.Is(err, ErrInsufficientStock) {
+				t.Error(err)
+			}
+			if err == nil {
+				mu.Lock()
+				reserved++
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	if reserved != inStock || stock.Available("BOOK") != 0 {
+		t.Errorf("%d reserved, %d left; want %d and 0", reserved, stock.Available("BOOK"), inStock)
+	}
+}
+
+// An order that would oversell is rejected before the charge.
+func TestOrderService_PlaceOrder_OutOfStock(t *testing.T) {
+	stock := NewInMemoryStockRepository()
+	stock.SetStock("BOOK", 1)
+	log := &callLog{}
+	orders, err := NewOrderService(fakeStore{NewInMemoryOrderRepository(), log, nil}, fakeGateway{log, nil}, fakeSender{log, nil}, fakeInvoicer{log, nil},
+		WithInventory(NewInventoryService(stock, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// testOrder wants two books.
+	if _, err := orders.PlaceOrder(context.Background(), "", testOrder(t, 1)); !errors.Is(err, ErrInsufficientStock) {
+		t.Fatalf("PlaceOrder = %v, want ErrInsufficientStock", err)
+	}
+	for _, call := range log.all() {
+		if call == "payment.Charge 25.00 USD" {
+			t.Errorf("the order was charged: %q", log.all())
+		}
+	}
+	if n := stock.Available("BOOK"); n != 1 {
+		t.Errorf("%d books available, want 1", n)
+	}
+}
@@ -0,0 +1,306 @@
+package singleresponsibility
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"maps"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/i18n"
+)
+
+var ErrInvoiceNotFound = errors.New("invoice not found")
+
+// Invoice is the document produced for a placed order. Its amounts
+// come from PricingService.
+type Invoice struct {
+	OrderID    int
+	CustomerID int
+	BillTo     string
+	Address    Address
+	IssuedAt   time.Time
+	Locale     string // the customer's, for the labels
+	Price
+}
+
+// InvoiceRenderer turns an Invoice into a concrete document format.
+type InvoiceRenderer interface {
+	Render(w io.Writer, inv Invoice) error
+}
+
+// InvoiceFormat names a document format a customer can ask for.
+type InvoiceFormat string
+
+const (
+	InvoiceText InvoiceFormat = "text"
+	InvoiceHTML InvoiceFormat = "html"
+	InvoicePDF  InvoiceFormat = "pdf"
+)
+
+// Valid reports whether f is one of the known formats.
+func (f InvoiceFormat) Valid() bool {
+	return f == InvoiceText || f == InvoiceHTML || f == InvoicePDF
+}
+
+// InvoiceService is responsible only for building invoices and
+// rendering them. Amounts come from a PricingService and the output
+// format from an InvoiceRenderer, chosen by the customer's preferred
+// InvoiceFormat or, for customers without one, by an
+// InvoiceExperiment.
+type InvoiceService struct {
+	renderer InvoiceRenderer
+	formats  map[InvoiceFormat]InvoiceRenderer
+	pricing  *PricingService
+	log      Logger
+
+	experiment *InvoiceExperiment
+}
+
+// NewInvoiceService returns an invoice service. pricing may be nil, in
+// which case invoices carry no tax.
+func NewInvoiceService(renderer InvoiceRenderer, pricing *PricingService, log Logger) *InvoiceService {
+	if pricing == nil {
+		pricing = NewPricingService(nil)
+	}
+	return &InvoiceService{renderer: renderer, pricing: pricing, log: orNop(log)}
+}
+
+// WithFormat returns a copy of the service that renders invoices with
+// renderer for customers preferring format. Customers without a
+// preference, or preferring a format without a renderer, get the
+// renderer given to NewInvoiceService.
+func (s *InvoiceService) WithFormat(format InvoiceFormat, renderer InvoiceRenderer) *InvoiceService {
+	c := *s
+	c.formats = maps.Clone(s.formats)
+	if c.formats == nil {
+		c.formats = make(map[InvoiceFormat]InvoiceRenderer)
+	}
+	c.formats[format] = renderer
+	return &c
+}
+
+func (s *InvoiceService) rendererFor(customer Customer) InvoiceRenderer {
+	if customer.InvoiceFormat == "" && s.experiment != nil {
+		if segment, format, ok := s.experiment.format(customer); ok {
+			if r, ok := s.formats[format]; ok {
+				if s.experiment.Served != nil {
+					s.experiment.Served(segment, format)
+				}
+				return r
+			}
+		}
+	}
+	if customer.InvoiceFormat == "" {
+		return s.renderer
+	}
+	if r, ok := s.formats[customer.InvoiceFormat]; ok {
+		return r
+	}
+	s.log.Printf("No %s invoice renderer for customer %d; using the default", customer.InvoiceFormat, customer.ID)
+	return s.renderer
+}
+
+// Build computes the invoice for order, billed to customer.
+func (s *InvoiceService) Build(customer Customer, order Order) (Invoice, error) {
+	price, err := s.pricing.Price(customer, order)
+	if err != nil {
+		return Invoice{}, fmt.Errorf("invoice for order %d: %w", order.ID, err)
+	}
+	return Invoice{
+		OrderID:    order.ID,
+		CustomerID: order.CustomerID,
+		BillTo:     customer.Name,
+		Address:    customer.Address,
+		IssuedAt:   time.Now(),
+		Locale:     customer.Locale,
+		Price:      price,
+	}, nil
+}
+
+// Generate builds the invoice for order and returns the rendered
+// document, so callers can persist or email it.
+func (s *InvoiceService) Generate(ctx context.Context, customer Customer, order Order) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	inv, err := s.Build(customer, order)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := s.rendererFor(customer).Render(&buf, inv); err != nil {
+		return nil, fmt.Errorf("rendering invoice for order %d: %w", order.ID, err)
+	}
+	logf(ctx, s.log, "Generated invoice for order %d (%d bytes)", order.ID, buf.Len())
+	return buf.Bytes(), nil
+}
+
+// invoiceLabels is the wording of an invoice in its locale, from the
+// invoice.* messages.
+type invoiceLabels struct {
+	Heading, Title, Date, BillTo, Customer string
+	SKU, Qty, Unit, Amount                 string
+	Subtotal, Discount, Total              string
+}
+
+func labelsFor(messages i18n.Translator, inv Invoice) (invoiceLabels, error) {
+	var l invoiceLabels
+	fields := map[string]*string{
+		"heading": &l.Heading, "title": &l.Title, "date": &l.Date, "bill_to": &l.BillTo, "customer": &l.Customer,
+		"sku": &l.SKU, "qty": &l.Qty, "unit": &l.Unit, "amount": &l.Amount,
+		"subtotal": &l.Subtotal, "discount": &l.Discount, "total": &l.Total,
+	}
+	for key, field := range fields {
+		text, err := messagesOr(messages).Translate(inv.Locale, "invoice."+key, inv)
+		if err != nil {
+			return invoiceLabels{}, err
+		}
+		*field = text
+	}
+	return l, nil
+}
+
+// invoiceText lays out an invoice as lines of plain text. The text and
+// PDF renderers share it, so they always show the same content.
+func invoiceText(inv Invoice, l invoiceLabels) []string {
+	// The values line up after the longer label, its colon and two
+	// spaces; the address lines up below the name.
+	indent := max(utf8.RuneCountInString(l.Date), utf8.RuneCountInString(l.BillTo)) + 3
+	lines := []string{
+		l.Heading,
+		fmt.Sprintf("%-*s%s", indent, l.Date+":", inv.IssuedAt.Format("2006-01-02")),
+		"",
+		fmt.Sprintf("%-*s%s (%s)", indent, l.BillTo+":", inv.BillTo, l.Customer),
+	}
+	for _, line := range inv.Address.Lines() {
+		lines = append(lines, strings.Repeat(" ", indent)+line)
+	}
+	lines = append(lines,
+		"",
+		fmt.Sprintf("%-12s %5s %10s %10s", strings.ToUpper(l.SKU), strings.ToUpper(l.Qty), strings.ToUpper(l.Unit), strings.ToUpper(l.Amount)),
+	)
+	for _, line := range inv.Lines {
+		lines = append(lines, fmt.Sprintf("%-12s %5d %10s %10s", line.SKU, line.Quantity, line.UnitPrice.Decimal(), line.Amount.Decimal()))
+	}
+	lines = append(lines, "", fmt.Sprintf("%-29s %10s", l.Subtotal, inv.Subtotal.Decimal()))
+	if !inv.Discount.IsZero() {
+		lines = append(lines, fmt.Sprintf("%-29s %10s", l.Discount, "-"+inv.Discount.Decimal()))
+	}
+	for _, tax := range inv.Taxes {
+		lines = append(lines, fmt.Sprintf("%-29s %10s", tax.Name, tax.Amount.Decimal()))
+	}
+	return append(lines, fmt.Sprintf("%-29s %10s %s", l.Total, inv.Total.Decimal(), inv.Total.Currency))
+}
+
+// TextInvoiceRenderer renders invoices as plain text. Messages words
+// them; nil means the built-in messages. The other renderers work the
+// same way.
+type TextInvoiceRenderer struct {
+	Messages i18n.Translator
+}
+
+func (r TextInvoiceRenderer) Render(w io.Writer, inv Invoice) error {
+	l, err := labelsFor(r.Messages, inv)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, strings.Join(invoiceText(inv, l), "\n")+"\n")
+	return err
+}
+
+// HTMLInvoiceRenderer renders invoices as a standalone HTML page, for
+// customers who read their invoice in a browser or mail client.
+type HTMLInvoiceRenderer struct {
+	Messages i18n.Translator
+}
+
+var invoiceHTMLTemplate = htmltemplate.Must(htmltemplate.New("invoice").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.L.Title}}</title>
+</head>
+<body>
+<h1>{{.L.Title}}</h1>
+<p>{{.L.Date}}: {{.IssuedAt.Format "2006-01-02"}}</p>
+<p>{{.L.BillTo}}: {{.BillTo}} ({{.L.Customer}}){{range .Address.Lines}}<br>{{.}}{{end}}</p>
+<table>
+<tr><th>{{.L.SKU}}</th><th>{{.L.Qty}}</th><th>{{.L.Unit}}</th><th>{{.L.Amount}}</th></tr>
+{{range .Lines}}<tr><td>{{.SKU}}</td><td>{{.Quantity}}</td><td>{{.UnitPrice.Decimal}}</td><td>{{.Amount.Decimal}}</td></tr>
+{{end}}<tr><td colspan="3">{{.L.Subtotal}}</td><td>{{.Subtotal.Decimal}}</td></tr>
+{{if not .Discount.IsZero}}<tr><td colspan="3">{{.L.Discount}}</td><td>-{{.Discount.Decimal}}</td></tr>
+{{end}}{{range .Taxes}}<tr><td colspan="3">{{.Name}}</td><td>{{.Amount.Decimal}}</td></tr>
+{{end}}<tr><th colspan="3">{{.L.Total}}</th><th>{{.Total}}</th></tr>
+</table>
+</body>
+</html>
+`))
+
+func (r HTMLInvoiceRenderer) Render(w io.Writer, inv Invoice) error {
+	l, err := labelsFor(r.Messages, inv)
+	if err != nil {
+		return err
+	}
+	return invoiceHTMLTemplate.Execute(w, struct {
+		Invoice
+		L invoiceLabels
+	}{inv, l})
+}
+
+// PDFInvoiceRenderer renders invoices as a minimal single-page PDF
+// using the built-in Courier font.
+type PDFInvoiceRenderer struct {
+	Messages i18n.Translator
+}
+
+func (r PDFInvoiceRenderer) Render(w io.Writer, inv Invoice) error {
+	l, err := labelsFor(r.Messages, inv)
+	if err != nil {
+		return err
+	}
+	var content strings.Builder
+	content.WriteString("BT\n/F1 10 Tf\n14 TL\n50 800 Td\n")
+	for _, line := range invoiceText(inv, l) {
+		fmt.Fprintf(&content, "(%s) Tj T*\n", pdfEscape(line))
+	}
+	content.WriteString("ET")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] " +
+			"/Resources << /Font << /F1 5 0 R >> >> /Contents 4 0 R >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func pdfEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(s)
+}
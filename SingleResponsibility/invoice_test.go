@@ -0,0 +1,80 @@
+package singleresponsibility
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// invoiceFixture prices an order with a coupon and German VAT for a
+// customer whose name needs escaping in HTML and PDF.
+func invoiceFixture(t *testing.T) (*InvoiceService, Customer, Order) {
+	t.Helper()
+	customer := Customer{
+		ID:      1,
+		Name:    "Ada & Co (Berlin)",
+		Address: Address{Street: "Unter den Linden 1", City: "Berlin", PostalCode: "10117", Country: "DE"},
+	}
+	order, err := NewOrder(7, 1, []OrderItem{
+		{SKU: "BOOK", Quantity: 2, UnitPrice: NewMoney(1250, "EUR")},
+		{SKU: "PEN", Quantity: 3, UnitPrice: NewMoney(199, "EUR")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	order.CouponCode = "SAVE5"
+	order.Discount = NewMoney(500, "EUR")
+	pricing := NewPricingService(NewTaxService(VATRule{Country: "DE", Rate: 0.19, ReducedRate: 0.07, Reduced: map[string]bool{"BOOK": true}}))
+	return NewInvoiceService(TextInvoiceRenderer{}, pricing, nil), customer, order
+}
+
+func TestInvoiceRenderers(t *testing.T) {
+	s, customer, order := invoiceFixture(t)
+	inv, err := s.Build(customer, order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv.IssuedAt = time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	for name, r := range map[string]InvoiceRenderer{
+		"invoice.txt":  TextInvoiceRenderer{},
+		"invoice.html": HTMLInvoiceRenderer{},
+		"invoice.pdf":  PDFInvoiceRenderer{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := r.Render(&buf, inv); err != nil {
+				t.Fatal(err)
+			}
+			golden(t, name, buf.Bytes())
+		})
+	}
+}
+
+// Generate renders in the customer's preferred format, falling back to
+// the default renderer.
+func TestInvoiceService_GenerateByPreference(t *testing.T) {
+	s, customer, order := invoiceFixture(t)
+	s = s.WithFormat(InvoiceHTML, HTMLInvoiceRenderer{}).WithFormat(InvoicePDF, PDFInvoiceRenderer{})
+	tests := []struct {
+		format InvoiceFormat
+		prefix string
+	}{
+		{"", "INVOICE"},
+		{InvoiceText, "INVOICE"}, // no text renderer registered: the default
+		{InvoiceHTML, "<!DOCTYPE html>"},
+		{InvoicePDF, "%PDF-1.4"},
+	}
+	for _, tt := range tests {
+		customer.InvoiceFormat = tt.format
+		doc, err := s.Generate(context.Background(), customer, order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(doc), tt.prefix) {
+			t.Errorf("format %q: document starts %q, want %q", tt.format, doc[:min(len(doc), 20)], tt.prefix)
+		}
+	}
+}
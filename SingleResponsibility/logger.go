@@ -0,0 +1,60 @@
+package singleresponsibility
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Logger is the only way the services produce output. Injecting it
+// instead of calling fmt directly keeps output a replaceable detail
+// and makes it assertable.
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+// ContextLogger is a Logger that can also use the context an entry is
+// logged in, for example to attach the request and order IDs from
+// WithRequestID and WithOrderID. SlogLogger is one.
+type ContextLogger interface {
+	Logger
+	PrintfContext(ctx context.Context, format string, args ...any)
+}
+
+// logf logs through log, passing ctx along if log is a ContextLogger.
+func logf(ctx context.Context, log Logger, format string, args ...any) {
+	if cl, ok := log.(ContextLogger); ok {
+		cl.PrintfContext(ctx, format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+// StdoutLogger prints every entry on its own line.
+type StdoutLogger struct{}
+
+func (StdoutLogger) Printf(format string, args ...any) {
+	fmt.Printf(format+"\n", args...)
+}
+
+// WriterLogger prints every entry on its own line to W.
+type WriterLogger struct {
+	W io.Writer
+}
+
+func (l WriterLogger) Printf(format string, args ...any) {
+	fmt.Fprintf(l.W, format+"\n", args...)
+}
+
+// NopLogger discards every entry.
+type NopLogger struct{}
+
+func (NopLogger) Printf(string, ...any) {}
+
+// orNop lets constructors accept a nil Logger.
+func orNop(log Logger) Logger {
+	if log == nil {
+		return NopLogger{}
+	}
+	return log
+}
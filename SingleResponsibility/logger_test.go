@@ -0,0 +1,26 @@
+package singleresponsibility
+
+import (
+	"fmt"
+	"sync"
+)
+
+// CapturingLogger records entries in memory so tests can assert on
+// them.
+type CapturingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *CapturingLogger) Printf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+// Lines returns the entries logged so far.
+func (l *CapturingLogger) Lines() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.lines...)
+}
@@ -7,19 +7,94 @@
 //
 // In this example:
 //
-// OrderRepository  → Responsible only for saving orders to DB.
-// PaymentService   → Responsible only for processing payments.
-// EmailService     → Responsible only for sending emails.
-// InvoiceService   → Responsible only for generating invoices.
-// OrderService     → Responsible only for coordinating the order workflow.
+// Order            → Domain model; NewOrder validates its own invariants.
+// Money            → Value type for amounts: integer minor units plus
+//                    a currency, so arithmetic never rounds.
+// OrderStore       → Responsible only for persisting orders
+//                    (InMemoryOrderRepository and SQLOrderRepository
+//                    implement it).
+// PaymentGateway   → Responsible only for charging and refunding
+//                    (FakeStripeGateway and FakePayPalGateway
+//                    implement it).
+// EmailService     → Responsible only for composing customer emails
+//                    (delivery goes through an EmailSender).
+// EmailQueue       → Responsible only for delivering emails in the
+//                    background, off the request path.
+// InvoiceService   → Responsible only for generating invoices
+//                    (each format comes from an InvoiceRenderer,
+//                    picked by the customer's preference).
+// CouponService    → Responsible only for validating coupon codes and
+//                    computing their discount (one DiscountKind each).
+// InventoryService → Responsible only for reserving and releasing stock
+//                    (levels live behind a StockRepository).
+// ShippingService  → Responsible only for booking deliveries with a
+//                    Carrier.
+// TaxService       → Responsible only for computing tax, one TaxRule
+//                    per jurisdiction.
+// Rule             → Responsible only for checking one property of an
+//                    incoming order; And/Or combine rules.
+// PricingService   → Responsible only for working out what an order
+//                    costs: lines, discount and tax.
+// FraudCheckService
+//                  → Responsible only for deciding whether an order is
+//                    too risky to charge, one FraudRule per signal.
+// OrderService     → Responsible only for coordinating the order workflow,
+//                    including which status changes are legal.
+// PlacementStep    → Responsible only for one optional step of placing
+//                    an order; coupons, fraud checks, stock and
+//                    shipping plug in as steps, not as OrderService
+//                    fields.
+// RefundService    → Responsible only for coordinating refunds.
+// CustomerRepository
+//                  → Responsible only for storing customers.
+// OutboxDispatcher → Responsible only for turning recorded outbox
+//                    messages into emails.
+// UnitOfWork       → Responsible only for committing writes to several
+//                    stores together, or not at all (SQLUnitOfWork
+//                    does it for SQLOrderRepository, SQLAuditStore and
+//                    SQLOutbox, which share one database).
+// OrderExporter    → Responsible only for exporting stored orders
+//                    (the format comes from an OrderEncoder).
+// AuditLogService  → Responsible only for the append-only audit trail.
+// Config, Wire     → Responsible only for choosing which implementations
+//                    are wired together (the composition root).
+// OrderHandler     → Responsible only for HTTP: decoding requests and
+//                    mapping results to status codes.
+// PaymentWebhook   → Responsible only for turning the payment gateway's
+//                    verified callbacks into order status changes.
+// Commands         → Responsible only for routing the order commands
+//                    to the services through the command bus.
+// OrderProjector   → Responsible only for keeping the order summaries
+//                    up to date from the placed orders' events.
 //
 // Why this follows SRP:
 //
-// - If database logic changes → Only OrderRepository changes.
-// - If payment gateway changes → Only PaymentService changes.
-// - If email provider changes → Only EmailService changes.
-// - If invoice format changes → Only InvoiceService changes.
+// - If database logic changes → Only the OrderStore implementation changes.
+// - If payment gateway changes → Only the PaymentGateway implementation changes.
+// - If email provider changes → Only the EmailSender implementation changes.
+// - If email or invoice wording changes → Only the locales/ catalog changes.
+// - If a language is added → Only a locales/<locale>.json file is added.
+// - If emails must be sent in the background → Only EmailQueue changes.
+// - If invoice format changes → Only its InvoiceRenderer changes.
+// - If price calculation changes → Only PricingService changes.
+// - If stock rules change → Only InventoryService changes.
+// - If a new kind of discount is added → Only a new DiscountKind is added.
+// - If the carrier changes → Only the Carrier implementation changes.
+// - If an order acceptance rule changes → Only its Rule changes.
+// - If a status change needs a new reaction → Only a StatusHook is added.
+// - If a fraud signal is added → Only a new FraudRule is added.
+// - If placing an order needs a new step → Only a new PlacementStep is added.
 // - If order flow changes → Only OrderService changes.
+// - If the gateway signs its callbacks differently → Only the
+//   webhook.Verifier changes.
+// - If compliance logging changes → Only AuditLogService changes.
+// - If an export format changes → Only its OrderEncoder changes.
+// - If deleted orders must be kept → Only ArchivingRepository wraps the store.
+// - If how writes are made atomic changes → Only the UnitOfWork implementation changes.
+// - If monitoring changes → Only the Metered* decorators change.
+// - If the metrics backend changes → Only the metrics.Provider changes.
+// - If a deployment swaps an implementation → Only its Config changes.
+// - If the HTTP API changes → Only OrderHandler changes.
 //
 // Each struct has exactly ONE responsibility.
 // Each struct has exactly ONE reason to change.
@@ -44,100 +119,349 @@
 // BAD EXAMPLE - Violates Single Responsibility Principle (SRP)
 // =========================================
 //
-// Definition Reminder:
-// A struct should have only ONE reason to change.
-//
-// Problem in this example:
-// OrderService is handling MULTIPLE responsibilities:
-//
-// 1. Saving order to database
-// 2. Processing payment
-// 3. Sending confirmation email
-// 4. Generating invoice
-//
-// Why this violates SRP:
-//
-// If database logic changes → this struct changes
-// If payment gateway changes → this struct changes
-// If email provider changes → this struct changes
-// If invoice format changes → this struct changes
+// The violating OrderService lives in badsrp/main.go as a program of
+// its own, so it compiles and runs next to this one:
 //
-// That means this struct has MULTIPLE reasons to change.
-//
-// This makes the code:
-//
-// ❌ Hard to maintain
-// ❌ Hard to test
-// ❌ Tightly coupled
-// ❌ Difficult to scale
-//
-// Proper design would separate these responsibilities
+//	go run badsrp/main.go
 
-// into different structs/services.
 
-// type OrderService struct{}
 
-// func (o OrderService) PlaceOrder(orderID int, amount float64) {
+// =============== PERFECT EXAMPLE ===============
+package singleresponsibility
 
-// 	// Responsibility 1: Database logic
-// 	fmt.Printf("Saving order %d to database\n", orderID)
+import (
+	"context"
+	"errors"
+	"fmt"
 
-// 	// Responsibility 2: Payment processing
-// 	fmt.Printf("Processing payment of %.2f\n", amount)
+	"github.com/anil-vinnakoti/go-SOLID/pkg/tracing"
+)
 
-// 	// Responsibility 3: Email sending
-// 	fmt.Println("Sending confirmation email")
+var ErrMissingDependency = errors.New("missing dependency")
 
-// 	// Responsibility 4: Invoice generation
-// 	fmt.Printf("Generating invoice for order %d\n", orderID)
-// }
+// OrderStore is the storage abstraction OrderService needs. It is
+// defined here, next to its consumer, so any storage can be plugged in.
+type OrderStore interface {
+	// Save inserts an order whose Version is 0, failing with
+	// ErrOrderExists if its ID is taken, and otherwise updates the
+	// stored order if it still has order's Version, failing with
+	// ErrOrderChanged if another save came first. The stored Version is
+	// one more than order's.
+	Save(ctx context.Context, order Order) error
+	FindByID(ctx context.Context, id int) (Order, error)
+	List(ctx context.Context, filter OrderFilter) ([]Order, error)
+	Delete(ctx context.Context, id int) error
+}
 
-// func main() {
-// 	service := OrderService{}
-// 	service.PlaceOrder(1, 5000)
-// }
+// PaymentGateway charges and refunds customers. Charge returns the
+// provider's payment ID, which Refund takes to reverse it.
+type PaymentGateway interface {
+	Charge(ctx context.Context, orderID int, amount Money) (string, error)
+	Refund(ctx context.Context, paymentID string) error
+}
 
+// InvoiceGenerator produces the invoice document of a placed order.
+// InvoiceService implements it.
+type InvoiceGenerator interface {
+	Generate(ctx context.Context, customer Customer, order Order) ([]byte, error)
+}
 
+type OrderService struct {
+	repo        OrderStore
+	payment     PaymentGateway
+	pricing     *PricingService
+	email       *EmailService
+	invoice     InvoiceGenerator
+	audit       *AuditLogService
+	outbox      Outbox
+	uow         UnitOfWork
+	validation  Rule
+	steps       []PlacementStep
+	idempotency IdempotencyStore
+	customers   CustomerRepository
+	events      EventPublisher
+	eventDriven bool
+	statusHooks []StatusHook
+	tracer      tracing.Tracer
+	reporter    ErrReporter
+	analytics   AnalyticsTracker
+	log         Logger
 
-// =============== PERFECT EXAMPLE ===============
-package main
+	batchConcurrency int
+}
 
-import "fmt"
+// OrderOption configures the optional collaborators and steps of
+// NewOrderService.
+type OrderOption func(*OrderService)
 
-type OrderRepository struct{}
+// NewOrderService wires the workflow's required collaborators, all
+// given as interfaces, and then applies opts in order. A missing
+// collaborator is reported as ErrMissingDependency instead of
+// surfacing later as a nil pointer panic in the middle of an order.
+// Idempotency keys are remembered in memory unless
+// WithIdempotencyStore replaces it, and orders are priced without tax
+// unless WithPricing replaces it.
+func NewOrderService(repo OrderStore, payment PaymentGateway, mail EmailSender, invoice InvoiceGenerator, opts ...OrderOption) (*OrderService, error) {
+	deps := []struct {
+		name  string
+		value any
+	}{{"repo", repo}, {"payment", payment}, {"mail", mail}, {"invoice", invoice}}
+	var missing []error
+	for _, dep := range deps {
+		if dep.value == nil {
+			missing = append(missing, fmt.Errorf("%w: %s", ErrMissingDependency, dep.name))
+		}
+	}
+	if err := errors.Join(missing...); err != nil {
+		return nil, fmt.Errorf("new order service: %w", err)
+	}
 
-func (o OrderRepository) Save(orderID int) {
-	fmt.Printf("Saving order %d to database\n", orderID)
+	os := &OrderService{
+		repo:        repo,
+		payment:     payment,
+		pricing:     NewPricingService(nil),
+		email:       NewEmailService(mail),
+		invoice:     invoice,
+		idempotency: NewInMemoryIdempotencyStore(),
+	}
+	for _, opt := range opts {
+		opt(os)
+	}
+	return os, nil
 }
 
-type PaymentService struct{}
+// WithIdempotencyStore has the service remember idempotency keys in
+// store.
+func WithIdempotencyStore(store IdempotencyStore) OrderOption {
+	return func(os *OrderService) { os.idempotency = store }
+}
 
-func (p PaymentService) Process(amount float64) {
-	fmt.Printf("Processing payment of %.2f\n", amount)
+// WithTracer has the service report spans to t.
+func WithTracer(t tracing.Tracer) OrderOption {
+	return func(os *OrderService) { os.tracer = t }
 }
 
-type EmailService struct{}
+// WithLogger has the service log to log.
+func WithLogger(log Logger) OrderOption {
+	return func(os *OrderService) { os.log = log }
+}
 
-func (e EmailService) Send() {
-	fmt.Println("Sending confirmation email")
+func (os OrderService) logger() Logger {
+	return orNop(os.log)
 }
 
-type InvoiceService struct{}
+// PlaceOrder runs the order workflow and returns the order in its
+// final status. The order moves Pending → Paid → Invoiced; every
+// change goes through the status state machine. PaymentCaptured,
+// InvoiceGenerated and finally OrderPlaced are published on the way.
+//
+// A non-empty idempotencyKey makes the call safe to retry: once a
+// request with that key succeeded, later calls return the stored order
+// without charging again. A failed request releases its key.
+//
+// The workflow stops at the first failing step. Steps that already
+// succeeded are compensated in reverse order: the shipment is
+// cancelled, the payment is refunded,
+// reserved stock is released, the saved order is deleted and the
+// coupon use is given back.
+//
+// Cancelling ctx stops the workflow before the next step. The
+// compensations still run, detached from the cancellation.
+//
+// The returned error wraps the cause, so callers can still use
+// errors.Is on the collaborator's error.
+func (os OrderService) PlaceOrder(ctx context.Context, idempotencyKey string, order Order) (Order, error) {
+	ctx, span := tracing.OrNoop(os.tracer).StartSpan(ctx, "OrderService.PlaceOrder")
+	defer span.End()
+
+	if idempotencyKey == "" {
+		return os.placeOrder(ctx, order)
+	}
+
+	orderID, done, err := os.idempotency.Begin(ctx, idempotencyKey)
+	if err != nil {
+		return Order{}, fmt.Errorf("idempotency key %q: %w", idempotencyKey, err)
+	}
+	if done {
+		return os.repo.FindByID(ctx, orderID)
+	}
 
-func (i InvoiceService) Generate(orderID int) {
-	fmt.Printf("Generating invoice for order %d\n", orderID)
+	// The key must be settled even if ctx was cancelled mid-flow.
+	settleCtx := context.WithoutCancel(ctx)
+
+	placed, err := os.placeOrder(ctx, order)
+	if err != nil {
+		if rerr := os.idempotency.Release(settleCtx, idempotencyKey); rerr != nil {
+			err = errors.Join(err, fmt.Errorf("releasing idempotency key %q: %w", idempotencyKey, rerr))
+		}
+		return Order{}, err
+	}
+	if err := os.idempotency.Complete(settleCtx, idempotencyKey, placed.ID); err != nil {
+		// The order is placed. Leaving the key claimed makes retries
+		// fail with ErrRequestInProgress rather than charge twice.
+		logf(ctx, os.logger(), "Order %d placed but idempotency key %q not recorded: %v", placed.ID, idempotencyKey, err)
+	}
+	return placed, nil
 }
 
-type OrderService struct {
-	repo    OrderRepository
-	payment PaymentService
-	email   EmailService
-	invoice InvoiceService
+func (os OrderService) placeOrder(ctx context.Context, order Order) (_ Order, err error) {
+	ctx = WithOrderID(ctx, order.ID)
+	var undo compensations
+	undoCtx := context.WithoutCancel(ctx)
+	charged := false
+	os.track(ctx, FunnelCheckoutStarted, order, nil)
+	defer func() {
+		os.trackOutcome(undoCtx, order, err)
+		if err != nil && len(undo) > 0 {
+			os.record(undoCtx, AuditOrderRolledBack, order.ID, err.Error())
+		}
+		if err != nil && (charged || errors.As(err, new(*CompensationError))) {
+			os.report(undoCtx, err, "PlaceOrder")
+		}
+	}()
+
+	if err := os.checkOrder(ctx, order); err != nil {
+		return Order{}, err
+	}
+	customer, err := resolveCustomer(ctx, os.customers, order)
+	if err != nil {
+		return Order{}, err
+	}
+
+	if err := stopped(ctx, order.ID); err != nil {
+		return Order{}, err
+	}
+	if order.CouponCode != "" && !os.acceptsCoupons() {
+		return Order{}, fmt.Errorf("%w: %q: coupons are not accepted", ErrCouponNotFound, order.CouponCode)
+	}
+	if order, err = os.runSteps(ctx, undoCtx, BeforePricing, customer, order, &undo); err != nil {
+		return Order{}, undo.rollback(err)
+	}
+
+	priced, err := os.pricing.PriceOrder(customer, order)
+	if err != nil {
+		return Order{}, undo.rollback(err)
+	}
+	order = priced
+	if order, err = os.runSteps(ctx, undoCtx, BeforeSave, customer, order, &undo); err != nil {
+		return Order{}, undo.rollback(err)
+	}
+
+	if err := stopped(ctx, order.ID); err != nil {
+		return Order{}, undo.rollback(err)
+	}
+	// The gateway's callbacks must leave alone an order charged here;
+	// see CapturePayment.
+	order.SyncPayment = true
+	if err := os.repo.Save(ctx, order); err != nil {
+		return Order{}, undo.rollback(fmt.Errorf("saving order %d: %w", order.ID, err))
+	}
+	order.Version++
+	undo.add("saved order", func() error { return os.repo.Delete(undoCtx, order.ID) })
+	os.record(ctx, AuditOrderSaved, order.ID, "")
+
+	if err := stopped(ctx, order.ID); err != nil {
+		return Order{}, undo.rollback(err)
+	}
+	if order, err = os.runSteps(ctx, undoCtx, BeforeCharge, customer, order, &undo); err != nil {
+		return Order{}, undo.rollback(err)
+	}
+
+	if err := stopped(ctx, order.ID); err != nil {
+		return Order{}, undo.rollback(err)
+	}
+	paymentID, err := os.payment.Charge(ctx, order.ID, order.Total)
+	if err != nil {
+		return Order{}, undo.rollback(fmt.Errorf("charging order %d: %w", order.ID, err))
+	}
+	charged = true
+	undo.add("payment", func() error { return os.payment.Refund(undoCtx, paymentID) })
+	os.record(ctx, AuditPaymentCharged, order.ID, fmt.Sprintf("%s (%s)", order.Total, paymentID))
+
+	if err := stopped(ctx, order.ID); err != nil {
+		return Order{}, undo.rollback(err)
+	}
+	if order, err = os.runSteps(ctx, undoCtx, BeforePaid, customer, order, &undo); err != nil {
+		return Order{}, undo.rollback(err)
+	}
+
+	if err := stopped(ctx, order.ID); err != nil {
+		return Order{}, undo.rollback(err)
+	}
+	order.PaymentID = paymentID
+	if err := os.markPaid(ctx, &order); err != nil {
+		return Order{}, undo.rollback(err)
+	}
+	os.publish(ctx, PaymentCaptured{OrderID: order.ID, PaymentID: paymentID, Amount: order.Total})
+
+	if !os.eventDriven {
+		if err := stopped(ctx, order.ID); err != nil {
+			return Order{}, undo.rollback(err)
+		}
+		if err := os.fulfil(ctx, customer, &order); err != nil {
+			return Order{}, undo.rollback(err)
+		}
+	}
+
+	logf(ctx, os.logger(), "Order %d placed", order.ID)
+	os.publish(ctx, OrderPlaced{Order: order})
+	return order, nil
+}
+
+// checkOrder rejects an order that cannot be placed: one that is no
+// longer pending or breaks a validation rule. It has no side effects.
+func (os OrderService) checkOrder(ctx context.Context, order Order) error {
+	if err := stopped(ctx, order.ID); err != nil {
+		return err
+	}
+	if order.Status != StatusPending {
+		return fmt.Errorf("%w: order %d is already %s", ErrInvalidTransition, order.ID, order.Status)
+	}
+	if os.validation != nil {
+		if err := os.validation.Check(ctx, order); err != nil {
+			return fmt.Errorf("validating order %d: %w", order.ID, err)
+		}
+	}
+	if os.outbox == nil {
+		// The confirmation is sent right after the charge; without room
+		// for it the order would be charged only to be refunded.
+		if err := os.email.Accepting(ctx); err != nil {
+			return fmt.Errorf("placing order %d: %w", order.ID, err)
+		}
+	}
+	return nil
+}
+
+// stopped returns the error of ctx, once it is cancelled, as the reason
+// placing the order stops before its next step.
+func stopped(ctx context.Context, orderID int) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("placing order %d: %w", orderID, err)
+	}
+	return nil
 }
 
-func (os OrderService) PlaceOrder(orderId int, amount int) {
-	os.repo.Save(orderId)
-	os.payment.Process(float64(amount))
-	os.email.Send()
-	os.invoice.Generate(orderId)
+// fulfil runs the steps after payment: it sends the confirmation,
+// generates the invoice and marks the order Invoiced. With an outbox
+// the confirmation was already recorded by markPaid.
+func (os OrderService) fulfil(ctx context.Context, customer Customer, order *Order) error {
+	if os.outbox == nil {
+		if err := os.email.SendOrderConfirmation(ctx, customer, *order); err != nil {
+			return fmt.Errorf("sending confirmation for order %d: %w", order.ID, err)
+		}
+		os.record(ctx, AuditEmailSent, order.ID, "order confirmation")
+	}
+	if err := stopped(ctx, order.ID); err != nil {
+		return err
+	}
+	doc, err := os.invoice.Generate(ctx, customer, *order)
+	if err != nil {
+		return fmt.Errorf("generating invoice for order %d: %w", order.ID, err)
+	}
+	os.record(ctx, AuditInvoiceGenerated, order.ID, fmt.Sprintf("%d bytes", len(doc)))
+	if err := os.advance(ctx, order, StatusInvoiced); err != nil {
+		return err
+	}
+	os.publish(ctx, InvoiceGenerated{OrderID: order.ID, Document: doc})
+	return nil
 }
@@ -0,0 +1,393 @@
+package singleresponsibility
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// failingStore is an in-memory store whose Save fails with err.
+type failingStore struct {
+	*InMemoryOrderRepository
+	err error
+}
+
+func (s failingStore) Save(ctx context.Context, order Order) error {
+	return s.err
+}
+
+func TestOrderService_PlaceOrder_FailureBranches(t *testing.T) {
+	errDisk := errors.New("disk full")
+	errSMTP := errors.New("smtp down")
+	errPrinter := errors.New("renderer crashed")
+	invoices := NewInvoiceService(TextInvoiceRenderer{}, NewPricingService(nil), nil)
+	sendOK := emailSenderFunc(func(context.Context, EmailMessage) error { return nil })
+
+	tests := []struct {
+		name    string
+		store   OrderStore
+		payment PaymentGateway
+		mail    EmailSender
+		invoice InvoiceGenerator
+		cause   error
+		prefix  string
+		charged bool // whether the failure came after the charge
+	}{
+		{"repository", failingStore{NewInMemoryOrderRepository(), errDisk}, NewFakeStripeGateway(NewMoney(10000, "USD"), nil), sendOK, invoices,
+			errDisk, "saving order 1: ", false},
+		{"payment", NewInMemoryOrderRepository(), NewFakeStripeGateway(NewMoney(100, "USD"), nil), sendOK, invoices,
+			ErrPaymentDeclined, "charging order 1: ", false},
+		{"email", NewInMemoryOrderRepository(), NewFakeStripeGateway(NewMoney(10000, "USD"), nil), emailSenderFunc(func(context.Context, EmailMessage) error { return errSMTP }), invoices,
+			errSMTP, "sending confirmation for order 1: ", true},
+		{"invoice", NewInMemoryOrderRepository(), NewFakeStripeGateway(NewMoney(10000, "USD"), nil), sendOK, invoiceFunc(func(context.Context, Customer, Order) ([]byte, error) { return nil, errPrinter }),
+			errPrinter, "generating invoice for order 1: ", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders, err := NewOrderService(tt.store, tt.payment, tt.mail, tt.invoice)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = orders.PlaceOrder(context.Background(), "", testOrder(t, 1))
+			if !errors.Is(err, tt.cause) || !strings.HasPrefix(err.Error(), tt.prefix) {
+				t.Fatalf("err = %v, want %q wrapping %v", err, tt.prefix, tt.cause)
+			}
+			if _, err := tt.store.FindByID(context.Background(), 1); !errors.Is(err, ErrOrderNotFound) {
+				t.Errorf("the failed order was left stored: %v", err)
+			}
+			if tt.charged {
+				// A compensated charge cannot be refunded a second time.
+				if err := tt.payment.Refund(context.Background(), "ch_1"); !errors.Is(err, ErrAlreadyRefunded) {
+					t.Errorf("Refund(ch_1) = %v, want the charge already refunded", err)
+				}
+			}
+		})
+	}
+}
+
+// callLog records the calls the fakes below receive, in order. If set,
+// after is called with each call once it is recorded.
+type callLog struct {
+	mu    sync.Mutex
+	calls []string
+	after func(call string)
+}
+
+// add records call. A nil log records nothing, for the benchmarks.
+func (l *callLog) add(call string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	l.calls = append(l.calls, call)
+	l.mu.Unlock()
+	if l.after != nil {
+		l.after(call)
+	}
+}
+
+func (l *callLog) all() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.calls...)
+}
+
+// fakeStore records its writes and fails Save with err.
+type fakeStore struct {
+	*InMemoryOrderRepository
+	log *callLog
+	err error
+}
+
+func (s fakeStore) Save(ctx context.Context, order Order) error {
+	s.log.add("store.Save " + string(order.Status))
+	if s.err != nil {
+		return s.err
+	}
+	return s.InMemoryOrderRepository.Save(ctx, order)
+}
+
+func (s fakeStore) Delete(ctx context.Context, id int) error {
+	s.log.add("store.Delete")
+	return s.InMemoryOrderRepository.Delete(ctx, id)
+}
+
+// fakeGateway records its calls and fails Charge with err.
+type fakeGateway struct {
+	log *callLog
+	err error
+}
+
+func (g fakeGateway) Charge(ctx context.Context, orderID int, amount Money) (string, error) {
+	g.log.add("payment.Charge " + amount.String())
+	if g.err != nil {
+		return "", g.err
+	}
+	return "pay_1", nil
+}
+
+func (g fakeGateway) Refund(ctx context.Context, paymentID string) error {
+	g.log.add("payment.Refund " + paymentID)
+	return nil
+}
+
+// fakeSender records the emails it is asked to send and fails with err.
+type fakeSender struct {
+	log *callLog
+	err error
+}
+
+func (s fakeSender) Send(ctx context.Context, msg EmailMessage) error {
+	s.log.add("email.Send")
+	return s.err
+}
+
+// fakeInvoicer records the invoices it is asked for and fails with err.
+type fakeInvoicer struct {
+	log *callLog
+	err error
+}
+
+func (g fakeInvoicer) Generate(ctx context.Context, customer Customer, order Order) ([]byte, error) {
+	g.log.add("invoice.Generate")
+	if g.err != nil {
+		return nil, g.err
+	}
+	return []byte("invoice"), nil
+}
+
+func TestOrderService_PlaceOrder_CallOrder(t *testing.T) {
+	errFail := errors.New("dependency failed")
+	tests := []struct {
+		name      string
+		failing   string // the dependency that fails, if any
+		wantCalls []string
+	}{
+		{"success", "", []string{
+			"store.Save pending", "payment.Charge 25.00 USD", "store.Save paid",
+			"email.Send", "invoice.Generate", "store.Save invoiced",
+		}},
+		{"store fails", "store", []string{
+			"store.Save pending",
+		}},
+		{"payment fails", "payment", []string{
+			"store.Save pending", "payment.Charge 25.00 USD", "store.Delete",
+		}},
+		{"email fails", "email", []string{
+			"store.Save pending", "payment.Charge 25.00 USD", "store.Save paid",
+			"email.Send", "payment.Refund pay_1", "store.Delete",
+		}},
+		{"invoice fails", "invoice", []string{
+			"store.Save pending", "payment.Charge 25.00 USD", "store.Save paid",
+			"email.Send", "invoice.Generate", "payment.Refund pay_1", "store.Delete",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &callLog{}
+			errIf := func(dep string) error {
+				if dep == tt.failing {
+					return errFail
+				}
+				return nil
+			}
+			orders, err := NewOrderService(
+				fakeStore{NewInMemoryOrderRepository(), log, errIf("store")},
+				fakeGateway{log, errIf("payment")},
+				fakeSender{log, errIf("email")},
+				fakeInvoicer{log, errIf("invoice")},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			placed, err := orders.PlaceOrder(context.Background(), "", testOrder(t, 1))
+			if tt.failing == "" {
+				if err != nil || placed.Status != StatusInvoiced || placed.PaymentID != "pay_1" {
+					t.Fatalf("PlaceOrder = %+v, %v", placed, err)
+				}
+			} else if !errors.Is(err, errFail) {
+				t.Fatalf("err = %v, want %v", err, errFail)
+			}
+			if got := log.all(); !reflect.DeepEqual(got, tt.wantCalls) {
+				t.Errorf("calls:\n got %q\nwant %q", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// Cancelling ctx during a step stops the workflow before the next one
+// and compensates the steps already taken.
+func TestOrderService_PlaceOrder_CancelledMidFlow(t *testing.T) {
+	tests := []struct {
+		during    string // the call that cancels ctx
+		wantCalls []string
+	}{
+		// The store sees the cancellation itself, so nothing was saved.
+		{"store.Save pending", []string{
+			"store.Save pending",
+		}},
+		{"payment.Charge 25.00 USD", []string{
+			"store.Save pending", "payment.Charge 25.00 USD", "payment.Refund pay_1", "store.Delete",
+		}},
+		{"store.Save paid", []string{
+			"store.Save pending", "payment.Charge 25.00 USD", "store.Save paid",
+			"payment.Refund pay_1", "store.Delete",
+		}},
+		{"email.Send", []string{
+			"store.Save pending", "payment.Charge 25.00 USD", "store.Save paid",
+			"email.Send", "payment.Refund pay_1", "store.Delete",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.during, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			log := &callLog{after: func(call string) {
+				if call == tt.during {
+					cancel()
+				}
+			}}
+			orders, err := NewOrderService(fakeStore{NewInMemoryOrderRepository(), log, nil}, fakeGateway{log, nil}, fakeSender{log, nil}, fakeInvoicer{log, nil})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := orders.PlaceOrder(ctx, "", testOrder(t, 1)); !errors.Is(err, context.Canceled) {
+				t.Fatalf("err = %v, want cancelled", err)
+			}
+			if got := log.all(); !reflect.DeepEqual(got, tt.wantCalls) {
+				t.Errorf("calls:\n got %q\nwant %q", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// An order placed through the HTTP API of the default wiring can be
+// refunded over HTTP, and another one cancelled through the commands.
+// Both are invoiced, and every step lands in their audit trails.
+func TestWire_OrderLifecycle(t *testing.T) {
+	ctx := context.Background()
+	log := &CapturingLogger{}
+	cfg := DefaultConfig()
+	cfg.StorageDir = t.TempDir()
+	services, err := Wire(ctx, cfg, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer services.Close()
+	mux := http.NewServeMux()
+	NewOrderHandler(services.Commands, services.Store, services.Commands, NewSequence(0)).Register(mux)
+	post := func(path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return rec
+	}
+	const body = `{"customer_id":1,"currency":"USD","items":[{"sku":"BOOK","quantity":2,"unit_price":"12.50"}]}`
+	var (
+		mu       sync.Mutex
+		invoices = map[int][]byte{}
+	)
+	services.Events.Subscribe(EventInvoiceGenerated, func(_ context.Context, e Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		generated := e.(InvoiceGenerated)
+		invoices[generated.OrderID] = generated.Document
+		return nil
+	})
+
+	for id := 1; id <= 2; id++ {
+		if rec := post("/orders", body); rec.Code != http.StatusCreated {
+			t.Fatalf("placing order %d: %d %s", id, rec.Code, rec.Body)
+		}
+	}
+	rec := post("/orders/1/refund", "")
+	var refunded orderResponse
+	if err := json.NewDecoder(rec.Body).Decode(&refunded); err != nil || rec.Code != http.StatusOK || refunded.Status != string(StatusRefunded) {
+		t.Fatalf("refund: %d %+v %v", rec.Code, refunded, err)
+	}
+	if rec := post("/orders/1/refund", ""); rec.Code != http.StatusConflict {
+		t.Errorf("second refund: %d, want 409", rec.Code)
+	}
+	cancelled, err := services.Commands.CancelOrder(ctx, 2)
+	if err != nil || cancelled.Status != StatusCancelled {
+		t.Fatalf("CancelOrder(2) = %+v, %v", cancelled, err)
+	}
+
+	for id, want := range map[int]OrderStatus{1: StatusRefunded, 2: StatusCancelled} {
+		if order, err := services.Store.FindByID(ctx, id); err != nil || order.Status != want {
+			t.Errorf("stored order %d: %+v, %v; want %s", id, order, err, want)
+		}
+	}
+	summary, err := services.Summaries.Customer(ctx, 1)
+	if err != nil || summary.Orders != 2 {
+		t.Errorf("summary = %+v, %v; want both placed orders", summary, err)
+	}
+	mu.Lock()
+	for id := 1; id <= 2; id++ {
+		doc := string(invoices[id])
+		if !strings.Contains(doc, "BOOK") || !strings.Contains(doc, "25.00") {
+			t.Errorf("invoice of order %d:\n%s\nwant the books and their total", id, doc)
+		}
+	}
+	mu.Unlock()
+	for id, want := range map[int][]AuditAction{
+		1: {AuditOrderSaved, AuditPaymentCharged, AuditOrderPaid, AuditInvoiceGenerated, AuditOrderRefunded},
+		2: {AuditOrderSaved, AuditPaymentCharged, AuditOrderPaid, AuditInvoiceGenerated, AuditOrderCancelled},
+	} {
+		entries, err := services.Audit.History(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []AuditAction
+		for _, e := range entries {
+			got = append(got, e.Action)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("audit trail of order %d = %v, want %v", id, got, want)
+		}
+	}
+
+	// The confirmations wait in the outbox.
+	if sent, err := services.Outbox.DispatchPending(ctx); err != nil || sent != 2 {
+		t.Errorf("DispatchPending = %d, %v; want both confirmations", sent, err)
+	}
+	var confirmations, refundNotices int
+	for _, line := range log.Lines() {
+		switch {
+		case strings.Contains(line, "Subject: Order #") && strings.Contains(line, "confirm"):
+			confirmations++
+		case strings.Contains(line, "Subject: ") && strings.Contains(line, "refund"):
+			refundNotices++
+		}
+	}
+	if confirmations != 2 || refundNotices != 1 {
+		t.Errorf("sent %d confirmations and %d refund notices, want 2 and 1:\n%s", confirmations, refundNotices, strings.Join(log.Lines(), "\n"))
+	}
+}
+
+// Compare with BenchmarkOrderService_PlaceOrder in badsrp: every
+// responsibility here sits behind an interface, so the benchmark can
+// run the workflow with in-memory fakes. They record nothing, so the
+// benchmark does not measure a growing call log.
+func BenchmarkOrderService_PlaceOrder(b *testing.B) {
+	orders, err := NewOrderService(fakeStore{NewInMemoryOrderRepository(), nil, nil}, fakeGateway{nil, nil}, fakeSender{nil, nil}, fakeInvoicer{nil, nil})
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+	id := 0
+	for b.Loop() {
+		id++
+		if _, err := orders.PlaceOrder(ctx, "", testOrder(b, id)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package singleresponsibility
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/metrics"
+)
+
+// OpStats summarises the calls to one operation.
+type OpStats struct {
+	Name   string
+	Calls  int
+	Errors int
+	Total  time.Duration
+	Max    time.Duration
+}
+
+// ErrorRate is the fraction of calls that failed.
+func (s OpStats) ErrorRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Calls)
+}
+
+// Mean is the average call duration.
+func (s OpStats) Mean() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Calls)
+}
+
+// MetricsRegistry collects call counts, durations and errors per
+// operation in memory. It is safe for concurrent use.
+//
+// If provider is not nil, every call is also reported to it as
+// <op>_calls_total, <op>_errors_total and <op>_duration_seconds, with
+// the dots in the operation name replaced by underscores.
+type MetricsRegistry struct {
+	clock    clock.Clock
+	provider metrics.Provider
+
+	mu  sync.Mutex
+	ops map[string]*OpStats
+}
+
+func NewMetricsRegistry(clk clock.Clock, provider metrics.Provider) *MetricsRegistry {
+	return &MetricsRegistry{clock: clock.OrSystem(clk), provider: provider, ops: make(map[string]*OpStats)}
+}
+
+// start times a call to the operation name. The returned function
+// records it with the call's error.
+func (r *MetricsRegistry) start(name string) func(error) {
+	begin := r.clock.Now()
+	return func(err error) {
+		r.Observe(name, r.clock.Now().Sub(begin), err)
+	}
+}
+
+// Observe records one call to the operation name.
+func (r *MetricsRegistry) Observe(name string, d time.Duration, err error) {
+	if r.provider != nil {
+		prefix := strings.ReplaceAll(name, ".", "_")
+		r.provider.Counter(prefix+"_calls_total", "Calls to "+name+".").Add(1)
+		if err != nil {
+			r.provider.Counter(prefix+"_errors_total", "Failed calls to "+name+".").Add(1)
+		}
+		r.provider.Histogram(prefix+"_duration_seconds", "Duration of calls to "+name+".").Observe(d.Seconds())
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	op := r.ops[name]
+	if op == nil {
+		op = &OpStats{Name: name}
+		r.ops[name] = op
+	}
+	op.Calls++
+	if err != nil {
+		op.Errors++
+	}
+	op.Total += d
+	op.Max = max(op.Max, d)
+}
+
+// Snapshot returns the statistics of every operation, sorted by name.
+func (r *MetricsRegistry) Snapshot() []OpStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make([]OpStats, 0, len(r.ops))
+	for _, op := range r.ops {
+		stats = append(stats, *op)
+	}
+	slices.SortFunc(stats, func(a, b OpStats) int { return cmp.Compare(a.Name, b.Name) })
+	return stats
+}
+
+// Export writes the snapshot as one line per operation.
+func (r *MetricsRegistry) Export(w io.Writer) error {
+	for _, s := range r.Snapshot() {
+		_, err := fmt.Fprintf(w, "%s calls=%d errors=%d error_rate=%.2f mean=%s max=%s\n",
+			s.Name, s.Calls, s.Errors, s.ErrorRate(), s.Mean(), s.Max)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CountOrders returns a hook that adds one to placed for every order
+// that is paid, which is the order throughput.
+func CountOrders(placed metrics.Counter) StatusHook {
+	return func(order Order, _ OrderStatus) {
+		if order.Status == StatusPaid {
+			placed.Add(1)
+		}
+	}
+}
+
+// MeteredOrderStore is an OrderStore decorator that records metrics
+// for every call. Like the other Metered types, it adds measurement
+// without touching the services or the wrapped implementation.
+type MeteredOrderStore struct {
+	next    OrderStore
+	metrics *MetricsRegistry
+}
+
+func NewMeteredOrderStore(next OrderStore, metrics *MetricsRegistry) *MeteredOrderStore {
+	return &MeteredOrderStore{next: next, metrics: metrics}
+}
+
+func (s *MeteredOrderStore) Save(ctx context.Context, order Order) error {
+	done := s.metrics.start("store.save")
+	err := s.next.Save(ctx, order)
+	done(err)
+	return err
+}
+
+func (s *MeteredOrderStore) FindByID(ctx context.Context, id int) (Order, error) {
+	done := s.metrics.start("store.find_by_id")
+	order, err := s.next.FindByID(ctx, id)
+	done(err)
+	return order, err
+}
+
+func (s *MeteredOrderStore) List(ctx context.Context, filter OrderFilter) ([]Order, error) {
+	done := s.metrics.start("store.list")
+	orders, err := s.next.List(ctx, filter)
+	done(err)
+	return orders, err
+}
+
+func (s *MeteredOrderStore) Delete(ctx context.Context, id int) error {
+	done := s.metrics.start("store.delete")
+	err := s.next.Delete(ctx, id)
+	done(err)
+	return err
+}
+
+// MeteredUnitOfWork is a UnitOfWork decorator that records metrics
+// for every unit of work, committed or not.
+type MeteredUnitOfWork struct {
+	next    UnitOfWork
+	metrics *MetricsRegistry
+}
+
+func NewMeteredUnitOfWork(next UnitOfWork, metrics *MetricsRegistry) *MeteredUnitOfWork {
+	return &MeteredUnitOfWork{next: next, metrics: metrics}
+}
+
+func (u *MeteredUnitOfWork) Do(ctx context.Context, fn func(tx Tx) error) error {
+	done := u.metrics.start("store.unit_of_work")
+	err := u.next.Do(ctx, fn)
+	done(err)
+	return err
+}
+
+// MeteredPaymentGateway is a PaymentGateway decorator that records
+// metrics for every call.
+type MeteredPaymentGateway struct {
+	next    PaymentGateway
+	metrics *MetricsRegistry
+}
+
+func NewMeteredPaymentGateway(next PaymentGateway, metrics *MetricsRegistry) *MeteredPaymentGateway {
+	return &MeteredPaymentGateway{next: next, metrics: metrics}
+}
+
+func (g *MeteredPaymentGateway) Charge(ctx context.Context, orderID int, amount Money) (string, error) {
+	done := g.metrics.start("payment.charge")
+	id, err := g.next.Charge(ctx, orderID, amount)
+	done(err)
+	return id, err
+}
+
+func (g *MeteredPaymentGateway) Refund(ctx context.Context, paymentID string) error {
+	done := g.metrics.start("payment.refund")
+	err := g.next.Refund(ctx, paymentID)
+	done(err)
+	return err
+}
+
+func (g *MeteredPaymentGateway) Void(ctx context.Context, paymentID string) error {
+	voider, ok := g.next.(PaymentVoider)
+	if !ok {
+		return ErrVoidUnsupported
+	}
+	done := g.metrics.start("payment.void")
+	err := voider.Void(ctx, paymentID)
+	done(err)
+	return err
+}
+
+// MeteredEmailSender is an EmailSender decorator that records metrics
+// for every send.
+type MeteredEmailSender struct {
+	next    EmailSender
+	metrics *MetricsRegistry
+}
+
+func NewMeteredEmailSender(next EmailSender, metrics *MetricsRegistry) *MeteredEmailSender {
+	return &MeteredEmailSender{next: next, metrics: metrics}
+}
+
+func (s *MeteredEmailSender) Send(ctx context.Context, msg EmailMessage) error {
+	done := s.metrics.start("email.send")
+	err := s.next.Send(ctx, msg)
+	done(err)
+	return err
+}
+
+// MeteredInvoiceRenderer is an InvoiceRenderer decorator that records
+// metrics for every render.
+type MeteredInvoiceRenderer struct {
+	next    InvoiceRenderer
+	metrics *MetricsRegistry
+}
+
+func NewMeteredInvoiceRenderer(next InvoiceRenderer, metrics *MetricsRegistry) *MeteredInvoiceRenderer {
+	return &MeteredInvoiceRenderer{next: next, metrics: metrics}
+}
+
+func (r *MeteredInvoiceRenderer) Render(w io.Writer, inv Invoice) error {
+	done := r.metrics.start("invoice.render")
+	err := r.next.Render(w, inv)
+	done(err)
+	return err
+}
@@ -0,0 +1,74 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWire_PrometheusMetrics(t *testing.T) {
+	ctx := context.Background()
+	cfg := DefaultConfig()
+	cfg.Metrics = "prometheus"
+	cfg.StripeLimit = "100.00"
+	services, err := Wire(ctx, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer services.Close()
+
+	place := func(id int, price string) error {
+		unit, err := ParseMoney(price, cfg.Currency)
+		if err != nil {
+			t.Fatal(err)
+		}
+		order, err := NewOrder(id, 1, []OrderItem{{SKU: "BOOK", Quantity: 1, UnitPrice: unit}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = services.Orders.PlaceOrder(ctx, "", order)
+		return err
+	}
+	if err := place(1, "12.50"); err != nil {
+		t.Fatal(err)
+	}
+	if err := place(2, "500.00"); !errors.Is(err, ErrPaymentDeclined) {
+		t.Fatalf("err = %v, want ErrPaymentDeclined", err)
+	}
+	if _, err := services.Outbox.DispatchPending(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	services.MetricsHandler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	for _, want := range []string{
+		"orders_paid_total 1",
+		"payment_charge_calls_total 2",
+		"payment_charge_errors_total 1",
+		"email_send_duration_seconds_count 1",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics lack %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestWire_TextMetrics(t *testing.T) {
+	services, err := Wire(context.Background(), DefaultConfig(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer services.Close()
+	services.Metrics.Observe("payment.charge", 0, nil)
+
+	rec := httptest.NewRecorder()
+	services.MetricsHandler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if got := rec.Body.String(); !strings.HasPrefix(got, "payment.charge calls=1 errors=0") {
+		t.Errorf("metrics = %q", got)
+	}
+}
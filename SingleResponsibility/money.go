@@ -0,0 +1,34 @@
+package singleresponsibility
+
+import "github.com/anil-vinnakoti/go-SOLID/pkg/money"
+
+// Money and Currency come from pkg/money, which owns the currency
+// registry, parsing, formatting, allocation and conversion. The
+// aliases keep the order code reading as it always has.
+type (
+	Money    = money.Money
+	Currency = money.Currency
+)
+
+var (
+	ErrCurrencyMismatch = money.ErrCurrencyMismatch
+	ErrUnknownCurrency  = money.ErrUnknownCurrency
+	ErrInvalidMoney     = money.ErrInvalidMoney
+	ErrOverflow         = money.ErrOverflow
+)
+
+// NewMoney returns minor units of currency: NewMoney(1250, "EUR") is
+// 12.50 EUR.
+func NewMoney(minor int64, currency Currency) Money {
+	return money.New(minor, currency)
+}
+
+// ParseMoney parses a decimal string such as "12.50" in currency.
+func ParseMoney(s string, currency Currency) (Money, error) {
+	return money.Parse(s, currency)
+}
+
+// SumMoney adds up amounts that must all be in currency.
+func SumMoney(currency Currency, amounts ...Money) (Money, error) {
+	return money.Sum(currency, amounts...)
+}
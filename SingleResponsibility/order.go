@@ -0,0 +1,86 @@
+package singleresponsibility
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidOrderID    = errors.New("invalid order id")
+	ErrInvalidCustomerID = errors.New("invalid customer id")
+	ErrNoItems           = errors.New("order has no items")
+	ErrInvalidItem       = errors.New("invalid order item")
+)
+
+// OrderItem is a single line of an order.
+type OrderItem struct {
+	SKU       string
+	Quantity  int
+	UnitPrice Money
+}
+
+// Order is the domain model every service in the workflow operates on.
+type Order struct {
+	ID         int
+	CustomerID int
+	Items      []OrderItem
+	Total      Money // what the customer pays, once PricingService has priced the order
+	CreatedAt  time.Time
+	Status     OrderStatus
+	PaymentID  string // set once the order is paid
+	// SyncPayment is set on the orders PlaceOrder charges itself. The
+	// gateway's payment callbacks are only for the others.
+	SyncPayment bool
+
+	CouponCode   string
+	Discount     Money
+	FreeShipping bool
+
+	Carrier        string
+	TrackingNumber string // set once a shipment is booked
+
+	DeletedAt time.Time // set when an ArchivingRepository soft-deletes the order
+
+	// Version counts the saves of the order; 0 means it was never
+	// saved. See OrderStore.Save.
+	Version int
+}
+
+// NewOrder validates its inputs and returns a pending order whose
+// Total is computed from the items. All items must be priced in the
+// same currency.
+func NewOrder(id, customerID int, items []OrderItem) (Order, error) {
+	if id <= 0 {
+		return Order{}, fmt.Errorf("%w: %d", ErrInvalidOrderID, id)
+	}
+	if customerID <= 0 {
+		return Order{}, fmt.Errorf("%w: %d", ErrInvalidCustomerID, customerID)
+	}
+	if len(items) == 0 {
+		return Order{}, ErrNoItems
+	}
+
+	total := NewMoney(0, items[0].UnitPrice.Currency)
+	for i, item := range items {
+		if item.SKU == "" || item.Quantity <= 0 || item.UnitPrice.IsNegative() {
+			return Order{}, fmt.Errorf("%w: item %d (%+v)", ErrInvalidItem, i, item)
+		}
+		amount, err := item.UnitPrice.Mul(item.Quantity)
+		if err == nil {
+			total, err = total.Add(amount)
+		}
+		if err != nil {
+			return Order{}, fmt.Errorf("%w: item %d: %w", ErrInvalidItem, i, err)
+		}
+	}
+
+	return Order{
+		ID:         id,
+		CustomerID: customerID,
+		Items:      append([]OrderItem(nil), items...),
+		Total:      total,
+		CreatedAt:  time.Now(),
+		Status:     StatusPending,
+	}, nil
+}
@@ -0,0 +1,40 @@
+package singleresponsibility
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNewOrder(t *testing.T) {
+	book := OrderItem{SKU: "BOOK", Quantity: 2, UnitPrice: NewMoney(1250, "USD")}
+	tests := []struct {
+		name    string
+		id      int
+		items   []OrderItem
+		total   Money
+		wantErr error
+	}{
+		{"valid", 1, []OrderItem{book, {SKU: "PEN", Quantity: 1, UnitPrice: NewMoney(199, "USD")}}, NewMoney(2699, "USD"), nil},
+		{"bad id", 0, []OrderItem{book}, Money{}, ErrInvalidOrderID},
+		{"no items", 1, nil, Money{}, ErrNoItems},
+		{"zero quantity", 1, []OrderItem{{SKU: "BOOK", UnitPrice: NewMoney(1250, "USD")}}, Money{}, ErrInvalidItem},
+		{"mixed currencies", 1, []OrderItem{book, {SKU: "PEN", Quantity: 1, UnitPrice: NewMoney(199, "EUR")}}, Money{}, ErrCurrencyMismatch},
+		{"line overflows", 1, []OrderItem{{SKU: "GOLD", Quantity: 3, UnitPrice: NewMoney(math.MaxInt64/2, "USD")}}, Money{}, ErrOverflow},
+		{"total overflows", 1, []OrderItem{
+			{SKU: "GOLD", Quantity: 1, UnitPrice: NewMoney(math.MaxInt64, "USD")},
+			{SKU: "PEN", Quantity: 1, UnitPrice: NewMoney(1, "USD")},
+		}, Money{}, ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := NewOrder(tt.id, 1, tt.items)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (order.Total != tt.total || order.Status != StatusPending) {
+				t.Errorf("order = %+v, want a pending order of %v", order, tt.total)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package singleresponsibility
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The protobuf wire format of the messages in proto/orders.proto,
+// written with protowire so the API needs no generated code. Field
+// numbers must follow the .proto file.
+
+// MarshalProto encodes the orders.v1.Order message.
+func (r orderResponse) MarshalProto() ([]byte, error) {
+	return appendOrderProto(nil, r.order), nil
+}
+
+// MarshalProto encodes the orders.v1.ListOrdersResponse message.
+func (r orderListResponse) MarshalProto() ([]byte, error) {
+	var b []byte
+	for _, order := range r.orders {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, appendOrderProto(nil, order))
+	}
+	b = appendString(b, 2, r.Sort)
+	b = appendInt(b, 3, int64(r.Limit))
+	b = appendInt(b, 4, int64(r.Offset))
+	return appendString(b, 5, r.NextCursor), nil
+}
+
+func appendOrderProto(b []byte, o Order) []byte {
+	b = appendInt(b, 1, int64(o.ID))
+	b = appendInt(b, 2, int64(o.CustomerID))
+	for _, item := range o.Items {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendBytes(b, appendOrderItemProto(nil, item))
+	}
+	b = appendMoney(b, 4, o.Total)
+	if !o.Discount.IsZero() {
+		b = appendMoney(b, 5, o.Discount)
+	}
+	b = appendString(b, 6, o.CouponCode)
+	b = appendString(b, 7, string(o.Status))
+	b = appendString(b, 8, o.PaymentID)
+	b = appendString(b, 9, o.Carrier)
+	b = appendString(b, 10, o.TrackingNumber)
+	if !o.CreatedAt.IsZero() {
+		b = appendInt(b, 11, o.CreatedAt.Unix())
+	}
+	return b
+}
+
+func appendOrderItemProto(b []byte, item OrderItem) []byte {
+	b = appendString(b, 1, item.SKU)
+	b = appendInt(b, 2, int64(item.Quantity))
+	return appendMoney(b, 3, item.UnitPrice)
+}
+
+// parseOrderProto decodes the orders.v1.Order message, as a client
+// would.
+func parseOrderProto(b []byte) (Order, error) {
+	var o Order
+	err := parseFields(b, func(num protowire.Number, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			o.ID = int(v)
+		case 2:
+			o.CustomerID = int(v)
+		case 3:
+			item, err := parseOrderItemProto(data)
+			o.Items = append(o.Items, item)
+			return err
+		case 4, 5:
+			m, err := parseMoneyProto(data)
+			if num == 4 {
+				o.Total = m
+			} else {
+				o.Discount = m
+			}
+			return err
+		case 6:
+			o.CouponCode = string(data)
+		case 7:
+			o.Status = OrderStatus(data)
+		case 8:
+			o.PaymentID = string(data)
+		case 9:
+			o.Carrier = string(data)
+		case 10:
+			o.TrackingNumber = string(data)
+		case 11:
+			o.CreatedAt = time.Unix(int64(v), 0).UTC()
+		}
+		return nil
+	})
+	return o, err
+}
+
+func parseOrderItemProto(b []byte) (OrderItem, error) {
+	var item OrderItem
+	err := parseFields(b, func(num protowire.Number, v uint64, data []byte) (err error) {
+		switch num {
+		case 1:
+			item.SKU = string(data)
+		case 2:
+			item.Quantity = int(v)
+		case 3:
+			item.UnitPrice, err = parseMoneyProto(data)
+		}
+		return err
+	})
+	return item, err
+}
+
+func appendMoney(b []byte, num protowire.Number, m Money) []byte {
+	var mb []byte
+	mb = appendInt(mb, 1, m.Amount)
+	mb = appendString(mb, 2, string(m.Currency))
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, mb)
+}
+
+func parseMoneyProto(b []byte) (Money, error) {
+	var m Money
+	err := parseFields(b, func(num protowire.Number, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			m.Amount = int64(v)
+		case 2:
+			m.Currency = Currency(data)
+		}
+		return nil
+	})
+	return m, err
+}
+
+// appendInt and appendString leave out zero values, as proto3 does.
+func appendInt(b []byte, num protowire.Number, v int64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(v))
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+// parseFields calls field with each varint or length-delimited field of
+// the message b, skipping the other wire types.
+func parseFields(b []byte, field func(num protowire.Number, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("protobuf: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+		var (
+			v    uint64
+			data []byte
+		)
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			data, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return fmt.Errorf("protobuf: field %d: %w", num, protowire.ParseError(n))
+		}
+		b = b[n:]
+		if typ == protowire.VarintType || typ == protowire.BytesType {
+			if err := field(num, v, data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
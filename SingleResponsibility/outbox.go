@@ -0,0 +1,276 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/sched"
+)
+
+var ErrOutboxMessageNotFound = errors.New("outbox message not found")
+
+// OutboxOrderConfirmation is the kind of message asking for an order
+// confirmation email.
+const OutboxOrderConfirmation = "order_confirmation"
+
+// OutboxMessage is the recorded intent to perform a side effect for an
+// order once the order itself is safely stored.
+type OutboxMessage struct {
+	ID        int64
+	OrderID   int
+	Kind      string
+	CreatedAt time.Time
+	Attempts  int
+	LastError string
+}
+
+// Outbox stores orders together with the messages their changes
+// produce. Because both are written in one transaction, a crash can
+// never leave a paid order without its pending confirmation, or a
+// confirmation for an order that was not saved.
+type Outbox interface {
+	// SaveWithMessage saves order and enqueues msg as one unit.
+	SaveWithMessage(ctx context.Context, order Order, msg OutboxMessage) error
+	// Pending returns up to limit unsent messages, oldest first.
+	Pending(ctx context.Context, limit int) ([]OutboxMessage, error)
+	MarkSent(ctx context.Context, id int64) error
+	// MarkFailed records a failed attempt; the message stays pending.
+	MarkFailed(ctx context.Context, id int64, cause error) error
+}
+
+// InMemoryOutbox adds an outbox to an InMemoryOrderRepository. Saves
+// and enqueues happen under one lock, the in-memory counterpart of a
+// database transaction. It is safe for concurrent use.
+type InMemoryOutbox struct {
+	orders *InMemoryOrderRepository
+
+	mu       sync.Mutex
+	seq      int64
+	messages []OutboxMessage
+	sent     map[int64]bool
+}
+
+// NewInMemoryOutbox returns an outbox that stores orders in orders,
+// which should be the OrderStore of the OrderService using it.
+func NewInMemoryOutbox(orders *InMemoryOrderRepository) *InMemoryOutbox {
+	return &InMemoryOutbox{orders: orders, sent: make(map[int64]bool)}
+}
+
+func (o *InMemoryOutbox) SaveWithMessage(ctx context.Context, order Order, msg OutboxMessage) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err := o.orders.Save(ctx, order); err != nil {
+		return err
+	}
+	o.seq++
+	msg.ID = o.seq
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now()
+	}
+	o.messages = append(o.messages, msg)
+	return nil
+}
+
+func (o *InMemoryOutbox) Pending(ctx context.Context, limit int) ([]OutboxMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var pending []OutboxMessage
+	for _, msg := range o.messages {
+		if len(pending) == limit {
+			break
+		}
+		if !o.sent[msg.ID] {
+			pending = append(pending, msg)
+		}
+	}
+	return pending, nil
+}
+
+func (o *InMemoryOutbox) MarkSent(ctx context.Context, id int64) error {
+	return o.update(ctx, id, func(*OutboxMessage) { o.sent[id] = true })
+}
+
+func (o *InMemoryOutbox) MarkFailed(ctx context.Context, id int64, cause error) error {
+	return o.update(ctx, id, func(msg *OutboxMessage) {
+		msg.Attempts++
+		msg.LastError = cause.Error()
+	})
+}
+
+func (o *InMemoryOutbox) update(ctx context.Context, id int64, fn func(*OutboxMessage)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i := range o.messages {
+		if o.messages[i].ID == id {
+			fn(&o.messages[i])
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %d", ErrOutboxMessageNotFound, id)
+}
+
+// OutboxDispatcher drains the outbox in the background, sending each
+// confirmation through EmailService. It is the only place that turns
+// recorded intents into emails.
+//
+// Delivery is at least once: a crash between sending and MarkSent
+// sends the email again on restart. Each email therefore carries the
+// message ID as its dedup key, so the sender drops the repeat.
+type OutboxDispatcher struct {
+	outbox    Outbox
+	orders    OrderStore
+	customers CustomerRepository
+	email     *EmailService
+	log       Logger
+}
+
+// NewOutboxDispatcher returns a dispatcher. customers may be nil, in
+// which case the placeholder customer is emailed.
+func NewOutboxDispatcher(outbox Outbox, orders OrderStore, customers CustomerRepository, mail EmailSender, log Logger) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		outbox:    outbox,
+		orders:    orders,
+		customers: customers,
+		email:     NewEmailService(mail),
+		log:       orNop(log),
+	}
+}
+
+// outboxBatch caps how many messages one DispatchPending handles.
+const outboxBatch = 100
+
+// DispatchPending sends the pending messages and returns how many were
+// sent. Failed messages stay pending for the next round; their errors
+// are joined into the returned error.
+func (d *OutboxDispatcher) DispatchPending(ctx context.Context) (int, error) {
+	pending, err := d.outbox.Pending(ctx, outboxBatch)
+	if err != nil {
+		return 0, fmt.Errorf("reading outbox: %w", err)
+	}
+
+	sent := 0
+	var errs []error
+	for _, msg := range pending {
+		if err := d.dispatch(ctx, msg); err != nil {
+			errs = append(errs, fmt.Errorf("outbox message %d: %w", msg.ID, err))
+			if merr := d.outbox.MarkFailed(ctx, msg.ID, err); merr != nil {
+				errs = append(errs, merr)
+			}
+			continue
+		}
+		if err := d.outbox.MarkSent(ctx, msg.ID); err != nil {
+			errs = append(errs, fmt.Errorf("outbox message %d: %w", msg.ID, err))
+			continue
+		}
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
+
+func (d *OutboxDispatcher) dispatch(ctx context.Context, msg OutboxMessage) error {
+	if msg.Kind != OutboxOrderConfirmation {
+		return fmt.Errorf("unknown message kind %q", msg.Kind)
+	}
+
+	order, err := d.orders.FindByID(ctx, msg.OrderID)
+	if errors.Is(err, ErrOrderNotFound) {
+		// The order was rolled back after it was paid; there is
+		// nothing to confirm.
+		logf(ctx, d.log, "Outbox: dropping confirmation of missing order %d", msg.OrderID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	customer, err := resolveCustomer(ctx, d.customers, order)
+	if err != nil {
+		return err
+	}
+	return d.email.SendOrderConfirmationOnce(ctx, fmt.Sprintf("outbox-%d", msg.ID), customer, order)
+}
+
+// Job returns a sched.Job that dispatches the pending messages.
+func (d *OutboxDispatcher) Job() sched.Job {
+	return sched.JobFunc(func(ctx context.Context) error {
+		_, err := d.DispatchPending(ctx)
+		return err
+	})
+}
+
+// Run calls DispatchPending at once and then every interval, timed by
+// clk, until ctx is done. Failures are logged and retried on the next
+// run. clk may be nil, for the system clock.
+func (d *OutboxDispatcher) Run(ctx context.Context, interval time.Duration, clk clock.Clock) error {
+	s := sched.New(clk, func(_ string, err error) { logf(ctx, d.log, "Outbox: %v", err) })
+	if err := s.Add(sched.Entry{Name: "outbox", Schedule: sched.Every(interval), Job: d.Job()}); err != nil {
+		return err
+	}
+	if _, err := d.DispatchPending(ctx); err != nil && ctx.Err() == nil {
+		logf(ctx, d.log, "Outbox: %v", err)
+	}
+	return s.Run(ctx)
+}
+
+// WithOutbox has the service record the confirmation email in outbox,
+// in the same save that marks the order Paid, instead of sending it
+// inline. An OutboxDispatcher sends it. outbox must store orders
+// where the service's OrderStore does.
+func WithOutbox(outbox Outbox) OrderOption {
+	return func(os *OrderService) { os.outbox = outbox }
+}
+
+// markPaid moves order to Paid and records it in the audit trail.
+// With a unit of work, the order, its audit entry and, with an outbox,
+// the confirmation intent are written together. With only an outbox,
+// the order and the confirmation intent are.
+func (os OrderService) markPaid(ctx context.Context, order *Order) error {
+	save := os.repo.Save
+	switch {
+	case os.uow != nil:
+		save = func(ctx context.Context, o Order) error {
+			return os.uow.Do(ctx, func(tx Tx) error { return os.writePaid(ctx, tx, o) })
+		}
+	case os.outbox != nil:
+		save = func(ctx context.Context, o Order) error {
+			return os.outbox.SaveWithMessage(ctx, o, OutboxMessage{OrderID: o.ID, Kind: OutboxOrderConfirmation})
+		}
+	}
+	if err := os.advanceAndSave(ctx, order, StatusPaid, save); err != nil {
+		return err
+	}
+	if os.uow == nil {
+		os.record(ctx, AuditOrderPaid, order.ID, order.PaymentID)
+	}
+	return nil
+}
+
+// writePaid makes the writes of markPaid through one unit of work.
+func (os OrderService) writePaid(ctx context.Context, tx Tx, order Order) error {
+	if err := tx.SaveOrder(ctx, order); err != nil {
+		return err
+	}
+	if os.audit != nil {
+		if err := tx.AppendAudit(ctx, os.audit.Entry(ctx, AuditOrderPaid, order.ID, order.PaymentID)); err != nil {
+			return err
+		}
+	}
+	if os.outbox != nil {
+		return tx.Enqueue(ctx, OutboxMessage{OrderID: order.ID, Kind: OutboxOrderConfirmation})
+	}
+	return nil
+}
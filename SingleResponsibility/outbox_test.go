@@ -0,0 +1,117 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// A dispatcher stopped between sending a confirmation and marking it
+// sent sends it again after a restart, and the dedup key makes the
+// customer get it once.
+func TestOutboxDispatcher_RedeliveryAfterCrash(t *testing.T) {
+	repo := NewInMemoryOrderRepository()
+	outbox := NewInMemoryOutbox(repo)
+	if err := outbox.SaveWithMessage(context.Background(), testOrder(t, 1), OutboxMessage{OrderID: 1, Kind: OutboxOrderConfirmation}); err != nil {
+		t.Fatal(err)
+	}
+	provider := NewLoggingEmailSender(nil)
+
+	// The first dispatcher dies right after the provider accepted the
+	// email.
+	ctx, kill := context.WithCancel(context.Background())
+	crashing := emailSenderFunc(func(ctx context.Context, msg EmailMessage) error {
+		err := provider.Send(ctx, msg)
+		kill()
+		return err
+	})
+	sent, err := NewOutboxDispatcher(outbox, repo, nil, crashing, nil).DispatchPending(ctx)
+	if sent != 0 || !errors.Is(err, context.Canceled) {
+		t.Fatalf("DispatchPending = %d, %v; want it killed before MarkSent", sent, err)
+	}
+	if pending, _ := outbox.Pending(context.Background(), 10); len(pending) != 1 {
+		t.Fatalf("%d messages pending after the crash, want 1", len(pending))
+	}
+
+	restarted := NewOutboxDispatcher(outbox, repo, nil, provider, nil)
+	if sent, err := restarted.DispatchPending(context.Background()); sent != 1 || err != nil {
+		t.Fatalf("after restart DispatchPending = %d, %v; want 1", sent, err)
+	}
+	if pending, _ := outbox.Pending(context.Background(), 10); len(pending) != 0 {
+		t.Errorf("%d messages still pending, want none", len(pending))
+	}
+	if delivered := provider.Sent(); len(delivered) != 1 {
+		t.Errorf("the customer got %d confirmations, want 1", len(delivered))
+	}
+
+	// Nothing is left for a later round.
+	if sent, err := restarted.DispatchPending(context.Background()); sent != 0 || err != nil {
+		t.Errorf("second round = %d, %v; want nothing to send", sent, err)
+	}
+}
+
+// Wire records the confirmations in the outbox, and Services.Close
+// sends those still pending.
+func TestWire_CloseSendsPendingConfirmations(t *testing.T) {
+	ctx := context.Background()
+	log := &CapturingLogger{}
+	services, err := Wire(ctx, DefaultConfig(), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := services.Orders.PlaceOrder(ctx, "", testOrder(t, 1)); err != nil {
+		t.Fatal(err)
+	}
+	confirmed := func() bool {
+		for _, line := range log.Lines() {
+			if strings.Contains(line, "Subject: Order #1 confirm") {
+				return true
+			}
+		}
+		return false
+	}
+	if confirmed() {
+		t.Fatal("the confirmation was sent inline")
+	}
+
+	if err := services.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !confirmed() {
+		t.Errorf("Close did not send the pending confirmation; log:\n%s", strings.Join(log.Lines(), "\n"))
+	}
+}
+
+// The outbox is dispatched as one of the scheduled jobs.
+func TestWire_OutboxJob(t *testing.T) {
+	ctx := context.Background()
+	cfg := DefaultConfig()
+	cfg.OutboxInterval = 0
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Validate without outbox_interval = %v, want ErrInvalidConfig", err)
+	}
+
+	log := &CapturingLogger{}
+	services, err := Wire(ctx, DefaultConfig(), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer services.Close()
+	if _, err := services.Orders.PlaceOrder(ctx, "", testOrder(t, 1)); err != nil {
+		t.Fatal(err)
+	}
+	for _, job := range services.Jobs {
+		if job.Name != "outbox" {
+			continue
+		}
+		if err := job.Job.Run(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if sent, _ := services.Outbox.DispatchPending(ctx); sent != 0 {
+			t.Errorf("%d confirmations were still pending after the job ran", sent)
+		}
+		return
+	}
+	t.Errorf("no outbox job in %+v", services.Jobs)
+}
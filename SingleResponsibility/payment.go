@@ -0,0 +1,182 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/money"
+)
+
+var (
+	ErrInvalidAmount      = errors.New("invalid amount")
+	ErrPaymentDeclined    = errors.New("payment declined")
+	ErrGatewayUnavailable = errors.New("payment gateway unavailable")
+	ErrUnknownPayment     = errors.New("unknown payment")
+	ErrAlreadyRefunded    = errors.New("payment already refunded")
+)
+
+// FakeStripeGateway declines every charge above Limit. A zero Limit
+// accepts any positive amount; otherwise charges must be in Limit's
+// currency, or one WithRates can convert to it.
+type FakeStripeGateway struct {
+	Limit     Money
+	ledger    *fakeLedger
+	converter *money.Converter
+	log       Logger
+}
+
+func NewFakeStripeGateway(limit Money, log Logger) *FakeStripeGateway {
+	return &FakeStripeGateway{Limit: limit, ledger: newFakeLedger("ch"), log: orNop(log)}
+}
+
+// WithRates makes the gateway check charges in other currencies
+// against Limit at the given exchange rates.
+func (g *FakeStripeGateway) WithRates(rates money.RateProvider) *FakeStripeGateway {
+	g.converter = money.NewConverter(rates)
+	return g
+}
+
+func (g *FakeStripeGateway) Charge(ctx context.Context, orderID int, amount Money) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if !amount.IsPositive() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidAmount, amount)
+	}
+	if !g.Limit.IsZero() {
+		inLimit := amount
+		if g.converter != nil {
+			var err error
+			if inLimit, err = g.converter.Convert(ctx, amount, g.Limit.Currency); err != nil {
+				return "", fmt.Errorf("stripe: %w: %w", ErrPaymentDeclined, err)
+			}
+		}
+		cmp, err := inLimit.Cmp(g.Limit)
+		if err != nil {
+			return "", fmt.Errorf("stripe: %w: %w", ErrPaymentDeclined, err)
+		}
+		if cmp > 0 {
+			return "", fmt.Errorf("stripe: %w: %s exceeds limit %s", ErrPaymentDeclined, amount, g.Limit)
+		}
+	}
+
+	id := g.ledger.charge(amount)
+	logf(ctx, g.log, "Stripe charged %s for order %d (%s)", amount, orderID, id)
+	return id, nil
+}
+
+func (g *FakeStripeGateway) Refund(ctx context.Context, paymentID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := g.ledger.refund(paymentID); err != nil {
+		return fmt.Errorf("stripe: %w", err)
+	}
+	logf(ctx, g.log, "Stripe refunded %s", paymentID)
+	return nil
+}
+
+// Void cancels a charge before it settles. The fake ledger books it
+// like a refund, so the charge cannot be refunded afterwards.
+func (g *FakeStripeGateway) Void(ctx context.Context, paymentID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := g.ledger.refund(paymentID); err != nil {
+		return fmt.Errorf("stripe: %w", err)
+	}
+	logf(ctx, g.log, "Stripe voided %s", paymentID)
+	return nil
+}
+
+// FakePayPalGateway is unavailable for every FailEvery-th charge,
+// which makes transient provider outages reproducible.
+// A zero FailEvery never fails.
+type FakePayPalGateway struct {
+	FailEvery int
+	ledger    *fakeLedger
+	log       Logger
+
+	mu    sync.Mutex
+	calls int
+}
+
+func NewFakePayPalGateway(failEvery int, log Logger) *FakePayPalGateway {
+	return &FakePayPalGateway{FailEvery: failEvery, ledger: newFakeLedger("PAY"), log: orNop(log)}
+}
+
+func (g *FakePayPalGateway) Charge(ctx context.Context, orderID int, amount Money) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if !amount.IsPositive() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidAmount, amount)
+	}
+
+	g.mu.Lock()
+	g.calls++
+	fail := g.FailEvery > 0 && g.calls%g.FailEvery == 0
+	g.mu.Unlock()
+	if fail {
+		return "", fmt.Errorf("paypal: %w", ErrGatewayUnavailable)
+	}
+
+	id := g.ledger.charge(amount)
+	logf(ctx, g.log, "PayPal charged %s for order %d (%s)", amount, orderID, id)
+	return id, nil
+}
+
+func (g *FakePayPalGateway) Refund(ctx context.Context, paymentID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := g.ledger.refund(paymentID); err != nil {
+		return fmt.Errorf("paypal: %w", err)
+	}
+	logf(ctx, g.log, "PayPal refunded %s", paymentID)
+	return nil
+}
+
+// fakeLedger is the bookkeeping shared by the fake gateways.
+type fakeLedger struct {
+	prefix string
+
+	mu       sync.Mutex
+	seq      int
+	charges  map[string]Money
+	refunded map[string]bool
+}
+
+func newFakeLedger(prefix string) *fakeLedger {
+	return &fakeLedger{
+		prefix:   prefix,
+		charges:  make(map[string]Money),
+		refunded: make(map[string]bool),
+	}
+}
+
+func (l *fakeLedger) charge(amount Money) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.seq++
+	id := fmt.Sprintf("%s_%d", l.prefix, l.seq)
+	l.charges[id] = amount
+	return id
+}
+
+func (l *fakeLedger) refund(paymentID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.charges[paymentID]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownPayment, paymentID)
+	}
+	if l.refunded[paymentID] {
+		return fmt.Errorf("%w: %s", ErrAlreadyRefunded, paymentID)
+	}
+	l.refunded[paymentID] = true
+	return nil
+}
@@ -0,0 +1,29 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/money"
+)
+
+func TestFakeStripeGateway_LimitInOtherCurrency(t *testing.T) {
+	ctx := context.Background()
+	limit := NewMoney(10000, "USD")
+
+	if _, err := NewFakeStripeGateway(limit, nil).Charge(ctx, 1, NewMoney(500, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("EUR charge without rates = %v, want ErrCurrencyMismatch", err)
+	}
+
+	gateway := NewFakeStripeGateway(limit, nil).WithRates(money.StaticRates{{"EUR", "USD"}: 1.1})
+	if _, err := gateway.Charge(ctx, 1, NewMoney(9000, "EUR")); err != nil {
+		t.Errorf("90 EUR (99 USD) = %v, want it charged", err)
+	}
+	if _, err := gateway.Charge(ctx, 2, NewMoney(9100, "EUR")); !errors.Is(err, ErrPaymentDeclined) {
+		t.Errorf("91 EUR (100.10 USD) = %v, want ErrPaymentDeclined", err)
+	}
+	if _, err := gateway.Charge(ctx, 3, NewMoney(100, "GBP")); !errors.Is(err, money.ErrNoRate) {
+		t.Errorf("GBP charge = %v, want ErrNoRate", err)
+	}
+}
@@ -0,0 +1,105 @@
+package singleresponsibility
+
+import (
+	"context"
+	"reflect"
+	"slices"
+)
+
+// PlacementStage is where in the order workflow a PlacementStep runs.
+type PlacementStage int
+
+const (
+	// BeforePricing steps see the order as it was submitted.
+	BeforePricing PlacementStage = iota
+	// BeforeSave steps see the priced order before it is stored.
+	BeforeSave
+	// BeforeCharge steps run once the order is stored.
+	BeforeCharge
+	// BeforePaid steps run once the order is charged.
+	BeforePaid
+)
+
+// PlacementStep is an optional step of placing an order, such as
+// redeeming its coupon or booking its delivery. OrderService only
+// runs the steps at their stage and undoes the ones that ran when a
+// later step fails; what a step does stays in the step.
+type PlacementStep interface {
+	// Name names the step in compensation errors and logs.
+	Name() string
+	Stage() PlacementStage
+	// Run performs the step for order, placed for customer.
+	Run(ctx context.Context, customer Customer, order Order) (StepResult, error)
+}
+
+// StepResult is what a PlacementStep did.
+type StepResult struct {
+	// Order is the order as the step left it.
+	Order Order
+	// Audit, with Detail, is recorded in the order's audit trail;
+	// empty records nothing.
+	Audit  AuditAction
+	Detail string
+	// Undo reverses the step when a later step fails; nil if there is
+	// nothing to reverse.
+	Undo func(ctx context.Context) error
+}
+
+// CancellingStep is a PlacementStep whose work is also reversed when a
+// placed order is cancelled.
+type CancellingStep interface {
+	PlacementStep
+	Cancel(ctx context.Context, order Order) error
+}
+
+// WithStep has the service run step when placing an order, after the
+// steps of its stage from earlier options. A step of the same type is
+// replaced instead.
+func WithStep(step PlacementStep) OrderOption {
+	return func(os *OrderService) {
+		i := slices.IndexFunc(os.steps, func(s PlacementStep) bool {
+			return reflect.TypeOf(s) == reflect.TypeOf(step)
+		})
+		if i < 0 {
+			os.steps = append(os.steps, step)
+			return
+		}
+		os.steps[i] = step
+	}
+}
+
+// runSteps runs the steps of stage on order and adds their undos to
+// undo, to be run in undoCtx. On failure it returns the order as the
+// last step that succeeded left it.
+func (os OrderService) runSteps(ctx, undoCtx context.Context, stage PlacementStage, customer Customer, order Order, undo *compensations) (Order, error) {
+	for _, step := range os.steps {
+		if step.Stage() != stage {
+			continue
+		}
+		result, err := step.Run(ctx, customer, order)
+		if err != nil {
+			return order, err
+		}
+		order = result.Order
+		if result.Undo != nil {
+			reverse := result.Undo
+			undo.add(step.Name(), func() error { return reverse(undoCtx) })
+		}
+		if result.Audit != "" {
+			os.record(ctx, result.Audit, order.ID, result.Detail)
+		}
+	}
+	return order, nil
+}
+
+// cancelSteps reverses the steps of a cancelled order. The order is
+// cancelled either way, so failures are only logged.
+func (os OrderService) cancelSteps(ctx context.Context, order Order) {
+	for _, step := range os.steps {
+		if c, ok := step.(CancellingStep); ok {
+			if err := c.Cancel(ctx, order); err != nil {
+				logf(ctx, os.logger(), "Order %d cancelled but its %s was not: %v", order.ID, step.Name(), err)
+			}
+		}
+	}
+}
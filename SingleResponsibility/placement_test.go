@@ -0,0 +1,79 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// recordingStep logs when it runs and is undone.
+type recordingStep struct {
+	name  string
+	stage PlacementStage
+	log   *callLog
+}
+
+func (s recordingStep) Name() string          { return s.name }
+func (s recordingStep) Stage() PlacementStage { return s.stage }
+
+func (s recordingStep) Run(_ context.Context, _ Customer, order Order) (StepResult, error) {
+	s.log.add(s.name)
+	return StepResult{Order: order, Undo: func(context.Context) error {
+		s.log.add("undo " + s.name)
+		return nil
+	}}, nil
+}
+
+// Steps run at their stage, whatever the order they were added in, and
+// are undone in reverse when a later step fails.
+func TestOrderService_WithStep(t *testing.T) {
+	log := &callLog{}
+	repo := fakeStore{NewInMemoryOrderRepository(), log, nil}
+	step := func(name string, stage PlacementStage) recordingStep {
+		return recordingStep{name: name, stage: stage, log: log}
+	}
+	// A second step of a type replaces the first, so only one
+	// recordingStep runs per service.
+	for i, tt := range []struct {
+		step recordingStep
+		want []string
+	}{
+		{step("before paid", BeforePaid), []string{"payment.Charge 25.00 USD", "before paid", "store.Save paid"}},
+		{step("before charge", BeforeCharge), []string{"store.Save pending", "before charge", "payment.Charge 25.00 USD"}},
+		{step("before save", BeforeSave), []string{"before save", "store.Save pending"}},
+		{step("before pricing", BeforePricing), []string{"before pricing", "store.Save pending"}},
+	} {
+		*log = callLog{}
+		orders, err := NewOrderService(repo, fakeGateway{log, nil}, fakeSender{log, nil}, fakeInvoicer{log, nil},
+			WithStep(step("replaced", BeforePricing)), WithStep(tt.step))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := orders.PlaceOrder(context.Background(), "", testOrder(t, i+1)); err != nil {
+			t.Fatal(err)
+		}
+		calls := log.all()
+		if slices.Contains(calls, "replaced") {
+			t.Errorf("replaced step ran: %q", calls)
+		}
+		if i := slices.Index(calls, tt.want[0]); i < 0 || !slices.Equal(calls[i:i+len(tt.want)], tt.want) {
+			t.Errorf("%s: calls %q, want %q in a row", tt.step.name, calls, tt.want)
+		}
+	}
+
+	*log = callLog{}
+	declined := fmt.Errorf("card declined: %w", ErrPaymentDeclined)
+	orders, err := NewOrderService(fakeStore{NewInMemoryOrderRepository(), log, nil}, fakeGateway{log, declined}, fakeSender{log, nil}, fakeInvoicer{log, nil},
+		WithStep(step("reserve", BeforeCharge)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := orders.PlaceOrder(context.Background(), "", testOrder(t, 1)); !errors.Is(err, ErrPaymentDeclined) {
+		t.Fatalf("PlaceOrder = %v, want ErrPaymentDeclined", err)
+	}
+	if calls := log.all(); !slices.Contains(calls, "undo reserve") {
+		t.Errorf("calls %q, want the step undone", calls)
+	}
+}
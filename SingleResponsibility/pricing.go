@@ -0,0 +1,171 @@
+package singleresponsibility
+
+import "fmt"
+
+// TaxCalculator computes the taxes owed on lines billed to addr. The
+// line amounts are net of the order's discount. TaxService implements
+// it.
+type TaxCalculator interface {
+	Calculate(addr Address, lines []PriceLine) []TaxLine
+}
+
+// PriceLine is the amount of one order item.
+type PriceLine struct {
+	SKU       string
+	Quantity  int
+	UnitPrice Money
+	Amount    Money
+}
+
+// Price is the breakdown of what an order costs.
+type Price struct {
+	Lines    []PriceLine
+	Subtotal Money
+	Discount Money
+	Taxes    []TaxLine
+	Tax      Money
+	Total    Money // Subtotal - Discount + Tax
+}
+
+// PricingService is responsible only for working out what an order
+// costs: line amounts, the coupon discount already on the order, and
+// the taxes of the customer's address on the discounted amounts.
+// Neither OrderService nor InvoiceService does any price arithmetic of
+// its own.
+type PricingService struct {
+	taxes TaxCalculator
+}
+
+// NewPricingService returns a pricing service. taxes may be nil, in
+// which case orders carry no tax.
+func NewPricingService(taxes TaxCalculator) *PricingService {
+	return &PricingService{taxes: taxes}
+}
+
+// Price computes the breakdown of order billed to customer.
+func (p *PricingService) Price(customer Customer, order Order) (Price, error) {
+	if len(order.Items) == 0 {
+		return Price{}, ErrNoItems
+	}
+
+	currency := itemsCurrency(order.Items)
+	price := Price{
+		Subtotal: itemsSubtotal(order.Items),
+		Discount: NewMoney(order.Discount.Amount, currency),
+		Tax:      NewMoney(0, currency),
+	}
+	for _, item := range order.Items {
+		price.Lines = append(price.Lines, PriceLine{
+			SKU:       item.SKU,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			Amount:    lineAmount(item),
+		})
+	}
+	var err error
+	if p.taxes != nil {
+		taxable, err := discountLines(price.Lines, price.Discount)
+		if err != nil {
+			return Price{}, fmt.Errorf("pricing order %d: %w", order.ID, err)
+		}
+		price.Taxes = p.taxes.Calculate(customer.Address, taxable)
+	}
+	for _, tax := range price.Taxes {
+		if price.Tax, err = price.Tax.Add(tax.Amount); err != nil {
+			return Price{}, fmt.Errorf("pricing order %d: %s: %w", order.ID, tax.Name, err)
+		}
+	}
+	if price.Total, err = price.Subtotal.Sub(price.Discount); err == nil {
+		price.Total, err = price.Total.Add(price.Tax)
+	}
+	if err != nil {
+		return Price{}, fmt.Errorf("pricing order %d: %w", order.ID, err)
+	}
+	return price, nil
+}
+
+// PriceOrder returns order with Total set to what customer is charged
+// for it, taxes included.
+func (p *PricingService) PriceOrder(customer Customer, order Order) (Order, error) {
+	price, err := p.Price(customer, order)
+	if err != nil {
+		return Order{}, err
+	}
+	order.Total = price.Total
+	return order, nil
+}
+
+// WithPricing has the service price orders with pricing instead of a
+// tax-free PricingService.
+func WithPricing(pricing *PricingService) OrderOption {
+	return func(os *OrderService) { os.pricing = pricing }
+}
+
+// discountLines spreads discount over lines in proportion to their
+// amounts and returns the lines net of it, which is what tax is owed
+// on. Allocate hands out the rounding, so the net amounts add up to
+// the subtotal less the discount.
+func discountLines(lines []PriceLine, discount Money) ([]PriceLine, error) {
+	if discount.IsZero() {
+		return lines, nil
+	}
+	ratios := make([]int, len(lines))
+	for i, line := range lines {
+		ratios[i] = int(line.Amount.Amount)
+	}
+	shares, err := discount.Allocate(ratios...)
+	if err != nil {
+		return nil, fmt.Errorf("discounting %s: %w", discount, err)
+	}
+	net := make([]PriceLine, len(lines))
+	for i, line := range lines {
+		net[i] = line
+		if net[i].Amount, err = line.Amount.Sub(shares[i]); err != nil {
+			return nil, err
+		}
+	}
+	return net, nil
+}
+
+// linesCurrency is the currency the lines are priced in.
+func linesCurrency(lines []PriceLine) Currency {
+	if len(lines) == 0 {
+		return ""
+	}
+	return lines[0].Amount.Currency
+}
+
+// linesTotal sums the amounts of lines. They share a currency, so Add
+// cannot fail.
+func linesTotal(lines []PriceLine) Money {
+	total := NewMoney(0, linesCurrency(lines))
+	for _, line := range lines {
+		total, _ = total.Add(line.Amount)
+	}
+	return total
+}
+
+// itemsCurrency is the currency the items are priced in.
+func itemsCurrency(items []OrderItem) Currency {
+	if len(items) == 0 {
+		return ""
+	}
+	return items[0].UnitPrice.Currency
+}
+
+// lineAmount is what item costs: its unit price times its quantity.
+// NewOrder has checked that this does not overflow.
+func lineAmount(item OrderItem) Money {
+	amount, _ := item.UnitPrice.Mul(item.Quantity)
+	return amount
+}
+
+// itemsSubtotal sums the line amounts of items. NewOrder guarantees
+// they share a currency, so Add cannot fail.
+func itemsSubtotal(items []OrderItem) Money {
+	subtotal := NewMoney(0, itemsCurrency(items))
+	for _, item := range items {
+		subtotal, _ = subtotal.Add(lineAmount(item))
+	}
+	return subtotal
+}
@@ -0,0 +1,79 @@
+package singleresponsibility
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Tax is owed on what the customer pays for the goods, so it is
+// computed after the discount.
+func TestPricingService_TaxesTheDiscountedBase(t *testing.T) {
+	vat := NewTaxService(VATRule{Country: "DE", Rate: 0.19, ReducedRate: 0.07, Reduced: map[string]bool{"BOOK": true}})
+	salesTax := NewTaxService(SalesTaxRule{Name: "NYC", Country: "US", PostalPrefix: "100", Rate: 0.08875})
+	berlin := Customer{ID: 1, Address: Address{Country: "DE"}}
+	nyc := Customer{ID: 1, Address: Address{Country: "US", PostalCode: "10001"}}
+	items := func(currency Currency) []OrderItem {
+		return []OrderItem{
+			{SKU: "BOOK", Quantity: 2, UnitPrice: NewMoney(1250, currency)},
+			{SKU: "PEN", Quantity: 3, UnitPrice: NewMoney(199, currency)},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		taxes    *TaxService
+		customer Customer
+		currency Currency
+		discount int64
+		tax      int64
+		total    int64
+	}{
+		// 25.00 at 7% and 5.97 at 19%.
+		{"vat", vat, berlin, "EUR", 0, 175 + 113, 3097 + 288},
+		// 5.00 off splits 4.04/0.96: 20.96 at 7% and 5.01 at 19%.
+		{"vat after discount", vat, berlin, "EUR", 500, 147 + 95, 3097 - 500 + 242},
+		{"sales tax", salesTax, nyc, "USD", 0, 275, 3097 + 275},
+		// 8.875% of 25.97.
+		{"sales tax after discount", salesTax, nyc, "USD", 500, 230, 3097 - 500 + 230},
+		{"fully discounted", vat, berlin, "EUR", 3097, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := NewOrder(7, 1, items(tt.currency))
+			if err != nil {
+				t.Fatal(err)
+			}
+			order.Discount = NewMoney(tt.discount, tt.currency)
+			price, err := NewPricingService(tt.taxes).Price(tt.customer, order)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if price.Tax != NewMoney(tt.tax, tt.currency) || price.Total != NewMoney(tt.total, tt.currency) {
+				t.Errorf("tax %v, total %v; want %v, %v", price.Tax, price.Total, NewMoney(tt.tax, tt.currency), NewMoney(tt.total, tt.currency))
+			}
+			// The invoice lines still show the undiscounted amounts.
+			want := []Money{NewMoney(2500, tt.currency), NewMoney(597, tt.currency)}
+			if got := []Money{price.Lines[0].Amount, price.Lines[1].Amount}; !reflect.DeepEqual(got, want) {
+				t.Errorf("line amounts %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestDiscountLines_AddsUp(t *testing.T) {
+	lines := []PriceLine{
+		{SKU: "A", Amount: NewMoney(333, "USD")},
+		{SKU: "B", Amount: NewMoney(333, "USD")},
+		{SKU: "C", Amount: NewMoney(334, "USD")},
+	}
+	net, err := discountLines(lines, NewMoney(100, "USD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := linesTotal(net); got != NewMoney(900, "USD") {
+		t.Errorf("net lines add up to %v, want 9.00 USD", got)
+	}
+	if lines[0].Amount != NewMoney(333, "USD") {
+		t.Errorf("discountLines changed its input: %v", lines[0].Amount)
+	}
+}
@@ -0,0 +1,323 @@
+package singleresponsibility
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/codec"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/validate"
+)
+
+var ErrSummaryNotFound = errors.New("order summary not found")
+
+// OrderSummary is the read model of a customer's placed orders. It is
+// derived from OrderPlaced events and never written to directly.
+type OrderSummary struct {
+	CustomerID  int
+	Orders      int
+	Spent       map[Currency]Money
+	LastOrderAt time.Time
+}
+
+// DailyRevenue is the revenue of the orders placed on Day, a UTC date
+// such as "2026-03-01".
+type DailyRevenue struct {
+	Day     string
+	Orders  int
+	Revenue map[Currency]Money
+}
+
+// SummaryStore keeps the order summaries. Apply must be idempotent: an
+// order already applied is left alone and reported as such, so events
+// delivered twice count once.
+type SummaryStore interface {
+	Apply(ctx context.Context, order Order) (applied bool, err error)
+	Customer(ctx context.Context, customerID int) (OrderSummary, error)
+	// Revenue lists the days in [from, to) with orders, oldest first;
+	// a zero bound is open.
+	Revenue(ctx context.Context, from, to time.Time) ([]DailyRevenue, error)
+	// Reset forgets everything, before a rebuild.
+	Reset(ctx context.Context) error
+}
+
+// InMemorySummaryStore is a SummaryStore in maps. It is safe for
+// concurrent use.
+type InMemorySummaryStore struct {
+	mu        sync.RWMutex
+	applied   map[int]bool
+	customers map[int]OrderSummary
+	days      map[string]DailyRevenue
+}
+
+func NewInMemorySummaryStore() *InMemorySummaryStore {
+	s := &InMemorySummaryStore{}
+	s.Reset(context.Background())
+	return s
+}
+
+const dayLayout = "2006-01-02"
+
+func (s *InMemorySummaryStore) Apply(ctx context.Context, order Order) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.applied[order.ID] {
+		return false, nil
+	}
+
+	customer := s.customers[order.CustomerID]
+	spent, err := addSpent(customer.Spent, order.Total)
+	if err != nil {
+		return false, err
+	}
+	day := order.CreatedAt.UTC().Format(dayLayout)
+	revenue := s.days[day]
+	earned, err := addSpent(revenue.Revenue, order.Total)
+	if err != nil {
+		return false, err
+	}
+
+	customer.CustomerID = order.CustomerID
+	customer.Orders++
+	customer.Spent = spent
+	if order.CreatedAt.After(customer.LastOrderAt) {
+		customer.LastOrderAt = order.CreatedAt
+	}
+	s.customers[order.CustomerID] = customer
+	s.days[day] = DailyRevenue{Day: day, Orders: revenue.Orders + 1, Revenue: earned}
+	s.applied[order.ID] = true
+	return true, nil
+}
+
+// addSpent returns a copy of totals with amount added to its currency.
+func addSpent(totals map[Currency]Money, amount Money) (map[Currency]Money, error) {
+	totals = maps.Clone(totals)
+	if totals == nil {
+		totals = make(map[Currency]Money, 1)
+	}
+	total, ok := totals[amount.Currency]
+	if !ok {
+		totals[amount.Currency] = amount
+		return totals, nil
+	}
+	sum, err := total.Add(amount)
+	if err != nil {
+		return nil, err
+	}
+	totals[amount.Currency] = sum
+	return totals, nil
+}
+
+func (s *InMemorySummaryStore) Customer(ctx context.Context, customerID int) (OrderSummary, error) {
+	if err := ctx.Err(); err != nil {
+		return OrderSummary{}, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	summary, ok := s.customers[customerID]
+	if !ok {
+		return OrderSummary{}, fmt.Errorf("%w: customer %d", ErrSummaryNotFound, customerID)
+	}
+	return summary, nil
+}
+
+func (s *InMemorySummaryStore) Revenue(ctx context.Context, from, to time.Time) ([]DailyRevenue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var days []DailyRevenue
+	for day, revenue := range s.days {
+		if (!from.IsZero() && day < from.UTC().Format(dayLayout)) || (!to.IsZero() && day >= to.UTC().Format(dayLayout)) {
+			continue
+		}
+		days = append(days, revenue)
+	}
+	slices.SortFunc(days, func(a, b DailyRevenue) int { return cmp.Compare(a.Day, b.Day) })
+	return days, nil
+}
+
+func (s *InMemorySummaryStore) Reset(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.applied = make(map[int]bool)
+	s.customers = make(map[int]OrderSummary)
+	s.days = make(map[string]DailyRevenue)
+	return nil
+}
+
+// OrderProjector keeps a SummaryStore up to date from the OrderPlaced
+// events. It reads nothing but events, so the summaries can live in a
+// store of their own and be rebuilt from the orders at any time.
+type OrderProjector struct {
+	store SummaryStore
+}
+
+func NewOrderProjector(store SummaryStore) *OrderProjector {
+	return &OrderProjector{store: store}
+}
+
+// Handle is the projector's EventHandler; subscribe it to
+// EventOrderPlaced on an EventBus, or with SubscribeEvents.
+func (p *OrderProjector) Handle(ctx context.Context, e Event) error {
+	placed, ok := e.(OrderPlaced)
+	if !ok {
+		return nil
+	}
+	if _, err := p.store.Apply(ctx, placed.Order); err != nil {
+		return fmt.Errorf("projecting order %d: %w", placed.Order.ID, err)
+	}
+	return nil
+}
+
+// Rebuild resets the summaries and projects every order in orders that
+// was placed, as if its OrderPlaced event were delivered again. It
+// returns the number of orders projected.
+func (p *OrderProjector) Rebuild(ctx context.Context, orders OrderFinder) (int, error) {
+	all, err := orders.List(ctx, OrderFilter{})
+	if err != nil {
+		return 0, fmt.Errorf("rebuilding summaries: %w", err)
+	}
+	if err := p.store.Reset(ctx); err != nil {
+		return 0, fmt.Errorf("rebuilding summaries: %w", err)
+	}
+	n := 0
+	for _, order := range all {
+		if order.Status == StatusPending {
+			continue
+		}
+		if err := p.Handle(ctx, OrderPlaced{Order: order}); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// SummaryHandler serves the order summaries:
+//
+//	GET /summaries/customers/{id}
+//	GET /summaries/revenue?from=2026-03-01&to=2026-04-01
+//
+// from and to are dates; to is exclusive and either may be left out.
+// The summaries are only served as JSON.
+type SummaryHandler struct {
+	store SummaryStore
+}
+
+func NewSummaryHandler(store SummaryStore) *SummaryHandler {
+	return &SummaryHandler{store: store}
+}
+
+// Register mounts the summary routes on mux.
+func (h *SummaryHandler) Register(mux *http.ServeMux) {
+	mux.Handle("GET /summaries/customers/{id}", validate.Handler(decodeCustomerID, h.customer, writeError))
+	mux.Handle("GET /summaries/revenue", validate.Handler(decodeRevenue, h.revenue, writeError))
+}
+
+type customerIDRequest struct{ id int }
+
+func (customerIDRequest) Validate() error { return nil }
+
+// decodeCustomerID reads the {id} path segment; like decodeOrderID, a
+// malformed ID names no summary.
+func decodeCustomerID(r *http.Request) (customerIDRequest, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		return customerIDRequest{}, fmt.Errorf("%w: customer %q", ErrSummaryNotFound, r.PathValue("id"))
+	}
+	return customerIDRequest{id: id}, nil
+}
+
+type revenueRequest struct{ from, to time.Time }
+
+func (r revenueRequest) Validate() error {
+	var errs validate.Errors
+	if !r.from.IsZero() && !r.to.IsZero() && !r.from.Before(r.to) {
+		errs.Add("to", "must be after from")
+	}
+	return errs.Err()
+}
+
+func decodeRevenue(r *http.Request) (revenueRequest, error) {
+	q := r.URL.Query()
+	var req revenueRequest
+	var errs validate.Errors
+	for name, field := range map[string]*time.Time{"from": &req.from, "to": &req.to} {
+		if v := q.Get(name); v != "" {
+			t, err := time.Parse(dayLayout, v)
+			if err != nil {
+				errs.Add(name, "%q is not a date like 2006-01-02", v)
+			}
+			*field = t
+		}
+	}
+	return req, errs.Err()
+}
+
+// summaryCodecs are the formats of the summary routes.
+var summaryCodecs = codec.NewRegistry(codec.JSON)
+
+type summaryResponse struct {
+	CustomerID  int               `json:"customer_id"`
+	Orders      int               `json:"orders"`
+	Spent       map[string]string `json:"spent"`
+	LastOrderAt time.Time         `json:"last_order_at"`
+}
+
+type revenueResponse struct {
+	Day     string            `json:"day"`
+	Orders  int               `json:"orders"`
+	Revenue map[string]string `json:"revenue"`
+}
+
+// decimals formats amounts by currency for a response.
+func decimals(amounts map[Currency]Money) map[string]string {
+	out := make(map[string]string, len(amounts))
+	for currency, amount := range amounts {
+		out[string(currency)] = amount.Decimal()
+	}
+	return out
+}
+
+func (h *SummaryHandler) customer(w http.ResponseWriter, r *http.Request, req customerIDRequest) {
+	summary, err := h.store.Customer(r.Context(), req.id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeBodyWith(summaryCodecs, w, r, http.StatusOK, summaryResponse{
+		CustomerID:  summary.CustomerID,
+		Orders:      summary.Orders,
+		Spent:       decimals(summary.Spent),
+		LastOrderAt: summary.LastOrderAt,
+	})
+}
+
+func (h *SummaryHandler) revenue(w http.ResponseWriter, r *http.Request, req revenueRequest) {
+	days, err := h.store.Revenue(r.Context(), req.from, req.to)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp := make([]revenueResponse, len(days))
+	for i, day := range days {
+		resp[i] = revenueResponse{Day: day.Day, Orders: day.Orders, Revenue: decimals(day.Revenue)}
+	}
+	writeBodyWith(summaryCodecs, w, r, http.StatusOK, resp)
+}
@@ -0,0 +1,120 @@
+package singleresponsibility
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// placedOrder is testOrder for customer, placed on day and paid.
+func placedOrder(tb testing.TB, id, customer int, day time.Time) Order {
+	order := testOrder(tb, id)
+	order.CustomerID = customer
+	order.CreatedAt = day
+	order.Status = StatusPaid
+	return order
+}
+
+func TestOrderProjector(t *testing.T) {
+	ctx := context.Background()
+	march1 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	march2 := march1.AddDate(0, 0, 1)
+	orders := []Order{
+		placedOrder(t, 1, 1, march1),
+		placedOrder(t, 2, 1, march2),
+		placedOrder(t, 3, 2, march2),
+	}
+
+	live := NewInMemorySummaryStore()
+	projector := NewOrderProjector(live)
+	bus := NewEventBus()
+	bus.Subscribe(EventOrderPlaced, projector.Handle)
+	for _, order := range orders {
+		// Delivered twice, applied once.
+		for range 2 {
+			if err := bus.Publish(ctx, OrderPlaced{Order: order}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	summary, err := live.Customer(ctx, 1)
+	if err != nil || summary.Orders != 2 || summary.Spent["USD"].Decimal() != "50.00" || !summary.LastOrderAt.Equal(march2) {
+		t.Errorf("customer 1 = %+v, %v", summary, err)
+	}
+	days, err := live.Revenue(ctx, march2, time.Time{})
+	if err != nil || len(days) != 1 || days[0].Day != "2026-03-02" || days[0].Orders != 2 || days[0].Revenue["USD"].Decimal() != "50.00" {
+		t.Errorf("revenue from March 2 = %+v, %v", days, err)
+	}
+
+	// Rebuilt from the stored orders, the summaries come out the same;
+	// a pending order was never placed and does not count.
+	repo := NewInMemoryOrderRepository()
+	for _, order := range append(orders, testOrder(t, 4)) {
+		if err := repo.Save(ctx, order); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rebuilt := NewInMemorySummaryStore()
+	rebuilt.Apply(ctx, placedOrder(t, 99, 7, march1)) // stale, dropped by the rebuild
+	n, err := NewOrderProjector(rebuilt).Rebuild(ctx, repo)
+	if err != nil || n != 3 {
+		t.Fatalf("Rebuild = %d, %v; want 3", n, err)
+	}
+	for _, id := range []int{1, 2} {
+		want, _ := live.Customer(ctx, id)
+		if got, err := rebuilt.Customer(ctx, id); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("rebuilt customer %d = %+v, %v; want %+v", id, got, err, want)
+		}
+	}
+	if _, err := rebuilt.Customer(ctx, 7); err == nil {
+		t.Error("the rebuild kept a stale summary")
+	}
+	wantDays, _ := live.Revenue(ctx, time.Time{}, time.Time{})
+	if got, _ := rebuilt.Revenue(ctx, time.Time{}, time.Time{}); !reflect.DeepEqual(got, wantDays) {
+		t.Errorf("rebuilt revenue = %+v, want %+v", got, wantDays)
+	}
+}
+
+func TestSummaryHandler(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemorySummaryStore()
+	march1 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	store.Apply(ctx, placedOrder(t, 1, 1, march1))
+	store.Apply(ctx, placedOrder(t, 2, 1, march1.AddDate(0, 1, 0)))
+	mux := http.NewServeMux()
+	NewSummaryHandler(store).Register(mux)
+
+	tests := []struct {
+		path   string
+		status int
+		want   string
+	}{
+		{"/summaries/customers/1", http.StatusOK, `{"customer_id":1,"orders":2,"spent":{"USD":"50.00"},"last_order_at":"2026-04-01T09:00:00Z"}`},
+		{"/summaries/customers/2", http.StatusNotFound, ""},
+		{"/summaries/revenue?from=2026-03-01&to=2026-04-01", http.StatusOK, `[{"day":"2026-03-01","orders":1,"revenue":{"USD":"25.00"}}]`},
+		{"/summaries/revenue?from=March", http.StatusBadRequest, ""},
+		{"/summaries/revenue?from=2026-04-01&to=2026-03-01", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.status {
+			t.Errorf("GET %s: %d %s, want %d", tt.path, rec.Code, rec.Body, tt.status)
+			continue
+		}
+		if tt.want == "" {
+			continue
+		}
+		var got, want any
+		json.Unmarshal(rec.Body.Bytes(), &got)
+		json.Unmarshal([]byte(tt.want), &want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GET %s: %s, want %s", tt.path, rec.Body, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package singleresponsibility
+
+import (
+	"context"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/result"
+)
+
+// Quote works out what order would cost without placing it: the order
+// is checked, its customer resolved and the order priced, the same
+// first steps PlaceOrder takes. Nothing is saved or charged.
+func (os OrderService) Quote(ctx context.Context, order Order) (Price, error) {
+	if err := os.checkOrder(ctx, order); err != nil {
+		return Price{}, err
+	}
+	customer, err := resolveCustomer(ctx, os.customers, order)
+	if err != nil {
+		return Price{}, err
+	}
+	return os.pricing.Price(customer, order)
+}
+
+// QuoteResult is Quote written as a result.Result pipeline, to compare
+// the two styles; both return the same price and the same errors.
+//
+// The chain has no if err != nil, but each step has to be adapted to
+// it, and because the price needs both the order and its customer, the
+// steps pass a quoteInput along instead of plain values.
+func (os OrderService) QuoteResult(ctx context.Context, order Order) result.Result[Price] {
+	checked := result.Map(result.Of(order, os.checkOrder(ctx, order)), func(o Order) quoteInput {
+		return quoteInput{order: o}
+	})
+	resolved := result.Try(checked, func(in quoteInput) (quoteInput, error) {
+		var err error
+		in.customer, err = resolveCustomer(ctx, os.customers, in.order)
+		return in, err
+	})
+	return result.Try(resolved, func(in quoteInput) (Price, error) {
+		return os.pricing.Price(in.customer, in.order)
+	})
+}
+
+type quoteInput struct {
+	order    Order
+	customer Customer
+}
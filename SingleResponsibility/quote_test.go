@@ -0,0 +1,79 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newQuotingService(tb testing.TB) OrderService {
+	tb.Helper()
+	customers := NewInMemoryCustomerRepository()
+	if err := customers.Save(context.Background(), Customer{ID: 1, Name: "Ada", Email: "ada@example.com"}); err != nil {
+		tb.Fatal(err)
+	}
+	orders, err := NewOrderService(NewInMemoryOrderRepository(), NewFakeStripeGateway(NewMoney(0, "USD"), nil), NewLoggingEmailSender(nil), NewInvoiceService(TextInvoiceRenderer{}, nil, nil),
+		WithCustomers(customers), WithValidation(DefaultOrderRules()))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return *orders
+}
+
+// Quote and QuoteResult agree on every outcome.
+func TestQuote_BothStylesAgree(t *testing.T) {
+	os := newQuotingService(t)
+	valid := testOrder(t, 1)
+	paid := valid
+	paid.Status = StatusPaid
+	stranger := valid
+	stranger.CustomerID = 2
+
+	tests := []struct {
+		name    string
+		order   Order
+		wantErr error
+	}{
+		{"valid", valid, nil},
+		{"not pending", paid, ErrInvalidTransition},
+		{"unknown customer", stranger, ErrCustomerNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			price, err := os.Quote(ctx, tt.order)
+			resultPrice, resultErr := os.QuoteResult(ctx, tt.order).Get()
+
+			if !errors.Is(err, tt.wantErr) || !errors.Is(resultErr, tt.wantErr) {
+				t.Fatalf("errors = %v and %v, want %v", err, resultErr, tt.wantErr)
+			}
+			if !reflect.DeepEqual(price, resultPrice) || (err == nil) != (resultErr == nil) || (err != nil && err.Error() != resultErr.Error()) {
+				t.Errorf("Quote = %+v, %v\nQuoteResult = %+v, %v", price, err, resultPrice, resultErr)
+			}
+			if tt.wantErr == nil && price.Total != NewMoney(2500, "USD") {
+				t.Errorf("total = %s, want 25.00 USD", price.Total)
+			}
+		})
+	}
+}
+
+func BenchmarkQuote(b *testing.B) {
+	os := newQuotingService(b)
+	order := testOrder(b, 1)
+	for b.Loop() {
+		if _, err := os.Quote(context.Background(), order); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkQuoteResult(b *testing.B) {
+	os := newQuotingService(b)
+	order := testOrder(b, 1)
+	for b.Loop() {
+		if err := os.QuoteResult(context.Background(), order).Err(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
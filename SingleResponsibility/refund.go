@@ -0,0 +1,68 @@
+package singleresponsibility
+
+import (
+	"context"
+	"fmt"
+)
+
+// RefundService coordinates refunding a paid order: reversing the
+// payment, marking the order refunded and telling the customer. It is
+// a separate use case, so it gets its own coordinator instead of
+// growing OrderService. The status change itself still goes through
+// orders, so its status hooks fire and its audit log records it.
+type RefundService struct {
+	orders    OrderService
+	payment   PaymentGateway
+	email     *EmailService
+	customers CustomerRepository
+	log       Logger
+}
+
+// NewRefundService wires the refund collaborators. customers may be
+// nil, in which case the placeholder customer is notified.
+func NewRefundService(orders OrderService, payment PaymentGateway, mail EmailSender, customers CustomerRepository, log Logger) *RefundService {
+	return &RefundService{
+		orders:    orders,
+		payment:   payment,
+		email:     NewEmailService(mail),
+		customers: customers,
+		log:       orNop(log),
+	}
+}
+
+// Refund reverses the payment of an order and marks it refunded. A
+// failing refund notice is logged but does not undo the refund.
+func (s *RefundService) Refund(ctx context.Context, orderID int) (Order, error) {
+	order, err := s.orders.repo.FindByID(ctx, orderID)
+	if err != nil {
+		return Order{}, err
+	}
+	if !CanTransition(order.Status, StatusRefunded) {
+		return Order{}, fmt.Errorf("%w: order %d from %s to %s", ErrInvalidTransition, order.ID, order.Status, StatusRefunded)
+	}
+	if order.PaymentID == "" {
+		return Order{}, fmt.Errorf("refunding order %d: %w: no payment recorded", order.ID, ErrUnknownPayment)
+	}
+
+	if err := s.payment.Refund(ctx, order.PaymentID); err != nil {
+		return Order{}, fmt.Errorf("refunding order %d: %w", order.ID, err)
+	}
+
+	// The money is back; finish even if the caller gives up now.
+	ctx = context.WithoutCancel(ctx)
+	if err := s.orders.advance(ctx, &order, StatusRefunded); err != nil {
+		return Order{}, fmt.Errorf("payment %s refunded but saving order %d failed: %w", order.PaymentID, order.ID, err)
+	}
+	s.orders.record(ctx, AuditOrderRefunded, order.ID, "refunded "+order.PaymentID)
+
+	customer, err := resolveCustomer(ctx, s.customers, order)
+	if err == nil {
+		err = s.email.SendRefundNotice(ctx, customer, order)
+	}
+	if err != nil {
+		logf(ctx, s.log, "Order %d refunded but the customer was not notified: %v", order.ID, err)
+	}
+
+	logf(ctx, s.log, "Order %d refunded", order.ID)
+	return order, nil
+}
@@ -0,0 +1,58 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+)
+
+func TestRefundService_TransitionsAndAudits(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryOrderRepository()
+	payment := NewFakeStripeGateway(NewMoney(10000, "USD"), nil)
+	mail := NewLoggingEmailSender(nil)
+	audit := NewAuditLogService(NewInMemoryAuditStore(), clocktest.NewFake(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)))
+	var changes []OrderStatus
+	orders, err := NewOrderService(repo, payment, mail, NewInvoiceService(TextInvoiceRenderer{}, NewPricingService(nil), nil),
+		WithAuditLog(audit),
+		OnStatusChange(func(order Order, from OrderStatus) {
+			changes = append(changes, from, order.Status)
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	placed, err := orders.PlaceOrder(ctx, "", testOrder(t, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes = nil
+	refunds := NewRefundService(*orders, payment, mail, nil, nil)
+
+	refunded, err := refunds.Refund(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refunded.Status != StatusRefunded {
+		t.Errorf("status = %s, want refunded", refunded.Status)
+	}
+	if want := []OrderStatus{StatusInvoiced, StatusRefunded}; len(changes) != 2 || changes[0] != want[0] || changes[1] != want[1] {
+		t.Errorf("status hook saw %v, want %v", changes, want)
+	}
+	history, err := audit.History(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := history[len(history)-1]; last.Action != AuditOrderRefunded || last.Detail != "refunded "+placed.PaymentID {
+		t.Errorf("last audit entry = %+v, want the refund", last)
+	}
+
+	if _, err := refunds.Refund(ctx, 1); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("second refund: err = %v, want ErrInvalidTransition", err)
+	}
+	if len(changes) != 2 {
+		t.Errorf("a rejected refund fired the status hook: %v", changes)
+	}
+}
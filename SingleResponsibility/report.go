@@ -0,0 +1,56 @@
+package singleresponsibility
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/storage"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/tracing"
+)
+
+// reportJitter spreads out the scheduled reports of replicas sharing
+// a schedule.
+const reportJitter = time.Minute
+
+// OrderReport is a sched.Job that exports every stored order as CSV
+// to a new blob in sink, reports/orders-<time of the run>.csv. It only
+// decides where reports go; OrderExporter reads the orders and
+// CSVOrderEncoder formats them.
+type OrderReport struct {
+	exporter *OrderExporter
+	sink     storage.Putter
+	clock    clock.Clock
+	tracer   tracing.Tracer
+}
+
+// NewOrderReport returns a report of orders written to sink. clk may
+// be nil, for the system clock.
+func NewOrderReport(orders OrderStore, sink storage.Putter, clk clock.Clock) *OrderReport {
+	return &OrderReport{exporter: NewOrderExporter(orders), sink: sink, clock: clock.OrSystem(clk)}
+}
+
+// WithTracer returns a copy of the report that traces each run, as a
+// child of the span in the run's context, with t.
+func (r *OrderReport) WithTracer(t tracing.Tracer) *OrderReport {
+	c := *r
+	c.tracer = t
+	return &c
+}
+
+func (r *OrderReport) Run(ctx context.Context) error {
+	ctx, span := tracing.OrNoop(r.tracer).StartSpan(ctx, "OrderReport.Run")
+	defer span.End()
+
+	var buf bytes.Buffer
+	if err := r.exporter.Export(ctx, &buf, CSVOrderEncoder{}); err != nil {
+		return err
+	}
+	key := "reports/orders-" + r.clock.Now().UTC().Format("20060102T150405Z") + ".csv"
+	if err := r.sink.Put(ctx, key, &buf); err != nil {
+		return fmt.Errorf("writing order report: %w", err)
+	}
+	return nil
+}
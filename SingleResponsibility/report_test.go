@@ -0,0 +1,98 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/storage"
+)
+
+func TestOrderReport(t *testing.T) {
+	repo := NewInMemoryOrderRepository()
+	if err := repo.Save(context.Background(), testOrder(t, 1)); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	clk := clocktest.NewFake(time.Date(2025, 1, 16, 2, 0, 0, 0, time.UTC))
+	if err := NewOrderReport(repo, storage.NewLocal(dir), clk).Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "reports", "orders-20250116T020000Z.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 {
+		t.Errorf("report has %d lines, want a header and one order:\n%s", len(lines), data)
+	}
+}
+
+func TestOutboxDispatcher_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	repo := NewInMemoryOrderRepository()
+	outbox := NewInMemoryOutbox(repo)
+	enqueue := func(id int) {
+		t.Helper()
+		if err := outbox.SaveWithMessage(ctx, testOrder(t, id), OutboxMessage{OrderID: id, Kind: OutboxOrderConfirmation}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sender := NewLoggingEmailSender(nil)
+	clk := clocktest.NewFake(time.Time{})
+	d := NewOutboxDispatcher(outbox, repo, nil, sender, nil)
+
+	enqueue(1)
+	done := make(chan error)
+	go func() { done <- d.Run(ctx, time.Minute, clk) }()
+	clk.BlockUntil(1)
+	if n := len(sender.Sent()); n != 1 {
+		t.Fatalf("sent %d emails before the first interval, want 1", n)
+	}
+
+	enqueue(2)
+	clk.Advance(time.Minute)
+	clk.BlockUntil(1)
+	for deadline := time.Now().Add(5 * time.Second); len(sender.Sent()) < 2; {
+		if time.Now().After(deadline) {
+			t.Fatal("the second confirmation was not sent")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run = %v", err)
+	}
+}
+
+func TestLoadConfig_ReportSchedule(t *testing.T) {
+	env := map[string]string{"ORDERS_REPORT_SCHEDULE": "0 25 * * *"}
+	if _, err := LoadConfig(func(k string) string { return env[k] }); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("LoadConfig = %v, want ErrInvalidConfig", err)
+	}
+
+	env["ORDERS_REPORT_SCHEDULE"] = "@daily"
+	env["ORDERS_STORAGE_DIR"] = t.TempDir()
+	cfg, err := LoadConfig(func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	services, err := Wire(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer services.Close()
+	var names []string
+	for _, job := range services.Jobs {
+		names = append(names, job.Name)
+	}
+	if got := strings.Join(names, ", "); got != "outbox, order report" {
+		t.Errorf("jobs = %s, want the outbox and the order report", got)
+	}
+}
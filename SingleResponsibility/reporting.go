@@ -0,0 +1,42 @@
+package singleresponsibility
+
+import (
+	"context"
+	"strconv"
+)
+
+// ErrReporter collects the errors an operator has to look at, such as
+// an order that failed after its payment was charged. A crash-reporting
+// service sits behind it; errreport provides a buffered in-memory one
+// and one posting to a Sentry-style endpoint. The services never see
+// a vendor SDK.
+type ErrReporter interface {
+	Capture(ctx context.Context, err error, tags map[string]string)
+}
+
+// WithErrReporter has the service report to r the orders that fail
+// after the payment was charged or whose rollback failed. Declines,
+// validation errors and the like are the caller's business and are
+// not reported.
+func WithErrReporter(r ErrReporter) OrderOption {
+	return func(os *OrderService) { os.reporter = r }
+}
+
+func (os OrderService) report(ctx context.Context, err error, op string) {
+	if os.reporter != nil {
+		os.reporter.Capture(ctx, err, errorTags(ctx, op))
+	}
+}
+
+// errorTags tags a reported error with the operation and, when ctx
+// carries them, the order and request IDs.
+func errorTags(ctx context.Context, op string) map[string]string {
+	tags := map[string]string{"op": op}
+	if id, ok := OrderIDFrom(ctx); ok {
+		tags["order_id"] = strconv.Itoa(id)
+	}
+	if id, ok := RequestIDFrom(ctx); ok {
+		tags["request_id"] = id
+	}
+	return tags
+}
@@ -0,0 +1,114 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/errreport"
+)
+
+// invoiceFunc is an InvoiceGenerator backed by a function.
+type invoiceFunc func(ctx context.Context, customer Customer, order Order) ([]byte, error)
+
+func (f invoiceFunc) Generate(ctx context.Context, customer Customer, order Order) ([]byte, error) {
+	return f(ctx, customer, order)
+}
+
+// refundFailingGateway charges through the fake Stripe gateway but
+// cannot refund.
+type refundFailingGateway struct{ *FakeStripeGateway }
+
+func (refundFailingGateway) Refund(ctx context.Context, paymentID string) error {
+	return ErrGatewayUnavailable
+}
+
+func TestOrderService_ReportsErrorsNeedingAttention(t *testing.T) {
+	errPrinter := errors.New("renderer crashed")
+	brokenInvoice := invoiceFunc(func(context.Context, Customer, Order) ([]byte, error) { return nil, errPrinter })
+	stripe := func() *FakeStripeGateway { return NewFakeStripeGateway(NewMoney(10000, "USD"), nil) }
+
+	tests := []struct {
+		name     string
+		payment  PaymentGateway
+		invoice  InvoiceGenerator
+		price    int64
+		reported []error // matched with errors.Is, in order
+	}{
+		{"declined", stripe(), NewInvoiceService(TextInvoiceRenderer{}, NewPricingService(nil), nil), 50000, nil},
+		{"failed after charging", stripe(), brokenInvoice, 1250, []error{errPrinter}},
+		{"refund failed", refundFailingGateway{stripe()}, brokenInvoice, 1250, []error{ErrGatewayUnavailable}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter := errreport.NewMemory(10, nil)
+			orders, err := NewOrderService(NewInMemoryOrderRepository(), tt.payment, NewLoggingEmailSender(nil), tt.invoice, WithErrReporter(reporter))
+			if err != nil {
+				t.Fatal(err)
+			}
+			order, err := NewOrder(7, 1, []OrderItem{{SKU: "BOOK", Quantity: 1, UnitPrice: NewMoney(tt.price, "USD")}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := orders.PlaceOrder(WithRequestID(context.Background(), "req-1"), "", order); err == nil {
+				t.Fatal("PlaceOrder succeeded")
+			}
+
+			events := reporter.Events()
+			if len(events) != len(tt.reported) {
+				t.Fatalf("reported %d errors, want %d: %v", len(events), len(tt.reported), events)
+			}
+			for i, want := range tt.reported {
+				e := events[i]
+				if !errors.Is(e.Err, want) {
+					t.Errorf("reported %v, want %v", e.Err, want)
+				}
+				if e.Tags["op"] != "PlaceOrder" || e.Tags["order_id"] != "7" || e.Tags["request_id"] != "req-1" {
+					t.Errorf("tags = %v", e.Tags)
+				}
+			}
+		})
+	}
+}
+
+func TestEmailQueue_ReportsFailuresAndPanics(t *testing.T) {
+	errBounced := errors.New("bounced")
+	var sent []EmailAddress
+	sender := emailSenderFunc(func(ctx context.Context, msg EmailMessage) error {
+		switch msg.To {
+		case "panic@example.com":
+			panic("template missing")
+		case "bounce@example.com":
+			return errBounced
+		}
+		sent = append(sent, msg.To)
+		return nil
+	})
+	reporter := errreport.NewMemory(10, nil)
+	queue := NewEmailQueue(sender, 1, 10, nil, reporter)
+
+	ctx := WithOrderID(context.Background(), 7)
+	for _, to := range []EmailAddress{"panic@example.com", "bounce@example.com", "ok@example.com"} {
+		if err := queue.Send(ctx, EmailMessage{To: to}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := queue.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sent) != 1 || sent[0] != "ok@example.com" {
+		t.Errorf("sent = %v; the worker should survive the panic", sent)
+	}
+	events := reporter.Events()
+	if len(events) != 2 {
+		t.Fatalf("reported %v, want the panic and the bounce", events)
+	}
+	var perr *errreport.PanicError
+	if !errors.As(events[0].Err, &perr) || events[0].Stack == nil {
+		t.Errorf("first report = %v, want a panic with its stack", events[0].Err)
+	}
+	if !errors.Is(events[1].Err, errBounced) || events[1].Tags["order_id"] != "7" {
+		t.Errorf("second report = %v %v", events[1].Err, events[1].Tags)
+	}
+}
@@ -0,0 +1,138 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/query"
+)
+
+var (
+	ErrOrderNotFound = errors.New("order not found")
+	ErrOrderExists   = errors.New("order already exists")
+	ErrOrderChanged  = errors.New("order was changed by another save")
+)
+
+// InMemoryOrderRepository keeps orders in a map. It is safe for
+// concurrent use and is the default store for the example.
+type InMemoryOrderRepository struct {
+	mu     sync.RWMutex
+	orders map[int]Order
+}
+
+func NewInMemoryOrderRepository() *InMemoryOrderRepository {
+	return &InMemoryOrderRepository{orders: make(map[int]Order)}
+}
+
+func (r *InMemoryOrderRepository) Save(ctx context.Context, order Order) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if order.ID <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidOrderID, order.ID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.put(order)
+}
+
+// put stores order as Save does. The caller holds r.mu.
+func (r *InMemoryOrderRepository) put(order Order) error {
+	if err := r.check(order); err != nil {
+		return err
+	}
+	order.Version++
+	r.orders[order.ID] = cloneOrder(order)
+	return nil
+}
+
+// check reports whether order can be stored: inserted if it was never
+// saved, updated if it is the stored version. The caller holds r.mu.
+func (r *InMemoryOrderRepository) check(order Order) error {
+	stored, ok := r.orders[order.ID]
+	switch {
+	case order.Version == 0 && ok:
+		return fmt.Errorf("%w: %d", ErrOrderExists, order.ID)
+	case order.Version > 0 && !ok:
+		return fmt.Errorf("%w: %d", ErrOrderNotFound, order.ID)
+	case order.Version != stored.Version:
+		return fmt.Errorf("%w: order %d is at version %d, not %d", ErrOrderChanged, order.ID, stored.Version, order.Version)
+	}
+	return nil
+}
+
+func (r *InMemoryOrderRepository) FindByID(ctx context.Context, id int) (Order, error) {
+	if err := ctx.Err(); err != nil {
+		return Order{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order, ok := r.orders[id]
+	if !ok {
+		return Order{}, fmt.Errorf("%w: %d", ErrOrderNotFound, id)
+	}
+	return cloneOrder(order), nil
+}
+
+// List returns the orders filter selects, in its order.
+func (r *InMemoryOrderRepository) List(ctx context.Context, filter OrderFilter) ([]Order, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	orders := make([]Order, 0, len(r.orders))
+	for _, order := range r.orders {
+		if filter.Matches(order) {
+			orders = append(orders, cloneOrder(order))
+		}
+	}
+	slices.SortFunc(orders, filter.compare)
+	return filter.page(orders), nil
+}
+
+func (r *InMemoryOrderRepository) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.orders[id]; !ok {
+		return fmt.Errorf("%w: %d", ErrOrderNotFound, id)
+	}
+	delete(r.orders, id)
+	return nil
+}
+
+// LastOrderID returns the highest order ID in store, or 0 if it is
+// empty, for a sequence that continues where the store left off.
+func LastOrderID(ctx context.Context, store OrderStore) (int, error) {
+	last, err := store.List(ctx, OrderFilter{Sort: query.Sort{Field: "id", Desc: true}, Page: query.Page{Limit: 1}})
+	if err != nil {
+		return 0, fmt.Errorf("reading the last order id: %w", err)
+	}
+	if len(last) == 0 {
+		return 0, nil
+	}
+	return last[0].ID, nil
+}
+
+// cloneOrder copies the items so callers never share a slice with the
+// stored order.
+func cloneOrder(order Order) Order {
+	order.Items = append([]OrderItem(nil), order.Items...)
+	return order
+}
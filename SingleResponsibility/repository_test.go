@@ -0,0 +1,103 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// Run with -race: orders are placed, read and listed from parallel
+// goroutines against one repository.
+func TestInMemoryOrderRepository_Concurrent(t *testing.T) {
+	const n = 500
+	ctx := context.Background()
+	repo := NewInMemoryOrderRepository()
+	orders, err := NewOrderService(repo, NewFakeStripeGateway(NewMoney(10000, "USD"), nil), NewLoggingEmailSender(nil),
+		NewInvoiceService(TextInvoiceRenderer{}, NewPricingService(nil), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*n)
+	for id := 1; id <= n; id++ {
+		wg.Go(func() {
+			if _, err := orders.PlaceOrder(ctx, "", testOrder(t, id)); err != nil {
+				errs <- err
+			}
+		})
+		wg.Go(func() {
+			// The order may or may not be stored yet; only races matter.
+			repo.FindByID(ctx, id)
+			if _, err := repo.List(ctx, OrderFilter{Status: StatusInvoiced}); err != nil {
+				errs <- err
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	placed, err := repo.List(ctx, OrderFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(placed) != n {
+		t.Fatalf("%d orders stored, want %d", len(placed), n)
+	}
+	for _, order := range placed {
+		if order.Status != StatusInvoiced {
+			t.Errorf("order %d is %s, want invoiced", order.ID, order.Status)
+		}
+	}
+}
+
+// The first save of an order inserts it and never replaces another
+// order with the same ID; later saves update it, unless another save
+// came first.
+func TestOrderStores_SaveInsertsOnce(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range map[string]OrderStore{
+		"memory": NewInMemoryOrderRepository(),
+		"sql":    NewSQLOrderRepository(openSQLite(t)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			order := testOrder(t, 3)
+			if err := store.Save(ctx, order); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Save(ctx, testOrder(t, 3)); !errors.Is(err, ErrOrderExists) {
+				t.Errorf("second insert = %v, want ErrOrderExists", err)
+			}
+
+			stored, err := store.FindByID(ctx, 3)
+			if err != nil || stored.Version != 1 {
+				t.Fatalf("FindByID = version %d, %v; want version 1", stored.Version, err)
+			}
+			stored.Status = StatusPaid
+			if err := store.Save(ctx, stored); err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := store.FindByID(ctx, 3); got.Status != StatusPaid || got.Version != 2 {
+				t.Errorf("after the update: %s, version %d; want paid, version 2", got.Status, got.Version)
+			}
+			// stored is now out of date.
+			stored.Status = StatusCancelled
+			if err := store.Save(ctx, stored); !errors.Is(err, ErrOrderChanged) {
+				t.Errorf("saving a stale order = %v, want ErrOrderChanged", err)
+			}
+
+			missing := testOrder(t, 4)
+			missing.Version = 1
+			if err := store.Save(ctx, missing); !errors.Is(err, ErrOrderNotFound) {
+				t.Errorf("updating a missing order = %v, want ErrOrderNotFound", err)
+			}
+			if last, err := LastOrderID(ctx, store); err != nil || last != 3 {
+				t.Errorf("LastOrderID = %d, %v; want 3", last, err)
+			}
+		})
+	}
+}
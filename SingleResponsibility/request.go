@@ -0,0 +1,159 @@
+package singleresponsibility
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/query"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/validate"
+)
+
+// The request types of the order API. Each validates itself, and
+// OrderHandler.Register has validate.Handler check it before the
+// handler runs, so handlers only ever see well-formed requests. The
+// domain still checks its own invariants; this only gives clients
+// every problem with a request at once, field by field.
+
+// Amounts travel as decimal strings such as "12.50" in the request's
+// currency, so no precision is lost to JSON floats.
+type orderItemJSON struct {
+	SKU       string `json:"sku" xml:"sku"`
+	Quantity  int    `json:"quantity" xml:"quantity"`
+	UnitPrice string `json:"unit_price" xml:"unit_price"`
+}
+
+type createOrderRequest struct {
+	CustomerID int             `json:"customer_id"`
+	Currency   Currency        `json:"currency"`
+	Items      []orderItemJSON `json:"items"`
+	CouponCode string          `json:"coupon_code,omitempty"`
+}
+
+const maxRequestBody = 1 << 20
+
+func (req createOrderRequest) Validate() error {
+	var errs validate.Errors
+	if req.CustomerID <= 0 {
+		errs.Add("customer_id", "must be a positive customer ID")
+	}
+	if !req.Currency.Valid() {
+		errs.Add("currency", "%q is not a supported currency", req.Currency)
+	}
+	if len(req.Items) == 0 {
+		errs.Add("items", "at least one item is required")
+	}
+	for i, item := range req.Items {
+		field := fmt.Sprintf("items[%d]", i)
+		if strings.TrimSpace(item.SKU) == "" {
+			errs.Add(field+".sku", "is required")
+		}
+		if item.Quantity <= 0 {
+			errs.Add(field+".quantity", "must be positive")
+		}
+		if !req.Currency.Valid() {
+			continue
+		}
+		if price, err := ParseMoney(item.UnitPrice, req.Currency); err != nil {
+			errs.Add(field+".unit_price", "%q is not an amount in %s", item.UnitPrice, req.Currency)
+		} else if price.IsNegative() {
+			errs.Add(field+".unit_price", "must not be negative")
+		}
+	}
+	return errs.Err()
+}
+
+// items returns the order items of a valid request.
+func (req createOrderRequest) items() []OrderItem {
+	items := make([]OrderItem, len(req.Items))
+	for i, item := range req.Items {
+		price, _ := ParseMoney(item.UnitPrice, req.Currency) // checked by Validate
+		items[i] = OrderItem{SKU: item.SKU, Quantity: item.Quantity, UnitPrice: price}
+	}
+	return items
+}
+
+// Pages of GET /orders hold defaultPageSize orders unless ?limit= asks
+// for another size, up to maxPageSize.
+const (
+	defaultPageSize = 50
+	maxPageSize     = 100
+)
+
+type listOrdersRequest struct {
+	filter OrderFilter
+}
+
+// decodeListOrders reads ?status=, ?customer_id=, ?from= and ?to=
+// (RFC 3339), ?sort= ("id" or "created_at", "-" first for descending)
+// and the page: ?limit= with ?offset= or ?cursor=.
+func decodeListOrders(r *http.Request) (listOrdersRequest, error) {
+	return parseListOrders(r.URL.Query())
+}
+
+// parseListOrders reads the parameters of decodeListOrders from q.
+func parseListOrders(q url.Values) (listOrdersRequest, error) {
+	var errs validate.Errors
+	filter := OrderFilter{Status: OrderStatus(q.Get("status"))}
+
+	if filter.Status != "" && !knownStatus(filter.Status) {
+		errs.Add("status", "%q is not an order status", filter.Status)
+	}
+	if v := q.Get("customer_id"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			errs.Add("customer_id", "%q is not a customer ID", v)
+		}
+		filter.CustomerID = n
+	}
+	for name, field := range map[string]*time.Time{"from": &filter.CreatedFrom, "to": &filter.CreatedTo} {
+		if v := q.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				errs.Add(name, "%q is not an RFC 3339 time", v)
+			}
+			*field = t
+		}
+	}
+
+	var err error
+	if filter.Sort, err = query.ParseSort(q.Get("sort"), orderSortFields...); err != nil {
+		errs.Add("sort", "must be one of %s, optionally preceded by -", strings.Join(orderSortFields, ", "))
+	}
+	if filter.Page, err = query.ParsePage(q, defaultPageSize, maxPageSize); err != nil {
+		errs.Add("page", "%v", err)
+	}
+	return listOrdersRequest{filter: filter}, errs.Err()
+}
+
+// Validate checks what decodeListOrders cannot check field by field,
+// such as an empty date range.
+func (req listOrdersRequest) Validate() error {
+	return req.filter.Validate()
+}
+
+func knownStatus(status OrderStatus) bool {
+	_, ok := orderTransitions[status]
+	return ok || status == StatusCancelled || status == StatusRefunded
+}
+
+// orderIDRequest is a request naming an order in its {id} path
+// segment.
+type orderIDRequest struct {
+	id int
+}
+
+// decodeOrderID reads the {id} path segment. Anything but an order ID
+// names no order, so it is not found rather than invalid.
+func decodeOrderID(r *http.Request) (orderIDRequest, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		return orderIDRequest{}, fmt.Errorf("%w: %q", ErrOrderNotFound, r.PathValue("id"))
+	}
+	return orderIDRequest{id: id}, nil
+}
+
+func (orderIDRequest) Validate() error { return nil }
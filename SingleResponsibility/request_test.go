@@ -0,0 +1,69 @@
+package singleresponsibility
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/apierror"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/query"
+)
+
+// Every endpoint rejects invalid input before its handler runs, with
+// the standard error body naming the invalid fields.
+func TestOrderHandler_InvalidRequests(t *testing.T) {
+	repo := NewInMemoryOrderRepository()
+	mux := http.NewServeMux()
+	// No services: a request reaching a handler would panic.
+	NewOrderHandler(nil, repo, nil, NewSequence(0)).Register(mux)
+	otherSort := query.Cursor{Sort: "created_at", ID: 1}.Encode()
+
+	tests := []struct {
+		method, path, body string
+		status             int
+		code               string
+		fields             []string
+	}{
+		{"POST", "/orders", `{"customer_id": 0, "currency": "XXX", "items": []}`, 400, "invalid_request",
+			[]string{"customer_id", "currency", "items"}},
+		{"POST", "/orders", `{"customer_id": 1, "currency": "USD", "items": [{"sku": " ", "quantity": 0, "unit_price": "1.999"}, {"sku": "PEN", "quantity": 1, "unit_price": "-1"}]}`, 400, "invalid_request",
+			[]string{"items[0].sku", "items[0].quantity", "items[0].unit_price", "items[1].unit_price"}},
+		{"POST", "/orders", `{"customer_id": 1, "currency": "USD", "items": [{"sku": "BOOK", "quantity": 1}]}`, 400, "invalid_request",
+			[]string{"items[0].unit_price"}},
+		{"POST", "/orders", `{"customer_id": 1, "surprise": true}`, 400, "malformed_request", nil},
+		{"POST", "/orders", `{"customer_id": "one"}`, 400, "malformed_request", nil},
+		{"POST", "/orders", strings.Repeat(" ", maxRequestBody+1), 400, "malformed_request", nil},
+
+		{"GET", "/orders?status=lost&customer_id=x&from=yesterday&sort=total&limit=-1", "", 400, "invalid_request",
+			[]string{"status", "customer_id", "from", "sort", "page"}},
+		{"GET", "/orders?cursor=garbage", "", 400, "invalid_request", []string{"page"}},
+		{"GET", "/orders?offset=5&cursor=" + otherSort, "", 400, "invalid_request", []string{"page"}},
+		{"GET", "/orders?cursor=" + otherSort, "", 400, "invalid_filter", nil},
+		{"GET", "/orders?from=2025-02-01T00:00:00Z&to=2025-01-01T00:00:00Z", "", 400, "invalid_filter", nil},
+
+		{"GET", "/orders/abc", "", 404, "order_not_found", nil},
+		{"GET", "/orders/-3", "", 404, "order_not_found", nil},
+		{"POST", "/orders/0/refund", "", 404, "order_not_found", nil},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+		var body apierror.Body
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("%s %s: %v", tt.method, tt.path, err)
+		}
+		var fields []string
+		for _, f := range body.Fields {
+			fields = append(fields, f.Field)
+		}
+		slices.Sort(fields)
+		slices.Sort(tt.fields)
+		if rec.Code != tt.status || body.Code != tt.code || !slices.Equal(fields, tt.fields) {
+			t.Errorf("%s %s %.60s: %d %s %q, want %d %s %q", tt.method, tt.path, tt.body, rec.Code, body.Code, fields, tt.status, tt.code, tt.fields)
+		}
+	}
+}
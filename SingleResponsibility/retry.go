@@ -0,0 +1,120 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+// RetryPolicy describes how often and how patiently a call is retried.
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts, including the first one
+	BaseDelay   time.Duration // delay before the second attempt
+	MaxDelay    time.Duration // upper bound for any single delay
+	Jitter      float64       // 0..1, fraction of each delay that is randomised
+
+	// Retryable decides whether an error is transient. Nil means
+	// IsTransientPaymentError.
+	Retryable func(error) bool
+}
+
+// DefaultRetryPolicy makes three attempts, waiting up to 100ms and then
+// up to 200ms between them.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+	Jitter:      0.2,
+}
+
+// IsTransientPaymentError reports whether err means the provider did
+// nothing and the call can safely be repeated. Declines and invalid
+// amounts are terminal: retrying cannot change the answer.
+func IsTransientPaymentError(err error) bool {
+	return errors.Is(err, ErrGatewayUnavailable)
+}
+
+// backoff returns the delay after the given failed attempt (1-based):
+// BaseDelay doubled per attempt, capped at MaxDelay, minus up to
+// Jitter of itself.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay << (attempt - 1)
+	if d <= 0 || (p.MaxDelay > 0 && d > p.MaxDelay) {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(rand.Float64() * p.Jitter * float64(d))
+	}
+	return d
+}
+
+// RetryingGateway is a PaymentGateway decorator that retries transient
+// failures with exponential backoff. The retry concern lives here, so
+// neither OrderService nor the gateways know about it.
+type RetryingGateway struct {
+	next   PaymentGateway
+	policy RetryPolicy
+	clock  clock.Clock
+}
+
+func NewRetryingGateway(next PaymentGateway, policy RetryPolicy, clk clock.Clock) *RetryingGateway {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	if policy.Retryable == nil {
+		policy.Retryable = IsTransientPaymentError
+	}
+	return &RetryingGateway{next: next, policy: policy, clock: clock.OrSystem(clk)}
+}
+
+func (g *RetryingGateway) Charge(ctx context.Context, orderID int, amount Money) (string, error) {
+	var paymentID string
+	err := g.retry(ctx, func() error {
+		var err error
+		paymentID, err = g.next.Charge(ctx, orderID, amount)
+		return err
+	})
+	return paymentID, err
+}
+
+func (g *RetryingGateway) Refund(ctx context.Context, paymentID string) error {
+	return g.retry(ctx, func() error {
+		return g.next.Refund(ctx, paymentID)
+	})
+}
+
+func (g *RetryingGateway) Void(ctx context.Context, paymentID string) error {
+	voider, ok := g.next.(PaymentVoider)
+	if !ok {
+		return ErrVoidUnsupported
+	}
+	return g.retry(ctx, func() error {
+		return voider.Void(ctx, paymentID)
+	})
+}
+
+func (g *RetryingGateway) retry(ctx context.Context, call func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil || !g.policy.Retryable(err) {
+			return err
+		}
+		if attempt == g.policy.MaxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+		// Checked first, since a backoff that is already over would
+		// otherwise race with the cancellation.
+		if cerr := ctx.Err(); cerr != nil {
+			return errors.Join(err, cerr)
+		}
+		select {
+		case <-g.clock.After(g.policy.backoff(attempt)):
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+)
+
+// flakyGateway fails its calls with errs, in turn, then succeeds.
+type flakyGateway struct {
+	errs  []error
+	calls int
+}
+
+func (g *flakyGateway) next() error {
+	g.calls++
+	if g.calls <= len(g.errs) {
+		return g.errs[g.calls-1]
+	}
+	return nil
+}
+
+func (g *flakyGateway) Charge(ctx context.Context, orderID int, amount Money) (string, error) {
+	if err := g.next(); err != nil {
+		return "", err
+	}
+	return "ch_1", nil
+}
+
+func (g *flakyGateway) Refund(ctx context.Context, paymentID string) error {
+	return g.next()
+}
+
+func TestRetryingGateway(t *testing.T) {
+	unavailable := ErrGatewayUnavailable
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 250 * time.Millisecond}
+	ms := func(d ...int) []time.Duration {
+		var out []time.Duration
+		for _, n := range d {
+			out = append(out, time.Duration(n)*time.Millisecond)
+		}
+		return out
+	}
+	tests := []struct {
+		name       string
+		policy     RetryPolicy
+		errs       []error
+		cancel     bool
+		wantErr    error
+		wantCalls  int
+		wantSleeps []time.Duration
+	}{
+		{"first attempt", policy, nil, false, nil, 1, nil},
+		// Doubling from BaseDelay, capped at MaxDelay.
+		{"backoff", policy, []error{unavailable, unavailable, unavailable}, false, nil, 4, ms(100, 200, 250)},
+		{"max attempts", RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond}, []error{unavailable, unavailable, unavailable, unavailable}, false, unavailable, 3, ms(100, 200)},
+		{"non-retryable", policy, []error{ErrPaymentDeclined}, false, ErrPaymentDeclined, 1, nil},
+		{"custom retryable", RetryPolicy{MaxAttempts: 2, BaseDelay: time.Second, Retryable: func(err error) bool { return errors.Is(err, ErrPaymentDeclined) }},
+			[]error{ErrPaymentDeclined, unavailable}, false, unavailable, 2, ms(1000)},
+		{"ctx cancelled", policy, []error{unavailable, unavailable}, true, context.Canceled, 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+			for _, call := range []struct {
+				name string
+				do   func(PaymentGateway) error
+			}{
+				{"Charge", func(g PaymentGateway) error { _, err := g.Charge(ctx, 1, NewMoney(2500, "USD")); return err }},
+				{"Refund", func(g PaymentGateway) error { return g.Refund(ctx, "ch_1") }},
+			} {
+				gateway := &flakyGateway{errs: tt.errs}
+				clk := clocktest.NewAuto(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+				err := call.do(NewRetryingGateway(gateway, tt.policy, clk))
+				if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+					t.Fatalf("%s: err = %v, want %v", call.name, err, tt.wantErr)
+				}
+				if gateway.calls != tt.wantCalls || !reflect.DeepEqual(clk.Waits(), tt.wantSleeps) {
+					t.Errorf("%s: %d calls sleeping %v, want %d sleeping %v", call.name, gateway.calls, clk.Waits(), tt.wantCalls, tt.wantSleeps)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryingGateway_GivingUpNamesAttempts(t *testing.T) {
+	gateway := &flakyGateway{errs: []error{ErrGatewayUnavailable, ErrGatewayUnavailable}}
+	retrying := NewRetryingGateway(gateway, RetryPolicy{MaxAttempts: 2}, clocktest.NewAuto(time.Time{}))
+	if _, err := retrying.Charge(context.Background(), 1, NewMoney(100, "USD")); err == nil || !strings.HasPrefix(err.Error(), "giving up after 2 attempts: ") {
+		t.Errorf("err = %v, want it to name the 2 attempts", err)
+	}
+}
+
+// Jitter only ever shortens a delay, by at most its fraction.
+func TestRetryPolicy_BackoffJitter(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, Jitter: 0.5}
+	for attempt, full := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 5: time.Second} {
+		for range 100 {
+			if d := p.backoff(attempt); d > full || d < full/2 {
+				t.Fatalf("backoff(%d) = %s, want within [%s, %s]", attempt, d, full/2, full)
+			}
+		}
+	}
+}
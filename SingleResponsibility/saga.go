@@ -0,0 +1,48 @@
+package singleresponsibility
+
+import (
+	"errors"
+	"fmt"
+)
+
+// compensations collects the undo steps of work that already
+// succeeded. When a later step fails, rollback runs them in reverse
+// order, like a small saga.
+type compensations []compensation
+
+type compensation struct {
+	name string
+	undo func() error
+}
+
+func (c *compensations) add(name string, undo func() error) {
+	*c = append(*c, compensation{name: name, undo: undo})
+}
+
+// CompensationError reports an undo step that failed, leaving the
+// work it should have undone in place.
+type CompensationError struct {
+	Step string
+	Err  error
+}
+
+func (e *CompensationError) Error() string {
+	return fmt.Sprintf("compensating %s: %v", e.Step, e.Err)
+}
+
+func (e *CompensationError) Unwrap() error {
+	return e.Err
+}
+
+// rollback runs every compensation and returns cause joined with a
+// *CompensationError for each compensation that failed. A failing
+// compensation does not stop the remaining ones.
+func (c compensations) rollback(cause error) error {
+	errs := []error{cause}
+	for i := len(c) - 1; i >= 0; i-- {
+		if err := c[i].undo(); err != nil {
+			errs = append(errs, &CompensationError{Step: c[i].name, Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
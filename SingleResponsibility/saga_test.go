@@ -0,0 +1,167 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+var errStage = errors.New("stage failed")
+
+// sagaLog records the calls of every collaborator of a saga and fails
+// the one named failing.
+type sagaLog struct {
+	callLog
+	failing string
+}
+
+func (l *sagaLog) call(name string) error {
+	l.add(name)
+	if name == l.failing {
+		return errStage
+	}
+	return nil
+}
+
+type sagaStore struct {
+	*InMemoryOrderRepository
+	log *sagaLog
+}
+
+func (s sagaStore) Save(ctx context.Context, order Order) error {
+	if err := s.log.call("store.Save " + string(order.Status)); err != nil {
+		return err
+	}
+	return s.InMemoryOrderRepository.Save(ctx, order)
+}
+
+func (s sagaStore) Delete(ctx context.Context, id int) error {
+	s.log.add("store.Delete")
+	return s.InMemoryOrderRepository.Delete(ctx, id)
+}
+
+type sagaCoupons struct{ log *sagaLog }
+
+func (c sagaCoupons) FindByCode(ctx context.Context, code string) (Coupon, error) {
+	return Coupon{Code: code, Kind: PercentageDiscount{Percent: 10}}, nil
+}
+
+func (c sagaCoupons) Redeem(ctx context.Context, code string) error {
+	return c.log.call("coupon.Redeem")
+}
+
+func (c sagaCoupons) Unredeem(ctx context.Context, code string) error {
+	return c.log.call("coupon.Unredeem")
+}
+
+type sagaStock struct{ log *sagaLog }
+
+func (s sagaStock) Reserve(ctx context.Context, orderID int, items []OrderItem) error {
+	return s.log.call("stock.Reserve")
+}
+
+func (s sagaStock) Release(ctx context.Context, orderID int) error {
+	return s.log.call("stock.Release")
+}
+
+type sagaGateway struct{ log *sagaLog }
+
+func (g sagaGateway) Charge(ctx context.Context, orderID int, amount Money) (string, error) {
+	if err := g.log.call("payment.Charge " + amount.String()); err != nil {
+		return "", err
+	}
+	return "pay_1", nil
+}
+
+func (g sagaGateway) Refund(ctx context.Context, paymentID string) error {
+	return g.log.call("payment.Refund " + paymentID)
+}
+
+type sagaCarrier struct{ log *sagaLog }
+
+func (c sagaCarrier) Name() string { return "DHL" }
+
+func (c sagaCarrier) CreateShipment(ctx context.Context, s Shipment) (string, error) {
+	if err := c.log.call("carrier.CreateShipment"); err != nil {
+		return "", err
+	}
+	return "DHL-1", nil
+}
+
+func (c sagaCarrier) CancelShipment(ctx context.Context, trackingNumber string) error {
+	return c.log.call("carrier.CancelShipment " + trackingNumber)
+}
+
+// Whichever stage fails, exactly the stages before it are undone, in
+// reverse order.
+func TestOrderService_PlaceOrder_Compensations(t *testing.T) {
+	steps := []string{
+		"coupon.Redeem", "store.Save pending", "stock.Reserve", "payment.Charge 22.50 USD",
+		"carrier.CreateShipment", "store.Save paid",
+	}
+	tests := []struct {
+		failing string
+		undo    []string
+	}{
+		{"coupon.Redeem", nil},
+		{"store.Save pending", []string{"coupon.Unredeem"}},
+		{"stock.Reserve", []string{"store.Delete", "coupon.Unredeem"}},
+		{"payment.Charge 22.50 USD", []string{"stock.Release", "store.Delete", "coupon.Unredeem"}},
+		{"carrier.CreateShipment", []string{"payment.Refund pay_1", "stock.Release", "store.Delete", "coupon.Unredeem"}},
+		{"store.Save paid", []string{"carrier.CancelShipment DHL-1", "payment.Refund pay_1", "stock.Release", "store.Delete", "coupon.Unredeem"}},
+	}
+	for i, tt := range tests {
+		t.Run(tt.failing, func(t *testing.T) {
+			log := &sagaLog{failing: tt.failing}
+			orders, err := NewOrderService(sagaStore{NewInMemoryOrderRepository(), log}, sagaGateway{log}, NewLoggingEmailSender(nil), fakeInvoicer{&callLog{}, nil},
+				WithCoupons(NewCouponService(sagaCoupons{log}, clock.System{}, nil)),
+				WithInventory(NewInventoryService(sagaStock{log}, nil)),
+				WithShipping(NewShippingService(sagaCarrier{log}, nil)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			order := testOrder(t, 1)
+			order.CouponCode = "TENOFF"
+
+			_, err = orders.PlaceOrder(context.Background(), "", order)
+			if !errors.Is(err, errStage) {
+				t.Fatalf("err = %v, want %v", err, errStage)
+			}
+			var compErr *CompensationError
+			if errors.As(err, &compErr) {
+				t.Errorf("a compensation failed: %v", compErr)
+			}
+			want := append(append([]string(nil), steps[:i+1]...), tt.undo...)
+			if got := log.all(); !reflect.DeepEqual(got, want) {
+				t.Errorf("calls:\n got %q\nwant %q", got, want)
+			}
+		})
+	}
+}
+
+// A failing compensation is reported and does not stop the ones after
+// it.
+func TestOrderService_PlaceOrder_CompensationFails(t *testing.T) {
+	log := &sagaLog{failing: "payment.Refund pay_1"}
+	orders, err := NewOrderService(sagaStore{NewInMemoryOrderRepository(), log}, sagaGateway{log}, NewLoggingEmailSender(nil), fakeInvoicer{&callLog{}, errStage},
+		WithInventory(NewInventoryService(sagaStock{log}, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = orders.PlaceOrder(context.Background(), "", testOrder(t, 1))
+	var compErr *CompensationError
+	if !errors.As(err, &compErr) || compErr.Step != "payment" {
+		t.Fatalf("err = %v, want the payment compensation to fail", err)
+	}
+	want := []string{
+		"store.Save pending", "stock.Reserve", "payment.Charge 25.00 USD", "store.Save paid",
+		"payment.Refund pay_1", "stock.Release", "store.Delete",
+	}
+	if got := log.all(); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls:\n got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,200 @@
+package singleresponsibility
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/health"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/lifecycle"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/ratelimit"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/sched"
+	"google.golang.org/grpc"
+)
+
+// shutdownTimeout bounds how long requests in flight may take once the
+// server is asked to stop.
+const shutdownTimeout = 10 * time.Second
+
+// runServer serves the order API until ctx is cancelled:
+//
+//	go run ./cmd/orders serve -addr :8080
+//
+// The routes are the ones OrderHandler registers:
+//
+//	POST /orders              place an order
+//	GET  /orders              list orders, filtered and paged
+//	GET  /orders/{id}         look an order up
+//	POST /orders/{id}/refund  refund a paid order
+//
+// They answer in JSON, or in XML or protobuf (the messages of
+// proto/orders.proto) if the Accept header asks for it; errors are
+// always JSON.
+//
+// Every request gets a request ID (see RequestIDMiddleware), and the
+// log lines written while serving it carry that ID.
+//
+// With -grpc-addr, the same services are served over gRPC too, as the
+// orders.v1.OrderService of proto/orders.proto (see OrderGRPCServer),
+// with the same credentials and scopes.
+//
+// GET /metrics exports the call metrics of every dependency, as text or,
+// with ORDERS_METRICS=prometheus, in the Prometheus format: calls,
+// errors and latency per dependency (payment_charge_errors_total counts
+// payment failures, email_send_duration_seconds the notification
+// latency) and orders_paid_total for the order throughput; with
+// ORDERS_EMAIL_WORKERS set, the email_queue_* metrics cover the queue.
+// A full email queue turns new orders away with 503 until it has room.
+//
+// GET /healthz answers as long as the process runs; GET /readyz also
+// checks the database and the email queue, answering 503 if one fails.
+//
+// With ORDERS_REPORT_SCHEDULE set to a cron expression such as
+// "0 2 * * *", every stored order is exported as CSV to the blob store
+// on that schedule; with ORDERS_ARCHIVE_INVOICES=true, every invoice is
+// kept there too. The store is a directory, ORDERS_STORAGE_DIR, or with
+// ORDERS_STORAGE=s3 a bucket, ORDERS_S3_BUCKET, of an S3-compatible
+// store. With ORDERS_ARCHIVE_AFTER set, deleted orders are only marked
+// deleted, and "purge" moves older orders into the blob store.
+//
+// With ORDERS_CACHE=memory, or ORDERS_CACHE=redis and ORDERS_REDIS_ADDR,
+// orders and invoices are cached for ORDERS_CACHE_TTL.
+//
+// With ORDERS_JWT_SECRET set, or api_keys in the config file, the order
+// routes need an "Authorization: Bearer" JWT or an "X-API-Key" header
+// granting the route's scope: orders:read, orders:write or
+// orders:refund. Other requests are answered 401 or 403.
+//
+// With ORDERS_WEBHOOK_SECRET set, POST /webhooks/payments takes the
+// payment gateway's callbacks, signed with that secret in the
+// Webhook-Signature header: payment.captured marks a pending order
+// paid, payment.failed cancels it while it is pending. Each event is
+// applied once.
+//
+// GET /summaries/customers/{id} and GET /summaries/revenue serve the
+// order summaries, a read model projected from the placed orders; see
+// SummaryHandler. They need the orders:read scope.
+//
+// With ORDERS_ANALYTICS_URL set, the checkout funnel (checkout_started,
+// order_placed, checkout_failed) is posted there in batches;
+// ORDERS_ANALYTICS_SAMPLE keeps that percentage of the events.
+//
+// With ORDERS_RATE_LIMIT set, the order routes answer 429 to requests
+// beyond that many per second. The metrics and health routes are not
+// limited, so monitoring keeps working under load.
+func runServer(ctx context.Context, args []string, stdout io.Writer, getenv func(string) string) error {
+	fs := flag.NewFlagSet("orders serve", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	addr := fs.String("addr", "localhost:8080", "listen `address`")
+	grpcAddr := fs.String("grpc-addr", "localhost:9090", "gRPC listen `address`; empty serves no gRPC")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := LoadConfig(getenv)
+	if err != nil {
+		return err
+	}
+	log := SlogLogger{L: slog.New(slog.NewTextHandler(stdout, nil))}
+	services, err := Wire(ctx, cfg, log)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	api := http.NewServeMux()
+	handler := NewOrderHandler(services.Commands, services.Store, services.Commands, services.NextOrderID).
+		WithLogger(log).
+		WithErrReporter(services.Reporter).
+		WithVerifier(services.Verifier)
+	handler.Register(api)
+	orders := rateLimited(cfg.RateLimit, api)
+	mux.Handle("/orders", orders)
+	mux.Handle("/orders/", orders)
+	summaries := http.NewServeMux()
+	NewSummaryHandler(services.Summaries).Register(summaries)
+	mux.Handle("/summaries/", rateLimited(cfg.RateLimit, requireScope(services.Verifier, scopeRead, summaries)))
+	if services.Webhook != nil {
+		// Signed by the gateway instead of authenticated, and outside
+		// the rate limit, so callbacks are not turned away under load.
+		mux.Handle("POST /webhooks/payments", services.Webhook)
+	}
+	mux.Handle("GET /metrics", services.MetricsHandler)
+	mux.Handle("GET /healthz", health.LiveHandler())
+	mux.Handle("GET /readyz", services.Health.ReadyHandler())
+
+	scheduler := sched.New(nil, func(job string, err error) { log.Printf("%s: %v", job, err) })
+	for _, job := range services.Jobs {
+		if err := scheduler.Add(job); err != nil {
+			services.Close()
+			return fmt.Errorf("scheduling %s: %w", job.Name, err)
+		}
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		services.Close()
+		return err
+	}
+	var grpcLn net.Listener
+	if *grpcAddr != "" {
+		if grpcLn, err = net.Listen("tcp", *grpcAddr); err != nil {
+			ln.Close()
+			services.Close()
+			return err
+		}
+	}
+	srv := &http.Server{
+		Handler:           RequestIDMiddleware(mux),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	// The server stops first, so the requests in flight can still use
+	// the services, and the scheduler after it; then the confirmations
+	// left in the outbox and the queued emails are sent and the
+	// database is closed.
+	app := lifecycle.New()
+	app.Add("services", lifecycle.Hook{OnStop: func(context.Context) error { return services.Close() }}, outboxDrainTimeout+emailDrainTimeout)
+	app.Add("scheduler", lifecycle.Runner(scheduler.Run), shutdownTimeout)
+	app.Add("http server", lifecycle.HTTPServer(srv, ln), shutdownTimeout)
+	if grpcLn != nil {
+		grpcSrv := NewOrderGRPCServer(handler).NewServer()
+		app.Add("grpc server", lifecycle.Runner(func(ctx context.Context) error {
+			return serveGRPC(ctx, grpcSrv, grpcLn)
+		}), shutdownTimeout)
+	}
+
+	log.Printf("Serving orders on http://%s", ln.Addr())
+	if grpcLn != nil {
+		log.Printf("Serving orders over gRPC on %s", grpcLn.Addr())
+	}
+	return app.Run(ctx)
+}
+
+// serveGRPC serves srv on ln until ctx is cancelled, then lets the
+// calls in flight finish.
+func serveGRPC(ctx context.Context, srv *grpc.Server, ln net.Listener) error {
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ln) }()
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+		srv.GracefulStop()
+		return ctx.Err()
+	}
+}
+
+// rateLimited limits h to perSecond requests a second, allowing that
+// many at once; zero leaves h unlimited.
+func rateLimited(perSecond int, h http.Handler) http.Handler {
+	if perSecond <= 0 {
+		return h
+	}
+	return ratelimit.Middleware(ratelimit.NewTokenBucket(time.Second/time.Duration(perSecond), perSecond, nil), h)
+}
@@ -0,0 +1,200 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrUndeliverable   = errors.New("address is not deliverable")
+	ErrUnknownShipment = errors.New("unknown shipment")
+)
+
+// Shipment is what a carrier is asked to deliver.
+type Shipment struct {
+	OrderID   int
+	Recipient string
+	Address   Address
+	Items     []OrderItem
+}
+
+// Carrier books and cancels deliveries with one shipping company.
+type Carrier interface {
+	Name() string
+	// CreateShipment books s and returns its tracking number.
+	CreateShipment(ctx context.Context, s Shipment) (string, error)
+	CancelShipment(ctx context.Context, trackingNumber string) error
+}
+
+// ShippingService is responsible only for getting paid orders shipped:
+// booking the delivery with a Carrier and cancelling it if the order
+// falls through.
+type ShippingService struct {
+	carrier Carrier
+	log     Logger
+}
+
+func NewShippingService(carrier Carrier, log Logger) *ShippingService {
+	return &ShippingService{carrier: carrier, log: orNop(log)}
+}
+
+// Ship books the delivery of order to customer and returns the order
+// with its carrier and tracking number filled in.
+func (s *ShippingService) Ship(ctx context.Context, customer Customer, order Order) (Order, error) {
+	tracking, err := s.carrier.CreateShipment(ctx, Shipment{
+		OrderID:   order.ID,
+		Recipient: customer.Name,
+		Address:   customer.Address,
+		Items:     order.Items,
+	})
+	if err != nil {
+		return Order{}, fmt.Errorf("shipping order %d with %s: %w", order.ID, s.carrier.Name(), err)
+	}
+
+	order.Carrier = s.carrier.Name()
+	order.TrackingNumber = tracking
+	logf(ctx, s.log, "Order %d ships with %s (%s)", order.ID, order.Carrier, tracking)
+	return order, nil
+}
+
+// Cancel calls off the delivery with trackingNumber.
+func (s *ShippingService) Cancel(ctx context.Context, trackingNumber string) error {
+	if err := s.carrier.CancelShipment(ctx, trackingNumber); err != nil {
+		return fmt.Errorf("cancelling shipment %s with %s: %w", trackingNumber, s.carrier.Name(), err)
+	}
+	logf(ctx, s.log, "Cancelled shipment %s", trackingNumber)
+	return nil
+}
+
+// FakeDHLCarrier delivers to any complete address.
+type FakeDHLCarrier struct {
+	book *fakeShipmentBook
+}
+
+func NewFakeDHLCarrier() *FakeDHLCarrier {
+	return &FakeDHLCarrier{book: newFakeShipmentBook("JD%010d")}
+}
+
+func (c *FakeDHLCarrier) Name() string { return "DHL" }
+
+func (c *FakeDHLCarrier) CreateShipment(ctx context.Context, s Shipment) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if s.Address.Street == "" || s.Address.PostalCode == "" || s.Address.Country == "" {
+		return "", fmt.Errorf("%w: incomplete address for order %d", ErrUndeliverable, s.OrderID)
+	}
+	return c.book.create(), nil
+}
+
+func (c *FakeDHLCarrier) CancelShipment(ctx context.Context, trackingNumber string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.book.cancel(trackingNumber)
+}
+
+// FakeUPSCarrier delivers only to the listed Countries. An empty list
+// means anywhere.
+type FakeUPSCarrier struct {
+	Countries []string
+	book      *fakeShipmentBook
+}
+
+func NewFakeUPSCarrier(countries ...string) *FakeUPSCarrier {
+	return &FakeUPSCarrier{Countries: countries, book: newFakeShipmentBook("1Z%016d")}
+}
+
+func (c *FakeUPSCarrier) Name() string { return "UPS" }
+
+func (c *FakeUPSCarrier) CreateShipment(ctx context.Context, s Shipment) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	country := strings.ToUpper(s.Address.Country)
+	if country == "" || (len(c.Countries) > 0 && !slices.Contains(c.Countries, country)) {
+		return "", fmt.Errorf("%w: UPS does not deliver to %q", ErrUndeliverable, s.Address.Country)
+	}
+	return c.book.create(), nil
+}
+
+func (c *FakeUPSCarrier) CancelShipment(ctx context.Context, trackingNumber string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.book.cancel(trackingNumber)
+}
+
+// fakeShipmentBook is the bookkeeping shared by the fake carriers.
+type fakeShipmentBook struct {
+	format string // tracking number format, fed a sequence number
+
+	mu     sync.Mutex
+	seq    int
+	active map[string]bool
+}
+
+func newFakeShipmentBook(format string) *fakeShipmentBook {
+	return &fakeShipmentBook{format: format, active: make(map[string]bool)}
+}
+
+func (b *fakeShipmentBook) create() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	tracking := fmt.Sprintf(b.format, b.seq)
+	b.active[tracking] = true
+	return tracking
+}
+
+func (b *fakeShipmentBook) cancel(trackingNumber string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.active[trackingNumber] {
+		return fmt.Errorf("%w: %s", ErrUnknownShipment, trackingNumber)
+	}
+	delete(b.active, trackingNumber)
+	return nil
+}
+
+// WithShipping has the service book a delivery once an order is
+// charged.
+func WithShipping(shipping *ShippingService) OrderOption {
+	return WithStep(shippingStep{shipping: shipping})
+}
+
+// shippingStep books the delivery of a charged order, and cancels it
+// when the order is cancelled.
+type shippingStep struct {
+	shipping *ShippingService
+}
+
+func (shippingStep) Name() string          { return "shipment" }
+func (shippingStep) Stage() PlacementStage { return BeforePaid }
+
+func (s shippingStep) Run(ctx context.Context, customer Customer, order Order) (StepResult, error) {
+	shipped, err := s.shipping.Ship(ctx, customer, order)
+	if err != nil {
+		return StepResult{}, err
+	}
+	tracking := shipped.TrackingNumber
+	return StepResult{
+		Order:  shipped,
+		Audit:  AuditShipmentBooked,
+		Detail: fmt.Sprintf("%s %s", shipped.Carrier, tracking),
+		Undo:   func(ctx context.Context) error { return s.shipping.Cancel(ctx, tracking) },
+	}, nil
+}
+
+func (s shippingStep) Cancel(ctx context.Context, order Order) error {
+	if order.TrackingNumber == "" {
+		return nil
+	}
+	return s.shipping.Cancel(ctx, order.TrackingNumber)
+}
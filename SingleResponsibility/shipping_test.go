@@ -0,0 +1,118 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestShippingService_Ship(t *testing.T) {
+	full := Address{Street: "Unter den Linden 1", City: "Berlin", PostalCode: "10117", Country: "de"}
+	noStreet := full
+	noStreet.Street = ""
+	noCountry := full
+	noCountry.Country = ""
+
+	tests := []struct {
+		name         string
+		carrier      Carrier
+		addr         Address
+		wantErr      error
+		wantTracking string // prefix of the tracking number
+	}{
+		{"dhl", NewFakeDHLCarrier(), full, nil, "JD"},
+		{"dhl needs a street", NewFakeDHLCarrier(), noStreet, ErrUndeliverable, ""},
+		{"dhl needs a country", NewFakeDHLCarrier(), noCountry, ErrUndeliverable, ""},
+		{"ups anywhere", NewFakeUPSCarrier(), noStreet, nil, "1Z"},
+		{"ups in its countries", NewFakeUPSCarrier("US", "DE"), full, nil, "1Z"},
+		{"ups outside its countries", NewFakeUPSCarrier("US"), full, ErrUndeliverable, ""},
+		{"ups needs a country", NewFakeUPSCarrier(), noCountry, ErrUndeliverable, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shipped, err := NewShippingService(tt.carrier, nil).Ship(context.Background(), Customer{Name: "Ada", Address: tt.addr}, testOrder(t, 1))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Ship = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), "with "+tt.carrier.Name()) {
+					t.Errorf("err = %v, want it to name the carrier", err)
+				}
+				return
+			}
+			if shipped.Carrier != tt.carrier.Name() || !strings.HasPrefix(shipped.TrackingNumber, tt.wantTracking) {
+				t.Errorf("shipped with %s %q, want %s %s...", shipped.Carrier, shipped.TrackingNumber, tt.carrier.Name(), tt.wantTracking)
+			}
+		})
+	}
+}
+
+func TestShippingService_Cancel(t *testing.T) {
+	ctx := context.Background()
+	shipping := NewShippingService(NewFakeUPSCarrier(), nil)
+	customer := Customer{Address: Address{Country: "US"}}
+	first, err := shipping.Ship(ctx, customer, testOrder(t, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := shipping.Ship(ctx, customer, testOrder(t, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.TrackingNumber == second.TrackingNumber {
+		t.Fatalf("both shipments are tracked as %s", first.TrackingNumber)
+	}
+
+	if err := shipping.Cancel(ctx, first.TrackingNumber); err != nil {
+		t.Fatal(err)
+	}
+	if err := shipping.Cancel(ctx, first.TrackingNumber); !errors.Is(err, ErrUnknownShipment) {
+		t.Errorf("second Cancel = %v, want ErrUnknownShipment", err)
+	}
+	if err := shipping.Cancel(ctx, "1Z-NOPE"); !errors.Is(err, ErrUnknownShipment) {
+		t.Errorf("Cancel of an unknown shipment = %v, want ErrUnknownShipment", err)
+	}
+	if err := shipping.Cancel(ctx, second.TrackingNumber); err != nil {
+		t.Errorf("Cancel of the other shipment = %v", err)
+	}
+}
+
+// An undeliverable order is refunded, and a delivered one carries its
+// tracking number in the stored order.
+func TestOrderService_PlaceOrder_Shipping(t *testing.T) {
+	ctx := context.Background()
+	customers := NewInMemoryCustomerRepository()
+	if err := customers.Save(ctx, Customer{ID: 1, Name: "Ada", Email: "ada@example.com", Address: Address{Country: "FR"}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		carrier Carrier
+		wantErr error
+	}{
+		{NewFakeUPSCarrier("US"), ErrUndeliverable},
+		{NewFakeUPSCarrier("FR"), nil},
+	} {
+		log := &callLog{}
+		repo := NewInMemoryOrderRepository()
+		orders, err := NewOrderService(repo, fakeGateway{log, nil}, NewLoggingEmailSender(nil), fakeInvoicer{log, nil},
+			WithCustomers(customers), WithShipping(NewShippingService(tt.carrier, nil)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		placed, err := orders.PlaceOrder(ctx, "", testOrder(t, 1))
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("PlaceOrder = %v, want %v", err, tt.wantErr)
+		}
+		if err != nil {
+			if calls := log.all(); calls[len(calls)-1] != "payment.Refund pay_1" {
+				t.Errorf("calls %q, want the charge refunded", calls)
+			}
+			continue
+		}
+		if stored, _ := repo.FindByID(ctx, 1); stored.Carrier != "UPS" || stored.TrackingNumber != placed.TrackingNumber || placed.TrackingNumber == "" {
+			t.Errorf("stored %s %q, placed %q; want the UPS tracking number", stored.Carrier, stored.TrackingNumber, placed.TrackingNumber)
+		}
+	}
+}
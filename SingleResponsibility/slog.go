@@ -0,0 +1,92 @@
+package singleresponsibility
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+type (
+	requestIDKey struct{}
+	orderIDKey   struct{}
+)
+
+// WithRequestID returns a context whose log entries carry id as
+// request_id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom returns the ID set by WithRequestID, if any.
+func RequestIDFrom(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// WithOrderID returns a context whose log entries carry id as
+// order_id. PlaceOrder sets it for everything it calls.
+func WithOrderID(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, orderIDKey{}, id)
+}
+
+// OrderIDFrom returns the ID set by WithOrderID, if any.
+func OrderIDFrom(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(orderIDKey{}).(int)
+	return id, ok
+}
+
+// SlogLogger adapts a *slog.Logger to the Logger port. Entries are
+// logged at Info level. Entries logged with a context carry its
+// request and order IDs as attributes, so every line of one request
+// can be found together.
+type SlogLogger struct {
+	L *slog.Logger
+}
+
+func (l SlogLogger) Printf(format string, args ...any) {
+	l.PrintfContext(context.Background(), format, args...)
+}
+
+func (l SlogLogger) PrintfContext(ctx context.Context, format string, args ...any) {
+	var attrs []slog.Attr
+	if id, ok := RequestIDFrom(ctx); ok {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	if id, ok := OrderIDFrom(ctx); ok {
+		attrs = append(attrs, slog.Int("order_id", id))
+	}
+	l.L.LogAttrs(ctx, slog.LevelInfo, fmt.Sprintf(format, args...), attrs...)
+}
+
+// maxRequestIDLen bounds the X-Request-ID accepted from clients.
+const maxRequestIDLen = 128
+
+// RequestIDMiddleware seeds each request's context with a request ID:
+// the client's X-Request-ID if it sent a usable one, a random one
+// otherwise. The ID is echoed in the response's X-Request-ID header.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if !validRequestID(id) {
+			id = rand.Text()
+		}
+		w.Header().Set("X-Request-ID", id)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+	})
+}
+
+// validRequestID accepts short IDs of printable ASCII, so a client
+// cannot inject line breaks or control characters into the logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := range len(id) {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
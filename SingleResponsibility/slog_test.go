@@ -0,0 +1,120 @@
+package singleresponsibility
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// logRecords decodes the JSON lines written by a slog.JSONHandler.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for line := range strings.Lines(buf.String()) {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("decoding %q: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestSlogLogger_AttachesContextIDs(t *testing.T) {
+	var buf bytes.Buffer
+	log := SlogLogger{L: slog.New(slog.NewJSONHandler(&buf, nil))}
+
+	ctx := WithOrderID(WithRequestID(context.Background(), "req-1"), 42)
+	logf(ctx, log, "Order %d placed", 42)
+	log.Printf("no context")
+
+	recs := logRecords(t, &buf)
+	if len(recs) != 2 {
+		t.Fatalf("got %d records, want 2", len(recs))
+	}
+	if recs[0]["msg"] != "Order 42 placed" || recs[0]["request_id"] != "req-1" || recs[0]["order_id"] != 42.0 {
+		t.Errorf("record = %v", recs[0])
+	}
+	if _, ok := recs[1]["request_id"]; ok {
+		t.Errorf("Printf without context has a request_id: %v", recs[1])
+	}
+}
+
+func TestLogf_PlainLoggerIgnoresContext(t *testing.T) {
+	var buf bytes.Buffer
+	logf(WithRequestID(context.Background(), "req-1"), WriterLogger{W: &buf}, "hello %s", "world")
+	if got := buf.String(); got != "hello world\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"client ID kept", "abc-123", true},
+		{"missing ID generated", "", false},
+		{"control characters rejected", "abc\ninjected", false},
+		{"overlong ID rejected", strings.Repeat("x", maxRequestIDLen+1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			h := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen, _ = RequestIDFrom(r.Context())
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Request-ID", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if seen == "" || rec.Header().Get("X-Request-ID") != seen {
+				t.Fatalf("context ID %q, response header %q", seen, rec.Header().Get("X-Request-ID"))
+			}
+			if (seen == tt.header) != tt.keep {
+				t.Errorf("ID = %q, client sent %q", seen, tt.header)
+			}
+		})
+	}
+}
+
+func TestRequestIDMiddleware_PropagatesToServiceLogs(t *testing.T) {
+	var buf bytes.Buffer
+	log := SlogLogger{L: slog.New(slog.NewJSONHandler(&buf, nil))}
+	services, err := Wire(context.Background(), DefaultConfig(), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer services.Close()
+
+	mux := http.NewServeMux()
+	NewOrderHandler(services.Orders, services.Store, services.Refunds, NewSequence(0)).Register(mux)
+	body := `{"customer_id": 1, "currency": "USD", "items": [{"sku": "BOOK", "quantity": 1, "unit_price": "12.50"}]}`
+	req := httptest.NewRequest("POST", "/orders", strings.NewReader(body))
+	req.Header.Set("X-Request-ID", "req-7")
+	rec := httptest.NewRecorder()
+	RequestIDMiddleware(mux).ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+
+	var placed bool
+	for _, r := range logRecords(t, &buf) {
+		if r["request_id"] != "req-7" || r["order_id"] != 1.0 {
+			t.Errorf("record lacks correlation IDs: %v", r)
+		}
+		placed = placed || r["msg"] == "Order 1 placed"
+	}
+	if !placed {
+		t.Errorf("no %q record in:\n%s", "Order 1 placed", buf.String())
+	}
+}
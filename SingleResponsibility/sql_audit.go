@@ -0,0 +1,61 @@
+package singleresponsibility
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SQLAuditStore keeps the audit trail in the database of the orders
+// it describes, so a SQLUnitOfWork can append entries in the same
+// transaction as the orders. Migrate creates its table.
+type SQLAuditStore struct {
+	db *sql.DB
+}
+
+func NewSQLAuditStore(db *sql.DB) *SQLAuditStore {
+	return &SQLAuditStore{db: db}
+}
+
+func (s *SQLAuditStore) Append(ctx context.Context, entry AuditEntry) error {
+	return inTx(ctx, s.db, func(tx *sql.Tx) error { return appendAudit(ctx, tx, entry) })
+}
+
+func appendAudit(ctx context.Context, tx *sql.Tx, entry AuditEntry) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO audit_entries (actor, action, order_id, at, detail) VALUES (?, ?, ?, ?, ?)`,
+		entry.Actor, string(entry.Action), entry.OrderID, entry.At.UTC().Format(time.RFC3339Nano), entry.Detail,
+	)
+	if err != nil {
+		return fmt.Errorf("appending %s for order %d: %w", entry.Action, entry.OrderID, err)
+	}
+	return nil
+}
+
+func (s *SQLAuditStore) ByOrder(ctx context.Context, orderID int) ([]AuditEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT actor, action, order_id, at, detail
+		FROM audit_entries WHERE order_id = ? ORDER BY id`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("reading audit trail of order %d: %w", orderID, err)
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		var at string
+		if err := rows.Scan(&e.Actor, &e.Action, &e.OrderID, &at, &e.Detail); err != nil {
+			return nil, fmt.Errorf("reading audit trail of order %d: %w", orderID, err)
+		}
+		if e.At, err = time.Parse(time.RFC3339Nano, at); err != nil {
+			return nil, fmt.Errorf("reading audit trail of order %d: %w", orderID, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading audit trail of order %d: %w", orderID, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,95 @@
+package singleresponsibility
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SQLOutbox is the Outbox of the orders SQLOrderRepository stores in
+// the same database. Migrate creates its table.
+type SQLOutbox struct {
+	db *sql.DB
+}
+
+func NewSQLOutbox(db *sql.DB) *SQLOutbox {
+	return &SQLOutbox{db: db}
+}
+
+// SaveWithMessage saves order and enqueues msg in one transaction.
+func (o *SQLOutbox) SaveWithMessage(ctx context.Context, order Order, msg OutboxMessage) error {
+	return inTx(ctx, o.db, func(tx *sql.Tx) error {
+		if err := saveOrder(ctx, tx, order); err != nil {
+			return err
+		}
+		return enqueue(ctx, tx, msg)
+	})
+}
+
+func enqueue(ctx context.Context, tx *sql.Tx, msg OutboxMessage) error {
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now()
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO outbox (order_id, kind, created_at) VALUES (?, ?, ?)`,
+		msg.OrderID, msg.Kind, msg.CreatedAt.UTC().Format(time.RFC3339Nano),
+	)
+	if err != nil {
+		return fmt.Errorf("enqueueing %s for order %d: %w", msg.Kind, msg.OrderID, err)
+	}
+	return nil
+}
+
+func (o *SQLOutbox) Pending(ctx context.Context, limit int) ([]OutboxMessage, error) {
+	rows, err := o.db.QueryContext(ctx, `
+		SELECT id, order_id, kind, created_at, attempts, last_error
+		FROM outbox WHERE sent_at IS NULL
+		ORDER BY id LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("reading outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []OutboxMessage
+	for rows.Next() {
+		var msg OutboxMessage
+		var createdAt string
+		if err := rows.Scan(&msg.ID, &msg.OrderID, &msg.Kind, &createdAt, &msg.Attempts, &msg.LastError); err != nil {
+			return nil, fmt.Errorf("reading outbox: %w", err)
+		}
+		if msg.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+			return nil, fmt.Errorf("outbox message %d: parsing created_at: %w", msg.ID, err)
+		}
+		pending = append(pending, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading outbox: %w", err)
+	}
+	return pending, nil
+}
+
+func (o *SQLOutbox) MarkSent(ctx context.Context, id int64) error {
+	return o.update(ctx, id, `UPDATE outbox SET sent_at = ? WHERE id = ?`,
+		time.Now().UTC().Format(time.RFC3339Nano), id)
+}
+
+func (o *SQLOutbox) MarkFailed(ctx context.Context, id int64, cause error) error {
+	return o.update(ctx, id, `UPDATE outbox SET attempts = attempts + 1, last_error = ? WHERE id = ?`,
+		cause.Error(), id)
+}
+
+func (o *SQLOutbox) update(ctx context.Context, id int64, stmt string, args ...any) error {
+	res, err := o.db.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return fmt.Errorf("updating outbox message %d: %w", id, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("updating outbox message %d: %w", id, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: %d", ErrOutboxMessageNotFound, id)
+	}
+	return nil
+}
@@ -0,0 +1,411 @@
+package singleresponsibility
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// migrations are applied in order by Migrate. Their position is their
+// version, so existing entries must never be edited or reordered;
+// schema changes are appended.
+var migrations = []string{
+	`CREATE TABLE IF NOT EXISTS orders (
+		id              INTEGER PRIMARY KEY,
+		customer_id     INTEGER NOT NULL,
+		total_minor     INTEGER NOT NULL,
+		currency        TEXT    NOT NULL,
+		status          TEXT    NOT NULL,
+		payment_id      TEXT    NOT NULL DEFAULT '',
+		created_at      TEXT    NOT NULL,
+		coupon_code     TEXT    NOT NULL DEFAULT '',
+		discount_minor  INTEGER NOT NULL DEFAULT 0,
+		free_shipping   INTEGER NOT NULL DEFAULT 0,
+		carrier         TEXT    NOT NULL DEFAULT '',
+		tracking_number TEXT    NOT NULL DEFAULT '',
+		deleted_at      TEXT    NOT NULL DEFAULT ''
+	)`,
+	`CREATE TABLE IF NOT EXISTS order_items (
+		order_id         INTEGER NOT NULL REFERENCES orders(id),
+		position         INTEGER NOT NULL,
+		sku              TEXT    NOT NULL,
+		quantity         INTEGER NOT NULL,
+		unit_price_minor INTEGER NOT NULL,
+		PRIMARY KEY (order_id, position)
+	)`,
+	`CREATE TABLE IF NOT EXISTS outbox (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		order_id   INTEGER NOT NULL,
+		kind       TEXT    NOT NULL,
+		created_at TEXT    NOT NULL,
+		attempts   INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT    NOT NULL DEFAULT '',
+		sent_at    TEXT
+	)`,
+	`CREATE TABLE IF NOT EXISTS audit_entries (
+		id       INTEGER PRIMARY KEY AUTOINCREMENT,
+		actor    TEXT    NOT NULL,
+		action   TEXT    NOT NULL,
+		order_id INTEGER NOT NULL,
+		at       TEXT    NOT NULL,
+		detail   TEXT    NOT NULL DEFAULT ''
+	)`,
+	// Orders saved before versions counted as saved once.
+	`ALTER TABLE orders ADD COLUMN version INTEGER NOT NULL DEFAULT 1`,
+	`ALTER TABLE orders ADD COLUMN sync_payment INTEGER NOT NULL DEFAULT 0`,
+}
+
+// Migrate brings the schema of the SQL stores up to date.
+// Applied versions are recorded in schema_migrations, so running it
+// again only applies new migrations.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY
+	)`)
+	if err != nil {
+		return fmt.Errorf("creating schema_migrations: %w", err)
+	}
+
+	var current int
+	err = db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current)
+	if err != nil {
+		return fmt.Errorf("reading schema version: %w", err)
+	}
+
+	for i := current; i < len(migrations); i++ {
+		if err := applyMigration(ctx, db, i+1, migrations[i]); err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, version int, stmt string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, stmt); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (?)`, version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SQLOrderRepository stores orders through database/sql. The driver is
+// picked by whoever opens the *sql.DB; the queries use SQLite syntax.
+//
+// OrderService does not change when this replaces the in-memory store:
+// both satisfy OrderStore.
+type SQLOrderRepository struct {
+	db *sql.DB
+}
+
+func NewSQLOrderRepository(db *sql.DB) *SQLOrderRepository {
+	return &SQLOrderRepository{db: db}
+}
+
+func (r *SQLOrderRepository) Save(ctx context.Context, order Order) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error { return saveOrder(ctx, tx, order) })
+}
+
+// inTx runs fn in a transaction on db, committing only if fn succeeds.
+func inTx(ctx context.Context, db *sql.DB, fn func(*sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// saveOrder inserts or updates order, as OrderStore.Save does, and
+// replaces its items within tx.
+func saveOrder(ctx context.Context, tx *sql.Tx, order Order) error {
+	if order.ID <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidOrderID, order.ID)
+	}
+
+	args := []any{
+		order.CustomerID, order.Total.Amount, string(order.Total.Currency), string(order.Status), order.PaymentID,
+		order.CreatedAt.UTC().Format(time.RFC3339Nano),
+		order.CouponCode, order.Discount.Amount, order.FreeShipping, order.Carrier, order.TrackingNumber, formatDeletedAt(order.DeletedAt),
+		order.SyncPayment, order.Version + 1, order.ID,
+	}
+	if order.Version == 0 {
+		var exists bool
+		err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM orders WHERE id = ?)`, order.ID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("saving order %d: %w", order.ID, err)
+		}
+		if exists {
+			return fmt.Errorf("%w: %d", ErrOrderExists, order.ID)
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO orders (customer_id, total_minor, currency, status, payment_id, created_at,
+				coupon_code, discount_minor, free_shipping, carrier, tracking_number, deleted_at, sync_payment, version, id)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, args...)
+		if err != nil {
+			return fmt.Errorf("saving order %d: %w", order.ID, err)
+		}
+	} else {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE orders SET
+				customer_id = ?, total_minor = ?, currency = ?, status = ?, payment_id = ?, created_at = ?,
+				coupon_code = ?, discount_minor = ?, free_shipping = ?, carrier = ?, tracking_number = ?, deleted_at = ?,
+				sync_payment = ?, version = ?
+			WHERE id = ? AND version = ?`, append(args, order.Version)...)
+		if err != nil {
+			return fmt.Errorf("saving order %d: %w", order.ID, err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return fmt.Errorf("saving order %d: %w", order.ID, err)
+		} else if n == 0 {
+			var stored int
+			err := tx.QueryRowContext(ctx, `SELECT version FROM orders WHERE id = ?`, order.ID).Scan(&stored)
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: %d", ErrOrderNotFound, order.ID)
+			}
+			if err != nil {
+				return fmt.Errorf("saving order %d: %w", order.ID, err)
+			}
+			return fmt.Errorf("%w: order %d is at version %d, not %d", ErrOrderChanged, order.ID, stored, order.Version)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM order_items WHERE order_id = ?`, order.ID); err != nil {
+		return fmt.Errorf("replacing items of order %d: %w", order.ID, err)
+	}
+	for i, item := range order.Items {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO order_items (order_id, position, sku, quantity, unit_price_minor)
+			VALUES (?, ?, ?, ?, ?)`,
+			order.ID, i, item.SKU, item.Quantity, item.UnitPrice.Amount,
+		)
+		if err != nil {
+			return fmt.Errorf("saving item %d of order %d: %w", i, order.ID, err)
+		}
+	}
+	return nil
+}
+
+func (r *SQLOrderRepository) FindByID(ctx context.Context, id int) (Order, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT id, customer_id, total_minor, currency, status, payment_id, created_at,
+			coupon_code, discount_minor, free_shipping, carrier, tracking_number, deleted_at, sync_payment, version
+		FROM orders WHERE id = ?`, id)
+
+	order, err := scanOrder(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Order{}, fmt.Errorf("%w: %d", ErrOrderNotFound, id)
+	}
+	if err != nil {
+		return Order{}, fmt.Errorf("loading order %d: %w", id, err)
+	}
+
+	items, err := r.loadItems(ctx, `WHERE order_id = ?`, id)
+	if err != nil {
+		return Order{}, err
+	}
+	order.Items = items[id]
+	return order, nil
+}
+
+// List returns the orders filter selects, in its order.
+func (r *SQLOrderRepository) List(ctx context.Context, filter OrderFilter) ([]Order, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	where, args := orderFilterWhere(filter)
+	limit := -1 // SQLite's "no limit"
+	if filter.Limit > 0 {
+		limit = filter.Limit
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, customer_id, total_minor, currency, status, payment_id, created_at,
+			coupon_code, discount_minor, free_shipping, carrier, tracking_number, deleted_at, sync_payment, version
+		FROM orders `+where+`
+		ORDER BY `+orderSortSQL(filter)+` LIMIT ? OFFSET ?`, append(args, limit, filter.Offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("listing orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []Order
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("listing orders: %w", err)
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing orders: %w", err)
+	}
+
+	if len(orders) == 0 {
+		return nil, nil
+	}
+	ids := make([]any, len(orders))
+	for i, order := range orders {
+		ids[i] = order.ID
+	}
+	items, err := r.loadItems(ctx, `WHERE order_id IN (?`+strings.Repeat(", ?", len(ids)-1)+`)`, ids...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		orders[i].Items = items[orders[i].ID]
+	}
+	return orders, nil
+}
+
+// orderFilterWhere turns the conditions of filter into a WHERE clause
+// and its arguments. Timestamps are compared through julianday, since
+// RFC 3339 text with trimmed fractions does not sort as text.
+func orderFilterWhere(filter OrderFilter) (string, []any) {
+	var conds []string
+	var args []any
+	if filter.Status != "" {
+		conds = append(conds, "status = ?")
+		args = append(args, string(filter.Status))
+	}
+	if filter.CustomerID != 0 {
+		conds = append(conds, "customer_id = ?")
+		args = append(args, filter.CustomerID)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		conds = append(conds, "julianday(created_at) >= julianday(?)")
+		args = append(args, filter.CreatedFrom.UTC().Format(time.RFC3339Nano))
+	}
+	if !filter.CreatedTo.IsZero() {
+		conds = append(conds, "julianday(created_at) < julianday(?)")
+		args = append(args, filter.CreatedTo.UTC().Format(time.RFC3339Nano))
+	}
+	if !filter.After.IsZero() {
+		// Validate checked the cursor belongs to this Sort.
+		op := ">"
+		if filter.sort().Desc {
+			op = "<"
+		}
+		if filter.sort().Field == "created_at" {
+			conds = append(conds, "(julianday(created_at) "+op+" julianday(?) OR (julianday(created_at) = julianday(?) AND id "+op+" ?))")
+			args = append(args, filter.After.Key, filter.After.Key, filter.After.ID)
+		} else {
+			conds = append(conds, "id "+op+" ?")
+			args = append(args, filter.After.ID)
+		}
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+// orderSortSQL is the ORDER BY clause of filter's Sort.
+func orderSortSQL(filter OrderFilter) string {
+	dir := " ASC"
+	if filter.sort().Desc {
+		dir = " DESC"
+	}
+	if filter.sort().Field == "created_at" {
+		return "julianday(created_at)" + dir + ", id" + dir
+	}
+	return "id" + dir
+}
+
+func (r *SQLOrderRepository) Delete(ctx context.Context, id int) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM order_items WHERE order_id = ?`, id); err != nil {
+			return fmt.Errorf("deleting items of order %d: %w", id, err)
+		}
+		res, err := tx.ExecContext(ctx, `DELETE FROM orders WHERE id = ?`, id)
+		if err != nil {
+			return fmt.Errorf("deleting order %d: %w", id, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("deleting order %d: %w", id, err)
+		}
+		if n == 0 {
+			return fmt.Errorf("%w: %d", ErrOrderNotFound, id)
+		}
+		return nil
+	})
+}
+
+// loadItems returns the items matching the optional WHERE clause,
+// grouped by order ID and kept in their original order.
+func (r *SQLOrderRepository) loadItems(ctx context.Context, where string, args ...any) (map[int][]OrderItem, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT order_items.order_id, sku, quantity, unit_price_minor, orders.currency
+		FROM order_items JOIN orders ON orders.id = order_items.order_id `+where+`
+		ORDER BY order_id, position`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("loading order items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make(map[int][]OrderItem)
+	for rows.Next() {
+		var orderID int
+		var item OrderItem
+		if err := rows.Scan(&orderID, &item.SKU, &item.Quantity, &item.UnitPrice.Amount, &item.UnitPrice.Currency); err != nil {
+			return nil, fmt.Errorf("loading order items: %w", err)
+		}
+		items[orderID] = append(items[orderID], item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loading order items: %w", err)
+	}
+	return items, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanOrder(row rowScanner) (Order, error) {
+	var order Order
+	var createdAt, deletedAt string
+	if err := row.Scan(&order.ID, &order.CustomerID, &order.Total.Amount, &order.Total.Currency, &order.Status, &order.PaymentID, &createdAt,
+		&order.CouponCode, &order.Discount.Amount, &order.FreeShipping, &order.Carrier, &order.TrackingNumber, &deletedAt, &order.SyncPayment, &order.Version); err != nil {
+		return Order{}, err
+	}
+	if deletedAt != "" {
+		t, err := time.Parse(time.RFC3339Nano, deletedAt)
+		if err != nil {
+			return Order{}, fmt.Errorf("order %d: parsing deleted_at: %w", order.ID, err)
+		}
+		order.DeletedAt = t
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return Order{}, fmt.Errorf("order %d: parsing created_at: %w", order.ID, err)
+	}
+	order.CreatedAt = t
+	order.Discount.Currency = order.Total.Currency
+	return order, nil
+}
+
+// formatDeletedAt stores a zero DeletedAt, an order that is not
+// deleted, as the empty string.
+func formatDeletedAt(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,191 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+func TestMigrate_IsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t) // migrated once already
+	if err := Migrate(ctx, db); err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+	var applied int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations`).Scan(&applied); err != nil {
+		t.Fatal(err)
+	}
+	if applied != len(migrations) {
+		t.Errorf("%d migrations recorded, want %d", applied, len(migrations))
+	}
+}
+
+func TestSQLOrderRepository_RoundTripsEveryField(t *testing.T) {
+	ctx := context.Background()
+	r := NewSQLOrderRepository(openSQLite(t))
+	order := testOrder(t, 7)
+	order.Items = append(order.Items, OrderItem{SKU: "PEN", Quantity: 1, UnitPrice: NewMoney(199, "USD")})
+	order.Status = StatusShipped
+	order.PaymentID = "ch_1"
+	order.SyncPayment = true
+	order.CouponCode = "SAVE10"
+	order.Discount = NewMoney(250, "USD")
+	order.FreeShipping = true
+	order.Carrier = "ups"
+	order.TrackingNumber = "1Z999"
+	order.DeletedAt = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := r.Save(ctx, order); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.FindByID(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order.Version = 1
+	if !reflect.DeepEqual(got, order) {
+		t.Errorf("FindByID = %+v\nwant %+v", got, order)
+	}
+}
+
+func TestSQLOrderRepository_SaveReplacesItems(t *testing.T) {
+	ctx := context.Background()
+	r := NewSQLOrderRepository(openSQLite(t))
+	order := testOrder(t, 7)
+	order.Items = append(order.Items, OrderItem{SKU: "PEN", Quantity: 1, UnitPrice: NewMoney(199, "USD")})
+	if err := r.Save(ctx, order); err != nil {
+		t.Fatal(err)
+	}
+	order.Items = order.Items[1:]
+	order.Status = StatusPaid
+	order.Version = 1
+	if err := r.Save(ctx, order); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.FindByID(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != StatusPaid || len(got.Items) != 1 || got.Items[0].SKU != "PEN" {
+		t.Errorf("after the second Save: %+v", got)
+	}
+}
+
+func TestSQLOrderRepository_Errors(t *testing.T) {
+	ctx := context.Background()
+	r := NewSQLOrderRepository(openSQLite(t))
+	if err := r.Save(ctx, Order{}); !errors.Is(err, ErrInvalidOrderID) {
+		t.Errorf("Save(zero order) = %v, want ErrInvalidOrderID", err)
+	}
+	if _, err := r.FindByID(ctx, 7); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("FindByID(missing) = %v, want ErrOrderNotFound", err)
+	}
+	if err := r.Delete(ctx, 7); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("Delete(missing) = %v, want ErrOrderNotFound", err)
+	}
+}
+
+// OrderService runs unchanged on top of the SQL repository.
+func TestOrderService_OnSQLRepository(t *testing.T) {
+	ctx := context.Background()
+	r := NewSQLOrderRepository(openSQLite(t))
+	orders, err := NewOrderService(r, NewFakeStripeGateway(NewMoney(10000, "USD"), nil), NewLoggingEmailSender(nil),
+		NewInvoiceService(TextInvoiceRenderer{}, NewPricingService(nil), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	placed, err := orders.PlaceOrder(ctx, "", testOrder(t, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.FindByID(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != placed.Status || got.PaymentID != placed.PaymentID || got.Total != placed.Total {
+		t.Errorf("stored %+v, placed %+v", got, placed)
+	}
+}
+
+// The SQL stores share one database, so a SQLUnitOfWork writes the
+// order, its audit entry and its confirmation in one transaction.
+func TestSQLStores_PlaceOrder(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	repo, outbox, audit := NewSQLOrderRepository(db), NewSQLOutbox(db), NewSQLAuditStore(db)
+	orders, err := NewOrderService(repo, NewFakeStripeGateway(NewMoney(10000, "USD"), nil), NewLoggingEmailSender(nil),
+		NewInvoiceService(TextInvoiceRenderer{}, NewPricingService(nil), nil),
+		WithOutbox(outbox),
+		WithAuditLog(NewAuditLogService(audit, clock.System{})),
+		WithUnitOfWork(NewSQLUnitOfWork(db)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := orders.PlaceOrder(ctx, "", testOrder(t, 7)); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := outbox.Pending(ctx, 10)
+	if err != nil || len(pending) != 1 || pending[0].OrderID != 7 || pending[0].Kind != OutboxOrderConfirmation {
+		t.Fatalf("Pending = %+v, %v", pending, err)
+	}
+	if err := outbox.MarkSent(ctx, pending[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := outbox.Pending(ctx, 10); len(pending) != 0 {
+		t.Errorf("after MarkSent: %+v still pending", pending)
+	}
+	if err := outbox.MarkFailed(ctx, 99, errors.New("smtp down")); !errors.Is(err, ErrOutboxMessageNotFound) {
+		t.Errorf("MarkFailed(99) = %v, want ErrOutboxMessageNotFound", err)
+	}
+
+	entries, err := audit.ByOrder(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paid bool
+	for _, e := range entries {
+		paid = paid || e.Action == AuditOrderPaid
+	}
+	if !paid {
+		t.Errorf("audit trail %+v has no %s entry", entries, AuditOrderPaid)
+	}
+}
+
+func TestSQLUnitOfWork_RollsBack(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	errStop := errors.New("stop")
+	err := NewSQLUnitOfWork(db).Do(ctx, func(tx Tx) error {
+		if err := tx.SaveOrder(ctx, testOrder(t, 7)); err != nil {
+			return err
+		}
+		if err := tx.AppendAudit(ctx, AuditEntry{Actor: "test", Action: AuditOrderSaved, OrderID: 7, At: time.Now()}); err != nil {
+			return err
+		}
+		if err := tx.Enqueue(ctx, OutboxMessage{OrderID: 7, Kind: OutboxOrderConfirmation}); err != nil {
+			return err
+		}
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("Do = %v, want %v", err, errStop)
+	}
+
+	if _, err := NewSQLOrderRepository(db).FindByID(ctx, 7); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("order kept after rollback: %v", err)
+	}
+	if entries, _ := NewSQLAuditStore(db).ByOrder(ctx, 7); len(entries) != 0 {
+		t.Errorf("audit entries kept after rollback: %+v", entries)
+	}
+	if pending, _ := NewSQLOutbox(db).Pending(ctx, 10); len(pending) != 0 {
+		t.Errorf("outbox messages kept after rollback: %+v", pending)
+	}
+}
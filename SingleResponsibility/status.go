@@ -0,0 +1,80 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidTransition = errors.New("invalid order status transition")
+
+// OrderStatus is a step in the order lifecycle.
+type OrderStatus string
+
+const (
+	StatusPending   OrderStatus = "pending"
+	StatusPaid      OrderStatus = "paid"
+	StatusInvoiced  OrderStatus = "invoiced"
+	StatusShipped   OrderStatus = "shipped"
+	StatusDelivered OrderStatus = "delivered"
+	StatusCancelled OrderStatus = "cancelled"
+	StatusRefunded  OrderStatus = "refunded"
+)
+
+// orderTransitions lists the legal next statuses for every status.
+// Cancelled and Refunded are final.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	StatusPending:   {StatusPaid, StatusCancelled},
+	StatusPaid:      {StatusInvoiced, StatusCancelled, StatusRefunded},
+	StatusInvoiced:  {StatusShipped, StatusCancelled, StatusRefunded},
+	StatusShipped:   {StatusDelivered, StatusRefunded},
+	StatusDelivered: {StatusRefunded},
+}
+
+// CanTransition reports whether an order may move from one status to
+// another.
+func CanTransition(from, to OrderStatus) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves a stored order to a new status. Deciding which
+// status changes are legal is part of coordinating the workflow, so it
+// lives in OrderService rather than in the repository or the handlers.
+func (os OrderService) Transition(ctx context.Context, orderID int, to OrderStatus) (Order, error) {
+	order, err := os.repo.FindByID(ctx, orderID)
+	if err != nil {
+		return Order{}, err
+	}
+	if err := os.advance(ctx, &order, to); err != nil {
+		return Order{}, err
+	}
+	return order, nil
+}
+
+// advance checks and applies a status change and persists it.
+func (os OrderService) advance(ctx context.Context, order *Order, to OrderStatus) error {
+	return os.advanceAndSave(ctx, order, to, os.repo.Save)
+}
+
+// advanceAndSave is advance with a custom save, for steps that must
+// persist more than the order in the same write.
+func (os OrderService) advanceAndSave(ctx context.Context, order *Order, to OrderStatus, save func(context.Context, Order) error) error {
+	if !CanTransition(order.Status, to) {
+		return fmt.Errorf("%w: order %d from %s to %s", ErrInvalidTransition, order.ID, order.Status, to)
+	}
+
+	prev := order.Status
+	order.Status = to
+	if err := save(ctx, *order); err != nil {
+		order.Status = prev
+		return fmt.Errorf("saving order %d as %s: %w", order.ID, to, err)
+	}
+	order.Version++
+	os.fireStatusChange(*order, prev)
+	return nil
+}
@@ -0,0 +1,77 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// Every pair of statuses, legal or not, moved through
+// OrderService.Transition.
+func TestOrderService_Transition_Table(t *testing.T) {
+	statuses := []OrderStatus{
+		StatusPending, StatusPaid, StatusInvoiced, StatusShipped,
+		StatusDelivered, StatusCancelled, StatusRefunded, OrderStatus("lost"),
+	}
+	legal := map[[2]OrderStatus]bool{
+		{StatusPending, StatusPaid}:       true,
+		{StatusPending, StatusCancelled}:  true,
+		{StatusPaid, StatusInvoiced}:      true,
+		{StatusPaid, StatusCancelled}:     true,
+		{StatusPaid, StatusRefunded}:      true,
+		{StatusInvoiced, StatusShipped}:   true,
+		{StatusInvoiced, StatusCancelled}: true,
+		{StatusInvoiced, StatusRefunded}:  true,
+		{StatusShipped, StatusDelivered}:  true,
+		{StatusShipped, StatusRefunded}:   true,
+		{StatusDelivered, StatusRefunded}: true,
+	}
+
+	ctx := context.Background()
+	for _, from := range statuses {
+		for _, to := range statuses {
+			t.Run(string(from)+"->"+string(to), func(t *testing.T) {
+				want := legal[[2]OrderStatus{from, to}]
+				if got := CanTransition(from, to); got != want {
+					t.Fatalf("CanTransition = %v, want %v", got, want)
+				}
+
+				repo := NewInMemoryOrderRepository()
+				order := testOrder(t, 1)
+				order.Status = from
+				if err := repo.Save(ctx, order); err != nil {
+					t.Fatal(err)
+				}
+				fired := 0
+				orders, err := NewOrderService(repo, NewFakeStripeGateway(NewMoney(0, "USD"), nil), NewLoggingEmailSender(nil), fakeInvoicer{&callLog{}, nil},
+					OnStatusChange(func(Order, OrderStatus) { fired++ }))
+				if err != nil {
+					t.Fatal(err)
+				}
+				moved, err := orders.Transition(ctx, 1, to)
+
+				wantStored := from
+				if want {
+					wantStored = to
+					if err != nil || moved.Status != to || fired != 1 {
+						t.Errorf("Transition = %s, %v with %d hooks; want %s", moved.Status, err, fired, to)
+					}
+				} else if !errors.Is(err, ErrInvalidTransition) || fired != 0 {
+					t.Errorf("Transition = %v with %d hooks; want ErrInvalidTransition", err, fired)
+				}
+				if stored, _ := repo.FindByID(ctx, 1); stored.Status != wantStored {
+					t.Errorf("stored as %s, want %s", stored.Status, wantStored)
+				}
+			})
+		}
+	}
+}
+
+// Cancelled and Refunded are final: nothing leaves them.
+func TestOrderTransitions_FinalStatuses(t *testing.T) {
+	for _, final := range []OrderStatus{StatusCancelled, StatusRefunded} {
+		if next := orderTransitions[final]; len(next) != 0 {
+			t.Errorf("%s can move to %v", final, next)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package singleresponsibility
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TaxLine is one tax charged on an invoice.
+type TaxLine struct {
+	Name   string
+	Amount Money
+}
+
+// TaxRule computes the tax of one jurisdiction. Supporting a new
+// jurisdiction means adding a rule, not editing TaxService.
+type TaxRule interface {
+	// Applies reports whether the rule governs orders billed to addr.
+	Applies(addr Address) bool
+	// Tax returns the tax owed on lines under this rule. Their
+	// amounts are net of the order's discount.
+	Tax(lines []PriceLine) TaxLine
+}
+
+// TaxService is responsible only for computing order tax.
+type TaxService struct {
+	rules []TaxRule
+}
+
+func NewTaxService(rules ...TaxRule) *TaxService {
+	return &TaxService{rules: rules}
+}
+
+// Calculate returns one line per rule that applies to addr, in the
+// order the rules were registered. Rules that yield no tax are left
+// out.
+func (s *TaxService) Calculate(addr Address, lines []PriceLine) []TaxLine {
+	var taxes []TaxLine
+	for _, rule := range s.rules {
+		if !rule.Applies(addr) {
+			continue
+		}
+		if tax := rule.Tax(lines); !tax.Amount.IsZero() {
+			taxes = append(taxes, tax)
+		}
+	}
+	return taxes
+}
+
+// VATRule charges a country-wide value-added tax. SKUs listed in
+// Reduced are taxed at ReducedRate instead of Rate.
+type VATRule struct {
+	Country     string
+	Rate        float64
+	ReducedRate float64
+	Reduced     map[string]bool
+}
+
+func (r VATRule) Applies(addr Address) bool {
+	return strings.EqualFold(addr.Country, r.Country)
+}
+
+func (r VATRule) Tax(lines []PriceLine) TaxLine {
+	tax := NewMoney(0, linesCurrency(lines))
+	for _, line := range lines {
+		rate := r.Rate
+		if r.Reduced[line.SKU] {
+			rate = r.ReducedRate
+		}
+		// NewOrder guarantees a single currency, so Add cannot fail.
+		tax, _ = tax.Add(line.Amount.MulRate(rate))
+	}
+	return TaxLine{Name: fmt.Sprintf("VAT %s", strings.ToUpper(r.Country)), Amount: tax}
+}
+
+// SalesTaxRule charges a local sales tax in the part of a country
+// whose postal codes start with PostalPrefix.
+type SalesTaxRule struct {
+	Name         string
+	Country      string
+	PostalPrefix string
+	Rate         float64
+}
+
+func (r SalesTaxRule) Applies(addr Address) bool {
+	return strings.EqualFold(addr.Country, r.Country) && strings.HasPrefix(addr.PostalCode, r.PostalPrefix)
+}
+
+func (r SalesTaxRule) Tax(lines []PriceLine) TaxLine {
+	return TaxLine{
+		Name:   fmt.Sprintf("%s (%.2f%%)", r.Name, r.Rate*100),
+		Amount: linesTotal(lines).MulRate(r.Rate),
+	}
+}
@@ -0,0 +1,64 @@
+package singleresponsibility
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTaxService_Calculate(t *testing.T) {
+	taxes := NewTaxService(
+		VATRule{Country: "DE", Rate: 0.19, ReducedRate: 0.07, Reduced: map[string]bool{"BOOK": true}},
+		// Food is exempt in this jurisdiction.
+		VATRule{Country: "FR", Rate: 0.20, Reduced: map[string]bool{"FOOD": true}},
+		VATRule{Country: "DK", Rate: 0.25},
+		SalesTaxRule{Name: "NY State", Country: "US", PostalPrefix: "1", Rate: 0.04},
+		SalesTaxRule{Name: "NYC", Country: "US", PostalPrefix: "100", Rate: 0.045},
+	)
+	line := func(sku string, amount int64, currency Currency) PriceLine {
+		return PriceLine{SKU: sku, Quantity: 1, UnitPrice: NewMoney(amount, currency), Amount: NewMoney(amount, currency)}
+	}
+	tax := func(name string, amount int64, currency Currency) TaxLine {
+		return TaxLine{Name: name, Amount: NewMoney(amount, currency)}
+	}
+
+	tests := []struct {
+		name  string
+		addr  Address
+		lines []PriceLine
+		want  []TaxLine
+	}{
+		// 5.97 at 19% is 1.1343.
+		{"standard rate", Address{Country: "DE"}, []PriceLine{line("PEN", 597, "EUR")},
+			[]TaxLine{tax("VAT DE", 113, "EUR")}},
+		{"reduced rate", Address{Country: "DE"}, []PriceLine{line("BOOK", 2500, "EUR")},
+			[]TaxLine{tax("VAT DE", 175, "EUR")}},
+		{"mixed rates", Address{Country: "DE"}, []PriceLine{line("BOOK", 2500, "EUR"), line("PEN", 597, "EUR")},
+			[]TaxLine{tax("VAT DE", 175+113, "EUR")}},
+		{"country is case-insensitive", Address{Country: "de"}, []PriceLine{line("PEN", 597, "EUR")},
+			[]TaxLine{tax("VAT DE", 113, "EUR")}},
+		{"exempt item", Address{Country: "FR"}, []PriceLine{line("FOOD", 1000, "EUR"), line("PEN", 500, "EUR")},
+			[]TaxLine{tax("VAT FR", 100, "EUR")}},
+		// A rule yielding no tax adds no line.
+		{"only exempt items", Address{Country: "FR"}, []PriceLine{line("FOOD", 1000, "EUR")}, nil},
+		// 25% of 0.02 is half a cent, rounded away from zero.
+		{"rounds half away from zero", Address{Country: "DK"}, []PriceLine{line("PEN", 2, "EUR")},
+			[]TaxLine{tax("VAT DK", 1, "EUR")}},
+		// VAT rounds each line: 1 + 1, not 25% of 0.04.
+		{"rounds per line", Address{Country: "DK"}, []PriceLine{line("PEN", 2, "EUR"), line("INK", 2, "EUR")},
+			[]TaxLine{tax("VAT DK", 2, "EUR")}},
+		// Sales tax rounds the total: 4% of 0.26, not 1 + 1.
+		{"sales tax rounds the total", Address{Country: "US", PostalCode: "12000"}, []PriceLine{line("PEN", 13, "USD"), line("INK", 13, "USD")},
+			[]TaxLine{tax("NY State (4.00%)", 1, "USD")}},
+		{"stacked rules in registration order", Address{Country: "US", PostalCode: "10001"}, []PriceLine{line("BOOK", 10000, "USD")},
+			[]TaxLine{tax("NY State (4.00%)", 400, "USD"), tax("NYC (4.50%)", 450, "USD")}},
+		{"outside every postal prefix", Address{Country: "US", PostalCode: "90210"}, []PriceLine{line("BOOK", 10000, "USD")}, nil},
+		{"no rule for the country", Address{Country: "JP"}, []PriceLine{line("BOOK", 10000, "USD")}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := taxes.Calculate(tt.addr, tt.lines); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Calculate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
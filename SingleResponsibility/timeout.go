@@ -0,0 +1,63 @@
+package singleresponsibility
+
+import (
+	"context"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/timeout"
+)
+
+// TimeoutPaymentGateway is a PaymentGateway decorator that gives up on
+// a call after a fixed time with a *timeout.Error, which matches
+// timeout.ErrDeadlineExceeded. A charge that times out may still
+// succeed at the provider; only the caller stops waiting.
+type TimeoutPaymentGateway struct {
+	next    PaymentGateway
+	timeout time.Duration
+	clock   clock.Clock
+}
+
+func NewTimeoutPaymentGateway(next PaymentGateway, d time.Duration, clk clock.Clock) *TimeoutPaymentGateway {
+	return &TimeoutPaymentGateway{next: next, timeout: d, clock: clk}
+}
+
+func (g *TimeoutPaymentGateway) Charge(ctx context.Context, orderID int, amount Money) (string, error) {
+	return timeout.Value(ctx, g.clock, "payment.charge", g.timeout, func(ctx context.Context) (string, error) {
+		return g.next.Charge(ctx, orderID, amount)
+	})
+}
+
+func (g *TimeoutPaymentGateway) Refund(ctx context.Context, paymentID string) error {
+	return timeout.Do(ctx, g.clock, "payment.refund", g.timeout, func(ctx context.Context) error {
+		return g.next.Refund(ctx, paymentID)
+	})
+}
+
+func (g *TimeoutPaymentGateway) Void(ctx context.Context, paymentID string) error {
+	voider, ok := g.next.(PaymentVoider)
+	if !ok {
+		return ErrVoidUnsupported
+	}
+	return timeout.Do(ctx, g.clock, "payment.void", g.timeout, func(ctx context.Context) error {
+		return voider.Void(ctx, paymentID)
+	})
+}
+
+// TimeoutEmailSender is an EmailSender decorator that gives up on a
+// send after a fixed time.
+type TimeoutEmailSender struct {
+	next    EmailSender
+	timeout time.Duration
+	clock   clock.Clock
+}
+
+func NewTimeoutEmailSender(next EmailSender, d time.Duration, clk clock.Clock) *TimeoutEmailSender {
+	return &TimeoutEmailSender{next: next, timeout: d, clock: clk}
+}
+
+func (s *TimeoutEmailSender) Send(ctx context.Context, msg EmailMessage) error {
+	return timeout.Do(ctx, s.clock, "email.send", s.timeout, func(ctx context.Context) error {
+		return s.next.Send(ctx, msg)
+	})
+}
@@ -0,0 +1,98 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/timeout"
+)
+
+// hangingGateway never answers until the call is cancelled.
+type hangingGateway struct{}
+
+func (hangingGateway) Charge(ctx context.Context, orderID int, amount Money) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func (hangingGateway) Refund(ctx context.Context, paymentID string) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestTimeoutPaymentGateway_PlaceOrder(t *testing.T) {
+	clk := clocktest.NewFake(time.Time{})
+	payment := NewTimeoutPaymentGateway(hangingGateway{}, 5*time.Second, clk)
+	repo := NewInMemoryOrderRepository()
+	invoices := NewInvoiceService(TextInvoiceRenderer{}, NewPricingService(nil), nil)
+	orders, err := NewOrderService(repo, payment, NewLoggingEmailSender(nil), invoices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, err := NewOrder(1, 1, []OrderItem{{SKU: "BOOK", Quantity: 1, UnitPrice: NewMoney(1250, "USD")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errc := make(chan error)
+	go func() {
+		_, err := orders.PlaceOrder(context.Background(), "", order)
+		errc <- err
+	}()
+	clk.BlockUntil(1)
+	clk.Advance(5 * time.Second)
+
+	err = <-errc
+	if !errors.Is(err, timeout.ErrDeadlineExceeded) {
+		t.Fatalf("err = %v, want ErrDeadlineExceeded", err)
+	}
+	if got := statusFor(err); got != http.StatusGatewayTimeout {
+		t.Errorf("statusFor = %d, want 504", got)
+	}
+	if _, err := repo.FindByID(context.Background(), 1); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("timed-out order was kept: %v", err)
+	}
+}
+
+func TestTimeoutEmailSender(t *testing.T) {
+	clk := clocktest.NewFake(time.Time{})
+	hanging := emailSenderFunc(func(ctx context.Context, msg EmailMessage) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	sender := NewTimeoutEmailSender(hanging, time.Second, clk)
+
+	errc := make(chan error)
+	go func() { errc <- sender.Send(context.Background(), EmailMessage{To: "a@example.com"}) }()
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+
+	var te *timeout.Error
+	if err := <-errc; !errors.As(err, &te) || te.Op != "email.send" {
+		t.Errorf("err = %v, want a timeout of email.send", err)
+	}
+}
+
+type emailSenderFunc func(ctx context.Context, msg EmailMessage) error
+
+func (f emailSenderFunc) Send(ctx context.Context, msg EmailMessage) error { return f(ctx, msg) }
+
+func TestLoadConfig_Timeouts(t *testing.T) {
+	env := map[string]string{"ORDERS_PAYMENT_TIMEOUT": "1.5s", "ORDERS_EMAIL_TIMEOUT": "250ms"}
+	cfg, err := LoadConfig(func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Duration(cfg.PaymentTimeout) != 1500*time.Millisecond || time.Duration(cfg.EmailTimeout) != 250*time.Millisecond {
+		t.Errorf("timeouts = %v, %v", cfg.PaymentTimeout, cfg.EmailTimeout)
+	}
+
+	env["ORDERS_EMAIL_TIMEOUT"] = "soon"
+	if _, err := LoadConfig(func(k string) string { return env[k] }); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("err = %v, want ErrInvalidConfig", err)
+	}
+}
@@ -0,0 +1,31 @@
+package singleresponsibility
+
+import (
+	"context"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// logSpanExporter is an OpenTelemetry span exporter that writes each
+// finished span to a Logger, with the IDs that tie it to its trace and
+// parent. A deployment with a collector exports through the
+// collector's exporter instead; nothing else changes.
+type logSpanExporter struct {
+	log Logger
+}
+
+func (e logSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	for _, s := range spans {
+		parent := "-"
+		if s.Parent().IsValid() {
+			parent = s.Parent().SpanID().String()
+		}
+		logf(ctx, e.log, "Trace %s: span %s (parent %s) %s took %s",
+			s.SpanContext().TraceID(), s.SpanContext().SpanID(), parent, s.Name(), s.EndTime().Sub(s.StartTime()))
+	}
+	return nil
+}
+
+func (e logSpanExporter) Shutdown(context.Context) error {
+	return nil
+}
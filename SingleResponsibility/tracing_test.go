@@ -0,0 +1,53 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// With tracing "otel", the orders and the reports are traced, and
+// Services.Close exports the spans still batched.
+func TestWire_Tracing(t *testing.T) {
+	ctx := context.Background()
+	cfg := DefaultConfig()
+	cfg.Tracing = "zipkin"
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Validate = %v, want ErrInvalidConfig", err)
+	}
+
+	cfg.Tracing = "otel"
+	cfg.ReportSchedule = "@daily"
+	cfg.StorageDir = t.TempDir()
+	log := &CapturingLogger{}
+	services, err := Wire(ctx, cfg, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := services.Orders.PlaceOrder(ctx, "", testOrder(t, 1)); err != nil {
+		t.Fatal(err)
+	}
+	for _, job := range services.Jobs {
+		if job.Name == "order report" {
+			if err := job.Job.Run(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := services.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := map[string]bool{}
+	for _, line := range log.Lines() {
+		for _, name := range []string{"OrderService.PlaceOrder", "OrderReport.Run"} {
+			if strings.HasPrefix(line, "Trace ") && strings.Contains(line, " "+name+" took ") {
+				spans[name] = true
+			}
+		}
+	}
+	if len(spans) != 2 {
+		t.Errorf("exported spans %v, want PlaceOrder and the report; log:\n%s", spans, strings.Join(log.Lines(), "\n"))
+	}
+}
@@ -0,0 +1,178 @@
+package singleresponsibility
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrNoStore = errors.New("unit of work has no such store")
+
+// Tx is a unit of work's view of the stores. Writes made through it
+// land together when the unit of work commits, or not at all.
+type Tx interface {
+	SaveOrder(ctx context.Context, order Order) error
+	AppendAudit(ctx context.Context, entry AuditEntry) error
+	Enqueue(ctx context.Context, msg OutboxMessage) error
+}
+
+// UnitOfWork groups writes to several stores. Do commits the writes fn
+// makes through tx if fn returns nil and discards all of them
+// otherwise. Which stores take part, and how atomicity is achieved, is
+// up to the implementation; OrderService only sees Tx.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(tx Tx) error) error
+}
+
+// WithUnitOfWork has the service mark an order Paid, record it in the
+// audit trail and, with an outbox, enqueue its confirmation as one
+// unit of work. uow must write to the stores the service reads from.
+func WithUnitOfWork(uow UnitOfWork) OrderOption {
+	return func(os *OrderService) { os.uow = uow }
+}
+
+// InMemoryUnitOfWork is the UnitOfWork of the in-memory stores. Writes
+// are buffered until fn returns and then applied while holding every
+// store's lock, so readers never see half of a unit of work. audit and
+// outbox may be nil; writing to a missing store fails with ErrNoStore.
+type InMemoryUnitOfWork struct {
+	orders *InMemoryOrderRepository
+	audit  *InMemoryAuditStore
+	outbox *InMemoryOutbox
+}
+
+func NewInMemoryUnitOfWork(orders *InMemoryOrderRepository, audit *InMemoryAuditStore, outbox *InMemoryOutbox) *InMemoryUnitOfWork {
+	return &InMemoryUnitOfWork{orders: orders, audit: audit, outbox: outbox}
+}
+
+func (u *InMemoryUnitOfWork) Do(ctx context.Context, fn func(tx Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	tx := &memTx{uow: u}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return u.commit(tx)
+}
+
+// commit applies tx, or none of it if an order cannot be saved. Locks are taken outbox, orders, audit: the order
+// InMemoryOutbox.SaveWithMessage uses, so the two cannot deadlock.
+func (u *InMemoryUnitOfWork) commit(tx *memTx) error {
+	if u.outbox != nil {
+		u.outbox.mu.Lock()
+		defer u.outbox.mu.Unlock()
+	}
+	u.orders.mu.Lock()
+	defer u.orders.mu.Unlock()
+	if u.audit != nil {
+		u.audit.mu.Lock()
+		defer u.audit.mu.Unlock()
+	}
+
+	for _, order := range tx.orders {
+		if err := u.orders.check(order); err != nil {
+			return err
+		}
+	}
+	for _, order := range tx.orders {
+		u.orders.put(order)
+	}
+	// memTx refuses writes to a missing store, so there is nothing to
+	// apply to it.
+	if u.audit != nil {
+		u.audit.entries = append(u.audit.entries, tx.entries...)
+	}
+	if u.outbox != nil {
+		for _, msg := range tx.messages {
+			u.outbox.seq++
+			msg.ID = u.outbox.seq
+			u.outbox.messages = append(u.outbox.messages, msg)
+		}
+	}
+	return nil
+}
+
+// memTx buffers the writes of one InMemoryUnitOfWork.Do.
+type memTx struct {
+	uow      *InMemoryUnitOfWork
+	orders   []Order
+	entries  []AuditEntry
+	messages []OutboxMessage
+}
+
+func (tx *memTx) SaveOrder(ctx context.Context, order Order) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if order.ID <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidOrderID, order.ID)
+	}
+	tx.orders = append(tx.orders, cloneOrder(order))
+	return nil
+}
+
+func (tx *memTx) AppendAudit(ctx context.Context, entry AuditEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if tx.uow.audit == nil {
+		return fmt.Errorf("%w: audit", ErrNoStore)
+	}
+	tx.entries = append(tx.entries, entry)
+	return nil
+}
+
+func (tx *memTx) Enqueue(ctx context.Context, msg OutboxMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if tx.uow.outbox == nil {
+		return fmt.Errorf("%w: outbox", ErrNoStore)
+	}
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now()
+	}
+	tx.messages = append(tx.messages, msg)
+	return nil
+}
+
+// SQLUnitOfWork is the UnitOfWork of the SQL stores: the orders,
+// audit entries and outbox messages fn writes share one database
+// transaction. It must use the *sql.DB of SQLOrderRepository,
+// SQLAuditStore and SQLOutbox.
+type SQLUnitOfWork struct {
+	db *sql.DB
+}
+
+func NewSQLUnitOfWork(db *sql.DB) *SQLUnitOfWork {
+	return &SQLUnitOfWork{db: db}
+}
+
+// Do runs fn in a database transaction.
+func (u *SQLUnitOfWork) Do(ctx context.Context, fn func(tx Tx) error) error {
+	return inTx(ctx, u.db, func(tx *sql.Tx) error { return fn(sqlTx{tx: tx}) })
+}
+
+// sqlTx writes straight into the database transaction; rolling it back
+// discards the writes.
+type sqlTx struct {
+	tx *sql.Tx
+}
+
+func (t sqlTx) SaveOrder(ctx context.Context, order Order) error {
+	return saveOrder(ctx, t.tx, order)
+}
+
+func (t sqlTx) AppendAudit(ctx context.Context, entry AuditEntry) error {
+	return appendAudit(ctx, t.tx, entry)
+}
+
+func (t sqlTx) Enqueue(ctx context.Context, msg OutboxMessage) error {
+	return enqueue(ctx, t.tx, msg)
+}
@@ -0,0 +1,79 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// The audit store and the outbox are optional: without them the
+// orders still commit, and writes to them fail with ErrNoStore.
+func TestInMemoryUnitOfWork_MissingStores(t *testing.T) {
+	ctx := context.Background()
+	orders := NewInMemoryOrderRepository()
+	audit := NewInMemoryAuditStore()
+	outbox := NewInMemoryOutbox(orders)
+	tests := []struct {
+		name   string
+		uow    *InMemoryUnitOfWork
+		audit  bool
+		outbox bool
+	}{
+		{"orders only", NewInMemoryUnitOfWork(orders, nil, nil), false, false},
+		{"no outbox", NewInMemoryUnitOfWork(orders, audit, nil), true, false},
+		{"no audit", NewInMemoryUnitOfWork(orders, nil, outbox), false, true},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := i + 1
+			err := tt.uow.Do(ctx, func(tx Tx) error {
+				if err := tx.SaveOrder(ctx, testOrder(t, id)); err != nil {
+					return err
+				}
+				err := tx.AppendAudit(ctx, AuditEntry{Actor: "test", Action: AuditOrderSaved, OrderID: id, At: time.Now()})
+				if tt.audit != (err == nil) || (err != nil && !errors.Is(err, ErrNoStore)) {
+					t.Errorf("AppendAudit = %v", err)
+				}
+				err = tx.Enqueue(ctx, OutboxMessage{OrderID: id, Kind: OutboxOrderConfirmation})
+				if tt.outbox != (err == nil) || (err != nil && !errors.Is(err, ErrNoStore)) {
+					t.Errorf("Enqueue = %v", err)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Do = %v", err)
+			}
+
+			if _, err := orders.FindByID(ctx, id); err != nil {
+				t.Errorf("order not committed: %v", err)
+			}
+			if entries, _ := audit.ByOrder(ctx, id); (len(entries) == 1) != tt.audit {
+				t.Errorf("audit entries %+v", entries)
+			}
+			pending, _ := outbox.Pending(ctx, 10)
+			var queued bool
+			for _, msg := range pending {
+				queued = queued || msg.OrderID == id
+			}
+			if queued != tt.outbox {
+				t.Errorf("outbox %+v", pending)
+			}
+		})
+	}
+}
+
+// OrderService runs with a unit of work that has neither an audit
+// store nor an outbox.
+func TestOrderService_UnitOfWorkWithoutOptionalStores(t *testing.T) {
+	repo := NewInMemoryOrderRepository()
+	orders, err := NewOrderService(repo, NewFakeStripeGateway(NewMoney(10000, "USD"), nil), NewLoggingEmailSender(nil),
+		NewInvoiceService(TextInvoiceRenderer{}, NewPricingService(nil), nil), WithUnitOfWork(NewInMemoryUnitOfWork(repo, nil, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	placed, err := orders.PlaceOrder(context.Background(), "", testOrder(t, 1))
+	if err != nil || placed.Status != StatusInvoiced {
+		t.Fatalf("PlaceOrder = %+v, %v", placed, err)
+	}
+}
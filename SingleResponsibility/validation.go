@@ -0,0 +1,98 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Rule checks one property of an incoming order. Rules only inspect
+// the order; they never cause side effects, so OrderService can run
+// them before anything is saved or charged.
+type Rule interface {
+	Check(ctx context.Context, order Order) error
+}
+
+// RuleFunc adapts an ordinary function to a Rule.
+type RuleFunc func(ctx context.Context, order Order) error
+
+func (f RuleFunc) Check(ctx context.Context, order Order) error {
+	return f(ctx, order)
+}
+
+// And passes when every rule passes. It runs all of them and joins
+// their errors, so a rejected order reports every problem at once.
+func And(rules ...Rule) Rule {
+	return RuleFunc(func(ctx context.Context, order Order) error {
+		var errs []error
+		for _, rule := range rules {
+			if err := rule.Check(ctx, order); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// Or passes when any rule passes, trying them in order. If none does,
+// it joins all their errors.
+func Or(rules ...Rule) Rule {
+	return RuleFunc(func(ctx context.Context, order Order) error {
+		var errs []error
+		for _, rule := range rules {
+			err := rule.Check(ctx, order)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// NonEmptyItems rejects orders without items.
+func NonEmptyItems() Rule {
+	return RuleFunc(func(_ context.Context, order Order) error {
+		if len(order.Items) == 0 {
+			return ErrNoItems
+		}
+		return nil
+	})
+}
+
+// PositiveAmount rejects orders whose items or total would not be a
+// positive charge.
+func PositiveAmount() Rule {
+	return RuleFunc(func(_ context.Context, order Order) error {
+		for i, item := range order.Items {
+			if item.Quantity <= 0 || item.UnitPrice.IsNegative() {
+				return fmt.Errorf("%w: item %d (%+v)", ErrInvalidItem, i, item)
+			}
+		}
+		if !order.Total.IsPositive() {
+			return fmt.Errorf("%w: total %s", ErrInvalidAmount, order.Total)
+		}
+		return nil
+	})
+}
+
+// KnownCustomer rejects orders for customers missing from customers.
+func KnownCustomer(customers CustomerRepository) Rule {
+	return RuleFunc(func(ctx context.Context, order Order) error {
+		_, err := customers.FindByID(ctx, order.CustomerID)
+		return err
+	})
+}
+
+// DefaultOrderRules are the checks every order should pass whatever
+// the deployment.
+func DefaultOrderRules() Rule {
+	return And(NonEmptyItems(), PositiveAmount())
+}
+
+// WithValidation has the service check each order against rule before
+// any side effect runs. The error lists every failed check; errors.Is
+// still finds each one's sentinel.
+func WithValidation(rule Rule) OrderOption {
+	return func(os *OrderService) { os.validation = rule }
+}
@@ -0,0 +1,103 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRules(t *testing.T) {
+	ctx := context.Background()
+	customers := NewInMemoryCustomerRepository()
+	if err := customers.Save(ctx, Customer{ID: 1, Name: "Ada"}); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := testOrder(t, 1)
+	noItems := valid
+	noItems.Items = nil
+	badItem := valid
+	badItem.Items = []OrderItem{{SKU: "BOOK", Quantity: 0, UnitPrice: NewMoney(1250, "USD")}}
+	free := valid
+	free.Total = NewMoney(0, "USD")
+	stranger := valid
+	stranger.CustomerID = 2
+
+	tests := []struct {
+		name  string
+		rule  Rule
+		order Order
+		want  []error // every sentinel the error must wrap; none means it passes
+	}{
+		{"items present", NonEmptyItems(), valid, nil},
+		{"no items", NonEmptyItems(), noItems, []error{ErrNoItems}},
+		{"positive amount", PositiveAmount(), valid, nil},
+		{"zero quantity", PositiveAmount(), badItem, []error{ErrInvalidItem}},
+		{"zero total", PositiveAmount(), free, []error{ErrInvalidAmount}},
+		{"known customer", KnownCustomer(customers), valid, nil},
+		{"unknown customer", KnownCustomer(customers), stranger, []error{ErrCustomerNotFound}},
+
+		{"and passes", And(NonEmptyItems(), KnownCustomer(customers)), valid, nil},
+		// And reports every failed rule, not just the first.
+		{"and joins errors", And(NonEmptyItems(), PositiveAmount(), KnownCustomer(customers)), Order{CustomerID: 2},
+			[]error{ErrNoItems, ErrInvalidAmount, ErrCustomerNotFound}},
+		{"or passes on any", Or(KnownCustomer(customers), NonEmptyItems()), stranger, nil},
+		{"or joins errors", Or(NonEmptyItems(), KnownCustomer(customers)), Order{CustomerID: 2},
+			[]error{ErrNoItems, ErrCustomerNotFound}},
+		{"empty and", And(), noItems, nil},
+		{"empty or", Or(), noItems, nil},
+		{"default rules", DefaultOrderRules(), free, []error{ErrInvalidAmount}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Check(ctx, tt.order)
+			if len(tt.want) == 0 && err != nil {
+				t.Fatalf("Check = %v, want it to pass", err)
+			}
+			if len(tt.want) > 0 && err == nil {
+				t.Fatalf("Check passed, want %v", tt.want)
+			}
+			for _, want := range tt.want {
+				if !errors.Is(err, want) {
+					t.Errorf("Check = %v, want it to wrap %v", err, want)
+				}
+			}
+		})
+	}
+}
+
+// Or stops at the first rule that passes; And always runs them all.
+func TestRules_ShortCircuit(t *testing.T) {
+	var ran []string
+	rule := func(name string, err error) Rule {
+		return RuleFunc(func(context.Context, Order) error {
+			ran = append(ran, name)
+			return err
+		})
+	}
+	errFail := errors.New("fail")
+
+	tests := []struct {
+		name string
+		rule Rule
+		want string
+	}{
+		{"or stops at a pass", Or(rule("a", errFail), rule("b", nil), rule("c", nil)), "ab"},
+		{"or tries them all", Or(rule("a", errFail), rule("b", errFail)), "ab"},
+		{"and runs past a failure", And(rule("a", errFail), rule("b", nil), rule("c", errFail)), "abc"},
+		{"nested", And(Or(rule("a", nil), rule("b", nil)), rule("c", nil)), "ac"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran = nil
+			tt.rule.Check(context.Background(), Order{})
+			got := ""
+			for _, name := range ran {
+				got += name
+			}
+			if got != tt.want {
+				t.Errorf("ran %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package singleresponsibility
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/validate"
+)
+
+// The payment events a gateway calls back with.
+const (
+	PaymentEventCaptured = "payment.captured"
+	PaymentEventFailed   = "payment.failed"
+)
+
+// PaymentEvent is a payment gateway's callback about an order.
+type PaymentEvent struct {
+	ID        string `json:"id"` // unique per event; retries repeat it
+	Type      string `json:"type"`
+	OrderID   int    `json:"order_id"`
+	PaymentID string `json:"payment_id"`
+}
+
+// PaymentEventHandler is what PaymentWebhook needs from the service
+// layer. OrderService satisfies it.
+type PaymentEventHandler interface {
+	CapturePayment(ctx context.Context, orderID int, paymentID string) (Order, error)
+	FailPayment(ctx context.Context, orderID int, paymentID string) (Order, error)
+}
+
+// PaymentWebhook turns verified payment events into order status
+// changes: a capture marks the order Paid, a failure cancels it if it
+// is still pending. It only parses and dispatches; the signature was
+// checked before (see webhook.Handler) and the rules live in
+// OrderService.
+//
+// Gateways deliver at least once, so each event ID is processed once:
+// a repeated event is acknowledged without doing anything.
+type PaymentWebhook struct {
+	orders PaymentEventHandler
+	seen   IdempotencyStore
+	log    Logger
+}
+
+// NewPaymentWebhook returns a webhook remembering event IDs in seen.
+func NewPaymentWebhook(orders PaymentEventHandler, seen IdempotencyStore, log Logger) *PaymentWebhook {
+	return &PaymentWebhook{orders: orders, seen: seen, log: orNop(log)}
+}
+
+// Handle processes the payment event in body.
+func (h *PaymentWebhook) Handle(ctx context.Context, body []byte) (err error) {
+	var event PaymentEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return fmt.Errorf("%w: payment event: %w", validate.ErrMalformed, err)
+	}
+	if event.ID == "" || event.OrderID <= 0 {
+		return fmt.Errorf("%w: payment event without an id or order_id", validate.ErrInvalid)
+	}
+	ctx = WithOrderID(ctx, event.OrderID)
+
+	key := "webhook:" + event.ID
+	_, done, err := h.seen.Begin(ctx, key)
+	if err != nil {
+		return err
+	}
+	if done {
+		logf(ctx, h.log, "Payment event %s already processed", event.ID)
+		return nil
+	}
+	defer func() {
+		if err != nil {
+			// Let the gateway's retry through.
+			err = errors.Join(err, h.seen.Release(context.WithoutCancel(ctx), key))
+			return
+		}
+		err = h.seen.Complete(ctx, key, event.OrderID)
+	}()
+
+	switch event.Type {
+	case PaymentEventCaptured:
+		if event.PaymentID == "" {
+			return fmt.Errorf("%w: %s event %s without a payment_id", validate.ErrInvalid, event.Type, event.ID)
+		}
+		_, err = h.orders.CapturePayment(ctx, event.OrderID, event.PaymentID)
+	case PaymentEventFailed:
+		_, err = h.orders.FailPayment(ctx, event.OrderID, event.PaymentID)
+	default:
+		// Acknowledged, so the gateway stops sending what we ignore.
+		logf(ctx, h.log, "Payment event %s of type %q ignored", event.ID, event.Type)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("payment event %s: %w", event.ID, err)
+	}
+	logf(ctx, h.log, "Payment event %s (%s) applied", event.ID, event.Type)
+	return nil
+}
+
+// CapturePayment marks a pending order Paid when the gateway reports,
+// asynchronously, that paymentID was captured, then fulfils it like
+// PlaceOrder does. A capture already applied is not an error: the
+// order is returned as it is, after finishing its fulfilment if an
+// earlier delivery of the capture failed halfway through it.
+//
+// Orders PlaceOrder charges itself are returned as they are, even
+// while PlaceOrder is still working on them. A capture that races with
+// another change of the order fails with ErrOrderChanged, and the
+// gateway's retry sees the change.
+func (os OrderService) CapturePayment(ctx context.Context, orderID int, paymentID string) (Order, error) {
+	ctx = WithOrderID(ctx, orderID)
+	order, err := os.repo.FindByID(ctx, orderID)
+	if err != nil {
+		return Order{}, err
+	}
+	if order.SyncPayment {
+		logf(ctx, os.logger(), "Order %d is paid by PlaceOrder; capture of %q ignored", order.ID, paymentID)
+		return order, nil
+	}
+	resuming := order.PaymentID == paymentID && order.Status == StatusPaid
+	if order.PaymentID == paymentID && order.Status != StatusPending && !resuming {
+		return order, nil
+	}
+	if order.Status != StatusPending && !resuming {
+		return Order{}, fmt.Errorf("%w: order %d is already %s", ErrInvalidTransition, order.ID, order.Status)
+	}
+	customer, err := resolveCustomer(ctx, os.customers, order)
+	if err != nil {
+		return Order{}, err
+	}
+
+	if !resuming {
+		order.PaymentID = paymentID
+		if err := os.markPaid(ctx, &order); err != nil {
+			return Order{}, err
+		}
+		os.record(ctx, AuditPaymentCharged, order.ID, fmt.Sprintf("%s (%s) captured", order.Total, paymentID))
+		os.publish(ctx, PaymentCaptured{OrderID: order.ID, PaymentID: paymentID, Amount: order.Total})
+	}
+	if !os.eventDriven {
+		// A failure leaves the order Paid; the gateway's retry of the
+		// capture resumes here.
+		if err := os.fulfil(ctx, customer, &order); err != nil {
+			return Order{}, err
+		}
+	}
+	logf(ctx, os.logger(), "Order %d paid by capture %s", order.ID, paymentID)
+	return order, nil
+}
+
+// FailPayment cancels a pending order when the gateway reports that
+// paymentID failed. Only a failure of the order's own payment counts:
+// an order that is no longer pending, or that records another payment,
+// is returned as it is, and so is an order PlaceOrder charges itself.
+// A stale or replayed failure must never give back money that was
+// captured.
+func (os OrderService) FailPayment(ctx context.Context, orderID int, paymentID string) (Order, error) {
+	ctx = WithOrderID(ctx, orderID)
+	order, err := os.repo.FindByID(ctx, orderID)
+	if err != nil {
+		return Order{}, err
+	}
+	if order.SyncPayment {
+		logf(ctx, os.logger(), "Order %d is paid by PlaceOrder; failure of %q ignored", order.ID, paymentID)
+		return order, nil
+	}
+	if order.Status != StatusPending || (order.PaymentID != "" && order.PaymentID != paymentID) {
+		logf(ctx, os.logger(), "Order %d is %s with payment %q; failure of %q ignored", order.ID, order.Status, order.PaymentID, paymentID)
+		return order, nil
+	}
+	return os.CancelOrder(ctx, orderID)
+}
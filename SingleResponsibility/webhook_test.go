@@ -0,0 +1,224 @@
+package singleresponsibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/webhook"
+)
+
+func TestPaymentWebhook(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryOrderRepository()
+	for id := 1; id <= 2; id++ {
+		if err := repo.Save(ctx, testOrder(t, id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	invoices := 0
+	orders, err := NewOrderService(repo, NewFakeStripeGateway(NewMoney(0, "USD"), nil), NewLoggingEmailSender(nil),
+		invoiceFunc(func(ctx context.Context, customer Customer, order Order) ([]byte, error) {
+			invoices++
+			return []byte("invoice"), nil
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clk := clocktest.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	gateway := webhook.NewHMAC([]byte("whsec_test"), time.Minute, clk)
+	handler := webhook.Handler(webhook.NewHMAC([]byte("whsec_test"), time.Minute, clk), maxRequestBody,
+		NewPaymentWebhook(orders, NewInMemoryIdempotencyStore(), nil).Handle, writeError)
+	post := func(body, signature string) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/payments", strings.NewReader(body))
+		req.Header.Set(webhook.SignatureHeader, signature)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	event := func(id, typ string, orderID int) string {
+		return fmt.Sprintf(`{"id":%q,"type":%q,"order_id":%d,"payment_id":"ch_async"}`, id, typ, orderID)
+	}
+
+	captured := event("evt_1", PaymentEventCaptured, 1)
+	signature := gateway.Sign([]byte(captured))
+	if code := post(captured, signature); code != http.StatusNoContent {
+		t.Fatalf("capture: status %d", code)
+	}
+	// The gateway retries: the same event is acknowledged, not reapplied.
+	if code := post(captured, gateway.Sign([]byte(captured))); code != http.StatusNoContent {
+		t.Errorf("repeated capture: status %d", code)
+	}
+	if order, _ := repo.FindByID(ctx, 1); order.Status != StatusInvoiced || order.PaymentID != "ch_async" || invoices != 1 {
+		t.Errorf("order 1 is %s with payment %q, %d invoices; want invoiced once with ch_async", order.Status, order.PaymentID, invoices)
+	}
+
+	failed := event("evt_2", PaymentEventFailed, 2)
+	for name, tt := range map[string]struct {
+		body, signature string
+		status          int
+	}{
+		"forged":    {failed, webhook.NewHMAC([]byte("guess"), 0, clk).Sign([]byte(failed)), http.StatusUnauthorized},
+		"swapped":   {failed, signature, http.StatusUnauthorized},
+		"unsigned":  {failed, "", http.StatusUnauthorized},
+		"malformed": {`{"id":`, gateway.Sign([]byte(`{"id":`)), http.StatusBadRequest},
+	} {
+		if code := post(tt.body, tt.signature); code != tt.status {
+			t.Errorf("%s: status %d, want %d", name, code, tt.status)
+		}
+	}
+	if order, _ := repo.FindByID(ctx, 2); order.Status != StatusPending {
+		t.Errorf("order 2 is %s after rejected events, want pending", order.Status)
+	}
+
+	// A signature captured earlier is no good once it is too old, even
+	// for an event not seen before.
+	stale := gateway.Sign([]byte(failed))
+	clk.Advance(2 * time.Minute)
+	if code := post(failed, stale); code != http.StatusUnauthorized {
+		t.Errorf("replayed signature: status %d, want 401", code)
+	}
+	if code := post(failed, gateway.Sign([]byte(failed))); code != http.StatusNoContent {
+		t.Errorf("payment failed: status %d", code)
+	}
+	if order, _ := repo.FindByID(ctx, 2); order.Status != StatusCancelled {
+		t.Errorf("order 2 is %s, want cancelled", order.Status)
+	}
+
+	// A capture for an order that moved on is refused, and can be
+	// retried, since it was not recorded as processed.
+	late := event("evt_3", PaymentEventCaptured, 2)
+	for range 2 {
+		if code := post(late, gateway.Sign([]byte(late))); code != http.StatusConflict {
+			t.Errorf("capture of a cancelled order: status %d, want 409", code)
+		}
+	}
+}
+
+// A payment failure only cancels a pending order. Replayed against an
+// order that was paid, it must not give the money back.
+func TestPaymentWebhook_FailureOfPaidOrder(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryOrderRepository()
+	paid := testOrder(t, 1)
+	paid.Status, paid.PaymentID = StatusInvoiced, "pay_1"
+	if err := repo.Save(ctx, paid); err != nil {
+		t.Fatal(err)
+	}
+	log := &callLog{}
+	orders, err := NewOrderService(repo, fakeGateway{log, nil}, fakeSender{log, nil}, fakeInvoicer{log, nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hook := NewPaymentWebhook(orders, NewInMemoryIdempotencyStore(), nil)
+
+	for i, paymentID := range []string{"pay_1", "pay_other"} {
+		body := fmt.Sprintf(`{"id":"evt_%d","type":%q,"order_id":1,"payment_id":%q}`, i, PaymentEventFailed, paymentID)
+		if err := hook.Handle(ctx, []byte(body)); err != nil {
+			t.Fatalf("failure of %s: %v", paymentID, err)
+		}
+	}
+	if calls := log.all(); len(calls) > 0 {
+		t.Errorf("calls = %q, want none", calls)
+	}
+	if order, _ := repo.FindByID(ctx, 1); order.Status != StatusInvoiced {
+		t.Errorf("order is %s, want invoiced", order.Status)
+	}
+}
+
+// When fulfilment fails after the capture marked the order Paid, the
+// gateway's retry of the event finishes it.
+func TestPaymentWebhook_CaptureRetryResumesFulfilment(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryOrderRepository()
+	if err := repo.Save(ctx, testOrder(t, 1)); err != nil {
+		t.Fatal(err)
+	}
+	errPrinter := errors.New("renderer crashed")
+	invoices := 0
+	orders, err := NewOrderService(repo, NewFakeStripeGateway(NewMoney(0, "USD"), nil), NewLoggingEmailSender(nil),
+		invoiceFunc(func(ctx context.Context, customer Customer, order Order) ([]byte, error) {
+			invoices++
+			if invoices == 1 {
+				return nil, errPrinter
+			}
+			return []byte("invoice"), nil
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hook := NewPaymentWebhook(orders, NewInMemoryIdempotencyStore(), nil)
+	body := []byte(`{"id":"evt_1","type":"payment.captured","order_id":1,"payment_id":"ch_async"}`)
+
+	if err := hook.Handle(ctx, body); !errors.Is(err, errPrinter) {
+		t.Fatalf("first delivery: %v, want %v", err, errPrinter)
+	}
+	if order, _ := repo.FindByID(ctx, 1); order.Status != StatusPaid {
+		t.Fatalf("order is %s after the failed fulfilment, want paid", order.Status)
+	}
+	if err := hook.Handle(ctx, body); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if order, _ := repo.FindByID(ctx, 1); order.Status != StatusInvoiced || invoices != 2 {
+		t.Errorf("order is %s after %d invoice attempts, want invoiced after 2", order.Status, invoices)
+	}
+}
+
+// racingGateway delivers the gateway's callbacks while it charges.
+type racingGateway struct {
+	PaymentGateway
+	during func(ctx context.Context, orderID int)
+}
+
+func (g racingGateway) Charge(ctx context.Context, orderID int, amount Money) (string, error) {
+	g.during(ctx, orderID)
+	return g.PaymentGateway.Charge(ctx, orderID, amount)
+}
+
+// Callbacks about an order PlaceOrder charges itself, whether they
+// arrive during the charge or after it, change nothing: the order is
+// neither cancelled nor fulfilled twice.
+func TestPaymentWebhook_IgnoresOrdersPlacedSynchronously(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryOrderRepository()
+	invoices := 0
+	var hook *PaymentWebhook
+	gateway := racingGateway{NewFakeStripeGateway(NewMoney(0, "USD"), nil), func(ctx context.Context, orderID int) {
+		for _, body := range []string{
+			fmt.Sprintf(`{"id":"evt_fail_%d","type":"payment.failed","order_id":%d}`, orderID, orderID),
+			fmt.Sprintf(`{"id":"evt_capture_%d","type":"payment.captured","order_id":%d,"payment_id":"ch_1"}`, orderID, orderID),
+		} {
+			if err := hook.Handle(ctx, []byte(body)); err != nil {
+				t.Errorf("event during the charge: %v", err)
+			}
+		}
+	}}
+	orders, err := NewOrderService(repo, gateway, NewLoggingEmailSender(nil),
+		invoiceFunc(func(ctx context.Context, customer Customer, order Order) ([]byte, error) {
+			invoices++
+			return []byte("invoice"), nil
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hook = NewPaymentWebhook(orders, NewInMemoryIdempotencyStore(), nil)
+
+	placed, err := orders.PlaceOrder(ctx, "", testOrder(t, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	capture := fmt.Sprintf(`{"id":"evt_late","type":"payment.captured","order_id":1,"payment_id":%q}`, placed.PaymentID)
+	if err := hook.Handle(ctx, []byte(capture)); err != nil {
+		t.Fatal(err)
+	}
+	if order, _ := repo.FindByID(ctx, 1); order.Status != StatusInvoiced || order.PaymentID != placed.PaymentID || invoices != 1 {
+		t.Errorf("order is %s with payment %q after %d invoices; want invoiced once with %q", order.Status, order.PaymentID, invoices, placed.PaymentID)
+	}
+}
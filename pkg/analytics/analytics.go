@@ -0,0 +1,128 @@
+// Package analytics records product events, such as the steps of the
+// checkout funnel, for a product-analytics service. Code that emits
+// events depends on Tracker; whether they are kept in memory, posted in
+// batches to an HTTP collector or sampled first is chosen where it is
+// wired.
+package analytics
+
+import (
+	"context"
+	"maps"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+// Tracker records events. Track must not block: it is called on the
+// request path, and losing an event is better than slowing an order.
+type Tracker interface {
+	Track(ctx context.Context, event string, props map[string]string)
+}
+
+// Nop discards every event.
+type Nop struct{}
+
+func (Nop) Track(context.Context, string, map[string]string) {}
+
+// OrNop returns t, or Nop if t is nil.
+func OrNop(t Tracker) Tracker {
+	if t == nil {
+		return Nop{}
+	}
+	return t
+}
+
+// Event is a tracked event.
+type Event struct {
+	Time  time.Time
+	Name  string
+	Props map[string]string
+}
+
+func newEvent(clk clock.Clock, name string, props map[string]string) Event {
+	return Event{Time: clk.Now(), Name: name, Props: maps.Clone(props)}
+}
+
+// Memory keeps the most recent events in memory, for tests and for
+// inspecting a running process. It is safe for concurrent use.
+type Memory struct {
+	clock clock.Clock
+
+	mu     sync.Mutex
+	events []Event // a ring of at most cap(events)
+	next   int
+	total  int
+}
+
+// NewMemory returns a Memory keeping the last size events. A nil clk
+// means the system clock.
+func NewMemory(size int, clk clock.Clock) *Memory {
+	return &Memory{clock: clock.OrSystem(clk), events: make([]Event, 0, max(size, 1))}
+}
+
+func (m *Memory) Track(ctx context.Context, event string, props map[string]string) {
+	e := newEvent(m.clock, event, props)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.events) < cap(m.events) {
+		m.events = append(m.events, e)
+	} else {
+		m.events[m.next] = e
+	}
+	m.next = (m.next + 1) % cap(m.events)
+	m.total++
+}
+
+// Events returns the kept events, oldest first.
+func (m *Memory) Events() []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.events) < cap(m.events) {
+		return append([]Event(nil), m.events...)
+	}
+	return append(append([]Event(nil), m.events[m.next:]...), m.events[:m.next]...)
+}
+
+// Total returns the number of events tracked, including the ones no
+// longer kept.
+func (m *Memory) Total() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.total
+}
+
+// SampleRateProp is the property Sample adds to the events it keeps,
+// so the collector can weigh them back up.
+const SampleRateProp = "sample_rate"
+
+// Sample passes on a fraction rate, between 0 and 1, of the events to
+// next and drops the rest. random returns a number in [0, 1); nil
+// means math/rand/v2.Float64.
+func Sample(next Tracker, rate float64, random func() float64) Tracker {
+	if random == nil {
+		random = rand.Float64
+	}
+	return sampled{next: next, rate: rate, random: random}
+}
+
+type sampled struct {
+	next   Tracker
+	rate   float64
+	random func() float64
+}
+
+func (s sampled) Track(ctx context.Context, event string, props map[string]string) {
+	if s.rate <= 0 || (s.rate < 1 && s.random() >= s.rate) {
+		return
+	}
+	props = maps.Clone(props)
+	if props == nil {
+		props = make(map[string]string, 1)
+	}
+	props[SampleRateProp] = strconv.FormatFloat(s.rate, 'g', -1, 64)
+	s.next.Track(ctx, event, props)
+}
@@ -0,0 +1,135 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+)
+
+func TestMemory_KeepsTheLastEvents(t *testing.T) {
+	m := NewMemory(2, clocktest.NewFake(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)))
+	ctx := context.Background()
+	props := map[string]string{"step": "cart"}
+	for _, name := range []string{"a", "b", "c"} {
+		m.Track(ctx, name, props)
+	}
+	props["step"] = "changed"
+
+	events := m.Events()
+	if len(events) != 2 || events[0].Name != "b" || events[1].Name != "c" {
+		t.Fatalf("events = %+v", events)
+	}
+	if m.Total() != 3 {
+		t.Errorf("Total = %d, want 3", m.Total())
+	}
+	if events[0].Props["step"] != "cart" || events[0].Time.Hour() != 9 {
+		t.Errorf("event = %+v; the props must be copied", events[0])
+	}
+}
+
+func TestSample(t *testing.T) {
+	ctx := context.Background()
+	rolls := []float64{0.1, 0.5, 0.24, 0.9}
+	m := NewMemory(10, nil)
+	tracker := Sample(m, 0.25, func() float64 {
+		r := rolls[0]
+		rolls = rolls[1:]
+		return r
+	})
+	for range 4 {
+		tracker.Track(ctx, "order_placed", nil)
+	}
+	events := m.Events()
+	if len(events) != 2 || events[0].Props[SampleRateProp] != "0.25" {
+		t.Errorf("events = %+v, want 2 tagged with the rate", events)
+	}
+
+	m = NewMemory(10, nil)
+	never := func() float64 { t.Fatal("rolled for rate 0 or 1"); return 0 }
+	Sample(m, 1, never).Track(ctx, "kept", nil)
+	Sample(m, 0, never).Track(ctx, "dropped", nil)
+	if m.Total() != 1 {
+		t.Errorf("Total = %d, want 1", m.Total())
+	}
+}
+
+func TestHTTP_Batches(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches [][]httpEvent
+	)
+	posted := make(chan struct{}, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []httpEvent
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		batches = append(batches, batch)
+		mu.Unlock()
+		posted <- struct{}{}
+	}))
+	defer srv.Close()
+
+	clk := clocktest.NewFake(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	h := NewHTTP(srv.URL, HTTPOptions{Client: srv.Client(), BatchSize: 2, FlushInterval: time.Minute, Clock: clk})
+	ctx := context.Background()
+
+	// A full batch is posted at once.
+	h.Track(ctx, "checkout_started", map[string]string{"order_id": "7"})
+	h.Track(ctx, "order_placed", map[string]string{"order_id": "7"})
+	<-posted
+
+	// A partial one waits for the flush interval. The first batch's
+	// timer is still pending on the fake clock.
+	h.Track(ctx, "checkout_started", map[string]string{"order_id": "8"})
+	clk.BlockUntil(2)
+	clk.Advance(time.Minute)
+	<-posted
+
+	// Close flushes what is left.
+	h.Track(ctx, "checkout_failed", nil)
+	if err := h.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	h.Track(ctx, "after close", nil)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 3 || len(batches[0]) != 2 || len(batches[1]) != 1 || len(batches[2]) != 1 {
+		t.Fatalf("batches = %+v", batches)
+	}
+	if e := batches[0][1]; e.Event != "order_placed" || e.Properties["order_id"] != "7" || e.Timestamp.Hour() != 9 {
+		t.Errorf("event = %+v", e)
+	}
+	if batches[2][0].Event != "checkout_failed" {
+		t.Errorf("last batch = %+v", batches[2])
+	}
+	if h.Dropped() != 1 {
+		t.Errorf("Dropped = %d, want 1: the event after Close", h.Dropped())
+	}
+}
+
+func TestHTTP_DropsFailedBatches(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	h := NewHTTP(srv.URL, HTTPOptions{Client: srv.Client(), BatchSize: 10})
+	for range 3 {
+		h.Track(context.Background(), "order_placed", nil)
+	}
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if h.Dropped() != 3 {
+		t.Errorf("Dropped = %d, want 3", h.Dropped())
+	}
+}
@@ -0,0 +1,79 @@
+package analytics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/internal/poster"
+)
+
+// HTTPOptions tune an HTTP tracker. Zero values take the defaults.
+type HTTPOptions struct {
+	Client        *http.Client  // default http.DefaultClient
+	QueueSize     int           // events waiting to be batched; default 1000
+	BatchSize     int           // events per post; default 100
+	FlushInterval time.Duration // longest an event waits for its batch; default 10s
+	Clock         clock.Clock   // default the system clock
+}
+
+// HTTP posts events to a collector in batches, as a JSON array:
+//
+//	[{"event": "order_placed", "timestamp": "...", "properties": {...}}]
+//
+// A batch is posted once it holds BatchSize events or its first event
+// has waited FlushInterval, and on Close. Track only queues the event;
+// when the queue is full or the collector fails, events are dropped and
+// counted rather than slowing down the caller.
+type HTTP struct {
+	clock  clock.Clock
+	poster *poster.Poster[Event]
+}
+
+// NewHTTP starts posting to url.
+func NewHTTP(url string, opts HTTPOptions) *HTTP {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	clk := clock.OrSystem(opts.Clock)
+	return &HTTP{
+		clock: clk,
+		poster: poster.New("analytics", url, poster.Options{
+			Client:        opts.Client,
+			QueueSize:     opts.QueueSize,
+			BatchSize:     opts.BatchSize,
+			FlushInterval: opts.FlushInterval,
+			Clock:         clk,
+		}, encodeEvents),
+	}
+}
+
+func (h *HTTP) Track(ctx context.Context, event string, props map[string]string) {
+	h.poster.Send(newEvent(h.clock, event, props))
+}
+
+// Dropped returns the number of events that were not delivered.
+func (h *HTTP) Dropped() int64 {
+	return h.poster.Dropped()
+}
+
+// Close stops accepting events and waits until the queued ones are
+// posted, or until ctx is done.
+func (h *HTTP) Close(ctx context.Context) error {
+	return h.poster.Close(ctx)
+}
+
+type httpEvent struct {
+	Event      string            `json:"event"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+func encodeEvents(batch []Event) any {
+	events := make([]httpEvent, len(batch))
+	for i, e := range batch {
+		events[i] = httpEvent{Event: e.Name, Timestamp: e.Time.UTC(), Properties: e.Props}
+	}
+	return events
+}
@@ -0,0 +1,192 @@
+// Package pkg_test guards the exported API of the module: the packages
+// under pkg and the chapters, the ones other projects import. Commands
+// and internal packages are left out. Every exported declaration is
+// listed in testdata/api.txt; a change to that list fails TestAPI until
+// the golden file is updated on purpose:
+//
+//	go test ./pkg -run TestAPI -update
+package pkg_test
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/api.txt")
+
+func TestAPI(t *testing.T) {
+	var api []string
+	root := ".."
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || path == root {
+			return err
+		}
+		switch name := d.Name(); {
+		case strings.HasPrefix(name, "."), name == "testdata", name == "internal", name == "cmd":
+			return filepath.SkipDir
+		}
+		decls, err := exportedDecls(path)
+		rel, _ := filepath.Rel(root, path)
+		for _, decl := range decls {
+			api = append(api, filepath.ToSlash(rel)+": "+decl)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(api)
+	got := strings.Join(api, "\n") + "\n"
+
+	golden := filepath.Join("testdata", "api.txt")
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == string(want) {
+		return
+	}
+	gotLines, wantLines := strings.Split(got, "\n"), strings.Split(string(want), "\n")
+	for _, line := range wantLines {
+		if line != "" && !slices.Contains(gotLines, line) {
+			t.Errorf("removed: %s", line)
+		}
+	}
+	for _, line := range gotLines {
+		if line != "" && !slices.Contains(wantLines, line) {
+			t.Errorf("added: %s", line)
+		}
+	}
+	t.Log("if the change is intended, run: go test ./pkg -run TestAPI -update")
+}
+
+// exportedDecls lists the exported declarations of the package in dir,
+// one line each, or none if it is a command.
+func exportedDecls(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var decls []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		if file.Name.Name == "main" {
+			return nil, nil
+		}
+		for _, decl := range file.Decls {
+			decls = append(decls, exported(fset, decl)...)
+		}
+	}
+	return decls, nil
+}
+
+func exported(fset *token.FileSet, decl ast.Decl) []string {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if !d.Name.IsExported() || (d.Recv != nil && !ast.IsExported(receiverName(d.Recv))) {
+			return nil
+		}
+		fn := *d
+		fn.Doc, fn.Body = nil, nil
+		return []string{format(fset, &fn)}
+	case *ast.GenDecl:
+		var lines []string
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if s.Name.IsExported() {
+					ts := *s
+					ts.Doc, ts.Comment = nil, nil
+					ts.Type = withoutUnexported(s.Type)
+					lines = append(lines, "type "+format(fset, &ts))
+				}
+			case *ast.ValueSpec:
+				for _, name := range s.Names {
+					if !name.IsExported() {
+						continue
+					}
+					line := d.Tok.String() + " " + name.Name
+					if s.Type != nil {
+						line += " " + format(fset, s.Type)
+					}
+					lines = append(lines, line)
+				}
+			}
+		}
+		return lines
+	}
+	return nil
+}
+
+// receiverName is the name of the receiver's type, without pointer or
+// type parameters.
+func receiverName(recv *ast.FieldList) string {
+	expr := recv.List[0].Type
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// withoutUnexported drops the unexported fields of a struct type, which
+// importers cannot see.
+func withoutUnexported(expr ast.Expr) ast.Expr {
+	st, ok := expr.(*ast.StructType)
+	if !ok {
+		return expr
+	}
+	fields := &ast.FieldList{}
+	for _, f := range st.Fields.List {
+		var names []*ast.Ident
+		for _, n := range f.Names {
+			if n.IsExported() {
+				names = append(names, n)
+			}
+		}
+		embeddedExported := len(f.Names) == 0 && ast.IsExported(receiverName(&ast.FieldList{List: []*ast.Field{f}}))
+		if len(names) > 0 || embeddedExported {
+			fields.List = append(fields.List, &ast.Field{Names: names, Type: f.Type, Tag: f.Tag})
+		}
+	}
+	return &ast.StructType{Fields: fields}
+}
+
+// format prints node on one line.
+func format(fset *token.FileSet, node any) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, fset, node)
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
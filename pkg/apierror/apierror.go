@@ -0,0 +1,87 @@
+// Package apierror turns errors into HTTP answers. The domain only
+// returns its own typed errors; a Mapper, built at the transport edge,
+// decides which status and stable machine-readable code each one gets,
+// and writes the same JSON body for all of them.
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Body is the JSON body of every error answer. Code is stable and
+// meant for programs; Error is for people and may change. Fields lists
+// the invalid request fields, when there are any.
+type Body struct {
+	Error  string       `json:"error"`
+	Code   string       `json:"code"`
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError is one invalid field of a request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FieldErrors is implemented by errors that know which request fields
+// were invalid, so the body can list them.
+type FieldErrors interface {
+	error
+	FieldErrors() []FieldError
+}
+
+// Rule maps the errors matching Err, by errors.Is, to Status and Code.
+type Rule struct {
+	Err    error
+	Status int
+	Code   string
+}
+
+// Mapper maps errors to answers by its rules, the first matching one
+// winning. Errors no rule matches are internal errors: 500 with code
+// "internal" and a generic message, since their text may leak details
+// the client has no business seeing.
+type Mapper struct {
+	rules []Rule
+}
+
+func NewMapper(rules ...Rule) *Mapper {
+	return &Mapper{rules: rules}
+}
+
+// Map returns the status and body of the answer to err.
+func (m *Mapper) Map(err error) (int, Body) {
+	for _, r := range m.rules {
+		if errors.Is(err, r.Err) {
+			body := Body{Error: err.Error(), Code: r.Code}
+			var fe FieldErrors
+			if errors.As(err, &fe) {
+				body.Fields = fe.FieldErrors()
+			}
+			return r.Status, body
+		}
+	}
+	return http.StatusInternalServerError, Body{Error: http.StatusText(http.StatusInternalServerError), Code: "internal"}
+}
+
+// Status returns the status of the answer to err.
+func (m *Mapper) Status(err error) int {
+	status, _ := m.Map(err)
+	return status
+}
+
+// Write answers err on w.
+func (m *Mapper) Write(w http.ResponseWriter, err error) {
+	status, body := m.Map(err)
+	WriteBody(w, status, body)
+}
+
+// WriteBody answers with status and body, for errors found by the
+// transport itself, such as a malformed request.
+func WriteBody(w http.ResponseWriter, status int, body Body) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,68 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+var (
+	errNotFound = errors.New("not found")
+	errInvalid  = errors.New("invalid")
+	errDeclined = errors.New("payment declined")
+)
+
+type fieldsError struct{ fields []FieldError }
+
+func (e fieldsError) Error() string             { return "invalid request" }
+func (e fieldsError) Unwrap() error             { return errInvalid }
+func (e fieldsError) FieldErrors() []FieldError { return e.fields }
+
+func TestMapper(t *testing.T) {
+	m := NewMapper(
+		Rule{errNotFound, http.StatusNotFound, "not_found"},
+		Rule{errInvalid, http.StatusBadRequest, "invalid"},
+		Rule{errDeclined, http.StatusPaymentRequired, "payment_declined"},
+	)
+	fields := []FieldError{{Field: "items", Message: "at least one item is required"}}
+	tests := []struct {
+		err    error
+		status int
+		body   Body
+	}{
+		{errNotFound, 404, Body{Error: "not found", Code: "not_found"}},
+		{fmt.Errorf("order 7: %w", errNotFound), 404, Body{Error: "order 7: not found", Code: "not_found"}},
+		{fmt.Errorf("charging: %w", errDeclined), 402, Body{Error: "charging: payment declined", Code: "payment_declined"}},
+		{fieldsError{fields}, 400, Body{Error: "invalid request", Code: "invalid", Fields: fields}},
+		{errors.New("dial tcp 10.0.0.7:5432: refused"), 500, Body{Error: "Internal Server Error", Code: "internal"}},
+	}
+	for _, tt := range tests {
+		status, body := m.Map(tt.err)
+		if status != tt.status || body.Error != tt.body.Error || body.Code != tt.body.Code || !slices.Equal(body.Fields, tt.body.Fields) {
+			t.Errorf("Map(%v) = %d %+v, want %d %+v", tt.err, status, body, tt.status, tt.body)
+		}
+		if m.Status(tt.err) != tt.status {
+			t.Errorf("Status(%v) = %d, want %d", tt.err, m.Status(tt.err), tt.status)
+		}
+	}
+}
+
+func TestMapper_Write(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewMapper(Rule{errNotFound, http.StatusNotFound, "not_found"}).Write(rec, errNotFound)
+
+	if rec.Code != http.StatusNotFound || rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("answer = %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var body map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["code"] != "not_found" || body["error"] != "not found" || body["fields"] != nil {
+		t.Errorf("body = %v", body)
+	}
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+// APIKeys issues and verifies opaque API keys. Only a hash of each key
+// is kept, so the keys cannot be read back from memory or a dump. It
+// is safe for concurrent use.
+type APIKeys struct {
+	clock clock.Clock
+
+	mu   sync.RWMutex
+	keys map[[sha256.Size]byte]apiKey
+}
+
+type apiKey struct {
+	principal Principal
+	expires   time.Time // zero for no expiry
+}
+
+// NewAPIKeys returns an empty key set. clk may be nil, for the system
+// clock.
+func NewAPIKeys(clk clock.Clock) *APIKeys {
+	return &APIKeys{clock: clock.OrSystem(clk), keys: make(map[[sha256.Size]byte]apiKey)}
+}
+
+// Add accepts key, such as one from configuration, for p.
+func (k *APIKeys) Add(key string, p Principal) error {
+	if key == "" || p.Subject == "" {
+		return errors.New("api key: empty key or subject")
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[sha256.Sum256([]byte(key))] = apiKey{principal: p}
+	return nil
+}
+
+// Issue returns a new random key for p.
+func (k *APIKeys) Issue(ctx context.Context, p Principal, ttl time.Duration) (string, error) {
+	if p.Subject == "" {
+		return "", errors.New("api key: principal without a subject")
+	}
+	raw := make([]byte, 24)
+	rand.Read(raw)
+	key := "sk_" + base64.RawURLEncoding.EncodeToString(raw)
+
+	entry := apiKey{principal: p}
+	if ttl > 0 {
+		entry.expires = k.clock.Now().Add(ttl)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[sha256.Sum256([]byte(key))] = entry
+	return key, nil
+}
+
+// Revoke stops accepting key.
+func (k *APIKeys) Revoke(key string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.keys, sha256.Sum256([]byte(key)))
+}
+
+func (k *APIKeys) Verify(ctx context.Context, token string) (Principal, error) {
+	k.mu.RLock()
+	entry, ok := k.keys[sha256.Sum256([]byte(token))]
+	k.mu.RUnlock()
+	if !ok {
+		return Principal{}, fmt.Errorf("%w: unknown api key", ErrUnauthenticated)
+	}
+	if !entry.expires.IsZero() && !k.clock.Now().Before(entry.expires) {
+		return Principal{}, fmt.Errorf("%w: api key expired", ErrUnauthenticated)
+	}
+	return entry.principal, nil
+}
@@ -0,0 +1,125 @@
+// Package auth tells who is calling. A TokenIssuer hands out tokens
+// for a Principal and a TokenVerifier turns them back into one; JWT
+// and APIKeys implement both. Middleware verifies each request and
+// puts its Principal in the context, so handlers only ever depend on
+// the narrow TokenVerifier.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnauthenticated matches requests without valid credentials.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrForbidden matches authenticated requests lacking a scope.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Principal is who a request acts for and what it may do.
+type Principal struct {
+	Subject string
+	Scopes  []string
+}
+
+// HasScope reports whether p was granted scope.
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// TokenIssuer hands out a token for p, valid for ttl; zero means no
+// expiry, where the implementation allows it.
+type TokenIssuer interface {
+	Issue(ctx context.Context, p Principal, ttl time.Duration) (string, error)
+}
+
+// TokenVerifier returns the Principal of a token, or an error matching
+// ErrUnauthenticated if the token is not valid.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (Principal, error)
+}
+
+// Any is a TokenVerifier accepting a token any of its verifiers
+// accepts, such as a JWT or an API key.
+type Any []TokenVerifier
+
+func (a Any) Verify(ctx context.Context, token string) (Principal, error) {
+	var errs []error
+	for _, v := range a {
+		p, err := v.Verify(ctx, token)
+		if err == nil {
+			return p, nil
+		}
+		if !errors.Is(err, ErrUnauthenticated) {
+			return Principal{}, err
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return Principal{}, fmt.Errorf("%w: no verifier", ErrUnauthenticated)
+	}
+	return Principal{}, errors.Join(errs...)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the Principal in ctx, if there is one.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// Middleware verifies the token of every request, given as
+// "Authorization: Bearer <token>" or "X-API-Key: <token>", and passes
+// the request on with its Principal in the context. Requests without
+// a valid token go to fail instead.
+func Middleware(v TokenVerifier, fail func(http.ResponseWriter, error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.Header.Get("X-API-Key")
+			if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+				token = strings.TrimSpace(bearer)
+			}
+			if token == "" {
+				fail(w, fmt.Errorf("%w: no credentials", ErrUnauthenticated))
+				return
+			}
+			p, err := v.Verify(r.Context(), token)
+			if err != nil {
+				fail(w, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+		})
+	}
+}
+
+// Require passes on only the requests whose Principal has scope; the
+// others go to fail. It belongs inside Middleware.
+func Require(scope string, fail func(http.ResponseWriter, error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := PrincipalFrom(r.Context())
+			if !ok {
+				fail(w, fmt.Errorf("%w: no principal", ErrUnauthenticated))
+				return
+			}
+			if !p.HasScope(scope) {
+				fail(w, fmt.Errorf("%w: %s lacks scope %s", ErrForbidden, p.Subject, scope))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
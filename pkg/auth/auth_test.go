@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+)
+
+var testSecret = []byte(strings.Repeat("s", MinSecretSize))
+
+func TestJWT(t *testing.T) {
+	ctx := context.Background()
+	clk := clocktest.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	j, err := NewJWT(testSecret, "orders", clk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Principal{Subject: "alice", Scopes: []string{"orders:read", "orders:write"}}
+	token, err := j.Issue(ctx, want, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := j.Verify(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Subject != want.Subject || !got.HasScope("orders:write") || got.HasScope("orders:refund") {
+		t.Errorf("Verify = %+v, want %+v", got, want)
+	}
+
+	other, _ := NewJWT([]byte(strings.Repeat("o", MinSecretSize)), "orders", clk)
+	foreign, _ := NewJWT(testSecret, "billing", clk)
+	header, rest, _ := strings.Cut(token, ".")
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + rest
+	for name, verify := range map[string]func() (Principal, error){
+		"other secret": func() (Principal, error) { return other.Verify(ctx, token) },
+		"other issuer": func() (Principal, error) { return foreign.Verify(ctx, token) },
+		"alg none":     func() (Principal, error) { return j.Verify(ctx, none) },
+		"tampered":     func() (Principal, error) { return j.Verify(ctx, header+"."+rest+"x") },
+		"malformed":    func() (Principal, error) { return j.Verify(ctx, "not-a-token") },
+	} {
+		if _, err := verify(); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("%s: err = %v, want ErrUnauthenticated", name, err)
+		}
+	}
+
+	clk.Advance(time.Hour)
+	if _, err := j.Verify(ctx, token); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("expired: err = %v, want ErrUnauthenticated", err)
+	}
+
+	if _, err := NewJWT([]byte("short"), "orders", nil); err == nil {
+		t.Error("NewJWT accepted a short secret")
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	ctx := context.Background()
+	clk := clocktest.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	keys := NewAPIKeys(clk)
+	if err := keys.Add("static-key", Principal{Subject: "ops", Scopes: []string{"orders:refund"}}); err != nil {
+		t.Fatal(err)
+	}
+	issued, err := keys.Issue(ctx, Principal{Subject: "ci"}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p, err := keys.Verify(ctx, "static-key"); err != nil || !p.HasScope("orders:refund") {
+		t.Errorf("Verify(static-key) = %+v, %v", p, err)
+	}
+	if p, err := keys.Verify(ctx, issued); err != nil || p.Subject != "ci" {
+		t.Errorf("Verify(issued) = %+v, %v", p, err)
+	}
+
+	clk.Advance(time.Minute)
+	if _, err := keys.Verify(ctx, issued); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("expired key: err = %v", err)
+	}
+	keys.Revoke("static-key")
+	if _, err := keys.Verify(ctx, "static-key"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("revoked key: err = %v", err)
+	}
+}
+
+func TestAny(t *testing.T) {
+	ctx := context.Background()
+	j, _ := NewJWT(testSecret, "orders", nil)
+	keys := NewAPIKeys(nil)
+	keys.Add("key", Principal{Subject: "ops"})
+	token, _ := j.Issue(ctx, Principal{Subject: "alice"}, time.Hour)
+
+	v := Any{j, keys}
+	for token, want := range map[string]string{token: "alice", "key": "ops"} {
+		if p, err := v.Verify(ctx, token); err != nil || p.Subject != want {
+			t.Errorf("Verify = %+v, %v, want %s", p, err, want)
+		}
+	}
+	if _, err := v.Verify(ctx, "nope"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("unknown token: err = %v", err)
+	}
+	if _, err := (Any{}).Verify(ctx, "key"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("no verifiers: err = %v", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	keys := NewAPIKeys(nil)
+	keys.Add("reader", Principal{Subject: "r", Scopes: []string{"read"}})
+	keys.Add("writer", Principal{Subject: "w", Scopes: []string{"read", "write"}})
+	fail := func(w http.ResponseWriter, err error) {
+		switch {
+		case errors.Is(err, ErrForbidden):
+			w.WriteHeader(http.StatusForbidden)
+		case errors.Is(err, ErrUnauthenticated):
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+	h := Middleware(keys, fail)(Require("write", fail)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, _ := PrincipalFrom(r.Context())
+		w.Write([]byte(p.Subject))
+	})))
+
+	tests := []struct {
+		name, header, value string
+		status              int
+	}{
+		{"no credentials", "", "", http.StatusUnauthorized},
+		{"unknown key", "X-API-Key", "nope", http.StatusUnauthorized},
+		{"missing scope", "X-API-Key", "reader", http.StatusForbidden},
+		{"api key", "X-API-Key", "writer", http.StatusOK},
+		{"bearer", "Authorization", "Bearer writer", http.StatusOK},
+		{"basic", "Authorization", "Basic writer", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.header != "" {
+			req.Header.Set(tt.header, tt.value)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, rec.Code, tt.status)
+		}
+		if tt.status == http.StatusOK && rec.Body.String() != "w" {
+			t.Errorf("%s: principal %q, want w", tt.name, rec.Body)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+// MinSecretSize is the shortest HMAC secret JWT accepts: as long as
+// the SHA-256 output, as RFC 7518 requires for HS256.
+const MinSecretSize = 32
+
+// JWT issues and verifies JSON Web Tokens signed with HMAC-SHA256.
+// The principal travels in the "sub" and "scope" claims.
+type JWT struct {
+	secret []byte
+	issuer string
+	clock  clock.Clock
+}
+
+// NewJWT returns a JWT signing with secret, at least MinSecretSize
+// bytes, and naming issuer in the "iss" claim, which Verify checks.
+// clk may be nil, for the system clock.
+func NewJWT(secret []byte, issuer string, clk clock.Clock) (*JWT, error) {
+	if len(secret) < MinSecretSize {
+		return nil, fmt.Errorf("jwt: secret of %d bytes; at least %d are needed", len(secret), MinSecretSize)
+	}
+	return &JWT{secret: secret, issuer: issuer, clock: clock.OrSystem(clk)}, nil
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+type jwtClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Scope     string `json:"scope,omitempty"` // space-separated, as in RFC 8693
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+}
+
+var jwtHeaderSegment = encodeSegment(jwtHeader{Alg: "HS256", Typ: "JWT"})
+
+func (j *JWT) Issue(ctx context.Context, p Principal, ttl time.Duration) (string, error) {
+	if p.Subject == "" {
+		return "", errors.New("jwt: principal without a subject")
+	}
+	now := j.clock.Now()
+	claims := jwtClaims{Issuer: j.issuer, Subject: p.Subject, Scope: strings.Join(p.Scopes, " "), IssuedAt: now.Unix()}
+	if ttl > 0 {
+		claims.ExpiresAt = now.Add(ttl).Unix()
+	}
+	signed := jwtHeaderSegment + "." + encodeSegment(claims)
+	return signed + "." + j.sign(signed), nil
+}
+
+func (j *JWT) Verify(ctx context.Context, token string) (Principal, error) {
+	header, rest, ok1 := strings.Cut(token, ".")
+	payload, sig, ok2 := strings.Cut(rest, ".")
+	if !ok1 || !ok2 {
+		return Principal{}, fmt.Errorf("%w: malformed token", ErrUnauthenticated)
+	}
+
+	var h jwtHeader
+	if err := decodeSegment(header, &h); err != nil || h.Alg != "HS256" {
+		// Only HS256: accepting the token's own choice of "alg" is how
+		// "none" and key-confusion attacks get in.
+		return Principal{}, fmt.Errorf("%w: unsupported token", ErrUnauthenticated)
+	}
+	if !hmac.Equal([]byte(sig), []byte(j.sign(header+"."+payload))) {
+		return Principal{}, fmt.Errorf("%w: bad signature", ErrUnauthenticated)
+	}
+
+	var c jwtClaims
+	if err := decodeSegment(payload, &c); err != nil {
+		return Principal{}, fmt.Errorf("%w: malformed claims", ErrUnauthenticated)
+	}
+	if c.Issuer != j.issuer {
+		return Principal{}, fmt.Errorf("%w: issued by %q", ErrUnauthenticated, c.Issuer)
+	}
+	if c.ExpiresAt != 0 && !j.clock.Now().Before(time.Unix(c.ExpiresAt, 0)) {
+		return Principal{}, fmt.Errorf("%w: token expired", ErrUnauthenticated)
+	}
+	if c.Subject == "" {
+		return Principal{}, fmt.Errorf("%w: token without a subject", ErrUnauthenticated)
+	}
+	return Principal{Subject: c.Subject, Scopes: strings.Fields(c.Scope)}, nil
+}
+
+func (j *JWT) sign(s string) string {
+	mac := hmac.New(sha256.New, j.secret)
+	mac.Write([]byte(s))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func encodeSegment(v any) string {
+	data, _ := json.Marshal(v) // the header and claims always encode
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSegment(s string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
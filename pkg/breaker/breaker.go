@@ -0,0 +1,210 @@
+// Package breaker stops calling a dependency that keeps failing. A
+// Breaker wraps any call, through Do or the generic Value, so payment
+// gateways and notification channels share one implementation.
+//
+// A closed breaker lets calls through and counts consecutive failures.
+// Once they reach the threshold it opens and fails calls with ErrOpen
+// without making them. After the open timeout it is half-open: a few
+// probe calls go through, and it closes once enough of them succeed or
+// opens again on the first failure.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+// ErrOpen is returned, wrapped with the breaker's name, for calls an
+// open breaker refuses.
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the state of a Breaker.
+type State int
+
+const (
+	Closed State = iota
+	Open
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// Settings configure a Breaker. The zero value of each field has a
+// usable default.
+type Settings struct {
+	// Name identifies the breaker in errors and callbacks.
+	Name string
+	// FailureThreshold is the number of consecutive failures that
+	// opens the breaker. The default is 5.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before probing.
+	// The default is 30 seconds.
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of calls let through at once while
+	// half-open, all of which must succeed to close the breaker. The
+	// default is 1.
+	HalfOpenProbes int
+	// IsFailure decides which errors count against the dependency. The
+	// default counts every error; a declined payment, for one, says
+	// nothing about the gateway's health.
+	IsFailure func(error) bool
+	// OnStateChange, if set, is called after every change of state. It
+	// must not block.
+	OnStateChange func(name string, from, to State)
+	// Clock is the source of time; nil means the system clock.
+	Clock clock.Clock
+}
+
+// Breaker is a circuit breaker. It is safe for concurrent use.
+type Breaker struct {
+	s     Settings
+	clock clock.Clock
+
+	mu         sync.Mutex
+	state      State
+	generation int // changes with state, so late results of an old state are ignored
+	failures   int // consecutive, while closed
+	probes     int // in flight, while half-open
+	successes  int // while half-open
+	openedAt   time.Time
+}
+
+// New returns a closed breaker.
+func New(s Settings) *Breaker {
+	if s.FailureThreshold <= 0 {
+		s.FailureThreshold = 5
+	}
+	if s.OpenTimeout <= 0 {
+		s.OpenTimeout = 30 * time.Second
+	}
+	if s.HalfOpenProbes <= 0 {
+		s.HalfOpenProbes = 1
+	}
+	if s.IsFailure == nil {
+		s.IsFailure = func(err error) bool { return err != nil }
+	}
+	return &Breaker{s: s, clock: clock.OrSystem(s.Clock)}
+}
+
+// Name returns the name the breaker was configured with.
+func (b *Breaker) Name() string {
+	return b.s.Name
+}
+
+// State returns the current state. An open breaker whose timeout has
+// passed reports HalfOpen.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	state, change := b.refresh()
+	b.mu.Unlock()
+	b.notify(change)
+	return state
+}
+
+// Do calls fn unless the breaker is open, and records the outcome.
+func (b *Breaker) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	_, err := Value(ctx, b, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// Value calls fn unless b is open, and records the outcome.
+func Value[T any](ctx context.Context, b *Breaker, fn func(ctx context.Context) (T, error)) (T, error) {
+	generation, err := b.before()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	v, err := fn(ctx)
+	b.after(generation, err)
+	return v, err
+}
+
+// transition is a change of state to report once the lock is released.
+type transition struct{ from, to State }
+
+func (b *Breaker) before() (int, error) {
+	b.mu.Lock()
+	state, change := b.refresh()
+	var err error
+	switch {
+	case state == Open, state == HalfOpen && b.probes >= b.s.HalfOpenProbes:
+		err = fmt.Errorf("%s: %w", b.s.Name, ErrOpen)
+	case state == HalfOpen:
+		b.probes++
+	}
+	generation := b.generation
+	b.mu.Unlock()
+
+	b.notify(change)
+	return generation, err
+}
+
+func (b *Breaker) after(generation int, err error) {
+	b.mu.Lock()
+	var change *transition
+	if generation == b.generation {
+		failed := err != nil && b.s.IsFailure(err)
+		switch b.state {
+		case Closed:
+			if !failed {
+				b.failures = 0
+			} else if b.failures++; b.failures >= b.s.FailureThreshold {
+				change = b.setState(Open)
+			}
+		case HalfOpen:
+			b.probes--
+			if failed {
+				change = b.setState(Open)
+			} else if b.successes++; b.successes >= b.s.HalfOpenProbes {
+				change = b.setState(Closed)
+			}
+		}
+	}
+	b.mu.Unlock()
+
+	b.notify(change)
+}
+
+// refresh moves an open breaker whose timeout has passed to half-open.
+// b.mu must be held.
+func (b *Breaker) refresh() (State, *transition) {
+	if b.state == Open && b.clock.Now().Sub(b.openedAt) >= b.s.OpenTimeout {
+		return HalfOpen, b.setState(HalfOpen)
+	}
+	return b.state, nil
+}
+
+// setState resets the counters for the new state. b.mu must be held.
+func (b *Breaker) setState(to State) *transition {
+	from := b.state
+	b.state = to
+	b.generation++
+	b.failures, b.probes, b.successes = 0, 0, 0
+	if to == Open {
+		b.openedAt = b.clock.Now()
+	}
+	return &transition{from: from, to: to}
+}
+
+func (b *Breaker) notify(change *transition) {
+	if change != nil && b.s.OnStateChange != nil {
+		b.s.OnStateChange(b.s.Name, change.from, change.to)
+	}
+}
@@ -0,0 +1,167 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+)
+
+var errDown = errors.New("down")
+
+func fail(context.Context) error    { return errDown }
+func succeed(context.Context) error { return nil }
+
+// newTestBreaker returns a breaker on a fake clock that records its
+// state changes.
+func newTestBreaker(s Settings) (*Breaker, *clocktest.Fake, *[]string) {
+	clk := clocktest.NewFake(time.Time{})
+	var changes []string
+	s.Name = "payment"
+	s.Clock = clk
+	s.OnStateChange = func(name string, from, to State) {
+		changes = append(changes, name+": "+from.String()+" -> "+to.String())
+	}
+	return New(s), clk, &changes
+}
+
+func TestBreaker_Trips(t *testing.T) {
+	b, _, changes := newTestBreaker(Settings{FailureThreshold: 3})
+	ctx := context.Background()
+
+	// A success resets the count of consecutive failures.
+	b.Do(ctx, fail)
+	b.Do(ctx, fail)
+	b.Do(ctx, succeed)
+	b.Do(ctx, fail)
+	b.Do(ctx, fail)
+	if b.State() != Closed {
+		t.Fatalf("state = %s after non-consecutive failures", b.State())
+	}
+	if err := b.Do(ctx, fail); !errors.Is(err, errDown) {
+		t.Fatalf("the tripping call: err = %v", err)
+	}
+
+	called := false
+	err := b.Do(ctx, func(context.Context) error { called = true; return nil })
+	if !errors.Is(err, ErrOpen) || called {
+		t.Fatalf("open breaker: err = %v, called = %v", err, called)
+	}
+	if want := []string{"payment: closed -> open"}; !slices.Equal(*changes, want) {
+		t.Errorf("changes = %q, want %q", *changes, want)
+	}
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	b, clk, changes := newTestBreaker(Settings{FailureThreshold: 1, OpenTimeout: time.Minute, HalfOpenProbes: 2})
+	ctx := context.Background()
+
+	b.Do(ctx, fail)
+	clk.Advance(time.Minute)
+	if b.State() != HalfOpen {
+		t.Fatalf("state = %s after the open timeout", b.State())
+	}
+	// A failed probe opens the breaker again for another minute.
+	b.Do(ctx, fail)
+	if err := b.Do(ctx, succeed); !errors.Is(err, ErrOpen) {
+		t.Fatalf("after a failed probe: err = %v", err)
+	}
+
+	clk.Advance(time.Minute)
+	b.Do(ctx, succeed)
+	if b.State() != HalfOpen {
+		t.Fatalf("state = %s after one of two probes", b.State())
+	}
+	b.Do(ctx, succeed)
+	if b.State() != Closed {
+		t.Fatalf("state = %s after two good probes", b.State())
+	}
+
+	want := []string{
+		"payment: closed -> open",
+		"payment: open -> half-open",
+		"payment: half-open -> open",
+		"payment: open -> half-open",
+		"payment: half-open -> closed",
+	}
+	if !slices.Equal(*changes, want) {
+		t.Errorf("changes:\n%q\nwant:\n%q", *changes, want)
+	}
+}
+
+func TestBreaker_LimitsProbes(t *testing.T) {
+	b, clk, _ := newTestBreaker(Settings{FailureThreshold: 1, OpenTimeout: time.Second})
+	ctx := context.Background()
+	b.Do(ctx, fail)
+	clk.Advance(time.Second)
+
+	release := make(chan struct{})
+	probing := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- b.Do(ctx, func(context.Context) error {
+			close(probing)
+			<-release
+			return nil
+		})
+	}()
+	<-probing
+	if err := b.Do(ctx, succeed); !errors.Is(err, ErrOpen) {
+		t.Errorf("a second probe: err = %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil || b.State() != Closed {
+		t.Errorf("probe: err = %v, state = %s", err, b.State())
+	}
+}
+
+func TestBreaker_IsFailure(t *testing.T) {
+	declined := errors.New("declined")
+	b, _, _ := newTestBreaker(Settings{
+		FailureThreshold: 1,
+		IsFailure:        func(err error) bool { return !errors.Is(err, declined) },
+	})
+
+	v, err := Value(context.Background(), b, func(context.Context) (string, error) { return "", declined })
+	if !errors.Is(err, declined) || v != "" || b.State() != Closed {
+		t.Fatalf("err = %v, state = %s", err, b.State())
+	}
+	v, err = Value(context.Background(), b, func(context.Context) (string, error) { return "ch_1", nil })
+	if err != nil || v != "ch_1" {
+		t.Fatalf("Value = %q, %v", v, err)
+	}
+}
+
+// TestBreaker_Concurrent checks under -race that concurrent failures
+// open the breaker exactly once.
+func TestBreaker_Concurrent(t *testing.T) {
+	var mu sync.Mutex
+	opened := 0
+	b := New(Settings{
+		FailureThreshold: 10,
+		OnStateChange: func(_ string, _, to State) {
+			if to == Open {
+				mu.Lock()
+				opened++
+				mu.Unlock()
+			}
+		},
+	})
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Go(func() {
+			for range 10 {
+				b.Do(context.Background(), fail)
+			}
+		})
+	}
+	wg.Wait()
+	if b.State() != Open || opened != 1 {
+		t.Errorf("state = %s, opened %d times", b.State(), opened)
+	}
+}
@@ -0,0 +1,79 @@
+// Package cache keeps values for a while so they need not be
+// recomputed or fetched again. Code depends on Cache, which stores
+// bytes under string keys with a time to live; LRU implements it in
+// process and Redis in a shared Redis server. Typed layers a value
+// type over any Cache.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrMiss is returned by Get for a key that is absent or expired.
+var ErrMiss = errors.New("cache miss")
+
+// Cache stores values under keys. A ttl of zero keeps a value until it
+// is evicted or deleted.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// Typed stores values of type T in a Cache as JSON, under keys
+// prefixed with its prefix so several types can share one cache.
+type Typed[T any] struct {
+	cache  Cache
+	prefix string
+	ttl    time.Duration
+}
+
+// NewTyped returns a typed view of c whose values live for ttl.
+func NewTyped[T any](c Cache, prefix string, ttl time.Duration) *Typed[T] {
+	return &Typed[T]{cache: c, prefix: prefix, ttl: ttl}
+}
+
+// Get returns the value stored under key, or an error matching ErrMiss.
+func (t *Typed[T]) Get(ctx context.Context, key string) (T, error) {
+	var v T
+	data, err := t.cache.Get(ctx, t.prefix+key)
+	if err != nil {
+		return v, err
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, fmt.Errorf("cache: decoding %s%s: %w", t.prefix, key, err)
+	}
+	return v, nil
+}
+
+func (t *Typed[T]) Set(ctx context.Context, key string, v T) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cache: encoding %s%s: %w", t.prefix, key, err)
+	}
+	return t.cache.Set(ctx, t.prefix+key, data, t.ttl)
+}
+
+func (t *Typed[T]) Delete(ctx context.Context, key string) error {
+	return t.cache.Delete(ctx, t.prefix+key)
+}
+
+// GetOrLoad returns the value cached under key or, on a miss, loads it
+// with load and caches it. A cache that fails is bypassed: the value
+// is loaded, and the cache error dropped, since a cache only ever
+// speeds things up.
+func (t *Typed[T]) GetOrLoad(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	if v, err := t.Get(ctx, key); err == nil {
+		return v, nil
+	}
+	v, err := load(ctx)
+	if err != nil {
+		return v, err
+	}
+	t.Set(ctx, key, v)
+	return v, nil
+}
@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+	"github.com/redis/go-redis/v9"
+)
+
+// testCache is the contract every Cache meets. advance moves the
+// cache's notion of time forward.
+func testCache(t *testing.T, c Cache, advance func(time.Duration)) {
+	ctx := context.Background()
+
+	if _, err := c.Get(ctx, "a"); !errors.Is(err, ErrMiss) {
+		t.Fatalf("Get on an empty cache = %v, want ErrMiss", err)
+	}
+	if err := c.Set(ctx, "a", []byte("1"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set(ctx, "b", []byte("2"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := c.Get(ctx, "a"); err != nil || string(v) != "1" {
+		t.Errorf("Get(a) = %q, %v", v, err)
+	}
+	if err := c.Set(ctx, "a", []byte("3"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := c.Get(ctx, "a"); err != nil || string(v) != "3" {
+		t.Errorf("Get(a) after overwrite = %q, %v", v, err)
+	}
+
+	advance(30 * time.Second)
+	if v, err := c.Get(ctx, "b"); err != nil || string(v) != "2" {
+		t.Errorf("Get(b) before expiry = %q, %v", v, err)
+	}
+	advance(30 * time.Second)
+	if _, err := c.Get(ctx, "b"); !errors.Is(err, ErrMiss) {
+		t.Errorf("Get(b) after expiry = %v, want ErrMiss", err)
+	}
+	if _, err := c.Get(ctx, "a"); err != nil {
+		t.Errorf("Get(a) without a ttl = %v", err)
+	}
+
+	if err := c.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(ctx, "a"); !errors.Is(err, ErrMiss) {
+		t.Errorf("Get(a) after Delete = %v, want ErrMiss", err)
+	}
+	if err := c.Delete(ctx, "missing"); err != nil {
+		t.Errorf("Delete(missing) = %v", err)
+	}
+
+	typed := NewTyped[map[string]int](c, "counts:", 0)
+	if err := typed.Set(ctx, "x", map[string]int{"n": 7}); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := typed.Get(ctx, "x"); err != nil || v["n"] != 7 {
+		t.Errorf("Typed.Get = %v, %v", v, err)
+	}
+	if _, err := c.Get(ctx, "counts:x"); err != nil {
+		t.Errorf("typed value not stored under its prefix: %v", err)
+	}
+}
+
+func TestLRU(t *testing.T) {
+	clk := clocktest.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	testCache(t, NewLRU(16, clk), clk.Advance)
+}
+
+func TestRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	testCache(t, NewRedis(client, "test:"), mr.FastForward)
+	if !mr.Exists("test:counts:x") {
+		t.Errorf("keys = %q, want them namespaced", mr.Keys())
+	}
+}
+
+func TestLRU_Evicts(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2, nil)
+	c.Set(ctx, "a", []byte("a"), 0)
+	c.Set(ctx, "b", []byte("b"), 0)
+	c.Get(ctx, "a") // b is now least recently used
+	c.Set(ctx, "c", []byte("c"), 0)
+
+	if _, err := c.Get(ctx, "b"); !errors.Is(err, ErrMiss) {
+		t.Errorf("Get(b) = %v, want it evicted", err)
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, err := c.Get(ctx, key); err != nil {
+			t.Errorf("Get(%s) = %v", key, err)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want 2", c.Len())
+	}
+}
+
+func TestTyped_GetOrLoad(t *testing.T) {
+	ctx := context.Background()
+	typed := NewTyped[int](NewLRU(4, nil), "n:", 0)
+	loads := 0
+	load := func(context.Context) (int, error) {
+		loads++
+		return 42, nil
+	}
+	for range 3 {
+		if v, err := typed.GetOrLoad(ctx, "k", load); err != nil || v != 42 {
+			t.Fatalf("GetOrLoad = %d, %v", v, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("loaded %d times, want 1", loads)
+	}
+
+	boom := errors.New("boom")
+	_, err := typed.GetOrLoad(ctx, "other", func(context.Context) (int, error) { return 0, boom })
+	if !errors.Is(err, boom) {
+		t.Errorf("GetOrLoad error = %v, want %v", err, boom)
+	}
+	if _, err := typed.Get(ctx, "other"); !errors.Is(err, ErrMiss) {
+		t.Errorf("a failed load was cached: %v", err)
+	}
+}
+
+func TestTyped_BypassesFailingCache(t *testing.T) {
+	typed := NewTyped[string](failingCache{}, "", 0)
+	v, err := typed.GetOrLoad(context.Background(), "k", func(context.Context) (string, error) { return "v", nil })
+	if err != nil || v != "v" {
+		t.Errorf("GetOrLoad = %q, %v", v, err)
+	}
+}
+
+type failingCache struct{}
+
+func (failingCache) Get(context.Context, string) ([]byte, error) {
+	return nil, fmt.Errorf("cache down")
+}
+func (failingCache) Set(context.Context, string, []byte, time.Duration) error {
+	return fmt.Errorf("cache down")
+}
+func (failingCache) Delete(context.Context, string) error { return fmt.Errorf("cache down") }
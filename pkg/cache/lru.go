@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+// LRU is an in-process Cache of bounded size. Once full, setting a new
+// key evicts the least recently used one. It is safe for concurrent
+// use.
+type LRU struct {
+	size  int
+	clock clock.Clock
+
+	mu      sync.Mutex
+	order   *list.List // front is the most recently used
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time // zero for no expiry
+}
+
+// NewLRU returns a cache holding up to size values, at least one. clk
+// may be nil, for the system clock.
+func NewLRU(size int, clk clock.Clock) *LRU {
+	return &LRU{
+		size:    max(size, 1),
+		clock:   clock.OrSystem(clk),
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *LRU) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, ErrMiss
+	}
+	e := el.Value.(*lruEntry)
+	if !e.expires.IsZero() && !c.clock.Now().Before(e.expires) {
+		c.remove(el)
+		return nil, ErrMiss
+	}
+	c.order.MoveToFront(el)
+	return slices.Clone(e.value), nil
+}
+
+func (c *LRU) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	e := &lruEntry{key: key, value: slices.Clone(value)}
+	if ttl > 0 {
+		e.expires = c.clock.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.order.MoveToFront(el)
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(e)
+	if c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *LRU) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	return nil
+}
+
+// Len returns how many values are held, expired ones included until
+// they are next read or evicted.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// remove drops el. c.mu must be held.
+func (c *LRU) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*lruEntry).key)
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is a Cache in a Redis server, shared by every process using
+// it. Its keys are prefixed with a namespace, so services can share a
+// server.
+type Redis struct {
+	client    redis.UniversalClient
+	namespace string
+}
+
+// NewRedis returns a cache over client, which the caller keeps
+// ownership of, storing keys as namespace+key.
+func NewRedis(client redis.UniversalClient, namespace string) *Redis {
+	return &Redis{client: client, namespace: namespace}
+}
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	v, err := r.client.Get(ctx, r.namespace+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cache: getting %s: %w", key, err)
+	}
+	return v, nil
+}
+
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := r.client.Set(ctx, r.namespace+key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("cache: setting %s: %w", key, err)
+	}
+	return nil
+}
+
+func (r *Redis) Delete(ctx context.Context, key string) error {
+	if err := r.client.Del(ctx, r.namespace+key).Err(); err != nil {
+		return fmt.Errorf("cache: deleting %s: %w", key, err)
+	}
+	return nil
+}
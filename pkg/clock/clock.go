@@ -0,0 +1,32 @@
+// Package clock is the source of time for the shared packages.
+// Injecting it keeps timeouts, schedules and rate limits deterministic
+// in tests; see clocktest.
+package clock
+
+import "time"
+
+// Clock tells the time and waits.
+type Clock interface {
+	Now() time.Time
+	// After sends the time on the returned channel once d has passed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// System is the real wall clock.
+type System struct{}
+
+func (System) Now() time.Time {
+	return time.Now()
+}
+
+func (System) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// OrSystem returns c, or System if c is nil.
+func OrSystem(c Clock) Clock {
+	if c == nil {
+		return System{}
+	}
+	return c
+}
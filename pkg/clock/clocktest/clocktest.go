@@ -0,0 +1,111 @@
+// Package clocktest provides a fake clock.Clock for tests.
+package clocktest
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a clock.Clock whose time only moves when Advance is called,
+// or, made by NewAuto, whenever After is. It is safe for concurrent
+// use.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	auto    bool
+	waits   []time.Duration
+	waiters []waiter
+	changed chan struct{} // closed and replaced whenever waiters changes
+}
+
+type waiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewFake returns a fake clock set to start.
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start, changed: make(chan struct{})}
+}
+
+// NewAuto returns a fake clock set to start whose After never blocks:
+// it moves the time forward by d and fires at once. It suits code that
+// only waits, such as a retry's backoff.
+func NewAuto(start time.Time) *Fake {
+	f := NewFake(start)
+	f.auto = true
+	return f
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// After returns a channel that receives the fake time once Advance has
+// moved it d forward. A non-positive d fires immediately.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.waits = append(f.waits, d)
+	ch := make(chan time.Time, 1)
+	if f.auto && d > 0 {
+		f.now = f.now.Add(d)
+	}
+	if d <= 0 || f.auto {
+		ch <- f.now
+		return ch
+	}
+	f.waiters = append(f.waiters, waiter{at: f.now.Add(d), ch: ch})
+	f.notify()
+	return ch
+}
+
+// Advance moves the time forward by d and fires every After that is
+// due.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+	pending := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.at.After(f.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- f.now
+	}
+	f.waiters = pending
+	f.notify()
+}
+
+// Waits returns every d passed to After so far.
+func (f *Fake) Waits() []time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]time.Duration(nil), f.waits...)
+}
+
+// BlockUntil waits until n calls to After are waiting for the time to
+// move. Tests call it before Advance to know the code under test has
+// started waiting.
+func (f *Fake) BlockUntil(n int) {
+	for {
+		f.mu.Lock()
+		waiting, changed := len(f.waiters), f.changed
+		f.mu.Unlock()
+		if waiting >= n {
+			return
+		}
+		<-changed
+	}
+}
+
+// notify wakes BlockUntil. f.mu must be held.
+func (f *Fake) notify() {
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
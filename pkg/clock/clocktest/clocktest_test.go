@@ -0,0 +1,59 @@
+package clocktest
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFake_AfterFiresOnAdvance(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+	soon, later := c.After(time.Second), c.After(time.Minute)
+
+	c.Advance(time.Second)
+	select {
+	case got := <-soon:
+		if !got.Equal(start.Add(time.Second)) {
+			t.Errorf("fired at %v", got)
+		}
+	default:
+		t.Fatal("After(1s) did not fire after 1s")
+	}
+	select {
+	case <-later:
+		t.Fatal("After(1m) fired after 1s")
+	default:
+	}
+
+	c.Advance(time.Minute)
+	<-later
+}
+
+func TestFake_BlockUntil(t *testing.T) {
+	c := NewFake(time.Time{})
+	done := make(chan struct{})
+	go func() {
+		<-c.After(time.Hour)
+		close(done)
+	}()
+
+	c.BlockUntil(1)
+	c.Advance(time.Hour)
+	<-done
+}
+
+func TestAuto_AfterAdvancesAtOnce(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewAuto(start)
+	if got := <-c.After(time.Second); !got.Equal(start.Add(time.Second)) {
+		t.Errorf("fired at %v", got)
+	}
+	<-c.After(time.Minute)
+	if got, want := c.Waits(), []time.Duration{time.Second, time.Minute}; !slices.Equal(got, want) {
+		t.Errorf("Waits = %v, want %v", got, want)
+	}
+	if got := c.Now(); !got.Equal(start.Add(time.Minute + time.Second)) {
+		t.Errorf("Now = %v after both waits", got)
+	}
+}
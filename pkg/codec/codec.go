@@ -0,0 +1,123 @@
+// Package codec turns values into bytes and back in one of several
+// formats. Code that serializes depends on the Codec port; JSON, XML
+// and Protobuf implement it, and a Registry picks one by content type,
+// such as from an HTTP Accept header or a message Envelope.
+package codec
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrUnsupported matches a format nobody registered, or a value a
+// codec cannot handle.
+var ErrUnsupported = errors.New("unsupported format")
+
+// Encoder writes v to w.
+type Encoder interface {
+	Encode(w io.Writer, v any) error
+}
+
+// Decoder reads r into v, which must be a pointer.
+type Decoder interface {
+	Decode(r io.Reader, v any) error
+}
+
+// Codec is an Encoder and Decoder for one format, named by its MIME
+// content type.
+type Codec interface {
+	Encoder
+	Decoder
+	ContentType() string
+}
+
+// ProtoMarshaler and ProtoUnmarshaler are implemented by types that
+// write their protobuf wire format themselves, such as with protowire,
+// where no generated code is wanted. Generated messages (proto.Message)
+// work with Protobuf as they are.
+type ProtoMarshaler interface {
+	MarshalProto() ([]byte, error)
+}
+
+type ProtoUnmarshaler interface {
+	UnmarshalProto(data []byte) error
+}
+
+// The codecs this package provides.
+var (
+	JSON     Codec = jsonCodec{}
+	XML      Codec = xmlCodec{}
+	Protobuf Codec = protobufCodec{}
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string { return "application/json" }
+
+func (jsonCodec) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+func (jsonCodec) Decode(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+type xmlCodec struct{}
+
+func (xmlCodec) ContentType() string { return "application/xml" }
+
+func (xmlCodec) Encode(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(v)
+}
+
+func (xmlCodec) Decode(r io.Reader, v any) error {
+	return xml.NewDecoder(r).Decode(v)
+}
+
+type protobufCodec struct{}
+
+func (protobufCodec) ContentType() string { return "application/x-protobuf" }
+
+func (protobufCodec) Encode(w io.Writer, v any) error {
+	var (
+		data []byte
+		err  error
+	)
+	switch m := v.(type) {
+	case ProtoMarshaler:
+		data, err = m.MarshalProto()
+	case proto.Message:
+		data, err = proto.Marshal(m)
+	default:
+		return fmt.Errorf("%w: %T is not a protobuf message", ErrUnsupported, v)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (protobufCodec) Decode(r io.Reader, v any) error {
+	switch v.(type) {
+	case ProtoUnmarshaler, proto.Message:
+	default:
+		return fmt.Errorf("%w: %T is not a protobuf message", ErrUnsupported, v)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if m, ok := v.(ProtoUnmarshaler); ok {
+		return m.UnmarshalProto(data)
+	}
+	return proto.Unmarshal(data, v.(proto.Message))
+}
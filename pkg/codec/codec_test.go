@@ -0,0 +1,155 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// note writes its own protobuf wire format:
+//
+//	message Note { string title = 1; int64 count = 2; repeated string tags = 3; }
+type note struct {
+	Title string   `json:"title" xml:"title"`
+	Count int64    `json:"count" xml:"count"`
+	Tags  []string `json:"tags" xml:"tag"`
+}
+
+func (n note) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, n.Title)
+	b = protowire.AppendTag(b, 2, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(n.Count))
+	for _, tag := range n.Tags {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendString(b, tag)
+	}
+	return b, nil
+}
+
+func (n *note) UnmarshalProto(b []byte) error {
+	*n = note{}
+	for len(b) > 0 {
+		num, typ, size := protowire.ConsumeTag(b)
+		if size < 0 {
+			return protowire.ParseError(size)
+		}
+		b = b[size:]
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			n.Title, size = protowire.ConsumeString(b)
+		case num == 2 && typ == protowire.VarintType:
+			var v uint64
+			v, size = protowire.ConsumeVarint(b)
+			n.Count = int64(v)
+		case num == 3 && typ == protowire.BytesType:
+			var tag string
+			tag, size = protowire.ConsumeString(b)
+			n.Tags = append(n.Tags, tag)
+		default:
+			size = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if size < 0 {
+			return protowire.ParseError(size)
+		}
+		b = b[size:]
+	}
+	return nil
+}
+
+// TestCodecs is the contract every Codec meets: what it encodes, it
+// decodes back unchanged.
+func TestCodecs(t *testing.T) {
+	want := note{Title: "Grüße <&>", Count: -42, Tags: []string{"a", "b"}}
+	for _, c := range []Codec{JSON, XML, Protobuf} {
+		t.Run(c.ContentType(), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := c.Encode(&buf, want); err != nil {
+				t.Fatal(err)
+			}
+			var got note
+			if err := c.Decode(&buf, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestProtobuf_GeneratedMessages(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Protobuf.Encode(&buf, wrapperspb.String("hello")); err != nil {
+		t.Fatal(err)
+	}
+	got := &wrapperspb.StringValue{}
+	if err := Protobuf.Decode(&buf, got); err != nil || got.GetValue() != "hello" {
+		t.Errorf("round trip = %v, %v", got, err)
+	}
+
+	if err := Protobuf.Encode(&buf, struct{}{}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Encode(struct{}{}): err = %v, want ErrUnsupported", err)
+	}
+	if err := Protobuf.Decode(&buf, new(int)); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Decode(*int): err = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestRegistry_Negotiate(t *testing.T) {
+	r := NewRegistry(JSON, XML, Protobuf)
+	tests := []struct {
+		accept string
+		want   Codec
+	}{
+		{"", JSON},
+		{"*/*", JSON},
+		{"application/xml", XML},
+		{"text/html, application/xml;q=0.9, */*;q=0.1", XML},
+		{"application/json;q=0.5, application/x-protobuf", Protobuf},
+		{"application/*;q=0.8, application/xml", XML},
+		{"text/html", nil},
+	}
+	for _, tt := range tests {
+		got, err := r.Negotiate(tt.accept)
+		if tt.want == nil {
+			if !errors.Is(err, ErrUnsupported) {
+				t.Errorf("Negotiate(%q) = %v, %v; want ErrUnsupported", tt.accept, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Negotiate(%q) = %v, %v; want %s", tt.accept, got, err, tt.want.ContentType())
+		}
+	}
+
+	if c, err := r.Lookup("application/json; charset=utf-8"); err != nil || c != JSON {
+		t.Errorf("Lookup with a charset = %v, %v", c, err)
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	r := NewRegistry(JSON, XML, Protobuf)
+	want := note{Title: "order.placed", Count: 7}
+	for _, c := range []Codec{JSON, XML, Protobuf} {
+		data, err := Seal(c, want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got note
+		if err := r.Open(data, &got); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s envelope: %+v, %v", c.ContentType(), got, err)
+		}
+	}
+
+	for _, data := range []string{`{"title":"x"}`, "text/csv\nx,y"} {
+		if err := r.Open([]byte(data), new(note)); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Open(%q): err = %v, want ErrUnsupported", data, err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package codec
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Registry finds codecs by content type. The first codec is the
+// default. A Registry is read-only once built, so it is safe for
+// concurrent use.
+type Registry struct {
+	codecs []Codec
+}
+
+// NewRegistry returns a registry of codecs; the first is the default.
+func NewRegistry(codecs ...Codec) *Registry {
+	return &Registry{codecs: slices.Clone(codecs)}
+}
+
+// Default returns the first codec.
+func (r *Registry) Default() Codec {
+	return r.codecs[0]
+}
+
+// Lookup returns the codec for contentType, ignoring parameters such as
+// charset.
+func (r *Registry) Lookup(contentType string) (Codec, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupported, contentType)
+	}
+	for _, c := range r.codecs {
+		if c.ContentType() == mediaType {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupported, mediaType)
+}
+
+// Negotiate returns the codec an HTTP Accept header prefers: the
+// registered type with the highest q value, wildcards such as "*/*"
+// and "application/*" included. An empty header accepts the default.
+func (r *Registry) Negotiate(accept string) (Codec, error) {
+	if strings.TrimSpace(accept) == "" {
+		return r.Default(), nil
+	}
+	var (
+		best  Codec
+		bestQ float64
+	)
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= bestQ {
+			continue
+		}
+		for _, c := range r.codecs {
+			if matches(mediaType, c.ContentType()) {
+				best, bestQ = c, q
+				break
+			}
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("%w: none of %q", ErrUnsupported, accept)
+	}
+	return best, nil
+}
+
+func matches(pattern, contentType string) bool {
+	if pattern == "*/*" || pattern == contentType {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(pattern, "/*")
+	return ok && strings.HasPrefix(contentType, prefix+"/")
+}
+
+// Seal encodes v with c into an envelope: the content type, a newline
+// and the payload, so the receiver can Open it without knowing which
+// codec the sender chose.
+func Seal(c Codec, v any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(c.ContentType())
+	buf.WriteByte('\n')
+	if err := c.Encode(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Open decodes the envelope data, made by Seal, into v with the codec
+// for its content type.
+func (r *Registry) Open(data []byte, v any) error {
+	contentType, payload, ok := bytes.Cut(data, []byte("\n"))
+	if !ok {
+		return fmt.Errorf("%w: no envelope", ErrUnsupported)
+	}
+	c, err := r.Lookup(string(contentType))
+	if err != nil {
+		return err
+	}
+	return c.Decode(bytes.NewReader(payload), v)
+}
@@ -0,0 +1,135 @@
+// Package commandbus dispatches commands, such as PlaceOrder, to the
+// one handler registered for each. Transports build a command and
+// dispatch it instead of calling a service, and every command passes
+// through the same pkg/middleware chain: validation, logging, a
+// transaction and whatever else the bus was built with.
+package commandbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/middleware"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/validate"
+)
+
+var (
+	ErrNoHandler        = errors.New("no handler for command")
+	ErrDuplicateHandler = errors.New("command already has a handler")
+)
+
+// Command is a request to change state. CommandName names the command
+// and must not depend on its fields: the bus finds the handler by it.
+type Command interface {
+	CommandName() string
+}
+
+// Middleware wraps the handling of every command on a bus.
+type Middleware = middleware.Middleware[Command, any]
+
+// Bus routes commands to their handlers. It is safe for concurrent
+// use.
+type Bus struct {
+	mws []Middleware
+
+	mu       sync.RWMutex
+	handlers map[string]middleware.Handler[Command, any]
+}
+
+// New returns a bus that runs every command through mws, the first
+// outermost.
+func New(mws ...Middleware) *Bus {
+	return &Bus{mws: mws, handlers: make(map[string]middleware.Handler[Command, any])}
+}
+
+// Route dispatches commands of type C, whose handler returns an R. It
+// is what Register returns, so a command dispatched through a Route
+// has a handler of the right types, checked at compile time.
+type Route[C Command, R any] struct {
+	bus  *Bus
+	name string
+}
+
+// Register makes h the handler of the commands of type C.
+func Register[C Command, R any](b *Bus, h middleware.Handler[C, R]) (Route[C, R], error) {
+	var zero C
+	name := zero.CommandName()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.handlers[name]; ok {
+		return Route[C, R]{}, fmt.Errorf("%w: %s", ErrDuplicateHandler, name)
+	}
+	b.handlers[name] = middleware.Chain(func(ctx context.Context, cmd Command) (any, error) {
+		return h(ctx, cmd.(C))
+	}, b.mws...)
+	return Route[C, R]{bus: b, name: name}, nil
+}
+
+// Dispatch runs cmd through the bus.
+func (r Route[C, R]) Dispatch(ctx context.Context, cmd C) (R, error) {
+	res, err := r.bus.Dispatch(ctx, cmd)
+	out, _ := res.(R)
+	return out, err
+}
+
+// Dispatch runs cmd through the middleware to its handler and returns
+// the handler's result. It fails with ErrNoHandler for a command
+// nothing was registered for.
+func (b *Bus) Dispatch(ctx context.Context, cmd Command) (any, error) {
+	name := cmd.CommandName()
+	b.mu.RLock()
+	h, ok := b.handlers[name]
+	b.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoHandler, name)
+	}
+	return h(ctx, cmd)
+}
+
+// Validate rejects a command implementing validate.Validator whose
+// Validate fails, before its handler runs. The error wraps the
+// command's, so invalid fields stay visible to apierror.
+func Validate() Middleware {
+	return func(next middleware.Handler[Command, any]) middleware.Handler[Command, any] {
+		return func(ctx context.Context, cmd Command) (any, error) {
+			if v, ok := cmd.(validate.Validator); ok {
+				if err := v.Validate(); err != nil {
+					return nil, fmt.Errorf("%s: %w", cmd.CommandName(), err)
+				}
+			}
+			return next(ctx, cmd)
+		}
+	}
+}
+
+// Logging logs every command, as "command <name>", with its duration
+// and, if it failed, its error. A nil clk means the system clock.
+func Logging(log middleware.Logger, clk clock.Clock) Middleware {
+	return func(next middleware.Handler[Command, any]) middleware.Handler[Command, any] {
+		return func(ctx context.Context, cmd Command) (any, error) {
+			return middleware.Logging[Command, any]("command "+cmd.CommandName(), log, clk)(next)(ctx, cmd)
+		}
+	}
+}
+
+// TxFunc runs fn in a transaction, committing it if fn returns nil and
+// rolling it back otherwise. The transaction travels in the context fn
+// is given.
+type TxFunc func(ctx context.Context, fn func(ctx context.Context) error) error
+
+// Transaction runs every handler in a transaction begun by tx.
+func Transaction(tx TxFunc) Middleware {
+	return func(next middleware.Handler[Command, any]) middleware.Handler[Command, any] {
+		return func(ctx context.Context, cmd Command) (res any, err error) {
+			err = tx(ctx, func(ctx context.Context) error {
+				res, err = next(ctx, cmd)
+				return err
+			})
+			return res, err
+		}
+	}
+}
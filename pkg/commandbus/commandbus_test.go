@@ -0,0 +1,101 @@
+package commandbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/validate"
+)
+
+type greet struct{ Name string }
+
+func (greet) CommandName() string { return "Greet" }
+
+func (g greet) Validate() error {
+	var errs validate.Errors
+	if g.Name == "" {
+		errs.Add("name", "is required")
+	}
+	return errs.Err()
+}
+
+type count struct{ N int }
+
+func (count) CommandName() string { return "Count" }
+
+// lines is a middleware.Logger collecting its lines.
+type lines []string
+
+func (l *lines) Printf(format string, args ...any) {
+	*l = append(*l, fmt.Sprintf(format, args...))
+}
+
+func TestBus(t *testing.T) {
+	var log lines
+	bus := New(Logging(&log, nil), Validate())
+	ctx := context.Background()
+
+	greetings, err := Register(bus, func(ctx context.Context, cmd greet) (string, error) {
+		return "hello " + cmd.Name, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := greetings.Dispatch(ctx, greet{Name: "Ada"})
+	if err != nil || got != "hello Ada" {
+		t.Fatalf("Dispatch = %q, %v", got, err)
+	}
+
+	_, err = greetings.Dispatch(ctx, greet{})
+	var fields validate.Errors
+	if !errors.Is(err, validate.ErrInvalid) || !errors.As(err, &fields) || fields[0].Field != "name" {
+		t.Errorf("invalid command: err = %v", err)
+	}
+
+	if _, err := Register(bus, func(ctx context.Context, cmd greet) (int, error) { return 0, nil }); !errors.Is(err, ErrDuplicateHandler) {
+		t.Errorf("second handler: err = %v, want ErrDuplicateHandler", err)
+	}
+	if _, err := bus.Dispatch(ctx, count{}); !errors.Is(err, ErrNoHandler) {
+		t.Errorf("unregistered command: err = %v, want ErrNoHandler", err)
+	}
+
+	if len(log) != 2 || !strings.HasPrefix(log[0], "command Greet took") || !strings.HasPrefix(log[1], "command Greet failed") {
+		t.Errorf("log = %q", log)
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	var committed, rolledBack int
+	tx := func(ctx context.Context, fn func(ctx context.Context) error) error {
+		if err := fn(ctx); err != nil {
+			rolledBack++
+			return err
+		}
+		committed++
+		return nil
+	}
+	bus := New(Transaction(tx))
+	errOdd := errors.New("odd")
+	counts, err := Register(bus, func(ctx context.Context, cmd count) (int, error) {
+		if cmd.N%2 == 1 {
+			return 0, errOdd
+		}
+		return cmd.N * 2, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := counts.Dispatch(context.Background(), count{N: 2}); n != 4 || err != nil {
+		t.Errorf("Dispatch(2) = %d, %v", n, err)
+	}
+	if _, err := counts.Dispatch(context.Background(), count{N: 3}); !errors.Is(err, errOdd) {
+		t.Errorf("Dispatch(3) = %v, want errOdd", err)
+	}
+	if committed != 1 || rolledBack != 1 {
+		t.Errorf("committed %d, rolled back %d; want 1 and 1", committed, rolledBack)
+	}
+}
@@ -0,0 +1,196 @@
+// Package consumer processes a partitioned log of records, Kafka
+// style. A Consumer reads its share of a topic's partitions, hands
+// each record to a MessageHandler and commits the record's offset
+// only once the handler succeeds. A failed record is retried with
+// exponential backoff, and a consumer that stops before committing
+// leaves the record to be redelivered to the next one: delivery is at
+// least once, so handlers must be idempotent.
+//
+// The Broker port stands for the log; MemoryBroker implements it in
+// process.
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+var ErrInvalidConfig = errors.New("invalid consumer config")
+
+// Record is one entry of a partition.
+type Record struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+}
+
+// MessageHandler processes a record. Returning an error has the
+// record retried.
+type MessageHandler interface {
+	Handle(ctx context.Context, r Record) error
+}
+
+// HandlerFunc lets a plain function be used as a MessageHandler.
+type HandlerFunc func(ctx context.Context, r Record) error
+
+func (f HandlerFunc) Handle(ctx context.Context, r Record) error {
+	return f(ctx, r)
+}
+
+// Broker is a partitioned log with offsets committed per consumer
+// group.
+type Broker interface {
+	// Partitions returns how many partitions topic has.
+	Partitions(ctx context.Context, topic string) (int, error)
+	// Fetch returns up to max records of a partition from offset on,
+	// waiting until there is at least one or ctx is done.
+	Fetch(ctx context.Context, topic string, partition int, offset int64, max int) ([]Record, error)
+	// Committed returns the offset group resumes a partition from, 0
+	// if it never committed one.
+	Committed(ctx context.Context, group, topic string, partition int) (int64, error)
+	// Commit records that group processed the partition up to, but
+	// not including, offset.
+	Commit(ctx context.Context, group, topic string, partition int, offset int64) error
+}
+
+// Config configures a Consumer.
+type Config struct {
+	Group string
+	Topic string
+	// Member and Members place the consumer in its group: it reads the
+	// partitions p with p%Members == Member. Zero Members means one.
+	Member, Members int
+	// BatchSize is the most records fetched at once; 100 by default.
+	BatchSize int
+	// Backoff is the delay before retrying a failed record, doubled on
+	// every further attempt up to MaxBackoff. They default to 100ms and
+	// 30s.
+	Backoff, MaxBackoff time.Duration
+	// OnError, which may be nil, is called on every failed attempt.
+	OnError func(r Record, err error, attempt int)
+	// Clock defaults to the system clock.
+	Clock clock.Clock
+}
+
+// Consumer is one member of a consumer group.
+type Consumer struct {
+	broker  Broker
+	handler MessageHandler
+	cfg     Config
+}
+
+// New returns a consumer reading from broker into h.
+func New(broker Broker, h MessageHandler, cfg Config) (*Consumer, error) {
+	if cfg.Members == 0 {
+		cfg.Members = 1
+	}
+	switch {
+	case cfg.Group == "" || cfg.Topic == "":
+		return nil, fmt.Errorf("%w: group and topic are required", ErrInvalidConfig)
+	case cfg.Members < 0 || cfg.Member < 0 || cfg.Member >= cfg.Members:
+		return nil, fmt.Errorf("%w: member %d of %d", ErrInvalidConfig, cfg.Member, cfg.Members)
+	case cfg.BatchSize < 0 || cfg.Backoff < 0 || cfg.MaxBackoff < 0:
+		return nil, fmt.Errorf("%w: negative batch size or backoff", ErrInvalidConfig)
+	}
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.Backoff == 0 {
+		cfg.Backoff = 100 * time.Millisecond
+	}
+	if cfg.MaxBackoff == 0 {
+		cfg.MaxBackoff = 30 * time.Second
+	}
+	if cfg.OnError == nil {
+		cfg.OnError = func(Record, error, int) {}
+	}
+	cfg.Clock = clock.OrSystem(cfg.Clock)
+	return &Consumer{broker: broker, handler: h, cfg: cfg}, nil
+}
+
+// Run consumes the member's partitions, one goroutine each, until ctx
+// is done, when it returns nil. A broker error stops every partition
+// and is returned.
+func (c *Consumer) Run(ctx context.Context) error {
+	n, err := c.broker.Partitions(ctx, c.cfg.Topic)
+	if err != nil {
+		return fmt.Errorf("consumer: partitions of %s: %w", c.cfg.Topic, err)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for p := c.cfg.Member; p < n; p += c.cfg.Members {
+		wg.Go(func() {
+			if err := c.consume(ctx, p); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+				cancel()
+			}
+		})
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// consume processes one partition from its committed offset on.
+func (c *Consumer) consume(ctx context.Context, partition int) error {
+	offset, err := c.broker.Committed(ctx, c.cfg.Group, c.cfg.Topic, partition)
+	if err != nil {
+		return c.stopped(ctx, fmt.Errorf("consumer: committed offset of %s/%d: %w", c.cfg.Topic, partition, err))
+	}
+	for {
+		records, err := c.broker.Fetch(ctx, c.cfg.Topic, partition, offset, c.cfg.BatchSize)
+		if err != nil {
+			return c.stopped(ctx, fmt.Errorf("consumer: fetching %s/%d@%d: %w", c.cfg.Topic, partition, offset, err))
+		}
+		for _, r := range records {
+			if err := c.handle(ctx, r); err != nil {
+				return nil // ctx is done; the record stays uncommitted
+			}
+			offset = r.Offset + 1
+			if err := c.broker.Commit(ctx, c.cfg.Group, c.cfg.Topic, partition, offset); err != nil {
+				return c.stopped(ctx, fmt.Errorf("consumer: committing %s/%d@%d: %w", c.cfg.Topic, partition, offset, err))
+			}
+		}
+	}
+}
+
+// handle retries r until the handler succeeds or ctx is done.
+func (c *Consumer) handle(ctx context.Context, r Record) error {
+	delay := c.cfg.Backoff
+	for attempt := 1; ; attempt++ {
+		err := c.handler.Handle(ctx, r)
+		if err == nil {
+			return nil
+		}
+		c.cfg.OnError(r, err, attempt)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.cfg.Clock.After(delay):
+		}
+		delay = min(delay*2, c.cfg.MaxBackoff)
+	}
+}
+
+// stopped returns nil for an error caused by ctx being done, which is
+// how Run is stopped, and err otherwise.
+func (c *Consumer) stopped(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
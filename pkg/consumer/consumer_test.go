@@ -0,0 +1,235 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+)
+
+// collector is a handler recording the values it handles.
+type collector struct {
+	mu     sync.Mutex
+	values []string
+	done   chan struct{}
+	want   int
+}
+
+func newCollector(want int) *collector {
+	return &collector{done: make(chan struct{}), want: want}
+}
+
+func (c *collector) Handle(ctx context.Context, r Record) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values = append(c.values, string(r.Value))
+	if len(c.values) == c.want {
+		close(c.done)
+	}
+	return nil
+}
+
+func (c *collector) wait(t *testing.T) []string {
+	t.Helper()
+	select {
+	case <-c.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for records")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.values)
+}
+
+// run starts c and returns a function stopping it and returning Run's
+// error.
+func run(t *testing.T, c *Consumer) (stop func() error) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- c.Run(ctx) }()
+	return func() error {
+		cancel()
+		return <-errc
+	}
+}
+
+func TestNew_Validates(t *testing.T) {
+	for _, cfg := range []Config{
+		{Topic: "orders"},
+		{Group: "mail"},
+		{Group: "mail", Topic: "orders", Member: 2, Members: 2},
+		{Group: "mail", Topic: "orders", Backoff: -time.Second},
+	} {
+		if _, err := New(NewMemoryBroker(1), HandlerFunc(nil), cfg); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("New(%+v) = %v, want ErrInvalidConfig", cfg, err)
+		}
+	}
+}
+
+func TestConsumer_KeepsKeyOrderAndCommits(t *testing.T) {
+	b := NewMemoryBroker(3)
+	var want []string
+	for i := range 30 {
+		key := fmt.Sprintf("order-%d", i%5)
+		v := fmt.Sprintf("%s#%d", key, i)
+		b.Produce("orders", []byte(key), []byte(v))
+		want = append(want, v)
+	}
+	h := newCollector(len(want))
+	c, err := New(b, h, Config{Group: "mail", Topic: "orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := run(t, c)
+	got := h.wait(t)
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each key's records arrive in the order produced.
+	for k := range 5 {
+		otherKey := func(v string) bool { return !strings.HasPrefix(v, fmt.Sprintf("order-%d#", k)) }
+		gotKey, wantKey := slices.DeleteFunc(slices.Clone(got), otherKey), slices.DeleteFunc(slices.Clone(want), otherKey)
+		if !slices.Equal(gotKey, wantKey) {
+			t.Errorf("key %d: got %q, want %q", k, gotKey, wantKey)
+		}
+	}
+	var committed int64
+	for p := range 3 {
+		off, _ := b.Committed(context.Background(), "mail", "orders", p)
+		committed += off
+	}
+	if committed != int64(len(want)) {
+		t.Errorf("committed %d offsets, want %d", committed, len(want))
+	}
+}
+
+func TestConsumer_RetriesWithBackoff(t *testing.T) {
+	clk := clocktest.NewFake(time.Time{})
+	b := NewMemoryBroker(1)
+	b.Produce("orders", nil, []byte("1"))
+	b.Produce("orders", nil, []byte("2"))
+
+	errDown := errors.New("mail server down")
+	var attempts []int
+	h := newCollector(2)
+	flaky := HandlerFunc(func(ctx context.Context, r Record) error {
+		if string(r.Value) == "1" && len(attempts) < 3 {
+			return errDown
+		}
+		return h.Handle(ctx, r)
+	})
+	c, err := New(b, flaky, Config{
+		Group: "mail", Topic: "orders", Clock: clk,
+		Backoff: time.Second, MaxBackoff: 3 * time.Second,
+		OnError: func(r Record, err error, attempt int) { attempts = append(attempts, attempt) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := run(t, c)
+	for _, d := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second} {
+		clk.BlockUntil(1)
+		clk.Advance(d)
+	}
+	if got := h.wait(t); !slices.Equal(got, []string{"1", "2"}) {
+		t.Errorf("handled %q, want the failing record first", got)
+	}
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(attempts, []int{1, 2, 3}) {
+		t.Errorf("attempts = %v", attempts)
+	}
+}
+
+func TestConsumer_RedeliversUncommitted(t *testing.T) {
+	b := NewMemoryBroker(1)
+	for _, v := range []string{"1", "2", "3"} {
+		b.Produce("orders", nil, []byte(v))
+	}
+
+	// The first consumer stops while handling record 2.
+	handling := make(chan struct{})
+	var first []string
+	stuck := HandlerFunc(func(ctx context.Context, r Record) error {
+		if string(r.Value) == "2" {
+			close(handling)
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		first = append(first, string(r.Value))
+		return nil
+	})
+	c, err := New(b, stuck, Config{Group: "mail", Topic: "orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := run(t, c)
+	<-handling
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	h := newCollector(2)
+	c, err = New(b, h, Config{Group: "mail", Topic: "orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop = run(t, c)
+	got := h.wait(t)
+	stop()
+	if !slices.Equal(first, []string{"1"}) || !slices.Equal(got, []string{"2", "3"}) {
+		t.Errorf("first consumer handled %q, second %q; want 1, then 2 and 3", first, got)
+	}
+}
+
+func TestConsumer_GroupSplitsPartitions(t *testing.T) {
+	b := NewMemoryBroker(4)
+	for i := range 40 {
+		b.Produce("orders", nil, []byte(fmt.Sprint(i)))
+	}
+	a, z := newCollector(20), newCollector(20)
+	var stops []func() error
+	for member, h := range []*collector{a, z} {
+		c, err := New(b, h, Config{Group: "mail", Topic: "orders", Member: member, Members: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stops = append(stops, run(t, c))
+	}
+	all := append(a.wait(t), z.wait(t)...)
+	for _, stop := range stops {
+		stop()
+	}
+	slices.Sort(all)
+	if len(slices.Compact(all)) != 40 {
+		t.Errorf("handled %d distinct records, want 40", len(all))
+	}
+}
+
+func TestMemoryBroker_FetchWaits(t *testing.T) {
+	b := NewMemoryBroker(1)
+	got := make(chan []Record)
+	go func() {
+		records, _ := b.Fetch(context.Background(), "orders", 0, 0, 10)
+		got <- records
+	}()
+	b.Produce("orders", nil, []byte("1"))
+	if records := <-got; len(records) != 1 || string(records[0].Value) != "1" {
+		t.Errorf("Fetch = %+v", records)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := b.Fetch(ctx, "orders", 0, 1, 10); !errors.Is(err, context.Canceled) {
+		t.Errorf("Fetch with a cancelled ctx = %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+)
+
+// MemoryBroker is an in-process Broker. Every topic has the same
+// number of partitions and is created by its first record. It is safe
+// for concurrent use.
+type MemoryBroker struct {
+	partitions int
+
+	mu      sync.Mutex
+	logs    map[string][][]Record // topic -> partition -> records
+	next    map[string]int        // round-robin partition for keyless records
+	offsets map[groupPartition]int64
+	wake    chan struct{} // closed and replaced when a record arrives
+}
+
+type groupPartition struct {
+	group, topic string
+	partition    int
+}
+
+// NewMemoryBroker returns an empty broker whose topics have the given
+// number of partitions, at least one.
+func NewMemoryBroker(partitions int) *MemoryBroker {
+	return &MemoryBroker{
+		partitions: max(partitions, 1),
+		logs:       make(map[string][][]Record),
+		next:       make(map[string]int),
+		offsets:    make(map[groupPartition]int64),
+		wake:       make(chan struct{}),
+	}
+}
+
+// Produce appends a record to topic and returns it. Records with the
+// same key go to the same partition, so they are processed in order;
+// keyless ones are spread round-robin.
+func (b *MemoryBroker) Produce(topic string, key, value []byte) Record {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	log, ok := b.logs[topic]
+	if !ok {
+		log = make([][]Record, b.partitions)
+	}
+	var p int
+	if key == nil {
+		p = b.next[topic]
+		b.next[topic] = (p + 1) % b.partitions
+	} else {
+		h := fnv.New32a()
+		h.Write(key)
+		p = int(h.Sum32() % uint32(b.partitions))
+	}
+	r := Record{Topic: topic, Partition: p, Offset: int64(len(log[p])), Key: key, Value: value}
+	log[p] = append(log[p], r)
+	b.logs[topic] = log
+
+	close(b.wake)
+	b.wake = make(chan struct{})
+	return r
+}
+
+// Publish produces a keyless record, so the broker can stand in for an
+// eventbus.Publisher.
+func (b *MemoryBroker) Publish(ctx context.Context, topic string, data []byte) error {
+	b.Produce(topic, nil, data)
+	return nil
+}
+
+func (b *MemoryBroker) Partitions(ctx context.Context, topic string) (int, error) {
+	return b.partitions, nil
+}
+
+func (b *MemoryBroker) Fetch(ctx context.Context, topic string, partition int, offset int64, max int) ([]Record, error) {
+	if partition < 0 || partition >= b.partitions {
+		return nil, fmt.Errorf("partition %d of %s does not exist", partition, topic)
+	}
+	for {
+		b.mu.Lock()
+		var records []Record
+		if log := b.logs[topic]; log != nil && offset < int64(len(log[partition])) {
+			records = log[partition][offset:min(offset+int64(max), int64(len(log[partition])))]
+		}
+		wake := b.wake
+		b.mu.Unlock()
+
+		if len(records) > 0 {
+			return slices.Clone(records), nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-wake:
+		}
+	}
+}
+
+func (b *MemoryBroker) Committed(ctx context.Context, group, topic string, partition int) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.offsets[groupPartition{group, topic, partition}], nil
+}
+
+func (b *MemoryBroker) Commit(ctx context.Context, group, topic string, partition int, offset int64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.offsets[groupPartition{group, topic, partition}] = offset
+	return nil
+}
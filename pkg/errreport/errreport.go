@@ -0,0 +1,136 @@
+// Package errreport sends errors that need an operator's attention to
+// a crash-reporting service. Code that fails depends on Reporter;
+// whether the errors end up in memory, in a Sentry-style HTTP endpoint
+// or nowhere is chosen where it is wired.
+package errreport
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+// Reporter captures errors. Capture must not block for long: it is
+// called on error paths and while recovering from panics.
+type Reporter interface {
+	Capture(ctx context.Context, err error, tags map[string]string)
+}
+
+// Nop discards every error.
+type Nop struct{}
+
+func (Nop) Capture(context.Context, error, map[string]string) {}
+
+// OrNop returns r, or Nop if r is nil.
+func OrNop(r Reporter) Reporter {
+	if r == nil {
+		return Nop{}
+	}
+	return r
+}
+
+// PanicError is a recovered panic, with the stack of the goroutine
+// that panicked.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Guard calls fn and returns its error. If fn panics, Guard recovers,
+// captures a *PanicError with r and returns it, so a worker goroutine
+// survives a bad job. r may be nil.
+func Guard(ctx context.Context, r Reporter, tags map[string]string, fn func() error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			perr := &PanicError{Value: v, Stack: debug.Stack()}
+			OrNop(r).Capture(ctx, perr, tags)
+			err = perr
+		}
+	}()
+	return fn()
+}
+
+// Event is a captured error.
+type Event struct {
+	Time  time.Time
+	Err   error
+	Tags  map[string]string
+	Stack []byte // set for a *PanicError
+}
+
+func newEvent(clk clock.Clock, err error, tags map[string]string) Event {
+	e := Event{Time: clk.Now(), Err: err, Tags: make(map[string]string, len(tags))}
+	for k, v := range tags {
+		e.Tags[k] = v
+	}
+	if perr, ok := err.(*PanicError); ok {
+		e.Stack = perr.Stack
+	}
+	return e
+}
+
+// Memory keeps the most recent events in memory, for tests and for
+// inspecting a running process. It is safe for concurrent use.
+type Memory struct {
+	clock clock.Clock
+
+	mu     sync.Mutex
+	events []Event // a ring of at most cap(events)
+	next   int
+	total  int
+}
+
+// NewMemory returns a Memory keeping the last size events. A nil clk
+// means the system clock.
+func NewMemory(size int, clk clock.Clock) *Memory {
+	return &Memory{clock: clock.OrSystem(clk), events: make([]Event, 0, max(size, 1))}
+}
+
+func (m *Memory) Capture(ctx context.Context, err error, tags map[string]string) {
+	if err == nil {
+		return
+	}
+	e := newEvent(m.clock, err, tags)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.events) < cap(m.events) {
+		m.events = append(m.events, e)
+	} else {
+		m.events[m.next] = e
+	}
+	m.next = (m.next + 1) % cap(m.events)
+	m.total++
+}
+
+// Events returns the kept events, oldest first.
+func (m *Memory) Events() []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.events) < cap(m.events) {
+		return append([]Event(nil), m.events...)
+	}
+	return append(append([]Event(nil), m.events[m.next:]...), m.events[:m.next]...)
+}
+
+// Total returns the number of events captured, including the ones no
+// longer kept.
+func (m *Memory) Total() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.total
+}
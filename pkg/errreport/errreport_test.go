@@ -0,0 +1,128 @@
+package errreport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+)
+
+func TestMemory_KeepsTheLastEvents(t *testing.T) {
+	m := NewMemory(2, clocktest.NewFake(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)))
+	ctx := context.Background()
+	for i := range 3 {
+		m.Capture(ctx, fmt.Errorf("error %d", i), map[string]string{"op": "test"})
+	}
+	m.Capture(ctx, nil, nil)
+
+	events := m.Events()
+	if len(events) != 2 || events[0].Err.Error() != "error 1" || events[1].Err.Error() != "error 2" {
+		t.Fatalf("events = %v", events)
+	}
+	if m.Total() != 3 {
+		t.Errorf("Total = %d, want 3", m.Total())
+	}
+	if events[0].Tags["op"] != "test" || events[0].Time.Hour() != 9 {
+		t.Errorf("event = %+v", events[0])
+	}
+}
+
+func TestGuard(t *testing.T) {
+	m := NewMemory(10, nil)
+	ctx := context.Background()
+	errBoom := errors.New("boom")
+
+	if err := Guard(ctx, m, nil, func() error { return errBoom }); err != errBoom {
+		t.Fatalf("err = %v, want errBoom", err)
+	}
+	if m.Total() != 0 {
+		t.Fatal("an ordinary error was captured; the caller decides")
+	}
+
+	err := Guard(ctx, m, map[string]string{"worker": "email"}, func() error { panic(errBoom) })
+	var perr *PanicError
+	if !errors.As(err, &perr) || !errors.Is(err, errBoom) {
+		t.Fatalf("err = %v, want a *PanicError wrapping errBoom", err)
+	}
+	events := m.Events()
+	if len(events) != 1 || events[0].Tags["worker"] != "email" || !strings.Contains(string(events[0].Stack), "TestGuard") {
+		t.Errorf("events = %+v", events)
+	}
+
+	if err := Guard(ctx, nil, nil, func() error { panic("no reporter") }); err == nil {
+		t.Error("a panic without a reporter was lost")
+	}
+}
+
+func TestHTTP_Posts(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		got  []httpEvent
+		fail = true
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			fail = false
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var e httpEvent
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Error(err)
+		}
+		got = append(got, e)
+	}))
+	defer srv.Close()
+
+	h := NewHTTP(srv.URL, HTTPOptions{Client: srv.Client(), Clock: clocktest.NewFake(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))})
+	ctx := context.Background()
+	h.Capture(ctx, errors.New("lost"), nil)
+	h.Capture(ctx, fmt.Errorf("charging order 7: %w", errors.New("gateway down")), map[string]string{"order_id": "7"})
+	Guard(ctx, h, nil, func() error { panic("worker crashed") })
+	if err := h.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	h.Capture(ctx, errors.New("after close"), nil)
+
+	if len(got) != 2 {
+		t.Fatalf("posted %d events, want 2: %+v", len(got), got)
+	}
+	if got[0].Message != "charging order 7: gateway down" || got[0].Level != "error" || got[0].Tags["order_id"] != "7" || got[0].Timestamp.Hour() != 9 {
+		t.Errorf("first event = %+v", got[0])
+	}
+	if got[1].Level != "fatal" || got[1].Type != "*errreport.PanicError" || got[1].Stacktrace == "" {
+		t.Errorf("panic event = %+v", got[1])
+	}
+	if h.Dropped() != 2 {
+		t.Errorf("Dropped = %d, want 2: the failed post and the one after Close", h.Dropped())
+	}
+}
+
+func TestHTTP_DropsWhenFull(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { <-release }))
+	defer srv.Close()
+
+	h := NewHTTP(srv.URL, HTTPOptions{Client: srv.Client(), QueueSize: 1})
+	// The first event may already be in flight; at most one more fits.
+	for range 5 {
+		h.Capture(context.Background(), errors.New("down"), nil)
+	}
+	if h.Dropped() < 3 {
+		t.Errorf("Dropped = %d, want at least 3", h.Dropped())
+	}
+	close(release)
+	if err := h.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
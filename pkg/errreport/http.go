@@ -0,0 +1,93 @@
+package errreport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/internal/poster"
+)
+
+// HTTPOptions tune an HTTP reporter. Zero values take the defaults.
+type HTTPOptions struct {
+	Client    *http.Client // default http.DefaultClient
+	QueueSize int          // events waiting to be posted; default 100
+	Clock     clock.Clock  // default the system clock
+}
+
+// HTTP posts each event as JSON to a collector, in the manner of
+// Sentry's store endpoint:
+//
+//	{"timestamp": "...", "level": "error", "message": "...",
+//	 "type": "*fmt.wrapError", "tags": {...}, "stacktrace": "..."}
+//
+// Capture only queues the event; a background goroutine posts it. When
+// the queue is full or the collector fails, the event is dropped and
+// counted rather than slowing down the failing code.
+type HTTP struct {
+	clock  clock.Clock
+	poster *poster.Poster[Event]
+}
+
+// NewHTTP starts posting to url.
+func NewHTTP(url string, opts HTTPOptions) *HTTP {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 100
+	}
+	clk := clock.OrSystem(opts.Clock)
+	return &HTTP{
+		clock: clk,
+		poster: poster.New("error reporter", url, poster.Options{
+			Client:    opts.Client,
+			QueueSize: opts.QueueSize,
+			Clock:     clk,
+		}, encodeEvent),
+	}
+}
+
+func (h *HTTP) Capture(ctx context.Context, err error, tags map[string]string) {
+	if err == nil {
+		return
+	}
+	h.poster.Send(newEvent(h.clock, err, tags))
+}
+
+// Dropped returns the number of events that were not delivered.
+func (h *HTTP) Dropped() int64 {
+	return h.poster.Dropped()
+}
+
+// Close stops accepting events and waits until the queued ones are
+// posted, or until ctx is done.
+func (h *HTTP) Close(ctx context.Context) error {
+	return h.poster.Close(ctx)
+}
+
+type httpEvent struct {
+	Timestamp  time.Time         `json:"timestamp"`
+	Level      string            `json:"level"`
+	Message    string            `json:"message"`
+	Type       string            `json:"type"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Stacktrace string            `json:"stacktrace,omitempty"`
+}
+
+// encodeEvent encodes the only event of a batch; the reporter posts
+// events one at a time.
+func encodeEvent(batch []Event) any {
+	e := batch[0]
+	level := "error"
+	if e.Stack != nil {
+		level = "fatal"
+	}
+	return httpEvent{
+		Timestamp:  e.Time.UTC(),
+		Level:      level,
+		Message:    e.Err.Error(),
+		Type:       fmt.Sprintf("%T", e.Err),
+		Tags:       e.Tags,
+		Stacktrace: string(e.Stack),
+	}
+}
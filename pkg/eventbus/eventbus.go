@@ -0,0 +1,154 @@
+// Package eventbus carries messages from publishers to the subscribers
+// of their topic, without either knowing the other. Code depends on
+// the Publisher and Subscriber ports; Memory implements them in
+// process and NATS over a NATS server, so a service moves between the
+// two without changing.
+//
+// Topics are dot-separated tokens such as "order.placed". A
+// subscription's pattern may use NATS wildcards: "*" matches exactly
+// one token and a trailing ">" matches one or more.
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidTopic is returned, wrapped, for a malformed topic or
+// pattern.
+var ErrInvalidTopic = errors.New("invalid topic")
+
+// Message is a published payload and the topic it was published on.
+type Message struct {
+	Topic string
+	Data  []byte
+}
+
+// Handler reacts to a message.
+type Handler func(ctx context.Context, msg Message) error
+
+// Publisher publishes messages.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, data []byte) error
+}
+
+// Subscriber registers handlers for the topics matching a pattern.
+type Subscriber interface {
+	Subscribe(pattern string, h Handler) (Subscription, error)
+}
+
+// Subscription is a registered handler.
+type Subscription interface {
+	Unsubscribe() error
+}
+
+// Match reports whether topic matches pattern.
+func Match(pattern, topic string) bool {
+	ps, ts := strings.Split(pattern, "."), strings.Split(topic, ".")
+	for i, p := range ps {
+		if p == ">" {
+			return len(ts) > i
+		}
+		if i >= len(ts) || (p != "*" && p != ts[i]) {
+			return false
+		}
+	}
+	return len(ps) == len(ts)
+}
+
+// ValidateTopic checks that topic is a non-empty list of
+// dot-separated tokens without wildcards.
+func ValidateTopic(topic string) error {
+	for _, t := range strings.Split(topic, ".") {
+		if t == "" || t == "*" || t == ">" || strings.ContainsAny(t, " \t\r\n") {
+			return fmt.Errorf("%w: %q", ErrInvalidTopic, topic)
+		}
+	}
+	return nil
+}
+
+// ValidatePattern checks that pattern is a topic whose tokens may be
+// "*", with ">" allowed only as the last one.
+func ValidatePattern(pattern string) error {
+	ts := strings.Split(pattern, ".")
+	for i, t := range ts {
+		switch {
+		case t == "*":
+		case t == ">" && i == len(ts)-1:
+		case t == "" || t == ">" || strings.ContainsAny(t, "*> \t\r\n"):
+			return fmt.Errorf("%w: %q", ErrInvalidTopic, pattern)
+		}
+	}
+	return nil
+}
+
+// Memory is an in-process Publisher and Subscriber. Publish calls the
+// matching handlers synchronously, in subscription order, on the
+// publisher's goroutine. It is safe for concurrent use.
+type Memory struct {
+	mu   sync.RWMutex
+	subs []*memorySub
+}
+
+type memorySub struct {
+	bus     *Memory
+	pattern string
+	handler Handler
+}
+
+// NewMemory returns an empty in-process bus.
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Publish calls every handler subscribed to a pattern matching topic.
+// A failing handler does not stop the others; all errors are joined.
+func (m *Memory) Publish(ctx context.Context, topic string, data []byte) error {
+	if err := ValidateTopic(topic); err != nil {
+		return err
+	}
+	m.mu.RLock()
+	var handlers []Handler
+	for _, s := range m.subs {
+		if Match(s.pattern, topic) {
+			handlers = append(handlers, s.handler)
+		}
+	}
+	m.mu.RUnlock()
+
+	var errs []error
+	for _, h := range handlers {
+		if err := h(ctx, Message{Topic: topic, Data: data}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Subscribe registers h for the topics matching pattern.
+func (m *Memory) Subscribe(pattern string, h Handler) (Subscription, error) {
+	if err := ValidatePattern(pattern); err != nil {
+		return nil, err
+	}
+	s := &memorySub{bus: m, pattern: pattern, handler: h}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subs = append(m.subs, s)
+	return s, nil
+}
+
+func (s *memorySub) Unsubscribe() error {
+	m := s.bus
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, other := range m.subs {
+		if other == s {
+			m.subs = append(m.subs[:i:i], m.subs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, topic string
+		want           bool
+	}{
+		{"order.placed", "order.placed", true},
+		{"order.placed", "order.paid", false},
+		{"order.*", "order.placed", true},
+		{"order.*", "order.placed.v2", false},
+		{"order.*", "order", false},
+		{"*.placed", "order.placed", true},
+		{"order.>", "order.placed", true},
+		{"order.>", "order.placed.v2", true},
+		{"order.>", "order", false},
+		{">", "order.placed", true},
+		{"order.placed.v2", "order.placed", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.topic); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.topic, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, topic := range []string{"", "order.", ".placed", "order.*", "order.>", "order placed"} {
+		if err := ValidateTopic(topic); !errors.Is(err, ErrInvalidTopic) {
+			t.Errorf("ValidateTopic(%q) = %v, want ErrInvalidTopic", topic, err)
+		}
+	}
+	for _, pattern := range []string{"", "order..placed", "order.>.v2", "order.p*"} {
+		if err := ValidatePattern(pattern); !errors.Is(err, ErrInvalidTopic) {
+			t.Errorf("ValidatePattern(%q) = %v, want ErrInvalidTopic", pattern, err)
+		}
+	}
+	for _, pattern := range []string{"order.placed", "order.*", "*.placed", "order.>", ">"} {
+		if err := ValidatePattern(pattern); err != nil {
+			t.Errorf("ValidatePattern(%q) = %v", pattern, err)
+		}
+	}
+}
+
+type bus interface {
+	Publisher
+	Subscriber
+}
+
+// recorder collects the topics its handler sees, from any goroutine.
+type recorder struct {
+	mu     sync.Mutex
+	topics []string
+	got    chan struct{}
+}
+
+func newRecorder() *recorder {
+	return &recorder{got: make(chan struct{}, 100)}
+}
+
+func (r *recorder) handle(ctx context.Context, msg Message) error {
+	r.mu.Lock()
+	r.topics = append(r.topics, msg.Topic+"="+string(msg.Data))
+	r.mu.Unlock()
+	r.got <- struct{}{}
+	return nil
+}
+
+// wait waits for n deliveries and returns every topic seen so far.
+func (r *recorder) wait(t *testing.T, n int) []string {
+	t.Helper()
+	for range n {
+		select {
+		case <-r.got:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %d deliveries", n)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	topics := slices.Clone(r.topics)
+	slices.Sort(topics)
+	return topics
+}
+
+// testBus is the contract every bus meets. Delivery may be
+// asynchronous, so it waits for the messages it expects and then
+// publishes a marker to make sure nothing else arrived before it.
+func testBus(t *testing.T, b bus) {
+	ctx := context.Background()
+	placed, orders, all := newRecorder(), newRecorder(), newRecorder()
+	if _, err := b.Subscribe("order.placed", placed.handle); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Subscribe("order.*", orders.handle); err != nil {
+		t.Fatal(err)
+	}
+	sub, err := b.Subscribe(">", all.handle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Subscribe("order.>.v2", placed.handle); !errors.Is(err, ErrInvalidTopic) {
+		t.Errorf("Subscribe(order.>.v2) = %v, want ErrInvalidTopic", err)
+	}
+
+	for _, topic := range []string{"order.placed", "order.refunded", "payment.captured"} {
+		if err := b.Publish(ctx, topic, []byte("1")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.Publish(ctx, "order.*", nil); !errors.Is(err, ErrInvalidTopic) {
+		t.Errorf("Publish(order.*) = %v, want ErrInvalidTopic", err)
+	}
+
+	if got, want := placed.wait(t, 1), []string{"order.placed=1"}; !slices.Equal(got, want) {
+		t.Errorf("order.placed got %q, want %q", got, want)
+	}
+	if got, want := orders.wait(t, 2), []string{"order.placed=1", "order.refunded=1"}; !slices.Equal(got, want) {
+		t.Errorf("order.* got %q, want %q", got, want)
+	}
+	if got, want := all.wait(t, 3), []string{"order.placed=1", "order.refunded=1", "payment.captured=1"}; !slices.Equal(got, want) {
+		t.Errorf("> got %q, want %q", got, want)
+	}
+
+	if err := sub.Unsubscribe(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Publish(ctx, "order.placed", []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+	if got := placed.wait(t, 1); len(got) != 2 {
+		t.Errorf("order.placed got %q after the second publish", got)
+	}
+	if got := all.wait(t, 0); len(got) != 3 {
+		t.Errorf("> got %q after unsubscribing", got)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	testBus(t, NewMemory())
+}
+
+func TestMemory_JoinsHandlerErrors(t *testing.T) {
+	b := NewMemory()
+	errA, errB := errors.New("a"), errors.New("b")
+	var called int
+	for _, err := range []error{errA, nil, errB} {
+		b.Subscribe("order.placed", func(ctx context.Context, msg Message) error {
+			called++
+			return err
+		})
+	}
+	err := b.Publish(context.Background(), "order.placed", nil)
+	if called != 3 || !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("called %d handlers, err = %v; want 3 and both errors", called, err)
+	}
+}
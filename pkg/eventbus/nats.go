@@ -0,0 +1,77 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATS is a Publisher and Subscriber over a NATS connection. Its
+// topics are NATS subjects, which share their wildcard syntax.
+// Handlers run on the connection's delivery goroutines, so unlike
+// Memory a publisher does not see their errors; they go to the
+// onError callback instead.
+type NATS struct {
+	conn    *nats.Conn
+	onError func(Message, error)
+}
+
+// NewNATS returns a bus over conn, which the caller keeps ownership
+// of. onError, which may be nil, is called with each message a handler
+// fails on.
+func NewNATS(conn *nats.Conn, onError func(Message, error)) *NATS {
+	if onError == nil {
+		onError = func(Message, error) {}
+	}
+	return &NATS{conn: conn, onError: onError}
+}
+
+// Publish sends data on topic and waits until the server has it, or
+// ctx is done.
+func (n *NATS) Publish(ctx context.Context, topic string, data []byte) error {
+	if err := ValidateTopic(topic); err != nil {
+		return err
+	}
+	if err := n.conn.Publish(topic, data); err != nil {
+		return fmt.Errorf("eventbus: publishing %s: %w", topic, err)
+	}
+	if err := n.flush(ctx); err != nil {
+		return fmt.Errorf("eventbus: publishing %s: %w", topic, err)
+	}
+	return nil
+}
+
+// flush waits until the server has processed everything sent so far.
+// The client needs a deadline for that, and uses its default flush
+// timeout when ctx has none.
+func (n *NATS) flush(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); ok {
+		return n.conn.FlushWithContext(ctx)
+	}
+	return n.conn.Flush()
+}
+
+// Subscribe registers h for the subjects matching pattern. h gets a
+// background context, since a message outlives the publisher's.
+func (n *NATS) Subscribe(pattern string, h Handler) (Subscription, error) {
+	if err := ValidatePattern(pattern); err != nil {
+		return nil, err
+	}
+	sub, err := n.conn.Subscribe(pattern, func(m *nats.Msg) {
+		msg := Message{Topic: m.Subject, Data: m.Data}
+		if err := h(context.Background(), msg); err != nil {
+			n.onError(msg, err)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("eventbus: subscribing to %s: %w", pattern, err)
+	}
+	// The subscription only exists once the server has processed it;
+	// flushing makes it see messages published right after.
+	if err := n.conn.Flush(); err != nil {
+		sub.Unsubscribe()
+		return nil, fmt.Errorf("eventbus: subscribing to %s: %w", pattern, err)
+	}
+	return sub, nil
+}
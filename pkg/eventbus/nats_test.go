@@ -0,0 +1,57 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// connectNATS starts an embedded NATS server on a random port and
+// returns a connection to it, both closed when the test ends.
+func connectNATS(t *testing.T) *nats.Conn {
+	t.Helper()
+	srv, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Start()
+	t.Cleanup(srv.Shutdown)
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server did not start")
+	}
+	conn, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(conn.Close)
+	return conn
+}
+
+func TestNATS(t *testing.T) {
+	testBus(t, NewNATS(connectNATS(t), nil))
+}
+
+func TestNATS_ReportsHandlerErrors(t *testing.T) {
+	failed := make(chan Message, 1)
+	b := NewNATS(connectNATS(t), func(msg Message, err error) { failed <- msg })
+	if _, err := b.Subscribe("order.placed", func(ctx context.Context, msg Message) error {
+		return errors.New("projection is down")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Publish(context.Background(), "order.placed", []byte("7")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-failed:
+		if msg.Topic != "order.placed" || string(msg.Data) != "7" {
+			t.Errorf("onError got %+v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("onError was not called")
+	}
+}
@@ -0,0 +1,97 @@
+package flags
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+// File is a Provider backed by a JSON object in a file, such as
+//
+//	{"payment.crypto": false, "channel.slack": true, "invoice.variant": "html"}
+//
+// Watch reloads it when the file changes, so flags can be flipped
+// without a restart. It is safe for concurrent use.
+type File struct {
+	path string
+
+	mu     sync.RWMutex
+	data   []byte
+	values map[string]string
+}
+
+// NewFile loads the flags in path.
+func NewFile(path string) (*File, error) {
+	f := &File{path: path}
+	if _, err := f.Reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *File) Lookup(name string) (string, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	v, ok := f.values[name]
+	return v, ok
+}
+
+// Reload reads the file again and reports whether it changed. A file
+// that cannot be read or parsed leaves the current flags in place.
+func (f *File) Reload() (changed bool, err error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return false, fmt.Errorf("reading flags: %w", err)
+	}
+
+	f.mu.RLock()
+	same := f.values != nil && bytes.Equal(data, f.data)
+	f.mu.RUnlock()
+	if same {
+		return false, nil
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return false, fmt.Errorf("parsing flags %s: %w", f.path, err)
+	}
+	values := make(map[string]string, len(raw))
+	for name, v := range raw {
+		switch v := v.(type) {
+		case string:
+			values[name] = v
+		case bool, float64:
+			values[name] = fmt.Sprint(v)
+		default:
+			return false, fmt.Errorf("parsing flags %s: %q is not a string, number or boolean", f.path, name)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data, f.values = data, values
+	return true, nil
+}
+
+// Watch reloads the file every interval on clk until ctx is done.
+// onError, which may be nil, receives the errors of failed reloads;
+// the previous flags stay in effect until the file is valid again.
+func (f *File) Watch(ctx context.Context, clk clock.Clock, interval time.Duration, onError func(error)) error {
+	clk = clock.OrSystem(clk)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clk.After(interval):
+		}
+		if _, err := f.Reload(); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Package flags turns features on and off at runtime. Code asks a
+// Provider for a flag's value; where the value comes from (a map, the
+// environment, a file that is reloaded when it changes) is decided by
+// whoever wires the Provider in.
+package flags
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Provider looks flags up by name. ok is false if the provider does not
+// know the flag, so callers can fall back to a default.
+type Provider interface {
+	Lookup(name string) (value string, ok bool)
+}
+
+// Enabled reports whether the flag name is on. A flag that is unknown
+// or not a boolean (see strconv.ParseBool) has the value def. A nil
+// Provider knows no flags.
+func Enabled(p Provider, name string, def bool) bool {
+	if p == nil {
+		return def
+	}
+	v, ok := p.Lookup(name)
+	if !ok {
+		return def
+	}
+	on, err := strconv.ParseBool(v)
+	if err != nil {
+		return def
+	}
+	return on
+}
+
+// String returns the value of the flag name, or def if it is unknown.
+func String(p Provider, name, def string) string {
+	if p == nil {
+		return def
+	}
+	if v, ok := p.Lookup(name); ok {
+		return v
+	}
+	return def
+}
+
+// Static is a Provider backed by a fixed map.
+type Static map[string]string
+
+func (s Static) Lookup(name string) (string, bool) {
+	v, ok := s[name]
+	return v, ok
+}
+
+// Env is a Provider that reads flags from environment variables: the
+// flag "payment.crypto" is the variable <Prefix>PAYMENT_CRYPTO. Getenv
+// is usually os.LookupEnv.
+type Env struct {
+	Prefix string
+	Getenv func(string) (string, bool)
+}
+
+func (e Env) Lookup(name string) (string, bool) {
+	key := strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(name))
+	return e.Getenv(e.Prefix + key)
+}
+
+// Layered asks each Provider in turn and returns the first answer, so
+// an environment variable can override a file, for example.
+type Layered []Provider
+
+func (l Layered) Lookup(name string) (string, bool) {
+	for _, p := range l {
+		if v, ok := p.Lookup(name); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,153 @@
+package flags
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+)
+
+func TestEnabled(t *testing.T) {
+	p := Static{"on": "true", "off": "0", "junk": "maybe"}
+	tests := []struct {
+		name string
+		def  bool
+		want bool
+	}{
+		{"on", false, true},
+		{"off", true, false},
+		{"junk", true, true},
+		{"missing", false, false},
+		{"missing", true, true},
+	}
+	for _, tt := range tests {
+		if got := Enabled(p, tt.name, tt.def); got != tt.want {
+			t.Errorf("Enabled(%q, %v) = %v, want %v", tt.name, tt.def, got, tt.want)
+		}
+	}
+	if !Enabled(nil, "anything", true) {
+		t.Error("a nil Provider must return the default")
+	}
+}
+
+func TestEnvAndLayered(t *testing.T) {
+	env := map[string]string{"FLAG_PAYMENT_CRYPTO": "false"}
+	p := Layered{
+		Env{Prefix: "FLAG_", Getenv: func(k string) (string, bool) { v, ok := env[k]; return v, ok }},
+		Static{"payment.crypto": "true", "channel.slack": "true"},
+	}
+	if Enabled(p, "payment.crypto", true) {
+		t.Error("the environment did not override the static value")
+	}
+	if !Enabled(p, "channel.slack", false) {
+		t.Error("the static value was not used")
+	}
+	if got := String(p, "invoice.variant", "pdf"); got != "pdf" {
+		t.Errorf("String = %q, want the default", got)
+	}
+}
+
+func writeFlags(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFile_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	writeFlags(t, path, `{"payment.crypto": false, "invoice.variant": "html", "rollout": 25}`)
+	f, err := NewFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Enabled(f, "payment.crypto", true) || String(f, "invoice.variant", "") != "html" || String(f, "rollout", "") != "25" {
+		t.Fatalf("loaded %v", f.values)
+	}
+
+	if changed, err := f.Reload(); changed || err != nil {
+		t.Errorf("unchanged file: changed=%v err=%v", changed, err)
+	}
+
+	writeFlags(t, path, `{"payment.crypto": true`)
+	if _, err := f.Reload(); err == nil {
+		t.Error("broken file reloaded without error")
+	}
+	if Enabled(f, "payment.crypto", true) {
+		t.Error("a broken file replaced the flags")
+	}
+}
+
+func TestFile_WatchHotReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	writeFlags(t, path, `{"channel.slack": false}`)
+	f, err := NewFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clk := clocktest.NewFake(time.Time{})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- f.Watch(ctx, clk, time.Second, nil) }()
+
+	writeFlags(t, path, `{"channel.slack": true}`)
+	if Enabled(f, "channel.slack", false) {
+		t.Fatal("flag changed before the watcher ran")
+	}
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	clk.BlockUntil(1) // the watcher is waiting again, so the reload is done
+	if !Enabled(f, "channel.slack", false) {
+		t.Error("flag not reloaded")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Watch returned %v", err)
+	}
+}
+
+func TestVariant(t *testing.T) {
+	p := Static{"split": "a:50,b:50", "all-b": "a:0,b", "junk": "a:x"}
+
+	counts := map[string]int{}
+	for i := range 1000 {
+		unit := strconv.Itoa(i)
+		v, ok := Variant(p, "split", unit)
+		if !ok {
+			t.Fatalf("Variant(split, %s) not assigned", unit)
+		}
+		if again, _ := Variant(p, "split", unit); again != v {
+			t.Fatalf("unit %s moved from %s to %s", unit, v, again)
+		}
+		counts[v]++
+	}
+	if counts["a"] < 400 || counts["b"] < 400 {
+		t.Errorf("split 50/50 gave %v", counts)
+	}
+
+	if v, ok := Variant(p, "all-b", "1"); !ok || v != "b" {
+		t.Errorf("Variant(all-b) = %q, %v; want b", v, ok)
+	}
+	for _, name := range []string{"junk", "missing"} {
+		if v, ok := Variant(p, name, "1"); ok {
+			t.Errorf("Variant(%s) = %q, want no variant", name, v)
+		}
+	}
+	if _, ok := Variant(nil, "split", "1"); ok {
+		t.Error("a nil Provider assigned a variant")
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	for _, bad := range []string{"", "a:0", "a:-1", ":3", "a,,b"} {
+		if _, err := ParseWeights(bad); err == nil {
+			t.Errorf("ParseWeights(%q) accepted", bad)
+		}
+	}
+}
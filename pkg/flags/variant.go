@@ -0,0 +1,84 @@
+package flags
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// Weight is one variant of an experiment and its share of the units.
+type Weight struct {
+	Variant string
+	Weight  int
+}
+
+// ParseWeights parses an experiment flag value: comma-separated
+// variants with their weights, such as "html:50,pdf:50". A variant
+// without a weight has weight 1.
+func ParseWeights(s string) ([]Weight, error) {
+	var ws []Weight
+	total := 0
+	for _, part := range strings.Split(s, ",") {
+		name, weight, hasWeight := strings.Cut(strings.TrimSpace(part), ":")
+		w := Weight{Variant: strings.TrimSpace(name), Weight: 1}
+		if hasWeight {
+			n, err := strconv.Atoi(strings.TrimSpace(weight))
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("flags: weight of %q must be a whole number of at least 0", w.Variant)
+			}
+			w.Weight = n
+		}
+		if w.Variant == "" {
+			return nil, fmt.Errorf("flags: empty variant in %q", s)
+		}
+		total += w.Weight
+		ws = append(ws, w)
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("flags: %q gives no variant any weight", s)
+	}
+	return ws, nil
+}
+
+// Bucket maps unit, such as a customer ID, to a number in [0, n) for
+// the experiment name. The same unit always lands in the same bucket of
+// an experiment, and different experiments split the units
+// independently.
+func Bucket(name, unit string, n int) int {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write([]byte(unit))
+	return int(h.Sum64() % uint64(n))
+}
+
+// Variant assigns unit to one of the variants of the experiment flag
+// name, in proportion to their weights; see ParseWeights. ok is false
+// if the flag is unknown or malformed, so callers can fall back to a
+// default.
+func Variant(p Provider, name, unit string) (variant string, ok bool) {
+	if p == nil {
+		return "", false
+	}
+	v, ok := p.Lookup(name)
+	if !ok {
+		return "", false
+	}
+	ws, err := ParseWeights(v)
+	if err != nil {
+		return "", false
+	}
+	total := 0
+	for _, w := range ws {
+		total += w.Weight
+	}
+	b := Bucket(name, unit, total)
+	for _, w := range ws {
+		if b < w.Weight {
+			return w.Variant, true
+		}
+		b -= w.Weight
+	}
+	return "", false // unreachable: b < total
+}
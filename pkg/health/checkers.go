@@ -0,0 +1,75 @@
+package health
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Pinger is the part of *sql.DB a database check needs.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// DB checks that a database answers a ping.
+func DB(db Pinger) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		if err := db.PingContext(ctx); err != nil {
+			return fmt.Errorf("database: %w", err)
+		}
+		return nil
+	})
+}
+
+// SMTP checks that the mail server at addr accepts connections and
+// greets with a 220 reply. It quits without sending anything.
+func SMTP(addr string) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return fmt.Errorf("smtp: %w", err)
+		}
+		defer conn.Close()
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+
+		greeting, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("smtp: reading greeting: %w", err)
+		}
+		if !strings.HasPrefix(greeting, "220") {
+			return fmt.Errorf("smtp: unexpected greeting %q", strings.TrimSpace(greeting))
+		}
+		fmt.Fprint(conn, "QUIT\r\n")
+		return nil
+	})
+}
+
+// HTTP checks that a GET of url, such as a payment gateway's status
+// endpoint, answers with a 2xx status. A nil client means
+// http.DefaultClient.
+func HTTP(client *http.Client, url string) Checker {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return CheckerFunc(func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("%s: %s", url, resp.Status)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,147 @@
+// Package health reports whether a service and its dependencies work.
+// Each dependency gets a Checker; an Aggregator runs them concurrently
+// with a timeout and serves the result on /healthz and /readyz.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+var ErrTimeout = errors.New("health check timed out")
+
+// Checker checks one dependency. It returns nil if the dependency is
+// usable and must return promptly once ctx is done.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc lets a plain function be used as a Checker.
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Status is the outcome of a check or of all of them.
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Result is the outcome of one check.
+type Result struct {
+	Name     string        `json:"name"`
+	Status   Status        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Report is the outcome of every check. Status is StatusUp only if
+// every check passed.
+type Report struct {
+	Status Status   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+// Aggregator runs a set of named checks. Register the checks before
+// serving; Register and Run must not race.
+type Aggregator struct {
+	timeout time.Duration
+	names   []string
+	checks  map[string]Checker
+}
+
+// NewAggregator returns an Aggregator that gives each check timeout
+// to finish.
+func NewAggregator(timeout time.Duration) *Aggregator {
+	return &Aggregator{timeout: timeout, checks: make(map[string]Checker)}
+}
+
+// Register adds a check under name, replacing any check of that name.
+func (a *Aggregator) Register(name string, c Checker) {
+	if _, ok := a.checks[name]; !ok {
+		a.names = append(a.names, name)
+	}
+	a.checks[name] = c
+}
+
+// Run runs every check concurrently and reports them in registration
+// order. A check that outlives its timeout is reported as down with
+// ErrTimeout, even if it never returns.
+func (a *Aggregator) Run(ctx context.Context) Report {
+	results := make([]Result, len(a.names))
+	var wg sync.WaitGroup
+	for i, name := range a.names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = a.run(ctx, name, a.checks[name])
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: results}
+	if slices.ContainsFunc(results, func(r Result) bool { return r.Status != StatusUp }) {
+		report.Status = StatusDown
+	}
+	return report
+}
+
+func (a *Aggregator) run(ctx context.Context, name string, c Checker) Result {
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errc := make(chan error, 1)
+	go func() { errc <- c.Check(ctx) }()
+
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+		err = fmt.Errorf("%w after %s", ErrTimeout, a.timeout)
+	}
+
+	result := Result{Name: name, Status: StatusUp, Duration: time.Since(start)}
+	if err != nil {
+		result.Status, result.Error = StatusDown, err.Error()
+	}
+	return result
+}
+
+// LiveHandler answers 200 as long as the process can serve requests.
+// It checks no dependencies, so an orchestrator does not restart the
+// service because a database is down.
+func LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, Report{Status: StatusUp, Checks: []Result{}})
+	})
+}
+
+// ReadyHandler runs every check and answers 200 if all passed, 503
+// otherwise, with the report as JSON.
+func (a *Aggregator) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, a.Run(r.Context()))
+	})
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	status := http.StatusOK
+	if report.Status != StatusUp {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
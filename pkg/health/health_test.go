@@ -0,0 +1,173 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func up() Checker {
+	return CheckerFunc(func(context.Context) error { return nil })
+}
+
+func down(err error) Checker {
+	return CheckerFunc(func(context.Context) error { return err })
+}
+
+// hang ignores ctx and never returns, like a stuck dependency.
+func hang() Checker {
+	return CheckerFunc(func(context.Context) error { select {} })
+}
+
+func TestAggregator_Run(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks map[string]Checker
+		want   Status
+		failed []string
+	}{
+		{"no checks", nil, StatusUp, nil},
+		{"all up", map[string]Checker{"db": up(), "smtp": up()}, StatusUp, nil},
+		{"one down", map[string]Checker{"db": up(), "smtp": down(errors.New("refused"))}, StatusDown, []string{"smtp"}},
+		{"timeout", map[string]Checker{"db": hang(), "smtp": up()}, StatusDown, []string{"db"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAggregator(20 * time.Millisecond)
+			for name, c := range tt.checks {
+				a.Register(name, c)
+			}
+			report := a.Run(context.Background())
+			if report.Status != tt.want {
+				t.Errorf("status = %s, want %s", report.Status, tt.want)
+			}
+			var failed []string
+			for _, r := range report.Checks {
+				if r.Status == StatusDown {
+					failed = append(failed, r.Name)
+				}
+			}
+			if strings.Join(failed, ",") != strings.Join(tt.failed, ",") {
+				t.Errorf("failed = %v, want %v", failed, tt.failed)
+			}
+		})
+	}
+}
+
+func TestAggregator_KeepsRegistrationOrder(t *testing.T) {
+	a := NewAggregator(time.Second)
+	for _, name := range []string{"payment", "db", "smtp"} {
+		a.Register(name, up())
+	}
+	a.Register("db", down(errors.New("gone")))
+
+	report := a.Run(context.Background())
+	var names []string
+	for _, r := range report.Checks {
+		names = append(names, r.Name)
+	}
+	if got := strings.Join(names, ","); got != "payment,db,smtp" {
+		t.Errorf("order = %s", got)
+	}
+	if report.Checks[1].Error != "gone" {
+		t.Errorf("db was not replaced: %+v", report.Checks[1])
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	a := NewAggregator(time.Second)
+	a.Register("db", down(errors.New("connection refused")))
+
+	for _, tt := range []struct {
+		name    string
+		handler http.Handler
+		code    int
+		status  Status
+	}{
+		{"healthz", LiveHandler(), http.StatusOK, StatusUp},
+		{"readyz", a.ReadyHandler(), http.StatusServiceUnavailable, StatusDown},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, httptest.NewRequest("GET", "/"+tt.name, nil))
+			if rec.Code != tt.code {
+				t.Errorf("code = %d, want %d", rec.Code, tt.code)
+			}
+			var report Report
+			if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+				t.Fatal(err)
+			}
+			if report.Status != tt.status {
+				t.Errorf("status = %s, want %s", report.Status, tt.status)
+			}
+		})
+	}
+}
+
+type fakePinger struct{ err error }
+
+func (p fakePinger) PingContext(context.Context) error { return p.err }
+
+func TestDB(t *testing.T) {
+	if err := DB(fakePinger{}).Check(context.Background()); err != nil {
+		t.Errorf("healthy database: %v", err)
+	}
+	if err := DB(fakePinger{err: errors.New("closed")}).Check(context.Background()); err == nil {
+		t.Error("failing ping passed")
+	}
+}
+
+// fakeSMTP accepts one connection and writes greeting.
+func fakeSMTP(t *testing.T, greeting string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte(greeting))
+		conn.Read(make([]byte, 64))
+	}()
+	return ln.Addr().String()
+}
+
+func TestSMTP(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := SMTP(fakeSMTP(t, "220 mail.example.com ESMTP\r\n")).Check(ctx); err != nil {
+		t.Errorf("healthy server: %v", err)
+	}
+	if err := SMTP(fakeSMTP(t, "554 no service\r\n")).Check(ctx); err == nil {
+		t.Error("refusing server passed")
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	check := HTTP(srv.Client(), srv.URL+"/status")
+	if err := check.Check(context.Background()); err != nil {
+		t.Errorf("healthy gateway: %v", err)
+	}
+	status = http.StatusBadGateway
+	if err := check.Check(context.Background()); err == nil {
+		t.Error("failing gateway passed")
+	}
+}
@@ -0,0 +1,208 @@
+// Package httpx hardens outgoing HTTP calls. Adapters depend on Doer,
+// which *http.Client implements, and get a client stack built from
+// decorators: retries with backoff, logging, auth headers and a
+// circuit breaker. Every adapter that calls out over HTTP shares the
+// same stack instead of each retrying and logging in its own way.
+package httpx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/breaker"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+// Doer sends an HTTP request.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc lets a plain function be used as a Doer.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Decorator wraps a Doer in another that adds behaviour around it.
+type Decorator func(next Doer) Doer
+
+// Wrap wraps d in decorators. The first one is the outermost, so
+// Wrap(d, Logging(log), Retry(...)) logs each call once, however often
+// it is retried.
+func Wrap(d Doer, decorators ...Decorator) Doer {
+	for i := len(decorators) - 1; i >= 0; i-- {
+		d = decorators[i](d)
+	}
+	return d
+}
+
+// Header sets the header name to value on every request that does not
+// already have it, such as an API key.
+func Header(name, value string) Decorator {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(name) != "" {
+				return next.Do(req)
+			}
+			req = req.Clone(req.Context())
+			req.Header.Set(name, value)
+			return next.Do(req)
+		})
+	}
+}
+
+// BearerToken authorizes every request with token.
+func BearerToken(token string) Decorator {
+	return Header("Authorization", "Bearer "+token)
+}
+
+// Logger is where Logging writes.
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+// Logging logs every call with its outcome and duration. A nil clk
+// means the system clock.
+func Logging(log Logger, clk clock.Clock) Decorator {
+	clk = clock.OrSystem(clk)
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			start := clk.Now()
+			resp, err := next.Do(req)
+			took := clk.Now().Sub(start)
+			if err != nil {
+				log.Printf("%s %s failed after %s: %v", req.Method, redact(req), took, err)
+			} else {
+				log.Printf("%s %s: %s in %s", req.Method, redact(req), resp.Status, took)
+			}
+			return resp, err
+		})
+	}
+}
+
+// redact returns req's URL without its query, which may hold secrets.
+func redact(req *http.Request) string {
+	u := *req.URL
+	u.RawQuery, u.User = "", nil
+	return u.String()
+}
+
+// RetryPolicy configures Retry.
+type RetryPolicy struct {
+	// Attempts is the most times a request is sent; at least 1.
+	Attempts int
+	// Backoff is the delay before the first retry, doubled for every
+	// further one. A Retry-After answer overrides it.
+	Backoff time.Duration
+	// Clock defaults to the system clock.
+	Clock clock.Clock
+}
+
+// Retry resends a request that failed in transport or was answered 429
+// or 5xx, as long as its body can be replayed. It gives the last
+// answer or error back once the attempts run out.
+func Retry(p RetryPolicy) Decorator {
+	clk := clock.OrSystem(p.Clock)
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			delay := p.Backoff
+			for attempt := 1; ; attempt++ {
+				resp, err := next.Do(req)
+				if attempt >= p.Attempts || !retryable(resp, err) || !replayable(req) {
+					return resp, err
+				}
+				wait := delay
+				if resp != nil {
+					wait = retryAfter(resp, delay)
+					io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+					resp.Body.Close()
+				}
+				select {
+				case <-req.Context().Done():
+					return nil, req.Context().Err()
+				case <-clk.After(wait):
+				}
+				delay *= 2
+				if req, err = rewind(req); err != nil {
+					return nil, err
+				}
+			}
+		})
+	}
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns req with a fresh copy of its body.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Body = body
+	return req, nil
+}
+
+// retryAfter returns the delay a Retry-After header in seconds asks
+// for, or fallback.
+func retryAfter(resp *http.Response, fallback time.Duration) time.Duration {
+	if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second
+	}
+	return fallback
+}
+
+// StatusError is the error a breaker sees for a 5xx answer.
+type StatusError struct {
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return "server answered " + e.Status
+}
+
+// CircuitBreaker stops calling a server that keeps failing: transport
+// errors and 5xx answers count against it, and while b is open
+// requests fail with an error matching breaker.ErrOpen without being
+// sent. A 5xx answer is still returned to the caller as is.
+func CircuitBreaker(b *breaker.Breaker) Decorator {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := breaker.Value(req.Context(), b, func(ctx context.Context) (*http.Response, error) {
+				resp, err := next.Do(req)
+				if err == nil && resp.StatusCode >= 500 {
+					return resp, &StatusError{Status: resp.Status}
+				}
+				return resp, err
+			})
+			var serr *StatusError
+			if errors.As(err, &serr) {
+				return resp, nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", req.Method, redact(req), err)
+			}
+			return resp, nil
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/breaker"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+)
+
+// flakyServer answers the first failures requests with status and the
+// rest with 200, echoing the request body. It counts every request.
+func flakyServer(t *testing.T, failures int, status int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if int(n) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		fmt.Fprintf(w, "%s", body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func post(t *testing.T, d Doer, url, body string) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d.Do(req)
+}
+
+// advancing returns a fake clock and keeps moving it forward for as
+// long as the test runs, so retries do not wait.
+func advancing(t *testing.T) *clocktest.Fake {
+	clk := clocktest.NewFake(time.Time{})
+	stop := make(chan struct{})
+	t.Cleanup(func() { close(stop) })
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(time.Millisecond):
+				clk.Advance(time.Second)
+			}
+		}
+	}()
+	return clk
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		status    int
+		attempts  int
+		wantCalls int32
+		wantCode  int
+	}{
+		{"recovers from 503", 2, http.StatusServiceUnavailable, 3, 3, http.StatusOK},
+		{"recovers from 429", 1, http.StatusTooManyRequests, 3, 2, http.StatusOK},
+		{"gives up", 5, http.StatusBadGateway, 3, 3, http.StatusBadGateway},
+		{"does not retry 400", 1, http.StatusBadRequest, 3, 1, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := flakyServer(t, tt.failures, tt.status, nil)
+			d := Wrap(srv.Client(), Retry(RetryPolicy{Attempts: tt.attempts, Backoff: time.Second, Clock: advancing(t)}))
+			resp, err := post(t, d, srv.URL, "hello")
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantCode || calls.Load() != tt.wantCalls {
+				t.Errorf("got %d after %d calls, want %d after %d", resp.StatusCode, calls.Load(), tt.wantCode, tt.wantCalls)
+			}
+			if resp.StatusCode == http.StatusOK && string(body) != "hello" {
+				t.Errorf("body = %q: the retried request lost its body", body)
+			}
+		})
+	}
+}
+
+func TestRetry_HonoursRetryAfter(t *testing.T) {
+	srv, calls := flakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"30"}})
+	clk := clocktest.NewFake(time.Time{})
+	d := Wrap(srv.Client(), Retry(RetryPolicy{Attempts: 2, Backoff: time.Second, Clock: clk}))
+
+	done := make(chan *http.Response)
+	go func() {
+		resp, _ := post(t, d, srv.URL, "")
+		done <- resp
+	}()
+	clk.BlockUntil(1)
+	clk.Advance(29 * time.Second)
+	if calls.Load() != 1 {
+		t.Fatalf("retried before Retry-After")
+	}
+	clk.Advance(time.Second)
+	if resp := <-done; resp == nil || resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Errorf("got %v after %d calls", resp, calls.Load())
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { got = r.Header.Clone() }))
+	defer srv.Close()
+	d := Wrap(srv.Client(), BearerToken("s3cret"), Header("X-Api-Version", "2"))
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("X-Api-Version", "3")
+	resp, err := d.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got.Get("Authorization") != "Bearer s3cret" || got.Get("X-Api-Version") != "3" {
+		t.Errorf("headers = %v, want the token and the request's own version", got)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Error("the caller's request was modified")
+	}
+}
+
+type capturingLogger struct{ lines []string }
+
+func (l *capturingLogger) Printf(format string, args ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestLogging(t *testing.T) {
+	srv, _ := flakyServer(t, 0, 0, nil)
+	log := &capturingLogger{}
+	d := Wrap(srv.Client(), Logging(log, nil))
+
+	resp, err := post(t, d, srv.URL+"/hooks?token=s3cret", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	post(t, d, "http://127.0.0.1:0/", "")
+
+	if len(log.lines) != 2 || !strings.HasPrefix(log.lines[0], "POST "+srv.URL+"/hooks: 200 OK in ") || !strings.Contains(log.lines[1], " failed after ") {
+		t.Errorf("log = %q", log.lines)
+	}
+	if strings.Contains(strings.Join(log.lines, "\n"), "s3cret") {
+		t.Error("the query string was logged")
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	srv, calls := flakyServer(t, 100, http.StatusInternalServerError, nil)
+	b := breaker.New(breaker.Settings{Name: "slack", FailureThreshold: 2})
+	d := Wrap(srv.Client(), CircuitBreaker(b))
+
+	for range 2 {
+		resp, err := post(t, d, srv.URL, "")
+		if err != nil || resp.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("got %v, %v; want the 500 answer", resp, err)
+		}
+		resp.Body.Close()
+	}
+	if _, err := post(t, d, srv.URL, ""); !errors.Is(err, breaker.ErrOpen) {
+		t.Errorf("err = %v, want breaker.ErrOpen", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("server called %d times, want 2", calls.Load())
+	}
+}
@@ -0,0 +1,130 @@
+// Package i18n translates customer-facing text. Code asks a Translator
+// for a message by key in the customer's locale; Catalog answers from
+// message files, one per locale, so adding a language means adding a
+// file, not code.
+package i18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+var (
+	ErrMissingMessage = errors.New("missing message")
+	ErrInvalidCatalog = errors.New("invalid message catalog")
+)
+
+// Translator renders the message key in locale with args, the data its
+// template refers to, such as {{.Order.ID}}.
+type Translator interface {
+	Translate(locale, key string, args any) (string, error)
+}
+
+// Catalog is a Translator over a fixed set of messages. A locale
+// without a message falls back to its base language, "de" for
+// "de-AT", then to the catalog's fallback locale. It is safe for
+// concurrent use.
+type Catalog struct {
+	fallback string
+	messages map[string]map[string]*template.Template // locale -> key -> message
+}
+
+// Load reads a catalog from the files named <locale>.json at the root
+// of fsys. Each holds a JSON object of message templates by key.
+// fallback must be one of the locales.
+func Load(fsys fs.FS, fallback string) (*Catalog, error) {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalog{fallback: normalize(fallback), messages: make(map[string]map[string]*template.Template)}
+	for _, name := range files {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		var texts map[string]string
+		if err := json.Unmarshal(data, &texts); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidCatalog, name, err)
+		}
+		locale := normalize(strings.TrimSuffix(path.Base(name), ".json"))
+		if err := c.add(locale, texts); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidCatalog, name, err)
+		}
+	}
+	if _, ok := c.messages[c.fallback]; !ok {
+		return nil, fmt.Errorf("%w: no messages for the fallback locale %q", ErrInvalidCatalog, fallback)
+	}
+	return c, nil
+}
+
+func (c *Catalog) add(locale string, texts map[string]string) error {
+	messages := make(map[string]*template.Template, len(texts))
+	for key, text := range texts {
+		tmpl, err := template.New(key).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return err
+		}
+		messages[key] = tmpl
+	}
+	c.messages[locale] = messages
+	return nil
+}
+
+func (c *Catalog) Translate(locale, key string, args any) (string, error) {
+	tmpl, ok := c.lookup(locale, key)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrMissingMessage, key)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, args); err != nil {
+		return "", fmt.Errorf("translating %s: %w", key, err)
+	}
+	return buf.String(), nil
+}
+
+func (c *Catalog) lookup(locale, key string) (*template.Template, bool) {
+	locale = normalize(locale)
+	candidates := []string{locale}
+	if base, _, ok := strings.Cut(locale, "-"); ok {
+		candidates = append(candidates, base)
+	}
+	for _, l := range append(candidates, c.fallback) {
+		if tmpl, ok := c.messages[l][key]; ok {
+			return tmpl, true
+		}
+	}
+	return nil, false
+}
+
+// Locales returns the catalog's locales, sorted.
+func (c *Catalog) Locales() []string {
+	return slices.Sorted(maps.Keys(c.messages))
+}
+
+// Missing returns the keys of the fallback locale that locale has no
+// message for, sorted. Those fall back, which is fine for a while but
+// usually means a translation is due.
+func (c *Catalog) Missing(locale string) []string {
+	var missing []string
+	for key := range c.messages[c.fallback] {
+		if _, ok := c.messages[normalize(locale)][key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	slices.Sort(missing)
+	return missing
+}
+
+// normalize writes locales one way: "de_at" and "de-AT" are "de-at".
+func normalize(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
@@ -0,0 +1,76 @@
+package i18n
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func testCatalog(t *testing.T) *Catalog {
+	t.Helper()
+	c, err := Load(fstest.MapFS{
+		"en.json":    {Data: []byte(`{"greeting": "Hello {{.Name}}", "bye": "Bye"}`)},
+		"de.json":    {Data: []byte(`{"greeting": "Hallo {{.Name}}"}`)},
+		"de-CH.json": {Data: []byte(`{"greeting": "Grüezi {{.Name}}"}`)},
+		"notes.txt":  {Data: []byte("not a catalog")},
+	}, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCatalog_Translate(t *testing.T) {
+	c := testCatalog(t)
+	args := struct{ Name string }{"Ada"}
+	tests := []struct {
+		locale, key, want string
+	}{
+		{"en", "greeting", "Hello Ada"},
+		{"de", "greeting", "Hallo Ada"},
+		{"de-AT", "greeting", "Hallo Ada"},
+		{"de_ch", "greeting", "Grüezi Ada"},
+		{"de", "bye", "Bye"},
+		{"fr", "greeting", "Hello Ada"},
+		{"", "greeting", "Hello Ada"},
+	}
+	for _, tt := range tests {
+		got, err := c.Translate(tt.locale, tt.key, args)
+		if err != nil || got != tt.want {
+			t.Errorf("Translate(%q, %q) = %q, %v; want %q", tt.locale, tt.key, got, err, tt.want)
+		}
+	}
+
+	if _, err := c.Translate("en", "unknown", nil); !errors.Is(err, ErrMissingMessage) {
+		t.Errorf("Translate(unknown) = %v, want ErrMissingMessage", err)
+	}
+	if _, err := c.Translate("en", "greeting", map[string]string{}); err == nil {
+		t.Error("Translate without the template's data succeeded")
+	}
+}
+
+func TestCatalog_LocalesAndMissing(t *testing.T) {
+	c := testCatalog(t)
+	if got, want := c.Locales(), []string{"de", "de-ch", "en"}; !slices.Equal(got, want) {
+		t.Errorf("Locales = %q, want %q", got, want)
+	}
+	if got := c.Missing("de"); !slices.Equal(got, []string{"bye"}) {
+		t.Errorf("Missing(de) = %q, want [bye]", got)
+	}
+	if got := c.Missing("en"); len(got) != 0 {
+		t.Errorf("Missing(en) = %q, want none", got)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	for name, fsys := range map[string]fstest.MapFS{
+		"bad json":    {"en.json": {Data: []byte(`{`)}},
+		"bad message": {"en.json": {Data: []byte(`{"a": "{{.X"}`)}},
+		"no fallback": {"de.json": {Data: []byte(`{"a": "b"}`)}},
+	} {
+		if _, err := Load(fsys, "en"); !errors.Is(err, ErrInvalidCatalog) {
+			t.Errorf("%s: Load = %v, want ErrInvalidCatalog", name, err)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Package poster posts values to an HTTP collector from a background
+// goroutine, for the reporters under pkg whose callers must never wait
+// for the network: values are queued, posted in batches as JSON, and
+// dropped and counted when the queue is full or the collector fails.
+package poster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+// Options tune a Poster. Zero values take the defaults.
+type Options struct {
+	Client        *http.Client  // default http.DefaultClient
+	QueueSize     int           // values waiting to be posted; default 1000
+	BatchSize     int           // values per post; default 1
+	FlushInterval time.Duration // longest a value waits for its batch; default 10s
+	Clock         clock.Clock   // default the system clock
+}
+
+// Poster queues values of type T and posts them to a URL. A batch is
+// posted once it holds BatchSize values or its first value has waited
+// FlushInterval, and on Close.
+type Poster[T any] struct {
+	name     string
+	url      string
+	client   *http.Client
+	clock    clock.Clock
+	size     int
+	interval time.Duration
+	encode   func([]T) any
+	values   chan T
+	done     chan struct{}
+
+	mu      sync.RWMutex // guards closed against sends on a closed values
+	closed  bool
+	dropped atomic.Int64
+}
+
+// New starts posting to url. encode turns a batch into the body that is
+// marshalled as JSON; name prefixes the errors.
+func New[T any](name, url string, opts Options, encode func(batch []T) any) *Poster[T] {
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1000
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 10 * time.Second
+	}
+	p := &Poster[T]{
+		name:     name,
+		url:      url,
+		client:   opts.Client,
+		clock:    clock.OrSystem(opts.Clock),
+		size:     opts.BatchSize,
+		interval: opts.FlushInterval,
+		encode:   encode,
+		values:   make(chan T, opts.QueueSize),
+		done:     make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Send queues v, or drops it if the queue is full or p is closed.
+func (p *Poster[T]) Send(v T) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		p.dropped.Add(1)
+		return
+	}
+	select {
+	case p.values <- v:
+	default:
+		p.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of values that were not delivered.
+func (p *Poster[T]) Dropped() int64 {
+	return p.dropped.Load()
+}
+
+// Close stops accepting values and waits until the queued ones are
+// posted, or until ctx is done.
+func (p *Poster[T]) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.values)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%s: %d events not posted: %w", p.name, len(p.values), ctx.Err())
+	}
+}
+
+// postTimeout bounds each post, so a hanging collector cannot stall
+// Close forever.
+const postTimeout = 5 * time.Second
+
+func (p *Poster[T]) run() {
+	defer close(p.done)
+	var (
+		batch []T
+		due   <-chan time.Time // nil while batch is empty
+	)
+	flush := func() {
+		if err := p.post(batch); err != nil {
+			p.dropped.Add(int64(len(batch)))
+		}
+		batch, due = nil, nil
+	}
+	for {
+		select {
+		case v, ok := <-p.values:
+			if !ok {
+				if len(batch) > 0 {
+					flush()
+				}
+				return
+			}
+			batch = append(batch, v)
+			if len(batch) >= p.size {
+				flush()
+			} else if len(batch) == 1 {
+				due = p.clock.After(p.interval)
+			}
+		case <-due:
+			flush()
+		}
+	}
+}
+
+func (p *Poster[T]) post(batch []T) error {
+	body, err := json.Marshal(p.encode(batch))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s: %s answered %s", p.name, p.url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package poster
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestPoster_PostsBatches(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies [][]int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body []int
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, body)
+		if len(body) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	p := New("test", srv.URL, Options{Client: srv.Client(), BatchSize: 2}, func(batch []int) any { return batch })
+	for i := range 5 {
+		p.Send(i)
+	}
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	p.Send(5)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 3 || len(bodies[0]) != 2 || len(bodies[2]) != 1 || bodies[2][0] != 4 {
+		t.Errorf("posted %v, want [[0 1] [2 3] [4]]", bodies)
+	}
+	if p.Dropped() != 2 {
+		t.Errorf("Dropped = %d, want 2: the failed batch and the value after Close", p.Dropped())
+	}
+}
@@ -0,0 +1,87 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// Runner adapts a function that runs until its context is cancelled,
+// such as a dispatcher's Run loop, to a Component. Stop cancels the
+// function and waits for it to return. A function that returns
+// context.Canceled after being stopped has stopped cleanly.
+func Runner(run func(ctx context.Context) error) Component {
+	return &runner{run: run}
+}
+
+type runner struct {
+	run    func(ctx context.Context) error
+	cancel context.CancelFunc
+	exited chan struct{}
+	err    error
+}
+
+func (r *runner) Start(ctx context.Context) error {
+	ctx, r.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	r.exited = make(chan struct{})
+	go func() {
+		r.err = r.run(ctx)
+		close(r.exited)
+	}()
+	return nil
+}
+
+func (r *runner) Stop(ctx context.Context) error {
+	r.cancel()
+	select {
+	case <-r.exited:
+		if errors.Is(r.err, context.Canceled) {
+			return nil
+		}
+		return r.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *runner) Exited() <-chan struct{} {
+	return r.exited
+}
+
+// HTTPServer serves srv on ln. Stop shuts the server down gracefully,
+// letting requests in flight finish until ctx is done.
+func HTTPServer(srv *http.Server, ln net.Listener) Component {
+	return &httpServer{srv: srv, ln: ln}
+}
+
+type httpServer struct {
+	srv    *http.Server
+	ln     net.Listener
+	exited chan struct{}
+	err    error
+}
+
+func (s *httpServer) Start(ctx context.Context) error {
+	s.exited = make(chan struct{})
+	go func() {
+		s.err = s.srv.Serve(s.ln)
+		close(s.exited)
+	}()
+	return nil
+}
+
+func (s *httpServer) Stop(ctx context.Context) error {
+	if err := s.srv.Shutdown(ctx); err != nil {
+		return err
+	}
+	<-s.exited
+	if !errors.Is(s.err, http.ErrServerClosed) {
+		return s.err
+	}
+	return nil
+}
+
+func (s *httpServer) Exited() <-chan struct{} {
+	return s.exited
+}
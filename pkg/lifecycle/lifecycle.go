@@ -0,0 +1,141 @@
+// Package lifecycle starts a program's components in order and stops
+// them in reverse order, each within its own timeout, so a SIGTERM
+// drains the HTTP server before the workers it feeds and the workers
+// before the database they write to.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Component is something with a lifetime, such as a server or a worker
+// pool. Start returns once the component is running; long-running work
+// continues in the background. Stop returns once it has stopped, or
+// when ctx is done.
+type Component interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+// Exiter is implemented by components that run in the background and
+// can stop on their own, such as a server whose listener fails. Run
+// shuts everything down when one of them exits.
+type Exiter interface {
+	Exited() <-chan struct{}
+}
+
+// Hook builds a Component from functions. A nil function does nothing.
+type Hook struct {
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+func (h Hook) Start(ctx context.Context) error {
+	if h.OnStart == nil {
+		return nil
+	}
+	return h.OnStart(ctx)
+}
+
+func (h Hook) Stop(ctx context.Context) error {
+	if h.OnStop == nil {
+		return nil
+	}
+	return h.OnStop(ctx)
+}
+
+// ErrExited means a component stopped on its own while running.
+var ErrExited = errors.New("component exited")
+
+type entry struct {
+	name        string
+	component   Component
+	stopTimeout time.Duration
+}
+
+// Coordinator owns the start and stop order of its components.
+type Coordinator struct {
+	entries []entry
+	started int
+}
+
+func New() *Coordinator {
+	return &Coordinator{}
+}
+
+// Add appends a component. Components start in the order they were
+// added and stop in reverse; stopTimeout bounds the component's Stop.
+func (c *Coordinator) Add(name string, component Component, stopTimeout time.Duration) {
+	c.entries = append(c.entries, entry{name: name, component: component, stopTimeout: stopTimeout})
+}
+
+// Start starts every component in order. If one fails, the ones
+// already started are stopped again and the error is returned.
+func (c *Coordinator) Start(ctx context.Context) error {
+	for _, e := range c.entries[c.started:] {
+		if err := e.component.Start(ctx); err != nil {
+			err = fmt.Errorf("starting %s: %w", e.name, err)
+			return errors.Join(err, c.Stop(context.WithoutCancel(ctx)))
+		}
+		c.started++
+	}
+	return nil
+}
+
+// Stop stops the started components in reverse order. Each gets its
+// own stopTimeout, and a failing or slow component does not keep the
+// others from stopping; all errors are joined.
+func (c *Coordinator) Stop(ctx context.Context) error {
+	var errs []error
+	for ; c.started > 0; c.started-- {
+		e := c.entries[c.started-1]
+		if err := stop(ctx, e); err != nil {
+			errs = append(errs, fmt.Errorf("stopping %s: %w", e.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func stop(ctx context.Context, e entry) error {
+	if e.stopTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.stopTimeout)
+		defer cancel()
+	}
+	return e.component.Stop(ctx)
+}
+
+// Run starts every component, waits until ctx is done, for example by
+// signal.NotifyContext on SIGTERM, or until an Exiter exits, and stops
+// them again. Stopping is not bound by ctx; the stop timeouts are.
+func (c *Coordinator) Run(ctx context.Context) error {
+	if err := c.Start(ctx); err != nil {
+		return err
+	}
+
+	exited := make(chan string, len(c.entries))
+	stopping := make(chan struct{})
+	for _, e := range c.entries {
+		if x, ok := e.component.(Exiter); ok {
+			go func() {
+				select {
+				case <-x.Exited():
+					exited <- e.name
+				case <-stopping:
+				}
+			}()
+		}
+	}
+
+	var cause error
+	select {
+	case <-ctx.Done():
+	case name := <-exited:
+		cause = fmt.Errorf("%w: %s", ErrExited, name)
+	}
+	close(stopping)
+	return errors.Join(cause, c.Stop(context.WithoutCancel(ctx)))
+}
@@ -0,0 +1,201 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// journal records the calls made to fake components.
+type journal struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (j *journal) add(call string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.calls = append(j.calls, call)
+}
+
+func (j *journal) get() []string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return slices.Clone(j.calls)
+}
+
+// fake returns a component that records its calls in j. startErr fails
+// Start; stopDelay slows Stop down, honouring ctx.
+func (j *journal) fake(name string, startErr error, stopDelay time.Duration) Component {
+	return Hook{
+		OnStart: func(context.Context) error {
+			j.add("start " + name)
+			return startErr
+		},
+		OnStop: func(ctx context.Context) error {
+			j.add("stop " + name)
+			select {
+			case <-time.After(stopDelay):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+func TestCoordinator_StartsInOrderStopsInReverse(t *testing.T) {
+	var j journal
+	c := New()
+	for _, name := range []string{"database", "workers", "http"} {
+		c.Add(name, j.fake(name, nil, 0), time.Second)
+	}
+
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Stop(ctx); err != nil {
+		t.Fatalf("second Stop: %v", err)
+	}
+
+	want := []string{"start database", "start workers", "start http", "stop http", "stop workers", "stop database"}
+	if got := j.get(); !slices.Equal(got, want) {
+		t.Errorf("calls = %v\nwant    %v", got, want)
+	}
+}
+
+func TestCoordinator_FailedStartStopsStartedComponents(t *testing.T) {
+	var j journal
+	boom := errors.New("port in use")
+	c := New()
+	c.Add("database", j.fake("database", nil, 0), time.Second)
+	c.Add("http", j.fake("http", boom, 0), time.Second)
+	c.Add("scheduler", j.fake("scheduler", nil, 0), time.Second)
+
+	if err := c.Start(context.Background()); !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want %v", err, boom)
+	}
+	want := []string{"start database", "start http", "stop database"}
+	if got := j.get(); !slices.Equal(got, want) {
+		t.Errorf("calls = %v\nwant    %v", got, want)
+	}
+}
+
+func TestCoordinator_StopTimeoutPerComponent(t *testing.T) {
+	var j journal
+	c := New()
+	c.Add("database", j.fake("database", nil, 0), time.Second)
+	c.Add("workers", j.fake("workers", nil, time.Hour), 10*time.Millisecond)
+
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	err := c.Stop(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want the workers' deadline", err)
+	}
+	if got := j.get(); !slices.Contains(got, "stop database") {
+		t.Errorf("slow workers kept the database from stopping: %v", got)
+	}
+}
+
+func TestCoordinator_RunStopsOnCancel(t *testing.T) {
+	var j journal
+	c := New()
+	c.Add("workers", j.fake("workers", nil, 0), time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- c.Run(ctx) }()
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if got := j.get(); !slices.Equal(got, []string{"start workers", "stop workers"}) {
+		t.Errorf("calls = %v", got)
+	}
+}
+
+func TestRunner(t *testing.T) {
+	ticks := make(chan struct{}, 1)
+	r := Runner(func(ctx context.Context) error {
+		ticks <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	ctx := context.Background()
+	if err := r.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	<-ticks
+	if err := r.Stop(ctx); err != nil {
+		t.Errorf("clean stop returned %v", err)
+	}
+}
+
+func TestCoordinator_RunStopsWhenComponentExits(t *testing.T) {
+	var j journal
+	boom := errors.New("broker gone")
+	c := New()
+	c.Add("database", j.fake("database", nil, 0), time.Second)
+	c.Add("consumer", Runner(func(context.Context) error { return boom }), time.Second)
+
+	err := c.Run(context.Background())
+	if !errors.Is(err, ErrExited) || !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want ErrExited and %v", err, boom)
+	}
+	if got := j.get(); !slices.Equal(got, []string{"start database", "stop database"}) {
+		t.Errorf("calls = %v", got)
+	}
+}
+
+func TestHTTPServer_DrainsRequestsInFlight(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entered, release := make(chan struct{}), make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+	})}
+	c := HTTPServer(srv, ln)
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := make(chan error)
+	go func() {
+		r, err := http.Get("http://" + ln.Addr().String())
+		if err == nil {
+			r.Body.Close()
+		}
+		resp <- err
+	}()
+	<-entered
+
+	stopped := make(chan error)
+	go func() { stopped <- c.Stop(context.Background()) }()
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned with a request in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	if err := <-resp; err != nil {
+		t.Errorf("request in flight failed: %v", err)
+	}
+	if err := <-stopped; err != nil {
+		t.Errorf("Stop: %v", err)
+	}
+}
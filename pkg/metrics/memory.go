@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// InMemory is a Provider that keeps every value in memory so tests
+// can assert on them. It is safe for concurrent use.
+type InMemory struct {
+	mu     sync.Mutex
+	values map[string]float64
+	series map[string][]float64
+}
+
+func NewInMemory() *InMemory {
+	return &InMemory{values: make(map[string]float64), series: make(map[string][]float64)}
+}
+
+func (m *InMemory) Counter(name, help string) Counter {
+	return memCounter{m: m, name: name}
+}
+
+// CounterVec records each counter of the family under its series
+// name, as in the Prometheus exposition: name{label="value",...}.
+func (m *InMemory) CounterVec(name, help string, labels ...string) CounterVec {
+	return memCounterVec{m: m, name: name, labels: labels}
+}
+
+func (m *InMemory) Gauge(name, help string) Gauge {
+	return memGauge{m: m, name: name}
+}
+
+func (m *InMemory) Histogram(name, help string) Histogram {
+	return memHistogram{m: m, name: name}
+}
+
+// Value returns the current value of a counter or gauge, or 0 if
+// nothing was recorded under name. Counters of a CounterVec are named
+// by series, such as `orders_total{method="card"}`.
+func (m *InMemory) Value(name string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.values[name]
+}
+
+// Observations returns every value a histogram observed, in order.
+func (m *InMemory) Observations(name string) []float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.series[name])
+}
+
+type memCounter struct {
+	m    *InMemory
+	name string
+}
+
+func (c memCounter) Add(delta float64) {
+	c.m.mu.Lock()
+	defer c.m.mu.Unlock()
+	c.m.values[c.name] += delta
+}
+
+type memCounterVec struct {
+	m      *InMemory
+	name   string
+	labels []string
+}
+
+func (v memCounterVec) With(values ...string) Counter {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s has %d labels, got %d values", v.name, len(v.labels), len(values)))
+	}
+	pairs := make([]string, len(values))
+	for i, value := range values {
+		pairs[i] = fmt.Sprintf("%s=%q", v.labels[i], value)
+	}
+	return memCounter{m: v.m, name: v.name + "{" + strings.Join(pairs, ",") + "}"}
+}
+
+type memGauge struct {
+	m    *InMemory
+	name string
+}
+
+func (g memGauge) Set(value float64) {
+	g.m.mu.Lock()
+	defer g.m.mu.Unlock()
+	g.m.values[g.name] = value
+}
+
+type memHistogram struct {
+	m    *InMemory
+	name string
+}
+
+func (h memHistogram) Observe(value float64) {
+	h.m.mu.Lock()
+	defer h.m.mu.Unlock()
+	h.m.series[h.name] = append(h.m.series[h.name], value)
+}
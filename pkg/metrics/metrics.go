@@ -0,0 +1,42 @@
+// Package metrics defines small instrument ports, one per kind of
+// measurement, so code that only counts never depends on histograms.
+// Providers create the instruments: InMemory for tests and demos,
+// Prometheus for production.
+package metrics
+
+// Counter only goes up, such as the number of orders placed.
+type Counter interface {
+	Add(delta float64)
+}
+
+// CounterVec is a family of counters that share a name and differ in
+// the values of their labels, such as orders placed per payment method.
+// Labels keep one metric per measurement instead of one per value.
+type CounterVec interface {
+	// With returns the counter for the label values, in the order the
+	// labels were declared. Values must be few and bounded: every
+	// combination is a series of its own.
+	With(values ...string) Counter
+}
+
+// Gauge goes up and down, such as the length of a queue.
+type Gauge interface {
+	Set(value float64)
+}
+
+// Histogram records the distribution of observed values, such as call
+// durations in seconds.
+type Histogram interface {
+	Observe(value float64)
+}
+
+// Provider creates named instruments. Asking twice for the same name
+// returns the same instrument. Names follow the Prometheus
+// conventions: snake_case, with _total for counters and a unit suffix
+// such as _seconds for histograms. Label names are snake_case too.
+type Provider interface {
+	Counter(name, help string) Counter
+	CounterVec(name, help string, labels ...string) CounterVec
+	Gauge(name, help string) Gauge
+	Histogram(name, help string) Histogram
+}
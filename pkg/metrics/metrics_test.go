@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// providers runs a test against every Provider.
+var providers = map[string]func() Provider{
+	"memory":     func() Provider { return NewInMemory() },
+	"prometheus": func() Provider { return NewPrometheus() },
+}
+
+func TestProvider_SameNameSameInstrument(t *testing.T) {
+	for name, newProvider := range providers {
+		t.Run(name, func(t *testing.T) {
+			p := newProvider()
+			var wg sync.WaitGroup
+			for range 50 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					p.Counter("orders_placed_total", "Orders placed.").Add(1)
+					p.CounterVec("payments_total", "Payments.", "method").With("card").Add(1)
+					p.Gauge("email_queue_length", "Queued emails.").Set(3)
+					p.Histogram("payment_charge_duration_seconds", "Charge latency.").Observe(0.2)
+				}()
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func TestInMemory_RecordsValues(t *testing.T) {
+	m := NewInMemory()
+	m.Counter("orders_placed_total", "").Add(1)
+	m.Counter("orders_placed_total", "").Add(2)
+	m.CounterVec("payments_total", "", "method", "outcome").With("card", "ok").Add(1)
+	m.CounterVec("payments_total", "", "method", "outcome").With("card", "ok").Add(1)
+	m.Gauge("email_queue_length", "").Set(5)
+	m.Gauge("email_queue_length", "").Set(4)
+	m.Histogram("email_send_duration_seconds", "").Observe(0.5)
+	m.Histogram("email_send_duration_seconds", "").Observe(1.5)
+
+	if got := m.Value("orders_placed_total"); got != 3 {
+		t.Errorf("counter = %v, want 3", got)
+	}
+	if got := m.Value(`payments_total{method="card",outcome="ok"}`); got != 2 {
+		t.Errorf("labelled counter = %v, want 2", got)
+	}
+	if got := m.Value("email_queue_length"); got != 4 {
+		t.Errorf("gauge = %v, want 4", got)
+	}
+	if got := m.Observations("email_send_duration_seconds"); len(got) != 2 || got[0] != 0.5 || got[1] != 1.5 {
+		t.Errorf("histogram = %v, want [0.5 1.5]", got)
+	}
+}
+
+func TestPrometheus_Handler(t *testing.T) {
+	p := NewPrometheus()
+	p.Counter("orders_placed_total", "Orders placed.").Add(2)
+	p.CounterVec("payments_total", "Payments.", "method").With("card").Add(3)
+	p.Gauge("email_queue_length", "Queued emails.").Set(7)
+	p.Histogram("email_send_duration_seconds", "Email latency.").Observe(0.25)
+
+	rec := httptest.NewRecorder()
+	p.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	for _, want := range []string{
+		"# HELP orders_placed_total Orders placed.",
+		"orders_placed_total 2",
+		`payments_total{method="card"} 3`,
+		"email_queue_length 7",
+		"email_send_duration_seconds_count 1",
+		"email_send_duration_seconds_sum 0.25",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("exposition lacks %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestPrometheus_KindClashPanics(t *testing.T) {
+	p := NewPrometheus()
+	p.Counter("orders_total", "")
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a gauge under a counter's name did not panic")
+		}
+	}()
+	p.Gauge("orders_total", "")
+}
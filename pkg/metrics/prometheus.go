@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prometheus is a Provider backed by a Prometheus registry of its own.
+type promCounterVec struct {
+	vec *prometheus.CounterVec
+}
+
+func (v promCounterVec) With(values ...string) Counter {
+	return v.vec.WithLabelValues(values...)
+}
+
+// Handler serves the registry in the Prometheus text format.
+type Prometheus struct {
+	registry *prometheus.Registry
+
+	mu         sync.Mutex
+	collectors map[string]prometheus.Collector
+}
+
+func NewPrometheus() *Prometheus {
+	return &Prometheus{registry: prometheus.NewRegistry(), collectors: make(map[string]prometheus.Collector)}
+}
+
+func (p *Prometheus) Counter(name, help string) Counter {
+	return register(p, name, func() prometheus.Counter {
+		return prometheus.NewCounter(prometheus.CounterOpts{Name: name, Help: help})
+	})
+}
+
+func (p *Prometheus) CounterVec(name, help string, labels ...string) CounterVec {
+	return promCounterVec{register(p, name, func() *prometheus.CounterVec {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels)
+	})}
+}
+
+func (p *Prometheus) Gauge(name, help string) Gauge {
+	return register(p, name, func() prometheus.Gauge {
+		return prometheus.NewGauge(prometheus.GaugeOpts{Name: name, Help: help})
+	})
+}
+
+func (p *Prometheus) Histogram(name, help string) Histogram {
+	return register(p, name, func() prometheus.Histogram {
+		return prometheus.NewHistogram(prometheus.HistogramOpts{Name: name, Help: help})
+	})
+}
+
+// register returns the collector already registered under name, or
+// registers a new one. Registering two kinds of instrument under one
+// name is a programming error and panics, as in Prometheus itself.
+func register[C prometheus.Collector](p *Prometheus, name string, create func() C) C {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.collectors[name]; ok {
+		return c.(C)
+	}
+	c := create()
+	p.registry.MustRegister(c)
+	p.collectors[name] = c
+	return c
+}
+
+// Handler serves every instrument created so far.
+func (p *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,94 @@
+// Package middleware wraps any call of the form
+// func(ctx, Req) (Res, error) with cross-cutting behaviour: recovering
+// from panics, timeouts and logging. HTTP endpoints, commands and
+// notification channels all reduce to such a call, so they share these
+// middlewares instead of each having its own.
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/errreport"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/timeout"
+)
+
+// Handler handles a request.
+type Handler[Req, Res any] func(ctx context.Context, req Req) (Res, error)
+
+// Middleware wraps a Handler in another.
+type Middleware[Req, Res any] func(Handler[Req, Res]) Handler[Req, Res]
+
+// Chain wraps h in mws. The first middleware is the outermost, so a
+// request passes through them in the order they are listed.
+func Chain[Req, Res any](h Handler[Req, Res], mws ...Middleware[Req, Res]) Handler[Req, Res] {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// Recover turns a panic in the handler into an *errreport.PanicError,
+// captured with r, which may be nil.
+func Recover[Req, Res any](r errreport.Reporter, tags map[string]string) Middleware[Req, Res] {
+	return func(next Handler[Req, Res]) Handler[Req, Res] {
+		return func(ctx context.Context, req Req) (res Res, err error) {
+			err = errreport.Guard(ctx, r, tags, func() error {
+				res, err = next(ctx, req)
+				return err
+			})
+			return res, err
+		}
+	}
+}
+
+// Timeout fails a call that takes longer than d with a
+// *timeout.Error named op. A non-positive d disables it and a nil clk
+// means the system clock.
+func Timeout[Req, Res any](op string, d time.Duration, clk clock.Clock) Middleware[Req, Res] {
+	return func(next Handler[Req, Res]) Handler[Req, Res] {
+		return func(ctx context.Context, req Req) (Res, error) {
+			return timeout.Value(ctx, clk, op, d, func(ctx context.Context) (Res, error) {
+				return next(ctx, req)
+			})
+		}
+	}
+}
+
+// Logger is where Logging writes. A logger that also has PrintfContext
+// gets the request's context, for IDs carried in it.
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+type contextLogger interface {
+	PrintfContext(ctx context.Context, format string, args ...any)
+}
+
+// Logging logs every call named op with its duration and, if it
+// failed, its error. A nil clk means the system clock.
+func Logging[Req, Res any](op string, log Logger, clk clock.Clock) Middleware[Req, Res] {
+	clk = clock.OrSystem(clk)
+	return func(next Handler[Req, Res]) Handler[Req, Res] {
+		return func(ctx context.Context, req Req) (Res, error) {
+			start := clk.Now()
+			res, err := next(ctx, req)
+			took := clk.Now().Sub(start)
+			if err != nil {
+				printf(ctx, log, "%s failed after %s: %v", op, took, err)
+			} else {
+				printf(ctx, log, "%s took %s", op, took)
+			}
+			return res, err
+		}
+	}
+}
+
+func printf(ctx context.Context, log Logger, format string, args ...any) {
+	if cl, ok := log.(contextLogger); ok {
+		cl.PrintfContext(ctx, format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/errreport"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/timeout"
+)
+
+func double(ctx context.Context, n int) (int, error) { return 2 * n, nil }
+
+// trace records the order in which middlewares see a request.
+func trace(name string, seen *[]string) Middleware[int, int] {
+	return func(next Handler[int, int]) Handler[int, int] {
+		return func(ctx context.Context, n int) (int, error) {
+			*seen = append(*seen, name)
+			return next(ctx, n)
+		}
+	}
+}
+
+func TestChain_Order(t *testing.T) {
+	var seen []string
+	h := Chain(double, trace("outer", &seen), trace("inner", &seen))
+	if got, err := h(context.Background(), 21); got != 42 || err != nil {
+		t.Fatalf("h(21) = %d, %v", got, err)
+	}
+	if strings.Join(seen, ",") != "outer,inner" {
+		t.Errorf("seen = %v, want outer then inner", seen)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	reporter := errreport.NewMemory(10, nil)
+	h := Chain(func(ctx context.Context, n int) (int, error) {
+		if n < 0 {
+			panic("negative")
+		}
+		return n, nil
+	}, Recover[int, int](reporter, map[string]string{"op": "test"}))
+
+	_, err := h(context.Background(), -1)
+	var perr *errreport.PanicError
+	if !errors.As(err, &perr) || perr.Value != "negative" {
+		t.Fatalf("err = %v, want a *PanicError", err)
+	}
+	if got, err := h(context.Background(), 1); got != 1 || err != nil {
+		t.Errorf("h(1) = %d, %v", got, err)
+	}
+	if events := reporter.Events(); len(events) != 1 || events[0].Tags["op"] != "test" {
+		t.Errorf("reported %+v", events)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	clk := clocktest.NewFake(time.Time{})
+	hang := func(ctx context.Context, n int) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	h := Chain(hang, Timeout[int, int]("hang", time.Second, clk))
+
+	errc := make(chan error)
+	go func() {
+		_, err := h(context.Background(), 1)
+		errc <- err
+	}()
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	if err := <-errc; !errors.Is(err, timeout.ErrDeadlineExceeded) {
+		t.Errorf("err = %v, want ErrDeadlineExceeded", err)
+	}
+}
+
+type lines []string
+
+func (l *lines) Printf(format string, args ...any) {
+	*l = append(*l, fmt.Sprintf(format, args...))
+}
+
+type ctxKey struct{}
+
+type ctxLines struct{ lines }
+
+func (l *ctxLines) PrintfContext(ctx context.Context, format string, args ...any) {
+	l.Printf(fmt.Sprint(ctx.Value(ctxKey{}))+" "+format, args...)
+}
+
+func TestLogging(t *testing.T) {
+	clk := clocktest.NewFake(time.Time{})
+	errBoom := errors.New("boom")
+	slow := func(ctx context.Context, n int) (int, error) {
+		clk.Advance(time.Duration(n) * time.Millisecond)
+		if n > 10 {
+			return 0, errBoom
+		}
+		return n, nil
+	}
+
+	var log lines
+	h := Chain(slow, Logging[int, int]("Slow", &log, clk))
+	h(context.Background(), 5)
+	if _, err := h(context.Background(), 20); err != errBoom {
+		t.Fatalf("err = %v, want the handler's error", err)
+	}
+	want := []string{"Slow took 5ms", "Slow failed after 20ms: boom"}
+	if fmt.Sprint(log) != fmt.Sprint(want) {
+		t.Errorf("log = %q, want %q", log, want)
+	}
+
+	var clog ctxLines
+	h = Chain(slow, Logging[int, int]("Slow", &clog, clk))
+	h(context.WithValue(context.Background(), ctxKey{}, "req-1"), 1)
+	if len(clog.lines) != 1 || clog.lines[0] != "req-1 Slow took 1ms" {
+		t.Errorf("context log = %q", clog.lines)
+	}
+}
@@ -0,0 +1,69 @@
+package money
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrNoRate = errors.New("no exchange rate")
+
+// RateProvider knows exchange rates: how many units of to one unit of
+// from buys. A provider may call a rates service, so it takes a ctx.
+type RateProvider interface {
+	Rate(ctx context.Context, from, to Currency) (float64, error)
+}
+
+// StaticRates is a RateProvider over fixed rates, keyed by
+// [from, to]. A missing rate is derived from the inverse one.
+type StaticRates map[[2]Currency]float64
+
+func (r StaticRates) Rate(ctx context.Context, from, to Currency) (float64, error) {
+	if rate, ok := r[[2]Currency{from, to}]; ok {
+		return rate, nil
+	}
+	if rate, ok := r[[2]Currency{to, from}]; ok && rate != 0 {
+		return 1 / rate, nil
+	}
+	return 0, fmt.Errorf("%w: %s to %s", ErrNoRate, from, to)
+}
+
+// Converter exchanges amounts between currencies.
+type Converter struct {
+	rates RateProvider
+}
+
+func NewConverter(rates RateProvider) *Converter {
+	return &Converter{rates: rates}
+}
+
+// Convert returns m in currency to, rounded half away from zero to
+// the minor unit of to. Amounts already in to are returned as they
+// are.
+func (c *Converter) Convert(ctx context.Context, m Money, to Currency) (Money, error) {
+	if m.Currency == to {
+		return m, nil
+	}
+	fromDecimals, err := m.Currency.Decimals()
+	if err != nil {
+		return Money{}, err
+	}
+	toDecimals, err := to.Decimals()
+	if err != nil {
+		return Money{}, err
+	}
+	rate, err := c.rates.Rate(ctx, m.Currency, to)
+	if err != nil {
+		return Money{}, fmt.Errorf("converting %s: %w", m, err)
+	}
+	if rate <= 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return Money{}, fmt.Errorf("converting %s: %w: %s to %s is %v", m, ErrNoRate, m.Currency, to, rate)
+	}
+
+	amount := math.Round(float64(m.Amount) * rate * math.Pow10(toDecimals-fromDecimals))
+	if amount >= math.MaxInt64 || amount <= math.MinInt64 {
+		return Money{}, fmt.Errorf("converting %s: %w", m, ErrOverflow)
+	}
+	return New(int64(amount), to), nil
+}
@@ -0,0 +1,53 @@
+package money
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Currency is an ISO 4217 currency code.
+type Currency string
+
+var (
+	registryMu sync.RWMutex
+	// registry is the number of minor-unit digits per currency.
+	registry = map[Currency]int{
+		"AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CLP": 0,
+		"CNY": 2, "CZK": 2, "DKK": 2, "EUR": 2, "GBP": 2, "HKD": 2,
+		"HUF": 2, "IDR": 2, "ILS": 2, "INR": 2, "ISK": 0, "JOD": 3,
+		"JPY": 0, "KRW": 0, "KWD": 3, "MXN": 2, "NOK": 2, "NZD": 2,
+		"OMR": 3, "PLN": 2, "SEK": 2, "SGD": 2, "THB": 2, "TND": 3,
+		"TRY": 2, "TWD": 2, "USD": 2, "VND": 0, "ZAR": 2,
+	}
+)
+
+// Register adds a currency, or changes the minor-unit digits of a
+// known one. It is meant for program start, before amounts in the
+// currency exist.
+func Register(c Currency, decimals int) error {
+	if c == "" || decimals < 0 || decimals > 4 {
+		return fmt.Errorf("%w: %q with %d decimals", ErrUnknownCurrency, string(c), decimals)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[c] = decimals
+	return nil
+}
+
+// Decimals returns the number of minor-unit digits of c: 2 for EUR,
+// whose minor unit is the cent, 0 for JPY.
+func (c Currency) Decimals() (int, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	d, ok := registry[c]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCurrency, string(c))
+	}
+	return d, nil
+}
+
+// Valid reports whether c is a registered currency.
+func (c Currency) Valid() bool {
+	_, err := c.Decimals()
+	return err == nil
+}
@@ -0,0 +1,235 @@
+// Package money represents amounts of money exactly: integer minor
+// units of a registered currency. Arithmetic refuses to mix currencies
+// or overflow instead of silently going wrong, Allocate splits an
+// amount without losing a cent, and a Converter exchanges currencies
+// at the rates of a RateProvider.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrInvalidMoney     = errors.New("invalid money amount")
+	ErrOverflow         = errors.New("money amount overflows")
+)
+
+// Money is an amount in the minor units (cents, paise, ...) of a
+// currency. Integer minor units avoid float rounding errors, and
+// carrying the currency prevents adding euros to dollars.
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+// New returns minor units of currency: New(1250, "EUR") is 12.50 EUR.
+func New(minor int64, currency Currency) Money {
+	return Money{Amount: minor, Currency: currency}
+}
+
+// Parse parses a decimal string such as "12.50" in currency. It
+// rejects more fractional digits than the currency has.
+func Parse(s string, currency Currency) (Money, error) {
+	decimals, err := currency.Decimals()
+	if err != nil {
+		return Money{}, err
+	}
+
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	whole, frac, hasFrac := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	if whole == "" || (hasFrac && frac == "") || len(frac) > decimals || !isDigits(whole) || !isDigits(frac) {
+		return Money{}, fmt.Errorf("%w: %q %s", ErrInvalidMoney, s, currency)
+	}
+
+	minor, err := strconv.ParseInt(whole+frac+strings.Repeat("0", decimals-len(frac)), 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q %s", ErrInvalidMoney, s, currency)
+	}
+	if neg {
+		minor = -minor
+	}
+	return New(minor, currency), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Money) IsZero() bool     { return m.Amount == 0 }
+func (m Money) IsNegative() bool { return m.Amount < 0 }
+func (m Money) IsPositive() bool { return m.Amount > 0 }
+
+// Equal reports whether m and o are the same amount in the same
+// currency.
+func (m Money) Equal(o Money) bool {
+	return m == o
+}
+
+// Cmp returns -1, 0 or +1 depending on whether m is less than, equal
+// to or greater than o.
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	sum := m.Amount + o.Amount
+	if (o.Amount > 0 && sum < m.Amount) || (o.Amount < 0 && sum > m.Amount) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrOverflow, m, o)
+	}
+	return New(sum, m.Currency), nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	diff := m.Amount - o.Amount
+	if (o.Amount > 0 && diff > m.Amount) || (o.Amount < 0 && diff < m.Amount) {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrOverflow, m, o)
+	}
+	return New(diff, m.Currency), nil
+}
+
+// Mul multiplies m by a whole quantity.
+func (m Money) Mul(n int) (Money, error) {
+	product := m.Amount * int64(n)
+	if n != 0 && (product/int64(n) != m.Amount || (m.Amount == math.MinInt64 && n == -1)) {
+		return Money{}, fmt.Errorf("%w: %s * %d", ErrOverflow, m, n)
+	}
+	return New(product, m.Currency), nil
+}
+
+// MulRate multiplies m by a rate such as a tax rate, rounding half
+// away from zero to the nearest minor unit.
+func (m Money) MulRate(rate float64) Money {
+	return New(int64(math.Round(float64(m.Amount)*rate)), m.Currency)
+}
+
+// Allocate splits m in proportion to ratios, such as 70 and 30. The
+// minor units lost to rounding down go one each to the first parts,
+// so the parts always add up to m.
+func (m Money) Allocate(ratios ...int) ([]Money, error) {
+	var total int64
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, fmt.Errorf("%w: negative ratio %d", ErrInvalidMoney, r)
+		}
+		total += int64(r)
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("%w: no ratio to allocate %s by", ErrInvalidMoney, m)
+	}
+
+	parts := make([]Money, len(ratios))
+	remainder := m.Amount
+	for i, r := range ratios {
+		share := mulDiv(m.Amount, int64(r), total)
+		parts[i] = New(share, m.Currency)
+		remainder -= share
+	}
+	step := int64(1)
+	if remainder < 0 {
+		step = -1
+	}
+	for i := 0; remainder != 0; i++ {
+		if ratios[i%len(ratios)] == 0 {
+			continue
+		}
+		parts[i%len(ratios)].Amount += step
+		remainder -= step
+	}
+	return parts, nil
+}
+
+// Split divides m into n parts as equal as they can be, differing by
+// at most one minor unit.
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: cannot split %s into %d parts", ErrInvalidMoney, m, n)
+	}
+	ratios := make([]int, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
+
+// mulDiv returns a*b/c, truncated toward zero. The product may
+// exceed int64; the quotient cannot, since b <= c.
+func mulDiv(a, b, c int64) int64 {
+	var q big.Int
+	q.Mul(big.NewInt(a), big.NewInt(b))
+	return q.Quo(&q, big.NewInt(c)).Int64()
+}
+
+// Decimal formats the amount without the currency, e.g. "12.50".
+// Unknown currencies are formatted with two decimals.
+func (m Money) Decimal() string {
+	decimals, err := m.Currency.Decimals()
+	if err != nil {
+		decimals = 2
+	}
+
+	sign := ""
+	amount := uint64(m.Amount)
+	if m.Amount < 0 {
+		sign, amount = "-", uint64(-m.Amount)
+	}
+	if decimals == 0 {
+		return sign + strconv.FormatUint(amount, 10)
+	}
+
+	unit := uint64(math.Pow10(decimals))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/unit, decimals, amount%unit)
+}
+
+// String formats m as "12.50 EUR".
+func (m Money) String() string {
+	return m.Decimal() + " " + string(m.Currency)
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.Currency != o.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return nil
+}
+
+// Sum adds up amounts that must all be in currency. The sum of no
+// amounts is zero.
+func Sum(currency Currency, amounts ...Money) (Money, error) {
+	total := New(0, currency)
+	for _, m := range amounts {
+		var err error
+		if total, err = total.Add(m); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
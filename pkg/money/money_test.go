@@ -0,0 +1,202 @@
+package money
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestParseAndFormat(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency Currency
+		want     Money
+		str      string
+	}{
+		{"12.50", "EUR", New(1250, "EUR"), "12.50 EUR"},
+		{"12.5", "EUR", New(1250, "EUR"), "12.50 EUR"},
+		{"-0.05", "USD", New(-5, "USD"), "-0.05 USD"},
+		{"1200", "JPY", New(1200, "JPY"), "1200 JPY"},
+		{" 1.234 ", "KWD", New(1234, "KWD"), "1.234 KWD"},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, tt.currency)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q, %s) = %v, %v; want %v", tt.in, tt.currency, got, err, tt.want)
+		}
+		if got.String() != tt.str {
+			t.Errorf("String() = %q, want %q", got.String(), tt.str)
+		}
+	}
+
+	for _, in := range []string{"", "1.", ".5", "1.234", "1,50", "1e3", "--1", "99999999999999999999"} {
+		if _, err := Parse(in, "EUR"); !errors.Is(err, ErrInvalidMoney) {
+			t.Errorf("Parse(%q) = %v, want ErrInvalidMoney", in, err)
+		}
+	}
+	if _, err := Parse("1", "XXX"); !errors.Is(err, ErrUnknownCurrency) {
+		t.Errorf("Parse in XXX = %v, want ErrUnknownCurrency", err)
+	}
+	if got := New(math.MinInt64, "USD").Decimal(); got != "-92233720368547758.08" {
+		t.Errorf("Decimal of the smallest amount = %q", got)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if err := Register("XTS", 3); err != nil {
+		t.Fatal(err)
+	}
+	if d, err := Currency("XTS").Decimals(); err != nil || d != 3 {
+		t.Errorf("Decimals = %d, %v; want 3", d, err)
+	}
+	if err := Register("XTS", -1); !errors.Is(err, ErrUnknownCurrency) {
+		t.Errorf("Register with -1 decimals = %v", err)
+	}
+	if Currency("XXX").Valid() || !Currency("EUR").Valid() {
+		t.Error("Valid is wrong")
+	}
+}
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		m       Money
+		n       int
+		want    Money
+		wantErr error
+	}{
+		{New(1250, "EUR"), 3, New(3750, "EUR"), nil},
+		{New(1250, "EUR"), 0, New(0, "EUR"), nil},
+		{New(-1250, "EUR"), 2, New(-2500, "EUR"), nil},
+		{New(1250, "EUR"), -1, New(-1250, "EUR"), nil},
+		{New(math.MaxInt64, "EUR"), 1, New(math.MaxInt64, "EUR"), nil},
+		{New(math.MaxInt64/2+1, "EUR"), 2, Money{}, ErrOverflow},
+		{New(math.MinInt64, "EUR"), -1, Money{}, ErrOverflow},
+		{New(math.MinInt64, "EUR"), 2, Money{}, ErrOverflow},
+		{New(1<<32, "EUR"), 1 << 32, Money{}, ErrOverflow},
+	}
+	for _, tt := range tests {
+		got, err := tt.m.Mul(tt.n)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("%v * %d = %v, %v; want %v, %v", tt.m, tt.n, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := New(1000, "EUR"), New(250, "EUR")
+	if got, err := a.Add(b); err != nil || got != New(1250, "EUR") {
+		t.Errorf("Add = %v, %v", got, err)
+	}
+	if got, err := b.Sub(a); err != nil || got != New(-750, "EUR") {
+		t.Errorf("Sub = %v, %v", got, err)
+	}
+	if cmp, err := a.Cmp(b); err != nil || cmp != 1 {
+		t.Errorf("Cmp = %d, %v", cmp, err)
+	}
+	if got := New(1999, "EUR").MulRate(0.19); got != New(380, "EUR") {
+		t.Errorf("MulRate = %v, want 3.80 EUR", got)
+	}
+
+	usd := New(1, "USD")
+	if _, err := a.Add(usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add across currencies = %v", err)
+	}
+	if _, err := a.Cmp(usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Cmp across currencies = %v", err)
+	}
+	if _, err := Sum("EUR", a, usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Sum across currencies = %v", err)
+	}
+
+	max, min := New(math.MaxInt64, "EUR"), New(math.MinInt64, "EUR")
+	one := New(1, "EUR")
+	if _, err := max.Add(one); !errors.Is(err, ErrOverflow) {
+		t.Errorf("max + 1 = %v, want ErrOverflow", err)
+	}
+	if _, err := min.Sub(one); !errors.Is(err, ErrOverflow) {
+		t.Errorf("min - 1 = %v, want ErrOverflow", err)
+	}
+	if _, err := one.Sub(min); !errors.Is(err, ErrOverflow) {
+		t.Errorf("1 - min = %v, want ErrOverflow", err)
+	}
+	if got, err := max.Sub(one); err != nil || got.Amount != math.MaxInt64-1 {
+		t.Errorf("max - 1 = %v, %v", got, err)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		amount int64
+		ratios []int
+		want   []int64
+	}{
+		{100, []int{1, 1, 1}, []int64{34, 33, 33}},
+		{5, []int{70, 30}, []int64{4, 1}},
+		{-100, []int{1, 1, 1}, []int64{-34, -33, -33}},
+		{10, []int{0, 1, 1}, []int64{0, 5, 5}},
+		{7, []int{0, 1, 1}, []int64{0, 4, 3}},
+		{math.MaxInt64, []int{1, 1}, []int64{math.MaxInt64/2 + 1, math.MaxInt64 / 2}},
+	}
+	for _, tt := range tests {
+		parts, err := New(tt.amount, "EUR").Allocate(tt.ratios...)
+		if err != nil {
+			t.Fatalf("Allocate(%d, %v): %v", tt.amount, tt.ratios, err)
+		}
+		var got []int64
+		for _, p := range parts {
+			got = append(got, p.Amount)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Allocate(%d, %v) = %v, want %v", tt.amount, tt.ratios, got, tt.want)
+		}
+	}
+
+	for _, ratios := range [][]int{nil, {0, 0}, {1, -1}} {
+		if _, err := New(100, "EUR").Allocate(ratios...); !errors.Is(err, ErrInvalidMoney) {
+			t.Errorf("Allocate(%v) = %v, want ErrInvalidMoney", ratios, err)
+		}
+	}
+	if parts, err := New(10, "EUR").Split(3); err != nil || len(parts) != 3 || parts[0].Amount != 4 {
+		t.Errorf("Split(3) = %v, %v", parts, err)
+	}
+	if _, err := New(10, "EUR").Split(0); !errors.Is(err, ErrInvalidMoney) {
+		t.Errorf("Split(0) = %v", err)
+	}
+}
+
+func TestConverter(t *testing.T) {
+	ctx := context.Background()
+	c := NewConverter(StaticRates{
+		{"EUR", "USD"}: 1.1,
+		{"USD", "JPY"}: 150,
+	})
+	tests := []struct {
+		in   Money
+		to   Currency
+		want Money
+	}{
+		{New(1000, "EUR"), "USD", New(1100, "USD")},
+		{New(1100, "USD"), "EUR", New(1000, "EUR")},
+		{New(1999, "USD"), "JPY", New(2999, "JPY")},
+		{New(3000, "JPY"), "USD", New(2000, "USD")},
+		{New(5, "EUR"), "EUR", New(5, "EUR")},
+	}
+	for _, tt := range tests {
+		got, err := c.Convert(ctx, tt.in, tt.to)
+		if err != nil || got != tt.want {
+			t.Errorf("Convert(%v, %s) = %v, %v; want %v", tt.in, tt.to, got, err, tt.want)
+		}
+	}
+
+	if _, err := c.Convert(ctx, New(1, "EUR"), "GBP"); !errors.Is(err, ErrNoRate) {
+		t.Errorf("Convert without a rate = %v, want ErrNoRate", err)
+	}
+	if _, err := c.Convert(ctx, New(1, "EUR"), "XXX"); !errors.Is(err, ErrUnknownCurrency) {
+		t.Errorf("Convert to XXX = %v, want ErrUnknownCurrency", err)
+	}
+	if _, err := c.Convert(ctx, New(math.MaxInt64, "USD"), "JPY"); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Convert overflowing = %v, want ErrOverflow", err)
+	}
+}
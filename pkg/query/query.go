@@ -0,0 +1,141 @@
+// Package query holds the value objects of a paged, sorted listing:
+// which Page to return, in which Sort order, and the Cursor where the
+// previous page ended. Stores apply them; the HTTP API reads them from
+// and writes them to the URL, so every listing pages the same way.
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalid = errors.New("invalid query")
+
+// Sort orders a listing by one field, ascending unless Desc is set.
+// Items with equal fields are ordered by ID, in the same direction, so
+// the order is total and a Cursor can resume it.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort parses "field" or "-field", for descending, where field
+// must be one of fields. An empty s sorts ascending by fields[0].
+func ParseSort(s string, fields ...string) (Sort, error) {
+	if len(fields) == 0 {
+		return Sort{}, fmt.Errorf("%w: no sortable fields", ErrInvalid)
+	}
+	if s == "" {
+		return Sort{Field: fields[0]}, nil
+	}
+	sort := Sort{Field: strings.TrimPrefix(s, "-"), Desc: strings.HasPrefix(s, "-")}
+	if !slices.Contains(fields, sort.Field) {
+		return Sort{}, fmt.Errorf("%w: cannot sort by %q; use one of %s", ErrInvalid, sort.Field, strings.Join(fields, ", "))
+	}
+	return sort, nil
+}
+
+// String formats s the way ParseSort reads it.
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+// Cursor marks the last item of a page: its sort key, formatted by the
+// store, and its ID. The next page starts after it. A Cursor only
+// continues the listing it came from, the one sorted by Sort.
+type Cursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k,omitempty"`
+	ID   int    `json:"i"`
+}
+
+func (c Cursor) IsZero() bool { return c == Cursor{} }
+
+// Encode returns c as an opaque token. Clients pass it back unchanged;
+// its content is not part of the API.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c) // cannot fail for strings and ints
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor reverses Encode.
+func DecodeCursor(token string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalid)
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.IsZero() {
+		return Cursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalid)
+	}
+	return c, nil
+}
+
+// Page selects part of a listing: up to Limit items, zero meaning no
+// limit, starting after the cursor After or else after skipping Offset
+// items. Cursors stay correct while items are added or removed;
+// offsets are simpler but may skip or repeat an item.
+type Page struct {
+	Limit  int
+	Offset int
+	After  Cursor
+}
+
+func (p Page) Validate() error {
+	if p.Limit < 0 || p.Offset < 0 {
+		return fmt.Errorf("%w: limit %d, offset %d", ErrInvalid, p.Limit, p.Offset)
+	}
+	if p.Offset > 0 && !p.After.IsZero() {
+		return fmt.Errorf("%w: offset and cursor together", ErrInvalid)
+	}
+	return nil
+}
+
+// Slice applies p's Offset and Limit to items; its After is for the
+// caller to apply first.
+func Slice[T any](items []T, p Page) []T {
+	if p.Offset >= len(items) {
+		return nil
+	}
+	items = items[p.Offset:]
+	if p.Limit > 0 && p.Limit < len(items) {
+		items = items[:p.Limit]
+	}
+	return items
+}
+
+// ParsePage reads ?limit=, ?offset= and ?cursor=. A missing limit is
+// defaultLimit; zero or one above maxLimit is maxLimit, so a client
+// never gets an unbounded page.
+func ParsePage(q url.Values, defaultLimit, maxLimit int) (Page, error) {
+	p := Page{Limit: defaultLimit}
+	for name, field := range map[string]*int{"limit": &p.Limit, "offset": &p.Offset} {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return Page{}, fmt.Errorf("%w: %s: %q", ErrInvalid, name, v)
+			}
+			*field = n
+		}
+	}
+	if token := q.Get("cursor"); token != "" {
+		c, err := DecodeCursor(token)
+		if err != nil {
+			return Page{}, err
+		}
+		p.After = c
+	}
+	if p.Limit == 0 || p.Limit > maxLimit {
+		p.Limit = maxLimit
+	}
+	return p, p.Validate()
+}
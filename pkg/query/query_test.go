@@ -0,0 +1,92 @@
+package query
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	fields := []string{"id", "created_at"}
+	tests := []struct {
+		in   string
+		want Sort
+	}{
+		{"", Sort{Field: "id"}},
+		{"created_at", Sort{Field: "created_at"}},
+		{"-created_at", Sort{Field: "created_at", Desc: true}},
+	}
+	for _, tt := range tests {
+		got, err := ParseSort(tt.in, fields...)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSort(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+		if tt.in != "" && got.String() != tt.in {
+			t.Errorf("String() = %q, want %q", got.String(), tt.in)
+		}
+	}
+	for _, in := range []string{"total", "-", "--id"} {
+		if _, err := ParseSort(in, fields...); !errors.Is(err, ErrInvalid) {
+			t.Errorf("ParseSort(%q) = %v, want ErrInvalid", in, err)
+		}
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c := Cursor{Sort: "-created_at", Key: "2025-01-01T00:00:00Z", ID: 42}
+	got, err := DecodeCursor(c.Encode())
+	if err != nil || got != c {
+		t.Errorf("DecodeCursor(Encode()) = %+v, %v; want %+v", got, err, c)
+	}
+	for _, token := range []string{"not base64!", "bm90IGpzb24", Cursor{}.Encode()} {
+		if _, err := DecodeCursor(token); !errors.Is(err, ErrInvalid) {
+			t.Errorf("DecodeCursor(%q) = %v, want ErrInvalid", token, err)
+		}
+	}
+}
+
+func TestParsePage(t *testing.T) {
+	after := Cursor{Sort: "id", ID: 7}
+	tests := []struct {
+		query string
+		want  Page
+	}{
+		{"", Page{Limit: 50}},
+		{"limit=10&offset=20", Page{Limit: 10, Offset: 20}},
+		{"limit=0", Page{Limit: 100}},
+		{"limit=1000", Page{Limit: 100}},
+		{"cursor=" + after.Encode(), Page{Limit: 50, After: after}},
+	}
+	for _, tt := range tests {
+		q, _ := url.ParseQuery(tt.query)
+		got, err := ParsePage(q, 50, 100)
+		if err != nil || got != tt.want {
+			t.Errorf("ParsePage(%q) = %+v, %v; want %+v", tt.query, got, err, tt.want)
+		}
+	}
+	for _, query := range []string{"limit=x", "offset=-1", "limit=-5", "cursor=!", "offset=1&cursor=" + after.Encode()} {
+		q, _ := url.ParseQuery(query)
+		if _, err := ParsePage(q, 50, 100); !errors.Is(err, ErrInvalid) {
+			t.Errorf("ParsePage(%q) = %v, want ErrInvalid", query, err)
+		}
+	}
+}
+
+func TestSlice(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		page Page
+		want []int
+	}{
+		{Page{}, items},
+		{Page{Limit: 2}, []int{1, 2}},
+		{Page{Limit: 2, Offset: 4}, []int{5}},
+		{Page{Offset: 5}, nil},
+	}
+	for _, tt := range tests {
+		if got := Slice(items, tt.page); !slices.Equal(got, tt.want) {
+			t.Errorf("Slice(%+v) = %v, want %v", tt.page, got, tt.want)
+		}
+	}
+}
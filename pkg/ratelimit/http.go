@@ -0,0 +1,15 @@
+package ratelimit
+
+import "net/http"
+
+// Middleware answers 429 Too Many Requests, without calling next, to
+// the requests l does not allow.
+func Middleware(l Limiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.Allow() {
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,109 @@
+// Package ratelimit limits how often something may happen. The
+// callers, such as a notification channel or an HTTP server, depend
+// on Limiter; whether it counts with a token bucket or a sliding
+// window is chosen where it is wired.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+// Limiter decides whether one more event may happen now. Allow
+// records the event when it returns true. Implementations are safe
+// for concurrent use.
+type Limiter interface {
+	Allow() bool
+}
+
+// TokenBucket allows burst events at once and one more every interval:
+// each event takes a token, the bucket holds at most burst tokens, and
+// a token is added every interval. A full bucket does not save up
+// time for later.
+type TokenBucket struct {
+	clock    clock.Clock
+	interval time.Duration
+	burst    int
+
+	mu     sync.Mutex
+	tokens int
+	last   time.Time // when the bucket was last refilled
+}
+
+// NewTokenBucket returns a full bucket. A non-positive interval never
+// refills it, and a nil clk means the system clock.
+func NewTokenBucket(interval time.Duration, burst int, clk clock.Clock) *TokenBucket {
+	clk = clock.OrSystem(clk)
+	return &TokenBucket{
+		clock:    clk,
+		interval: interval,
+		burst:    burst,
+		tokens:   burst,
+		last:     clk.Now(),
+	}
+}
+
+func (b *TokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.interval > 0 {
+		now := b.clock.Now()
+		added := int(now.Sub(b.last) / b.interval)
+		if added > 0 {
+			b.tokens = min(b.burst, b.tokens+added)
+			b.last = b.last.Add(time.Duration(added) * b.interval)
+		}
+		if b.tokens == b.burst {
+			b.last = now
+		}
+	}
+	if b.tokens == 0 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// SlidingWindow allows at most limit events in any window. Unlike a
+// token bucket it never lets a burst through at the edge of two
+// windows, at the cost of remembering when the last limit events
+// happened.
+type SlidingWindow struct {
+	clock  clock.Clock
+	window time.Duration
+
+	mu     sync.Mutex
+	events []time.Time // a ring of the last len(events) allowed events
+	next   int         // the oldest event, overwritten by the next one
+	count  int
+}
+
+// NewSlidingWindow returns a limiter allowing limit events per window.
+// A nil clk means the system clock.
+func NewSlidingWindow(window time.Duration, limit int, clk clock.Clock) *SlidingWindow {
+	return &SlidingWindow{
+		clock:  clock.OrSystem(clk),
+		window: window,
+		events: make([]time.Time, max(limit, 0)),
+	}
+}
+
+func (w *SlidingWindow) Allow() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.events) == 0 {
+		return false
+	}
+	now := w.clock.Now()
+	if w.count == len(w.events) && now.Sub(w.events[w.next]) < w.window {
+		return false
+	}
+	w.events[w.next] = now
+	w.next = (w.next + 1) % len(w.events)
+	w.count = min(w.count+1, len(w.events))
+	return true
+}
@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+)
+
+// allowed calls l.Allow n times and reports which calls were allowed.
+func allowed(l Limiter, n int) []bool {
+	got := make([]bool, n)
+	for i := range got {
+		got[i] = l.Allow()
+	}
+	return got
+}
+
+func TestTokenBucket(t *testing.T) {
+	clk := clocktest.NewFake(time.Time{})
+	b := NewTokenBucket(time.Minute, 2, clk)
+
+	if got := allowed(b, 3); !slices.Equal(got, []bool{true, true, false}) {
+		t.Fatalf("full bucket: %v", got)
+	}
+	clk.Advance(time.Minute)
+	if got := allowed(b, 2); !slices.Equal(got, []bool{true, false}) {
+		t.Fatalf("after one interval: %v", got)
+	}
+	// An hour idle only fills the bucket to burst.
+	clk.Advance(time.Hour)
+	if got := allowed(b, 3); !slices.Equal(got, []bool{true, true, false}) {
+		t.Fatalf("after an hour: %v", got)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	clk := clocktest.NewFake(time.Time{})
+	w := NewSlidingWindow(time.Minute, 2, clk)
+
+	if got := allowed(w, 3); !slices.Equal(got, []bool{true, true, false}) {
+		t.Fatalf("first window: %v", got)
+	}
+	// A fixed window would reset here; the sliding one still counts
+	// both events until a minute after each.
+	clk.Advance(59 * time.Second)
+	if w.Allow() {
+		t.Fatal("allowed before the first event left the window")
+	}
+	clk.Advance(time.Second)
+	if got := allowed(w, 3); !slices.Equal(got, []bool{true, true, false}) {
+		t.Fatalf("a minute later: %v", got)
+	}
+}
+
+func TestSlidingWindow_ZeroLimit(t *testing.T) {
+	if NewSlidingWindow(time.Minute, 0, nil).Allow() {
+		t.Error("a zero limit allowed an event")
+	}
+}
+
+// TestLimiters_Concurrent checks, under -race, that concurrent callers
+// together get exactly the limit while the clock stands still.
+func TestLimiters_Concurrent(t *testing.T) {
+	const limit, callers, calls = 100, 16, 50
+
+	limiters := map[string]func() Limiter{
+		"token bucket":   func() Limiter { return NewTokenBucket(time.Minute, limit, clocktest.NewFake(time.Time{})) },
+		"sliding window": func() Limiter { return NewSlidingWindow(time.Minute, limit, clocktest.NewFake(time.Time{})) },
+	}
+	for name, newLimiter := range limiters {
+		t.Run(name, func(t *testing.T) {
+			l := newLimiter()
+			var n atomic.Int64
+			var wg sync.WaitGroup
+			for range callers {
+				wg.Go(func() {
+					for range calls {
+						if l.Allow() {
+							n.Add(1)
+						}
+					}
+				})
+			}
+			wg.Wait()
+			if n.Load() != limit {
+				t.Errorf("allowed %d of %d calls, want %d", n.Load(), callers*calls, limit)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	h := Middleware(NewTokenBucket(time.Minute, 1, clocktest.NewFake(time.Time{})), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for _, want := range []int{http.StatusNoContent, http.StatusTooManyRequests} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders", nil))
+		if rec.Code != want {
+			t.Errorf("status = %d, want %d", rec.Code, want)
+		}
+	}
+}
+
+func BenchmarkTokenBucket(b *testing.B) {
+	l := NewTokenBucket(time.Nanosecond, 1000, nil)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Allow()
+		}
+	})
+}
+
+func BenchmarkSlidingWindow(b *testing.B) {
+	l := NewSlidingWindow(time.Millisecond, 1000, nil)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Allow()
+		}
+	})
+}
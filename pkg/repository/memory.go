@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Memory is a Repository kept in a map. It is safe for concurrent use.
+type Memory[T Entity] struct {
+	notFound error
+	clone    func(T) T
+
+	mu       sync.RWMutex
+	entities map[int]T
+}
+
+// NewMemory returns an empty repository. Missing IDs fail with
+// notFound, or ErrNotFound if it is nil. clone copies an entity on the
+// way in and out, so callers never share a slice with the stored one;
+// nil means the entities have none.
+func NewMemory[T Entity](notFound error, clone func(T) T) *Memory[T] {
+	if notFound == nil {
+		notFound = ErrNotFound
+	}
+	if clone == nil {
+		clone = func(e T) T { return e }
+	}
+	return &Memory[T]{notFound: notFound, clone: clone, entities: make(map[int]T)}
+}
+
+func (m *Memory[T]) Save(ctx context.Context, entity T) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	id := entity.EntityID()
+	if id <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidID, id)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entities[id] = m.clone(entity)
+	return nil
+}
+
+func (m *Memory[T]) FindByID(ctx context.Context, id int) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entity, ok := m.entities[id]
+	if !ok {
+		return zero, fmt.Errorf("%w: %d", m.notFound, id)
+	}
+	return m.clone(entity), nil
+}
+
+func (m *Memory[T]) List(ctx context.Context) ([]T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entities := make([]T, 0, len(m.entities))
+	for _, e := range m.entities {
+		entities = append(entities, m.clone(e))
+	}
+	slices.SortFunc(entities, func(a, b T) int { return cmp.Compare(a.EntityID(), b.EntityID()) })
+	return entities, nil
+}
+
+func (m *Memory[T]) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.entities[id]; !ok {
+		return fmt.Errorf("%w: %d", m.notFound, id)
+	}
+	delete(m.entities, id)
+	return nil
+}
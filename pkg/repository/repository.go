@@ -0,0 +1,37 @@
+// Package repository is one storage abstraction for every entity:
+// Repository[T] is instantiated per entity type instead of writing an
+// OrderStore, a CustomerRepository and so on by hand.
+//
+// The price is the common denominator. The generic SQL implementation
+// stores each entity as a JSON document, so it cannot filter, page or
+// join on a column the way a per-entity repository with its own schema
+// can; queries beyond FindByID and List still need their own interface.
+package repository
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned for an ID that is not stored, unless the
+// repository was given a more specific error.
+var ErrNotFound = errors.New("entity not found")
+
+// ErrInvalidID is returned when saving an entity whose ID is not
+// positive.
+var ErrInvalidID = errors.New("invalid entity id")
+
+// Entity is anything stored by ID.
+type Entity interface {
+	EntityID() int
+}
+
+// Repository stores entities of one type.
+type Repository[T Entity] interface {
+	// Save inserts or replaces the entity with the same ID.
+	Save(ctx context.Context, entity T) error
+	FindByID(ctx context.Context, id int) (T, error)
+	// List returns every entity, sorted by ID.
+	List(ctx context.Context) ([]T, error)
+	Delete(ctx context.Context, id int) error
+}
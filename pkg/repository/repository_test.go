@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+type note struct {
+	ID   int
+	Tags []string
+}
+
+func (n note) EntityID() int { return n.ID }
+
+func cloneNote(n note) note {
+	n.Tags = append([]string(nil), n.Tags...)
+	return n
+}
+
+var errNoteNotFound = errors.New("note not found")
+
+// openSQLite returns a private in-memory database, closed when the
+// test ends.
+func openSQLite(tb testing.TB) *sql.DB {
+	tb.Helper()
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=memory&cache=shared", tb.Name()))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	tb.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newSQLNotes(tb testing.TB) *SQL[note] {
+	tb.Helper()
+	db := openSQLite(tb)
+	if _, err := db.Exec(Schema("notes")); err != nil {
+		tb.Fatal(err)
+	}
+	r, err := NewSQL[note](db, "notes", errNoteNotFound)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return r
+}
+
+// testRepository is the contract every Repository meets.
+func testRepository(t *testing.T, r Repository[note]) {
+	ctx := context.Background()
+
+	if err := r.Save(ctx, note{ID: 0}); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Save(id 0) = %v, want ErrInvalidID", err)
+	}
+	for _, n := range []note{{ID: 2, Tags: []string{"b"}}, {ID: 1, Tags: []string{"a"}}, {ID: 2, Tags: []string{"c"}}} {
+		if err := r.Save(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := r.FindByID(ctx, 2)
+	if err != nil || len(got.Tags) != 1 || got.Tags[0] != "c" {
+		t.Fatalf("FindByID(2) = %+v, %v; want the replaced note", got, err)
+	}
+	got.Tags[0] = "changed"
+	if again, _ := r.FindByID(ctx, 2); again.Tags[0] != "c" {
+		t.Error("FindByID shares its slice with the stored note")
+	}
+
+	list, err := r.List(ctx)
+	if err != nil || len(list) != 2 || list[0].ID != 1 || list[1].ID != 2 {
+		t.Fatalf("List = %+v, %v", list, err)
+	}
+
+	if err := r.Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.FindByID(ctx, 1); !errors.Is(err, errNoteNotFound) {
+		t.Errorf("FindByID after Delete = %v, want errNoteNotFound", err)
+	}
+	if err := r.Delete(ctx, 1); !errors.Is(err, errNoteNotFound) {
+		t.Errorf("second Delete = %v, want errNoteNotFound", err)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	testRepository(t, NewMemory(errNoteNotFound, cloneNote))
+}
+
+func TestSQL(t *testing.T) {
+	testRepository(t, newSQLNotes(t))
+}
+
+func TestNewSQL_RejectsTableNames(t *testing.T) {
+	if _, err := NewSQL[note](nil, "notes; DROP TABLE orders", nil); err == nil {
+		t.Error("accepted a table name that is not an identifier")
+	}
+}
+
+func TestMemory_DefaultNotFound(t *testing.T) {
+	if _, err := NewMemory[note](nil, nil).FindByID(context.Background(), 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// SQL is a Repository storing each entity as a JSON document in a
+// table of two columns, created with Schema. The driver is picked by
+// whoever opens the *sql.DB; the queries use SQLite syntax.
+type SQL[T Entity] struct {
+	db       *sql.DB
+	table    string
+	notFound error
+}
+
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Schema returns the statement creating the table of an SQL repository.
+func Schema(table string) string {
+	return `CREATE TABLE IF NOT EXISTS ` + table + ` (
+		id   INTEGER PRIMARY KEY,
+		data TEXT    NOT NULL
+	)`
+}
+
+// NewSQL returns a repository over table, which must exist; see Schema.
+// Missing IDs fail with notFound, or ErrNotFound if it is nil.
+func NewSQL[T Entity](db *sql.DB, table string, notFound error) (*SQL[T], error) {
+	if !tableName.MatchString(table) {
+		return nil, fmt.Errorf("repository: invalid table name %q", table)
+	}
+	if notFound == nil {
+		notFound = ErrNotFound
+	}
+	return &SQL[T]{db: db, table: table, notFound: notFound}, nil
+}
+
+func (r *SQL[T]) Save(ctx context.Context, entity T) error {
+	id := entity.EntityID()
+	if id <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidID, id)
+	}
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return fmt.Errorf("encoding %s %d: %w", r.table, id, err)
+	}
+	_, err = r.db.ExecContext(ctx, `INSERT INTO `+r.table+` (id, data) VALUES (?, ?)
+		ON CONFLICT (id) DO UPDATE SET data = excluded.data`, id, data)
+	if err != nil {
+		return fmt.Errorf("saving %s %d: %w", r.table, id, err)
+	}
+	return nil
+}
+
+func (r *SQL[T]) FindByID(ctx context.Context, id int) (T, error) {
+	var entity T
+	var data []byte
+	err := r.db.QueryRowContext(ctx, `SELECT data FROM `+r.table+` WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity, fmt.Errorf("%w: %d", r.notFound, id)
+	}
+	if err != nil {
+		return entity, fmt.Errorf("loading %s %d: %w", r.table, id, err)
+	}
+	if err := json.Unmarshal(data, &entity); err != nil {
+		return entity, fmt.Errorf("decoding %s %d: %w", r.table, id, err)
+	}
+	return entity, nil
+}
+
+func (r *SQL[T]) List(ctx context.Context) ([]T, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT data FROM `+r.table+` ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("listing %s: %w", r.table, err)
+	}
+	defer rows.Close()
+
+	var entities []T
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var entity T
+		if err := json.Unmarshal(data, &entity); err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", r.table, err)
+		}
+		entities = append(entities, entity)
+	}
+	return entities, rows.Err()
+}
+
+func (r *SQL[T]) Delete(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM `+r.table+` WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("deleting %s %d: %w", r.table, id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("%w: %d", r.notFound, id)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Package result holds a value or an error in one Result[T], so steps
+// can be chained with Map and AndThen instead of an if err != nil
+// after each call.
+//
+// It exists to compare with Go's own (T, error). A chain reads as a
+// pipeline, but every step must be adapted to take and return
+// Results, Go's lack of generic methods makes Map and AndThen
+// functions rather than methods, and the error of the failed step is
+// only looked at once the chain ends. Most Go code, this repository's
+// included, stays with (T, error).
+package result
+
+// Result is either a value or an error. The zero Result is Ok with the
+// zero value.
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Ok returns a successful Result.
+func Ok[T any](v T) Result[T] {
+	return Result[T]{value: v}
+}
+
+// Err returns a failed Result. A nil err makes an Ok Result of the
+// zero value.
+func Err[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// Of turns the (T, error) a Go function returns into a Result.
+func Of[T any](v T, err error) Result[T] {
+	if err != nil {
+		return Err[T](err)
+	}
+	return Ok(v)
+}
+
+// Get returns the value and error, back in the (T, error) form. A
+// failed Result returns the zero value.
+func (r Result[T]) Get() (T, error) {
+	if r.err != nil {
+		var zero T
+		return zero, r.err
+	}
+	return r.value, nil
+}
+
+// IsOk reports whether r holds a value.
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// Err returns the error of a failed Result, or nil.
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Or returns the value, or def if r failed.
+func (r Result[T]) Or(def T) T {
+	if r.err != nil {
+		return def
+	}
+	return r.value
+}
+
+// Map applies f to the value of a successful Result. A failed Result
+// passes through without calling f.
+func Map[T, U any](r Result[T], f func(T) U) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+	return Ok(f(r.value))
+}
+
+// AndThen chains a step that can fail: it calls f with the value of a
+// successful Result and returns f's Result. A failed Result passes
+// through without calling f.
+func AndThen[T, U any](r Result[T], f func(T) Result[U]) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+	return f(r.value)
+}
+
+// Try is AndThen for a step written the Go way, returning (U, error).
+func Try[T, U any](r Result[T], f func(T) (U, error)) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+	return Of(f(r.value))
+}
+
+// MapErr applies f to the error of a failed Result, to wrap it with
+// context. A successful Result passes through.
+func MapErr[T any](r Result[T], f func(error) error) Result[T] {
+	if r.err == nil {
+		return r
+	}
+	return Err[T](f(r.err))
+}
@@ -0,0 +1,71 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+var errNegative = errors.New("negative")
+
+func parse(s string) (int, error) { return strconv.Atoi(s) }
+
+func positive(n int) Result[int] {
+	if n < 0 {
+		return Err[int](errNegative)
+	}
+	return Ok(n)
+}
+
+func TestChain(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr error
+	}{
+		{"21", "42", nil},
+		{"-1", "", errNegative},
+		{"x", "", strconv.ErrSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			doubled := Map(AndThen(Of(parse(tt.in)), positive), func(n int) int { return n * 2 })
+			got, err := Map(doubled, strconv.Itoa).Get()
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %q, %v; want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFailedResultSkipsLaterSteps(t *testing.T) {
+	called := false
+	r := AndThen(Err[int](errNegative), func(n int) Result[int] {
+		called = true
+		return Ok(n)
+	})
+	r = Try(r, func(n int) (int, error) {
+		called = true
+		return n, nil
+	})
+	if called || !errors.Is(r.Err(), errNegative) || r.IsOk() {
+		t.Errorf("called = %v, err = %v", called, r.Err())
+	}
+}
+
+func TestAccessors(t *testing.T) {
+	if v := Err[int](errNegative).Or(7); v != 7 {
+		t.Errorf("Or = %d, want 7", v)
+	}
+	if v, err := Err[int](nil).Get(); v != 0 || err != nil {
+		t.Errorf("Err(nil) = %d, %v; want an Ok zero value", v, err)
+	}
+	wrapped := MapErr(Err[int](errNegative), func(err error) error { return fmt.Errorf("step 2: %w", err) })
+	if wrapped.Err().Error() != "step 2: negative" || !errors.Is(wrapped.Err(), errNegative) {
+		t.Errorf("MapErr = %v", wrapped.Err())
+	}
+	if MapErr(Ok(1), func(error) error { return errNegative }).Err() != nil {
+		t.Error("MapErr changed an Ok Result")
+	}
+}
@@ -0,0 +1,65 @@
+package sched
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse_Next(t *testing.T) {
+	// Wednesday 15 January 2025, 10:07:30.
+	from := time.Date(2025, time.January, 15, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, 1, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 1, 15, 10, 15, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2025, 1, 16, 2, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2025, 1, 15, 13, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 1,5", time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either may match.
+		{"0 0 20 * 5", time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+		{"@hourly", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", from.Add(90 * time.Second)},
+	}
+	for _, tt := range tests {
+		s, err := Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := s.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Parse(%q).Next = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *", "@every", "@every -1s", "@yearly"} {
+		if _, err := Parse(expr); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("Parse(%q) = %v, want ErrInvalidSchedule", expr, err)
+		}
+	}
+}
+
+func TestCron_NextKeepsLocation(t *testing.T) {
+	kolkata := time.FixedZone("IST", 5*3600+1800)
+	s, err := Parse("0 9 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := s.Next(time.Date(2025, 1, 15, 8, 59, 0, 0, kolkata))
+	if want := time.Date(2025, 1, 15, 9, 0, 0, 0, kolkata); !got.Equal(want) {
+		t.Errorf("Next = %v, want %v", got, want)
+	}
+}
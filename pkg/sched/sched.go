@@ -0,0 +1,165 @@
+// Package sched runs jobs on schedules: fixed intervals or cron
+// expressions. Jobs only implement Job, and the Scheduler decides
+// when they run, what happens when a run is still going when the next
+// one is due, and how much random jitter spreads runs out. Time comes
+// from an injected clock.Clock, so tests drive schedules with a fake
+// clock instead of waiting.
+package sched
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+// Job is work run on a schedule.
+type Job interface {
+	Run(ctx context.Context) error
+}
+
+// JobFunc lets a plain function be used as a Job.
+type JobFunc func(ctx context.Context) error
+
+func (f JobFunc) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Overlap says what happens when a job is due while its previous run
+// is still going.
+type Overlap int
+
+const (
+	// Skip drops the due run. It is the default.
+	Skip Overlap = iota
+	// Wait runs the job again as soon as the previous run ends. Runs
+	// that fall due meanwhile are merged into that one.
+	Wait
+	// Allow starts the due run alongside the previous one.
+	Allow
+)
+
+// Entry is a job and when to run it.
+type Entry struct {
+	Name     string
+	Schedule Schedule
+	Job      Job
+	Overlap  Overlap
+	// Jitter delays every run by a random duration below it, so
+	// processes sharing a schedule do not all run at once.
+	Jitter time.Duration
+}
+
+// Scheduler runs entries until its context is done. Add every entry
+// before calling Run.
+type Scheduler struct {
+	clock   clock.Clock
+	onError func(job string, err error)
+	rand    func(n int64) int64
+	entries []Entry
+}
+
+// New returns a scheduler timed by clk, which may be nil for the
+// system clock. onError, which may be nil, is called with every
+// failed run.
+func New(clk clock.Clock, onError func(job string, err error)) *Scheduler {
+	if onError == nil {
+		onError = func(string, error) {}
+	}
+	return &Scheduler{clock: clock.OrSystem(clk), onError: onError, rand: rand.Int64N}
+}
+
+// Add registers e.
+func (s *Scheduler) Add(e Entry) error {
+	switch {
+	case e.Name == "" || e.Schedule == nil || e.Job == nil:
+		return fmt.Errorf("%w: entry needs a name, a schedule and a job", ErrInvalidSchedule)
+	case e.Jitter < 0 || e.Overlap < Skip || e.Overlap > Allow:
+		return fmt.Errorf("%w: %s: bad jitter or overlap", ErrInvalidSchedule, e.Name)
+	}
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+// Run runs the entries until ctx is done, then waits for the runs in
+// progress, which see ctx cancelled, and returns ctx's error.
+func (s *Scheduler) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, e := range s.entries {
+		wg.Go(func() { s.loop(ctx, e) })
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// loop runs one entry. Run times come from the schedule without
+// jitter, so jitter never accumulates.
+func (s *Scheduler) loop(ctx context.Context, e Entry) {
+	var (
+		runs    sync.WaitGroup
+		mu      sync.Mutex
+		running bool
+		pending bool // a Wait run fell due while running
+	)
+	defer runs.Wait()
+
+	var start func()
+	start = func() {
+		running = true
+		runs.Go(func() {
+			s.run(ctx, e)
+			mu.Lock()
+			defer mu.Unlock()
+			running = false
+			if pending && ctx.Err() == nil {
+				pending = false
+				start()
+			}
+		})
+	}
+
+	due := s.clock.Now()
+	for {
+		due = e.Schedule.Next(due)
+		if due.IsZero() {
+			return // the schedule never fires again
+		}
+		wait := due.Sub(s.clock.Now())
+		if e.Jitter > 0 {
+			wait += time.Duration(s.rand(int64(e.Jitter)))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(max(wait, 0)):
+		}
+
+		mu.Lock()
+		switch {
+		case e.Overlap == Allow:
+			runs.Go(func() { s.run(ctx, e) })
+		case !running:
+			start()
+		case e.Overlap == Wait:
+			pending = true
+		}
+		mu.Unlock()
+		// A run overdue by more than a period, after the process was
+		// suspended say, is not caught up on; the schedule resumes from
+		// now.
+		if now := s.clock.Now(); e.Schedule.Next(due).Before(now) {
+			due = now
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, e Entry) {
+	// A run cut short by Run stopping did not fail.
+	if err := e.Job.Run(ctx); err != nil && (ctx.Err() == nil || !errors.Is(err, ctx.Err())) {
+		s.onError(e.Name, err)
+	}
+}
@@ -0,0 +1,213 @@
+package sched
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+)
+
+// recordingJob records when it runs and, while block is set, waits
+// for release before returning.
+type recordingJob struct {
+	clk     *clocktest.Fake
+	started chan time.Time
+	release chan struct{}
+	block   bool
+	err     error
+}
+
+func newRecordingJob(clk *clocktest.Fake) *recordingJob {
+	return &recordingJob{clk: clk, started: make(chan time.Time, 10), release: make(chan struct{})}
+}
+
+func (j *recordingJob) Run(ctx context.Context) error {
+	j.started <- j.clk.Now()
+	if j.block {
+		select {
+		case <-j.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return j.err
+}
+
+// next waits for the job's next run and returns its time.
+func (j *recordingJob) next(t *testing.T) time.Time {
+	t.Helper()
+	select {
+	case at := <-j.started:
+		return at
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the job to run")
+		return time.Time{}
+	}
+}
+
+// none fails if the job started.
+func (j *recordingJob) none(t *testing.T) {
+	t.Helper()
+	select {
+	case at := <-j.started:
+		t.Fatalf("job ran at %v", at)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+var epoch = time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+// start runs s until the test ends.
+func start(t *testing.T, s *Scheduler) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("Run = %v", err)
+		}
+	})
+}
+
+func TestScheduler_Every(t *testing.T) {
+	clk := clocktest.NewFake(epoch)
+	job := newRecordingJob(clk)
+	s := New(clk, nil)
+	if err := s.Add(Entry{Name: "outbox", Schedule: Every(time.Minute), Job: job}); err != nil {
+		t.Fatal(err)
+	}
+	start(t, s)
+
+	for i := 1; i <= 3; i++ {
+		clk.BlockUntil(1)
+		clk.Advance(time.Minute)
+		if got, want := job.next(t), epoch.Add(time.Duration(i)*time.Minute); !got.Equal(want) {
+			t.Errorf("run %d at %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestScheduler_Cron(t *testing.T) {
+	clk := clocktest.NewFake(epoch.Add(23 * time.Hour))
+	job := newRecordingJob(clk)
+	s := New(clk, nil)
+	s.Add(Entry{Name: "report", Schedule: mustParse(t, "30 2 * * *"), Job: job})
+	start(t, s)
+
+	clk.BlockUntil(1)
+	clk.Advance(3*time.Hour + 29*time.Minute)
+	job.none(t)
+	clk.Advance(time.Minute)
+	if got, want := job.next(t), time.Date(2025, 1, 16, 2, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("ran at %v, want %v", got, want)
+	}
+}
+
+func TestScheduler_Overlap(t *testing.T) {
+	tests := []struct {
+		overlap Overlap
+		// runs while the first one is blocked, and right after it ends
+		during, after int
+	}{
+		{Skip, 0, 0},
+		{Wait, 0, 1},
+		{Allow, 2, 0},
+	}
+	for _, tt := range tests {
+		clk := clocktest.NewFake(epoch)
+		job := newRecordingJob(clk)
+		job.block = true
+		s := New(clk, nil)
+		s.Add(Entry{Name: "slow", Schedule: Every(time.Minute), Job: job, Overlap: tt.overlap})
+		start(t, s)
+
+		clk.BlockUntil(1)
+		clk.Advance(time.Minute)
+		job.next(t)
+		for range 2 {
+			clk.BlockUntil(1)
+			clk.Advance(time.Minute)
+		}
+		for range tt.during {
+			job.next(t)
+		}
+		job.none(t)
+
+		for range 1 + tt.during {
+			job.release <- struct{}{}
+		}
+		for range tt.after {
+			job.next(t)
+			job.release <- struct{}{}
+		}
+		job.none(t)
+	}
+}
+
+func TestScheduler_Jitter(t *testing.T) {
+	clk := clocktest.NewFake(epoch)
+	job := newRecordingJob(clk)
+	s := New(clk, nil)
+	s.rand = func(n int64) int64 { return n / 2 }
+	s.Add(Entry{Name: "report", Schedule: Every(time.Hour), Job: job, Jitter: 10 * time.Minute})
+	start(t, s)
+
+	// Jitter delays each run but does not shift the next one.
+	for i, wait := range []time.Duration{time.Hour, 55 * time.Minute} {
+		clk.BlockUntil(1)
+		clk.Advance(wait)
+		job.none(t)
+		clk.Advance(5 * time.Minute)
+		if got, want := job.next(t), epoch.Add(time.Duration(i+1)*time.Hour+5*time.Minute); !got.Equal(want) {
+			t.Errorf("run %d at %v, want %v", i+1, got, want)
+		}
+	}
+}
+
+func TestScheduler_ReportsErrors(t *testing.T) {
+	clk := clocktest.NewFake(epoch)
+	job := newRecordingJob(clk)
+	job.err = errors.New("smtp down")
+	failed := make(chan string, 1)
+	s := New(clk, func(name string, err error) { failed <- name + ": " + err.Error() })
+	s.Add(Entry{Name: "outbox", Schedule: Every(time.Minute), Job: job})
+	start(t, s)
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Minute)
+	select {
+	case got := <-failed:
+		if got != "outbox: smtp down" {
+			t.Errorf("error = %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the failure was not reported")
+	}
+}
+
+func TestScheduler_AddValidates(t *testing.T) {
+	s := New(nil, nil)
+	for _, e := range []Entry{
+		{Schedule: Every(time.Second), Job: JobFunc(nil)},
+		{Name: "x", Job: JobFunc(nil)},
+		{Name: "x", Schedule: Every(time.Second)},
+		{Name: "x", Schedule: Every(time.Second), Job: JobFunc(nil), Jitter: -1},
+	} {
+		if err := s.Add(e); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("Add(%+v) = %v, want ErrInvalidSchedule", e, err)
+		}
+	}
+}
+
+func mustParse(t *testing.T, expr string) Schedule {
+	t.Helper()
+	s, err := Parse(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
@@ -0,0 +1,274 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+// S3Config locates a bucket in an S3-compatible object store.
+type S3Config struct {
+	// Endpoint is the store's base URL, such as
+	// "https://s3.eu-west-1.amazonaws.com" or "http://localhost:9000".
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// S3 stores blobs as objects in a bucket of an S3-compatible store,
+// addressed path-style and signed with AWS Signature Version 4. Puts
+// are buffered in memory to sign their payload, which suits documents
+// and reports rather than large media.
+type S3 struct {
+	cfg    S3Config
+	base   *url.URL
+	client *http.Client
+	clock  clock.Clock
+}
+
+// NewS3 returns a store for cfg's bucket. client and clk may be nil,
+// for http.DefaultClient and the system clock.
+func NewS3(cfg S3Config, client *http.Client, clk clock.Clock) (*S3, error) {
+	base, err := url.Parse(cfg.Endpoint)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("storage: S3 endpoint %q is not an http(s) URL", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" || cfg.Region == "" {
+		return nil, errors.New("storage: S3 needs a bucket and a region")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &S3{cfg: cfg, base: base, client: client, clock: clock.OrSystem(clk)}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("storage: putting %s: %w", key, err)
+	}
+	resp, err := s.do(ctx, http.MethodPut, key, nil, body)
+	if err != nil {
+		return fmt.Errorf("storage: putting %s: %w", key, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("storage: getting %s: %w", key, err)
+	}
+	return resp.Body, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("storage: deleting %s: %w", key, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// listResult is the part of a ListObjectsV2 response List reads.
+type listResult struct {
+	Keys                  []string `xml:"Contents>Key"`
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+// List pages through ListObjectsV2 until every key is read.
+func (s *S3) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+	for {
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("storage: listing %s: %w", prefix, err)
+		}
+		var page listResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("storage: listing %s: %w", prefix, err)
+		}
+		keys = append(keys, page.Keys...)
+		if !page.IsTruncated {
+			return keys, nil
+		}
+		query.Set("continuation-token", page.NextContinuationToken)
+	}
+}
+
+// s3Error is the body of an S3 error response.
+type s3Error struct {
+	Code    string
+	Message string
+}
+
+// do sends a signed request for key, or for the bucket if key is
+// empty, and returns the response if it succeeded. A missing key is
+// reported as ErrNotFound.
+func (s *S3) do(ctx context.Context, method, key string, query url.Values, body []byte) (*http.Response, error) {
+	u := *s.base
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.cfg.Bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, body)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	var e s3Error
+	xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&e)
+	if resp.StatusCode == http.StatusNotFound && e.Code != "NoSuchBucket" {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return nil, fmt.Errorf("%s: %s: %s", resp.Status, e.Code, e.Message)
+}
+
+// emptySHA256 is the hash of an empty payload.
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// sign adds the Signature Version 4 headers to req, whose payload is
+// body. It signs the host, the Range header if any and every x-amz-
+// header.
+func (s *S3) sign(req *http.Request, body []byte) {
+	now := s.clock.Now().UTC()
+	payload := emptySHA256
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		payload = hex.EncodeToString(sum[:])
+	}
+	req.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	req.Header.Set("X-Amz-Content-Sha256", payload)
+
+	headers := map[string]string{"host": req.Host}
+	if req.Host == "" {
+		headers["host"] = req.URL.Host
+	}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "range" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payload,
+	}, "\n")
+
+	date := now.Format("20060102")
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + now.Format("20060102T150405Z") + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+
+	key := []byte("AWS4" + s.cfg.SecretKey)
+	for _, part := range []string{date, s.cfg.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// canonicalQuery encodes q sorted by key, as Signature Version 4
+// requires.
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var parts []string
+	for _, k := range keys {
+		values := slices.Clone(q[k])
+		slices.Sort(values)
+		for _, v := range values {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything but the unreserved characters
+// of RFC 3986 and, unless encodeSlash is set, "/".
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,63 @@
+// Package storage keeps blobs under slash-separated keys such as
+// "invoices/42.pdf". Its ports are deliberately narrow, one per
+// operation, so code asks for only what it uses: a report sink is a
+// Putter, an archive reader a Getter and Lister. Local stores blobs on
+// disk and S3 in any S3-compatible object store.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Putter stores blobs, replacing any blob with the same key.
+type Putter interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+}
+
+// Getter reads blobs. Get returns an error matching ErrNotFound for a
+// missing key.
+type Getter interface {
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// Deleter deletes blobs. Deleting a missing key is not an error.
+type Deleter interface {
+	Delete(ctx context.Context, key string) error
+}
+
+// Lister lists the keys starting with prefix, in lexical order.
+type Lister interface {
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Store is every operation, for code that needs them all.
+type Store interface {
+	Putter
+	Getter
+	Deleter
+	Lister
+}
+
+// ValidateKey checks that key is a relative slash-separated path
+// without empty, "." or ".." segments, so that it names the same blob
+// on disk and in an object store.
+func ValidateKey(key string) error {
+	if key == "" || strings.ContainsAny(key, "\\\x00") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	for seg := range strings.SplitSeq(key, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	return nil
+}
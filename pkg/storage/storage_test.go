@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateKey(t *testing.T) {
+	for _, key := range []string{"invoices/42.pdf", "a", "reports/2025/orders.csv", "a..b"} {
+		if err := ValidateKey(key); err != nil {
+			t.Errorf("ValidateKey(%q) = %v", key, err)
+		}
+	}
+	for _, key := range []string{"", "/abs", "trailing/", "a//b", "../escape", "a/./b", `win\path`} {
+		if err := ValidateKey(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("ValidateKey(%q) = %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
+
+// testStore is the contract every Store meets.
+func testStore(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	if keys, err := s.List(ctx, ""); err != nil || len(keys) != 0 {
+		t.Fatalf("List on an empty store = %q, %v", keys, err)
+	}
+	for _, key := range []string{"invoices/2.txt", "invoices/1.txt", "reports/orders.csv", "invoices/1.txt"} {
+		if err := s.Put(ctx, key, strings.NewReader("doc "+key)); err != nil {
+			t.Fatalf("Put(%s): %v", key, err)
+		}
+	}
+	if err := s.Put(ctx, "../x", strings.NewReader("")); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Put(../x) = %v, want ErrInvalidKey", err)
+	}
+
+	r, err := s.Get(ctx, "invoices/1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(r)
+	r.Close()
+	if err != nil || string(data) != "doc invoices/1.txt" {
+		t.Errorf("Get = %q, %v", data, err)
+	}
+	if _, err := s.Get(ctx, "invoices/3.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing) = %v, want ErrNotFound", err)
+	}
+
+	keys, err := s.List(ctx, "invoices/")
+	if want := []string{"invoices/1.txt", "invoices/2.txt"}; err != nil || !slices.Equal(keys, want) {
+		t.Errorf("List(invoices/) = %q, %v; want %q", keys, err, want)
+	}
+	if keys, err := s.List(ctx, ""); err != nil || len(keys) != 3 {
+		t.Errorf("List() = %q, %v; want 3 keys", keys, err)
+	}
+
+	if err := s.Delete(ctx, "invoices/1.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, "invoices/1.txt"); err != nil {
+		t.Errorf("deleting a missing key: %v", err)
+	}
+	if _, err := s.Get(ctx, "invoices/1.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocal(t *testing.T) {
+	testStore(t, NewLocal(t.TempDir()+"/blobs"))
+}
@@ -0,0 +1,73 @@
+// Package timeout bounds calls through any port with a per-call
+// timeout. Do and Value are generic over the call, so one mechanism
+// serves payment gateways, email senders and notification channels.
+package timeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+// ErrDeadlineExceeded is matched by every *Error.
+var ErrDeadlineExceeded = errors.New("deadline exceeded")
+
+// Error reports a call that did not finish within its timeout. It
+// matches ErrDeadlineExceeded and context.DeadlineExceeded.
+type Error struct {
+	Op      string
+	Timeout time.Duration
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s after %s", e.Op, ErrDeadlineExceeded, e.Timeout)
+}
+
+func (e *Error) Is(target error) bool {
+	return target == ErrDeadlineExceeded || target == context.DeadlineExceeded
+}
+
+// Value calls fn and returns its result, or an *Error once d has
+// passed on clk. The context fn receives is cancelled on timeout; a
+// call that ignores its context keeps running in the background, but
+// the caller no longer waits for it. A non-positive d disables the
+// timeout and a nil clk means the system clock.
+func Value[T any](ctx context.Context, clk clock.Clock, op string, d time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	if d <= 0 {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		v   T
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		v, err := fn(ctx)
+		done <- result{v, err}
+	}()
+
+	var zero T
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-clock.OrSystem(clk).After(d):
+		return zero, &Error{Op: op, Timeout: d}
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// Do is Value for calls that only return an error.
+func Do(ctx context.Context, clk clock.Clock, op string, d time.Duration, fn func(ctx context.Context) error) error {
+	_, err := Value(ctx, clk, op, d, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
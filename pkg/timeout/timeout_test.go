@@ -0,0 +1,78 @@
+package timeout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+)
+
+// slow returns a call that blocks until release is closed or its
+// context is done, reporting which on cancelled.
+func slow(release <-chan struct{}, cancelled chan<- bool) func(context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		select {
+		case <-release:
+			cancelled <- false
+			return "ch_1", nil
+		case <-ctx.Done():
+			cancelled <- true
+			return "", ctx.Err()
+		}
+	}
+}
+
+func TestValue_TimesOut(t *testing.T) {
+	clk := clocktest.NewFake(time.Time{})
+	cancelled := make(chan bool, 1)
+
+	errc := make(chan error)
+	go func() {
+		_, err := Value(context.Background(), clk, "payment.charge", 2*time.Second, slow(nil, cancelled))
+		errc <- err
+	}()
+	clk.BlockUntil(1)
+	clk.Advance(2 * time.Second)
+
+	err := <-errc
+	var te *Error
+	if !errors.As(err, &te) || te.Op != "payment.charge" || te.Timeout != 2*time.Second {
+		t.Fatalf("err = %v, want *Error for payment.charge after 2s", err)
+	}
+	if !errors.Is(err, ErrDeadlineExceeded) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("%v does not match the deadline sentinels", err)
+	}
+	if !<-cancelled {
+		t.Error("the slow call was not cancelled")
+	}
+}
+
+func TestValue_ReturnsInTime(t *testing.T) {
+	clk := clocktest.NewFake(time.Time{})
+	release := make(chan struct{})
+	close(release)
+
+	got, err := Value(context.Background(), clk, "payment.charge", time.Second, slow(release, make(chan bool, 1)))
+	if err != nil || got != "ch_1" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+}
+
+func TestValue_CallerCancels(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Value(ctx, clocktest.NewFake(time.Time{}), "op", time.Second, slow(nil, make(chan bool, 1)))
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrDeadlineExceeded) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestDo_NoTimeout(t *testing.T) {
+	boom := errors.New("boom")
+	err := Do(context.Background(), nil, "op", 0, func(ctx context.Context) error { return boom })
+	if err != boom {
+		t.Errorf("err = %v, want %v", err, boom)
+	}
+}
@@ -0,0 +1,32 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OTel adapts an OpenTelemetry tracer to the Tracer port. Spans are
+// exported by whatever TracerProvider created the tracer.
+type OTel struct {
+	tracer trace.Tracer
+}
+
+// NewOTel returns a Tracer that starts its spans on t, for example
+// otel.Tracer("orders").
+func NewOTel(t trace.Tracer) OTel {
+	return OTel{tracer: t}
+}
+
+func (o OTel) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	ctx, span := o.tracer.Start(ctx, name)
+	return ctx, otelSpan{span: span}
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s otelSpan) End() {
+	s.span.End()
+}
@@ -0,0 +1,75 @@
+// Package tracing is the tracing port shared by the examples.
+//
+// Tracing is a cross-cutting concern. The business code only knows
+// about this narrow port; concrete tracers (no-op, console,
+// OpenTelemetry, ...) are injected from the outside.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Tracer starts spans around units of work. The returned context
+// carries the span, so spans started from it become its children.
+type Tracer interface {
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single unit of work started by a Tracer.
+type Span interface {
+	End()
+}
+
+// Noop discards every span. It is the default, so tracing is never a
+// hard requirement.
+type Noop struct{}
+
+func (Noop) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) End() {}
+
+// OrNoop returns t, or Noop if t is nil.
+func OrNoop(t Tracer) Tracer {
+	if t == nil {
+		return Noop{}
+	}
+	return t
+}
+
+// Console writes every finished span with its duration to W, or to
+// standard output if W is nil. Child spans are printed with their
+// parent's name as a prefix.
+type Console struct {
+	W io.Writer
+}
+
+type consoleKey struct{}
+
+func (c Console) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	if parent, ok := ctx.Value(consoleKey{}).(string); ok {
+		name = parent + " > " + name
+	}
+	w := c.W
+	if w == nil {
+		w = os.Stdout
+	}
+	return context.WithValue(ctx, consoleKey{}, name), consoleSpan{w: w, name: name, start: time.Now()}
+}
+
+type consoleSpan struct {
+	w     io.Writer
+	name  string
+	start time.Time
+}
+
+func (s consoleSpan) End() {
+	fmt.Fprintf(s.w, "[trace] %s took %s\n", s.name, time.Since(s.start))
+}
@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestConsole_PrintsNestedSpans(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := Console{W: &buf}
+
+	ctx, outer := tracer.StartSpan(context.Background(), "PlaceOrder")
+	_, inner := tracer.StartSpan(ctx, "Charge")
+	inner.End()
+	outer.End()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "[trace] PlaceOrder > Charge took ") {
+		t.Errorf("inner span = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "[trace] PlaceOrder took ") {
+		t.Errorf("outer span = %q", lines[1])
+	}
+}
+
+func TestOrNoop(t *testing.T) {
+	ctx := context.Background()
+	got, span := OrNoop(nil).StartSpan(ctx, "x")
+	span.End()
+	if got != ctx {
+		t.Error("Noop must return the context unchanged")
+	}
+}
+
+func TestOTel_ExportsParentChildSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := NewOTel(provider.Tracer("test"))
+
+	ctx, outer := tracer.StartSpan(context.Background(), "PlaceOrder")
+	_, inner := tracer.StartSpan(ctx, "Charge")
+	inner.End()
+	outer.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	child, parent := spans[0], spans[1]
+	if child.Name() != "Charge" || parent.Name() != "PlaceOrder" {
+		t.Fatalf("spans = %q, %q", child.Name(), parent.Name())
+	}
+	if child.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("Charge is not a child of PlaceOrder")
+	}
+}
@@ -0,0 +1,91 @@
+// Package validate checks HTTP requests before handlers see them. A
+// Decoder reads the request type from the HTTP request and the type
+// validates itself; Handler runs both and only calls the handler with
+// a valid request. Failures carry the invalid fields, which the
+// apierror body lists.
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/apierror"
+)
+
+var (
+	// ErrInvalid matches requests with invalid fields.
+	ErrInvalid = errors.New("invalid request")
+	// ErrMalformed matches requests that could not be decoded at all.
+	ErrMalformed = errors.New("malformed request")
+)
+
+// Validator is implemented by request types. Validate returns nil or
+// the problems with the request, usually as Errors.
+type Validator interface {
+	Validate() error
+}
+
+// Errors lists the invalid fields of a request. It matches ErrInvalid.
+type Errors []apierror.FieldError
+
+// Add records that field is invalid.
+func (e *Errors) Add(field, format string, args ...any) {
+	*e = append(*e, apierror.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err returns e as an error, or nil if no field was invalid.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, f := range e {
+		msgs[i] = f.Field + ": " + f.Message
+	}
+	return ErrInvalid.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+func (e Errors) Unwrap() error                      { return ErrInvalid }
+func (e Errors) FieldErrors() []apierror.FieldError { return e }
+
+// Decoder reads a request of type Req from r. Its errors are answered
+// as they are, so a decoder returns Errors or errors matching
+// ErrMalformed for what the client got wrong.
+type Decoder[Req any] func(r *http.Request) (Req, error)
+
+// JSON decodes the request body as JSON, rejecting bodies over
+// maxBytes and unknown fields.
+func JSON[Req any](maxBytes int64) Decoder[Req] {
+	return func(r *http.Request) (Req, error) {
+		var req Req
+		dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBytes))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			return req, fmt.Errorf("%w: %w", ErrMalformed, err)
+		}
+		return req, nil
+	}
+}
+
+// Handler returns a handler decoding and validating each request
+// before calling h with it. Failures go to fail, which answers them.
+func Handler[Req Validator](decode Decoder[Req], h func(http.ResponseWriter, *http.Request, Req), fail func(http.ResponseWriter, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := decode(r)
+		if err == nil {
+			err = req.Validate()
+		}
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		h(w, r, req)
+	}
+}
@@ -0,0 +1,77 @@
+package validate
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/apierror"
+)
+
+type greeting struct {
+	Name  string `json:"name"`
+	Times int    `json:"times"`
+}
+
+func (g greeting) Validate() error {
+	var errs Errors
+	if g.Name == "" {
+		errs.Add("name", "is required")
+	}
+	if g.Times < 1 || g.Times > 3 {
+		errs.Add("times", "must be between 1 and 3, not %d", g.Times)
+	}
+	return errs.Err()
+}
+
+func TestHandler(t *testing.T) {
+	mapper := apierror.NewMapper(
+		apierror.Rule{Err: ErrInvalid, Status: http.StatusBadRequest, Code: "invalid_request"},
+		apierror.Rule{Err: ErrMalformed, Status: http.StatusBadRequest, Code: "malformed_request"},
+	)
+	calls := 0
+	h := Handler(JSON[greeting](64), func(w http.ResponseWriter, r *http.Request, g greeting) {
+		calls++
+		w.Write([]byte(strings.Repeat("hi "+g.Name+"\n", g.Times)))
+	}, mapper.Write)
+
+	tests := []struct {
+		body   string
+		status int
+		want   string
+	}{
+		{`{"name": "Ada", "times": 2}`, 200, "hi Ada\nhi Ada\n"},
+		{`{"times": 9}`, 400, `"fields":[{"field":"name","message":"is required"},{"field":"times","message":"must be between 1 and 3, not 9"}]`},
+		{`{"name": "Ada", "times": 1, "extra": true}`, 400, `"code":"malformed_request"`},
+		{`{"name": "` + strings.Repeat("a", 100) + `", "times": 1}`, 400, `"code":"malformed_request"`},
+		{`not json`, 400, `"code":"malformed_request"`},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)))
+		if rec.Code != tt.status || !strings.Contains(rec.Body.String(), tt.want) {
+			t.Errorf("%s: %d %s, want %d with %s", tt.body, rec.Code, rec.Body, tt.status, tt.want)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want only for the valid request", calls)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var errs Errors
+	if errs.Err() != nil {
+		t.Error("no field errors must be no error")
+	}
+	errs.Add("items[0].sku", "is required")
+	err := errs.Err()
+	if !errors.Is(err, ErrInvalid) || err.Error() != "invalid request: items[0].sku: is required" {
+		t.Errorf("err = %v", err)
+	}
+	var fe apierror.FieldErrors
+	if !errors.As(err, &fe) || len(fe.FieldErrors()) != 1 {
+		t.Errorf("err does not list its fields: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+// Package webhook receives signed callbacks, such as a payment
+// gateway's. A Verifier checks that a request really comes from the
+// sender and is not a replay of an old one; Handler runs it before
+// anything reads the payload.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+)
+
+// ErrInvalidSignature matches requests that fail verification: a
+// missing, malformed or wrong signature, or one too old to accept.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// SignatureHeader carries the HMAC signature: "t=<unix time>,v1=<hex>",
+// with one v1 per secret while the sender rotates secrets.
+const SignatureHeader = "Webhook-Signature"
+
+// DefaultTolerance is how old a signature HMAC accepts by default.
+const DefaultTolerance = 5 * time.Minute
+
+// Verifier checks that a request with header and body comes from the
+// sender; it returns an error matching ErrInvalidSignature if not.
+type Verifier interface {
+	Verify(header http.Header, body []byte) error
+}
+
+// HMAC signs and verifies with HMAC-SHA256 over the timestamp and the
+// body, so neither can be changed. Signatures older than the tolerance
+// are rejected, which stops an intercepted request being replayed
+// later; a replay within the tolerance is left to the receiver's
+// idempotency.
+type HMAC struct {
+	secret    []byte
+	tolerance time.Duration
+	clock     clock.Clock
+}
+
+// NewHMAC returns an HMAC using secret. A non-positive tolerance means
+// DefaultTolerance, and a nil clk the system clock.
+func NewHMAC(secret []byte, tolerance time.Duration, clk clock.Clock) *HMAC {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	return &HMAC{secret: secret, tolerance: tolerance, clock: clock.OrSystem(clk)}
+}
+
+// Sign returns the SignatureHeader value for body, signed now.
+func (h *HMAC) Sign(body []byte) string {
+	t := h.clock.Now().Unix()
+	return fmt.Sprintf("t=%d,v1=%s", t, h.mac(t, body))
+}
+
+func (h *HMAC) Verify(header http.Header, body []byte) error {
+	value := header.Get(SignatureHeader)
+	if value == "" {
+		return fmt.Errorf("%w: no %s header", ErrInvalidSignature, SignatureHeader)
+	}
+	var (
+		t    int64
+		sigs []string
+	)
+	for _, part := range strings.Split(value, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			t, _ = strconv.ParseInt(v, 10, 64)
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	if t == 0 || len(sigs) == 0 {
+		return fmt.Errorf("%w: malformed %s header", ErrInvalidSignature, SignatureHeader)
+	}
+
+	want := h.mac(t, body)
+	matched := false
+	for _, sig := range sigs {
+		if hmac.Equal([]byte(sig), []byte(want)) {
+			matched = true
+		}
+	}
+	if !matched {
+		return fmt.Errorf("%w: no matching signature", ErrInvalidSignature)
+	}
+	// Checked after the signature, so a forged timestamp cannot probe
+	// the clock.
+	if age := h.clock.Now().Sub(time.Unix(t, 0)); age > h.tolerance || age < -h.tolerance {
+		return fmt.Errorf("%w: signed %s ago, outside the %s tolerance", ErrInvalidSignature, age.Round(time.Second), h.tolerance)
+	}
+	return nil
+}
+
+func (h *HMAC) mac(t int64, body []byte) string {
+	m := hmac.New(sha256.New, h.secret)
+	fmt.Fprintf(m, "%d.", t)
+	m.Write(body)
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// Handler reads the body of each request, at most maxBytes, verifies
+// it with v and passes it to handle, answering 204 No Content when
+// handle succeeds. Requests that fail verification never reach handle;
+// they and the errors of handle go to fail.
+func Handler(v Verifier, maxBytes int64, handle func(ctx context.Context, body []byte) error, fail func(http.ResponseWriter, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBytes))
+		if err != nil {
+			fail(w, fmt.Errorf("%w: reading body: %w", ErrInvalidSignature, err))
+			return
+		}
+		if err := v.Verify(r.Header, body); err != nil {
+			fail(w, err)
+			return
+		}
+		if err := handle(r.Context(), body); err != nil {
+			fail(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
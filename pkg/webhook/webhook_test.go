@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock/clocktest"
+)
+
+func TestHMAC(t *testing.T) {
+	clk := clocktest.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	sender := NewHMAC([]byte("whsec"), time.Minute, clk)
+	receiver := NewHMAC([]byte("whsec"), time.Minute, clk)
+	body := []byte(`{"id":"evt_1"}`)
+	signed := http.Header{SignatureHeader: {sender.Sign(body)}}
+
+	if err := receiver.Verify(signed, body); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	rotated := http.Header{SignatureHeader: {signed.Get(SignatureHeader) + ",v1=" + strings.Repeat("0", 64)}}
+	if err := receiver.Verify(rotated, body); err != nil {
+		t.Errorf("Verify with a second signature: %v", err)
+	}
+
+	forger := NewHMAC([]byte("guess"), time.Minute, clk)
+	for name, tt := range map[string]struct {
+		header http.Header
+		body   string
+	}{
+		"unsigned":       {http.Header{}, string(body)},
+		"malformed":      {http.Header{SignatureHeader: {"v1=abc"}}, string(body)},
+		"tampered body":  {signed, `{"id":"evt_2"}`},
+		"wrong secret":   {http.Header{SignatureHeader: {forger.Sign(body)}}, string(body)},
+		"new timestamp":  {http.Header{SignatureHeader: {strings.Replace(signed.Get(SignatureHeader), "t=", "t=1", 1)}}, string(body)},
+		"future request": {http.Header{SignatureHeader: {NewHMAC([]byte("whsec"), 0, clocktest.NewFake(clk.Now().Add(time.Hour))).Sign(body)}}, string(body)},
+	} {
+		if err := receiver.Verify(tt.header, []byte(tt.body)); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: err = %v, want ErrInvalidSignature", name, err)
+		}
+	}
+
+	// A captured request replayed after the tolerance is turned away.
+	clk.Advance(time.Minute + time.Second)
+	if err := receiver.Verify(signed, body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("replay: err = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	h := NewHMAC([]byte("whsec"), 0, nil)
+	var handled []string
+	handler := Handler(h, 64, func(ctx context.Context, body []byte) error {
+		if string(body) == "bad" {
+			return errors.New("unknown event")
+		}
+		handled = append(handled, string(body))
+		return nil
+	}, func(w http.ResponseWriter, err error) {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrInvalidSignature) {
+			status = http.StatusUnauthorized
+		}
+		w.WriteHeader(status)
+	})
+
+	tests := []struct {
+		body, signature string
+		status          int
+	}{
+		{"ok", h.Sign([]byte("ok")), http.StatusNoContent},
+		{"forged", h.Sign([]byte("ok")), http.StatusUnauthorized},
+		{"bad", h.Sign([]byte("bad")), http.StatusInternalServerError},
+		{strings.Repeat("x", 65), h.Sign([]byte(strings.Repeat("x", 65))), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+		req.Header.Set(SignatureHeader, tt.signature)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%.10s: status %d, want %d", tt.body, rec.Code, tt.status)
+		}
+	}
+	if len(handled) != 1 || handled[0] != "ok" {
+		t.Errorf("handled %q, want only the verified event", handled)
+	}
+}
@@ -0,0 +1,27 @@
+package workqueue
+
+import (
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/metrics"
+)
+
+// Instrument returns Hooks recording the queue named name with p:
+// the name_depth gauge, the name_rejected_total and name_failed_total
+// counters and the name_duration_seconds histogram.
+func Instrument(p metrics.Provider, name string) Hooks {
+	depth := p.Gauge(name+"_depth", "Jobs waiting in the "+name+" queue.")
+	rejected := p.Counter(name+"_rejected_total", "Jobs the "+name+" queue turned away.")
+	failed := p.Counter(name+"_failed_total", "Jobs of the "+name+" queue that failed.")
+	duration := p.Histogram(name+"_duration_seconds", "How long the jobs of the "+name+" queue took.")
+	return Hooks{
+		Depth:    func(n int) { depth.Set(float64(n)) },
+		Rejected: func(error) { rejected.Add(1) },
+		Done: func(elapsed time.Duration, err error) {
+			duration.Observe(elapsed.Seconds())
+			if err != nil {
+				failed.Add(1)
+			}
+		},
+	}
+}
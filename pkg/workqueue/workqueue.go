@@ -0,0 +1,222 @@
+// Package workqueue runs jobs in the background: a bounded queue
+// drained by a pool of worker goroutines. Callers only enqueue, so a
+// slow dependency, such as a mail provider, no longer holds them up;
+// when the queue is full they learn it at once from ErrFull, or, with
+// Options.Block, wait for room.
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/clock"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/errreport"
+)
+
+var (
+	// ErrFull matches Put failing because no room was left.
+	ErrFull = errors.New("queue full")
+	// ErrClosed matches Put after Shutdown.
+	ErrClosed = errors.New("queue closed")
+)
+
+// Handler does one job.
+type Handler[T any] func(ctx context.Context, job T) error
+
+// Shared returns h for every worker, for New.
+func Shared[T any](h Handler[T]) func(worker int) Handler[T] {
+	return func(int) Handler[T] { return h }
+}
+
+// Options configure a Queue.
+type Options struct {
+	// Workers is the number of worker goroutines; at least one runs.
+	Workers int
+	// Size is the number of jobs that can wait for a worker.
+	Size int
+	// Block makes Put wait for room until its context is done, instead
+	// of failing with ErrFull at once.
+	Block bool
+	// Hooks observe the queue, such as for metrics; see Instrument.
+	Hooks Hooks
+	// Clock times the jobs for Hooks.Done; nil means the system clock.
+	Clock clock.Clock
+}
+
+// Hooks are called as the queue works. Any of them may be nil. They
+// are called from the goroutines of Put and of the workers, so they
+// must be safe for concurrent use.
+type Hooks struct {
+	// Depth receives the number of waiting jobs after each change.
+	Depth func(n int)
+	// Rejected receives the errors of failed Puts.
+	Rejected func(err error)
+	// Done receives how long each job took and its error.
+	Done func(elapsed time.Duration, err error)
+}
+
+// Queue is a bounded job queue with a worker pool. It is safe for
+// concurrent use.
+type Queue[T any] struct {
+	opts    Options
+	clock   clock.Clock
+	onError func(context.Context, T, error)
+	jobs    chan item[T]
+	quit    chan struct{} // closed by Shutdown, to release blocked Puts
+	done    chan struct{} // closed once every job is done
+	puts    sync.WaitGroup
+	workers sync.WaitGroup
+
+	// mu guards closed, and puts against Add after Shutdown. It is
+	// never held while waiting, so neither Put nor Shutdown can hold
+	// up the other.
+	mu     sync.Mutex
+	closed bool
+}
+
+type item[T any] struct {
+	ctx context.Context
+	job T
+}
+
+// New starts the workers of a queue. newHandler returns the handler of
+// each worker, numbered from 0, so each can have its own connection;
+// see Shared. onError receives the jobs that fail, panics included as
+// an *errreport.PanicError, and may be nil.
+func New[T any](newHandler func(worker int) Handler[T], opts Options, onError func(ctx context.Context, job T, err error)) *Queue[T] {
+	if onError == nil {
+		onError = func(context.Context, T, error) {}
+	}
+	q := &Queue[T]{
+		opts:    opts,
+		clock:   clock.OrSystem(opts.Clock),
+		onError: onError,
+		jobs:    make(chan item[T], opts.Size),
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	for i := range max(opts.Workers, 1) {
+		q.workers.Add(1)
+		go q.work(newHandler(i))
+	}
+	return q
+}
+
+// Put queues job. The job keeps ctx's values but not its cancellation,
+// so it outlives the request that queued it. Put fails with ErrClosed
+// after Shutdown and with ErrFull when there is no room.
+func (q *Queue[T]) Put(ctx context.Context, job T) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return q.reject(ErrClosed)
+	}
+	q.puts.Add(1)
+	q.mu.Unlock()
+	defer q.puts.Done()
+
+	it := item[T]{ctx: context.WithoutCancel(ctx), job: job}
+	if !q.opts.Block {
+		select {
+		case q.jobs <- it:
+			q.depth()
+			return nil
+		default:
+			return q.reject(fmt.Errorf("%w: %d jobs waiting", ErrFull, len(q.jobs)))
+		}
+	}
+	select {
+	case q.jobs <- it:
+		q.depth()
+		return nil
+	case <-q.quit:
+		return q.reject(ErrClosed)
+	case <-ctx.Done():
+		return q.reject(fmt.Errorf("%w: %w", ErrFull, ctx.Err()))
+	}
+}
+
+func (q *Queue[T]) reject(err error) error {
+	if q.opts.Hooks.Rejected != nil {
+		q.opts.Hooks.Rejected(err)
+	}
+	return err
+}
+
+func (q *Queue[T]) depth() {
+	if q.opts.Hooks.Depth != nil {
+		q.opts.Hooks.Depth(len(q.jobs))
+	}
+}
+
+func (q *Queue[T]) work(h Handler[T]) {
+	defer q.workers.Done()
+	for it := range q.jobs {
+		q.depth()
+		start := q.clock.Now()
+		err := errreport.Guard(it.ctx, nil, nil, func() error {
+			return h(it.ctx, it.job)
+		})
+		if q.opts.Hooks.Done != nil {
+			q.opts.Hooks.Done(q.clock.Now().Sub(start), err)
+		}
+		if err != nil {
+			q.onError(it.ctx, it.job, err)
+		}
+	}
+}
+
+// Shutdown stops accepting jobs and waits until the workers have done
+// everything queued or in flight, or until ctx is done. Calling it
+// again waits again.
+func (q *Queue[T]) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.quit)
+		go q.drain()
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d queued jobs not done: %w", len(q.jobs), ctx.Err())
+	}
+}
+
+// drain closes the jobs once the Puts in progress have returned, so
+// none sends on a closed channel, and waits for the workers.
+func (q *Queue[T]) drain() {
+	q.puts.Wait()
+	close(q.jobs)
+	q.workers.Wait()
+	close(q.done)
+}
+
+// Len is the number of jobs waiting for a worker.
+func (q *Queue[T]) Len() int { return len(q.jobs) }
+
+// Cap is the number of jobs that can wait.
+func (q *Queue[T]) Cap() int { return cap(q.jobs) }
+
+// Closed reports whether Shutdown was called.
+func (q *Queue[T]) Closed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed
+}
+
+// Drained reports whether Shutdown has finished every queued job.
+func (q *Queue[T]) Drained() bool {
+	select {
+	case <-q.done:
+		return true
+	default:
+		return false
+	}
+}
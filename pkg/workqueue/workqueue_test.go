@@ -0,0 +1,179 @@
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anil-vinnakoti/go-SOLID/pkg/errreport"
+	"github.com/anil-vinnakoti/go-SOLID/pkg/metrics"
+)
+
+// gate is a handler that waits until it is opened.
+type gate struct {
+	open chan struct{}
+
+	mu   sync.Mutex
+	jobs []int
+}
+
+func newGate() *gate { return &gate{open: make(chan struct{})} }
+
+func (g *gate) handle(ctx context.Context, job int) error {
+	<-g.open
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.jobs = append(g.jobs, job)
+	return nil
+}
+
+func (g *gate) done() []int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.jobs
+}
+
+func TestQueue_FullAndDrain(t *testing.T) {
+	ctx := context.Background()
+	g := newGate()
+	q := New(Shared(g.handle), Options{Workers: 1, Size: 2}, nil)
+
+	// One job in flight, two waiting, then no room.
+	q.Put(ctx, 1)
+	for q.Len() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 2; i <= 3; i++ {
+		if err := q.Put(ctx, i); err != nil {
+			t.Fatalf("Put(%d): %v", i, err)
+		}
+	}
+	if err := q.Put(ctx, 4); !errors.Is(err, ErrFull) {
+		t.Fatalf("Put on a full queue: err = %v, want ErrFull", err)
+	}
+
+	close(g.open)
+	if err := q.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := g.done(); len(got) != 3 || !q.Drained() {
+		t.Errorf("done %v, drained %v; want 3 jobs", got, q.Drained())
+	}
+	if err := q.Put(ctx, 5); !errors.Is(err, ErrClosed) {
+		t.Errorf("Put after Shutdown: err = %v, want ErrClosed", err)
+	}
+}
+
+func TestQueue_BlockedPutDoesNotHoldUpShutdown(t *testing.T) {
+	g := newGate()
+	q := New(Shared(g.handle), Options{Workers: 1, Size: 1, Block: true}, nil)
+	q.Put(context.Background(), 1)
+	for q.Len() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	q.Put(context.Background(), 2)
+
+	// The queue is full, so this Put waits for room.
+	put := make(chan error)
+	go func() { put <- q.Put(context.Background(), 3) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := q.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown with a stuck job: err = %v", err)
+	}
+	if err := <-put; !errors.Is(err, ErrClosed) {
+		t.Errorf("blocked Put: err = %v, want ErrClosed", err)
+	}
+
+	close(g.open)
+	if err := q.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := g.done(); len(got) != 2 {
+		t.Errorf("done %v, want the 2 queued jobs", got)
+	}
+}
+
+func TestQueue_BlockGivesUpWithContext(t *testing.T) {
+	g := newGate()
+	defer close(g.open)
+	q := New(Shared(g.handle), Options{Workers: 1, Block: true}, nil)
+	q.Put(context.Background(), 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := q.Put(ctx, 2); !errors.Is(err, ErrFull) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want ErrFull and DeadlineExceeded", err)
+	}
+}
+
+func TestQueue_WorkersAndErrors(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		failed []error
+	)
+	onError := func(ctx context.Context, job int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed = append(failed, err)
+	}
+	q := New(func(worker int) Handler[int] {
+		return func(ctx context.Context, job int) error {
+			if job < 0 {
+				panic("negative job")
+			}
+			if job%2 == 1 {
+				return errors.New("odd job")
+			}
+			return nil
+		}
+	}, Options{Workers: 3, Size: 10}, onError)
+	for _, job := range []int{1, 2, -1} {
+		if err := q.Put(context.Background(), job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var panics int
+	for _, err := range failed {
+		if errors.As(err, new(*errreport.PanicError)) {
+			panics++
+		}
+	}
+	if len(failed) != 2 || panics != 1 {
+		t.Errorf("failed = %v, want an error and a panic", failed)
+	}
+}
+
+func TestInstrument(t *testing.T) {
+	m := metrics.NewInMemory()
+	q := New(Shared(func(ctx context.Context, job int) error {
+		if job == 0 {
+			return errors.New("zero")
+		}
+		return nil
+	}), Options{Size: 1, Hooks: Instrument(m, "jobs")}, nil)
+	q.Put(context.Background(), 0)
+	q.Put(context.Background(), 1)
+	q.Shutdown(context.Background())
+	q.Put(context.Background(), 2)
+
+	if got := m.Value("jobs_failed_total"); got != 1 {
+		t.Errorf("jobs_failed_total = %v, want 1", got)
+	}
+	if got := m.Value("jobs_rejected_total"); got < 1 {
+		t.Errorf("jobs_rejected_total = %v, want the Put after Shutdown", got)
+	}
+	if got := m.Value("jobs_depth"); got != 0 {
+		t.Errorf("jobs_depth = %v, want 0", got)
+	}
+	if got := len(m.Observations("jobs_duration_seconds")); got+int(m.Value("jobs_rejected_total")) != 3 {
+		t.Errorf("%d durations observed for 3 Puts, %v rejected", got, m.Value("jobs_rejected_total"))
+	}
+}